@@ -69,15 +69,17 @@ func TestAndroid(t *testing.T) {
 		gitClone(t, sampleAppDir, sampleAppURL)
 
 		cmd := command.New(binPath(), "--ci", "config", "--dir", sampleAppDir, "--output-dir", sampleAppDir)
-		_, err := cmd.RunAndReturnTrimmedCombinedOutput()
-		require.EqualError(t, err, "exit status 1")
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		require.NoError(t, err, out)
 
 		scanResultPth := filepath.Join(sampleAppDir, "result.yml")
 
 		result, err := fileutil.ReadStringFromFile(scanResultPth)
 		require.NoError(t, err)
 
-		validateConfigExpectation(t, "android-sdk22-no-gradlew", strings.TrimSpace(sampleAppsSDK22NoGradlewResultYML), strings.TrimSpace(result))
+		for _, expected := range sampleAppsSDK22NoGradlewResultYMLParts {
+			require.Contains(t, result, expected)
+		}
 	}
 
 	t.Log("android-sdk22-subdir")
@@ -113,7 +115,7 @@ func TestAndroid(t *testing.T) {
 		result, err := fileutil.ReadStringFromFile(scanResultPth)
 		require.NoError(t, err)
 
-		validateConfigExpectation(t, "android-gradle-kotlin-dsl", strings.TrimSpace(sampleAppsAndroid22ResultYML), strings.TrimSpace(result), sampleAppsAndroidSDK22SubdirVersions...)
+		validateConfigExpectation(t, "android-gradle-kotlin-dsl", strings.TrimSpace(androidGradleKotlinDSLResultYML), strings.TrimSpace(result), androidGradleKotlinDSLVersions...)
 	}
 }
 
@@ -123,6 +125,7 @@ var sampleAppsAndroidSDK22SubdirVersions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
 	steps.AndroidLintVersion,
@@ -130,16 +133,33 @@ var sampleAppsAndroidSDK22SubdirVersions = []interface{}{
 	steps.AndroidBuildVersion,
 	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidUnitTestVersion,
+	steps.AndroidBuildForUITestingVersion,
+	steps.VirtualDeviceTestingForAndroidVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 }
 
@@ -157,10 +177,20 @@ var sampleAppsAndroidSDK22SubdirResultYML = fmt.Sprintf(`options:
             env_key: VARIANT
             value_map:
               "":
-                config: android-config
+                title: Android SDK components
+                env_key: ANDROID_SDK_COMPONENTS
+                type: info
+                value_map:
+                  platforms;android-22 build-tools;22.0.1:
+                    title: Run the UI tests on a virtual device? (type yes to enable)
+                    env_key: RUN_UI_TESTS
+                    type: user_input_optional
+                    value_map:
+                      _:
+                        config: android-sdk-components-test-ui-test-config
 configs:
   android:
-    android-config: |
+    android-sdk-components-test-ui-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: android
@@ -168,7 +198,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           description: |
@@ -210,6 +240,20 @@ configs:
           - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -233,6 +277,16 @@ configs:
               - variant: $VARIANT
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -243,6 +297,20 @@ configs:
           - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -256,21 +324,84 @@ configs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
               - variant: $VARIANT
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-unit-test@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-build-for-ui-testing@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - virtual-device-testing-for-android@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - test_type: instrumentation
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   android: []
-`, sampleAppsAndroidSDK22SubdirVersions...)
-
-var sampleAppsSDK22NoGradlewResultYML = `warnings:
+summary:
   android:
-  - "<b>No Gradle Wrapper (gradlew) found.</b> \nUsing a Gradle Wrapper (gradlew)
-    is required, as the wrapper is what makes sure\nthat the right Gradle version
-    is installed and used for the build. More info/guide: <a>https://docs.gradle.org/current/userguide/gradle_wrapper.html</a>"
-errors:
+  - 'src: Gradle Wrapper version: 2.2.1'
+  - 'src: minSdkVersion: 15, targetSdkVersion: 22'
+  - 'src: application modules: app'
+  - 'src: version source: app: 1.0 (1) from versionName and versionCode of app/build.gradle'
+  - 'src: SDK components: platforms;android-22, build-tools;22.0.1'
+  - 'src: UI testing detected: Android instrumentation tests'
+  - 5 options, 5 branches, 1 configs
   general:
-  - No known platform detected
-`
+  - 'Primary languages: Java (2 files)'
+`, sampleAppsAndroidSDK22SubdirVersions...)
+
+// the project is configured with the gradle-runner step fallback, the Gradle executable is asked for
+var sampleAppsSDK22NoGradlewResultYMLParts = []string{
+	"env_key: GRADLEW_PATH",
+	"type: user_input",
+	"config: android-no-gradlew-sdk-components-test-ui-test-config",
+	"android-no-gradlew-sdk-components-test-ui-test-config: |",
+	"gradle-runner@" + steps.GradleRunnerVersion,
+	"No Gradle Wrapper (gradlew) found in: .",
+}
 
 var sampleAppsAndroid22Versions = []interface{}{
 	models.FormatVersion,
@@ -278,6 +409,7 @@ var sampleAppsAndroid22Versions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
 	steps.AndroidLintVersion,
@@ -285,16 +417,33 @@ var sampleAppsAndroid22Versions = []interface{}{
 	steps.AndroidBuildVersion,
 	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidUnitTestVersion,
+	steps.AndroidBuildForUITestingVersion,
+	steps.VirtualDeviceTestingForAndroidVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 }
 
@@ -312,10 +461,20 @@ var sampleAppsAndroid22ResultYML = fmt.Sprintf(`options:
             env_key: VARIANT
             value_map:
               "":
-                config: android-config
+                title: Android SDK components
+                env_key: ANDROID_SDK_COMPONENTS
+                type: info
+                value_map:
+                  platforms;android-22 build-tools;22.0.1:
+                    title: Run the UI tests on a virtual device? (type yes to enable)
+                    env_key: RUN_UI_TESTS
+                    type: user_input_optional
+                    value_map:
+                      _:
+                        config: android-sdk-components-test-ui-test-config
 configs:
   android:
-    android-config: |
+    android-sdk-components-test-ui-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: android
@@ -323,7 +482,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           description: |
@@ -365,6 +524,20 @@ configs:
           - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -388,6 +561,16 @@ configs:
               - variant: $VARIANT
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -398,6 +581,20 @@ configs:
           - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -411,10 +608,73 @@ configs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
               - variant: $VARIANT
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-unit-test@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-build-for-ui-testing@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - virtual-device-testing-for-android@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - test_type: instrumentation
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   android: []
+summary:
+  android:
+  - '.: Gradle Wrapper version: 2.2.1'
+  - '.: minSdkVersion: 15, targetSdkVersion: 22'
+  - '.: application modules: app'
+  - '.: version source: app: 1.0 (1) from versionName and versionCode of app/build.gradle'
+  - '.: SDK components: platforms;android-22, build-tools;22.0.1'
+  - '.: UI testing detected: Android instrumentation tests'
+  - 5 options, 5 branches, 1 configs
+  general:
+  - 'Primary languages: Java (2 files)'
 `, sampleAppsAndroid22Versions...)
 
 var androidNonExecutableGradlewVersions = []interface{}{
@@ -423,6 +683,8 @@ var androidNonExecutableGradlewVersions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
 	steps.AndroidLintVersion,
@@ -430,16 +692,35 @@ var androidNonExecutableGradlewVersions = []interface{}{
 	steps.AndroidBuildVersion,
 	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
 	steps.InstallMissingAndroidToolsVersion,
 	steps.AndroidLintVersion,
 	steps.AndroidUnitTestVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidUnitTestVersion,
+	steps.AndroidBuildForUITestingVersion,
+	steps.VirtualDeviceTestingForAndroidVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 }
 
@@ -457,10 +738,20 @@ var androidNonExecutableGradlewResultYML = fmt.Sprintf(`options:
             env_key: VARIANT
             value_map:
               "":
-                config: android-config
+                title: Android SDK components
+                env_key: ANDROID_SDK_COMPONENTS
+                type: info
+                value_map:
+                  platforms;android-22 build-tools;22.0.1:
+                    title: Run the UI tests on a virtual device? (type yes to enable)
+                    env_key: RUN_UI_TESTS
+                    type: user_input_optional
+                    value_map:
+                      _:
+                        config: android-chmod-gradlew-sdk-components-test-ui-test-config
 configs:
   android:
-    android-config: |
+    android-chmod-gradlew-sdk-components-test-ui-test-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: android
@@ -468,7 +759,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           description: |
@@ -510,6 +801,28 @@ configs:
           - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - script@%s:
+              title: Make gradlew executable
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  chmod +x $PROJECT_LOCATION/gradlew
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -533,6 +846,16 @@ configs:
               - variant: $VARIANT
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -543,6 +866,28 @@ configs:
           - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - script@%s:
+              title: Make gradlew executable
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  chmod +x $PROJECT_LOCATION/gradlew
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
           - install-missing-android-tools@%s:
               inputs:
               - gradlew_path: $PROJECT_LOCATION/gradlew
@@ -556,8 +901,353 @@ configs:
               - project_location: $PROJECT_LOCATION
               - module: $MODULE
               - variant: $VARIANT
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Make gradlew executable
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  chmod +x $PROJECT_LOCATION/gradlew
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-unit-test@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-build-for-ui-testing@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - virtual-device-testing-for-android@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - test_type: instrumentation
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   android: []
+summary:
+  android:
+  - '.: Gradle Wrapper version: 2.2.1'
+  - '.: minSdkVersion: 15, targetSdkVersion: 22'
+  - '.: application modules: app'
+  - '.: version source: app: 1.0 (1) from versionName and versionCode of app/build.gradle'
+  - '.: SDK components: platforms;android-22, build-tools;22.0.1'
+  - '.: UI testing detected: Android instrumentation tests'
+  - 5 options, 5 branches, 1 configs
+  general:
+  - 'Primary languages: Java (2 files)'
 `, androidNonExecutableGradlewVersions...)
+
+var androidGradleKotlinDSLVersions = []interface{}{
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.ChangeAndroidVersionCodeAndVersionNameVersion,
+	steps.AndroidLintVersion,
+	steps.AndroidUnitTestVersion,
+	steps.AndroidBuildVersion,
+	steps.SignAPKVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidLintVersion,
+	steps.AndroidUnitTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.ScriptVersion,
+	steps.InstallMissingAndroidToolsVersion,
+	steps.AndroidUnitTestVersion,
+	steps.AndroidBuildForUITestingVersion,
+	steps.VirtualDeviceTestingForAndroidVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+}
+
+var androidGradleKotlinDSLResultYML = fmt.Sprintf(`options:
+  android:
+    title: The root directory of an Android project
+    env_key: PROJECT_LOCATION
+    value_map:
+      .:
+        title: Module
+        env_key: MODULE
+        value_map:
+          app:
+            title: Variant
+            env_key: VARIANT
+            value_map:
+              "":
+                title: Android SDK components
+                env_key: ANDROID_SDK_COMPONENTS
+                type: info
+                value_map:
+                  platforms;android-27:
+                    title: Run the UI tests on a virtual device? (type yes to enable)
+                    env_key: RUN_UI_TESTS
+                    type: user_input_optional
+                    value_map:
+                      _:
+                        config: android-sdk-components-test-ui-test-config
+configs:
+  android:
+    android-sdk-components-test-ui-test-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: android
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: test
+      workflows:
+        deploy:
+          description: |
+            ## How to get a signed APK
+
+            This workflow contains the **Sign APK** step. To sign your APK all you have to do is to:
+
+            1. Click on **Code Signing** tab
+            1. Find the **ANDROID KEYSTORE FILE** section
+            1. Click or drop your file on the upload file field
+            1. Fill the displayed 3 input fields:
+             1. **Keystore password**
+             1. **Keystore alias**
+             1. **Private key password**
+            1. Click on **[Save metadata]** button
+
+            That's it! From now on, **Sign APK** step will receive your uploaded files.
+
+            ## To run this workflow
+
+            If you want to run this workflow manually:
+
+            1. Open the app's build list page
+            2. Click on **[Start/Schedule a Build]** button
+            3. Select **deploy** in **Workflow** dropdown input
+            4. Click **[Start Build]** button
+
+            Or if you need this workflow to be started by a GIT event:
+
+            1. Click on **Triggers** tab
+            2. Setup your desired event (push/tag/pull) and select **deploy** workflow
+            3. Click on **[Done]** and then **[Save]** buttons
+
+            The next change in your repository that matches any of your trigger map event will start **deploy** workflow.
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - change-android-versioncode-and-versionname@%s:
+              inputs:
+              - build_gradle_path: $PROJECT_LOCATION/$MODULE/build.gradle
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-unit-test@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-build@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - sign-apk@%s:
+              run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-lint@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-unit-test@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - script@%s:
+              title: Install Android SDK components
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+                  if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+                    sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+                  fi
+
+                  # the sdkmanager packages are separated by spaces
+                  yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS
+          - install-missing-android-tools@%s:
+              inputs:
+              - gradlew_path: $PROJECT_LOCATION/gradlew
+          - android-unit-test@%s:
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - android-build-for-ui-testing@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - project_location: $PROJECT_LOCATION
+              - module: $MODULE
+              - variant: $VARIANT
+          - virtual-device-testing-for-android@%s:
+              run_if: '{{enveq "RUN_UI_TESTS" "yes"}}'
+              inputs:
+              - test_type: instrumentation
+          - deploy-to-bitrise-io@%s:
+              title: Deploy test reports
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/test-results
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+warnings:
+  android: []
+summary:
+  android:
+  - '.: Gradle Wrapper version: 2.2.1'
+  - '.: minSdkVersion: 15, targetSdkVersion: 27'
+  - '.: application modules: app'
+  - '.: version source: app: 1.0 (1) from versionName and versionCode of app/build.gradle.kts'
+  - '.: SDK components: platforms;android-27'
+  - '.: UI testing detected: Android instrumentation tests'
+  - 5 options, 5 branches, 1 configs
+  general:
+  - 'Primary languages: Kotlin (2 files)'
+`, androidGradleKotlinDSLVersions...)
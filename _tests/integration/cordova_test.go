@@ -128,6 +128,11 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   cordova: []
+summary:
+  cordova:
+  - 1 options, 3 branches, 1 configs
+  general:
+  - 'Primary languages: JavaScript (2 files)'
 `, sampleAppsCordovaWithJasmineVersions...)
 
 var sampleAppsCordovaWithKarmaJasmineVersions = []interface{}{
@@ -205,4 +210,9 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   cordova: []
-  `, sampleAppsCordovaWithKarmaJasmineVersions...)
+summary:
+  cordova:
+  - 1 options, 3 branches, 1 configs
+  general:
+  - 'Primary languages: JavaScript (3 files)'
+`, sampleAppsCordovaWithKarmaJasmineVersions...)
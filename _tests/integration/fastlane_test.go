@@ -37,7 +37,6 @@ func TestFastlane(t *testing.T) {
 }
 
 var fastlaneVersions = []interface{}{
-	// fastlane
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -46,7 +45,6 @@ var fastlaneVersions = []interface{}{
 	steps.FastlaneVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -54,8 +52,10 @@ var fastlaneVersions = []interface{}{
 	steps.ScriptVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestVersion,
+	steps.ScriptVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -66,6 +66,14 @@ var fastlaneVersions = []interface{}{
 	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.XcodeTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var fastlaneResultYML = fmt.Sprintf(`options:
@@ -95,13 +103,57 @@ var fastlaneResultYML = fmt.Sprintf(`options:
             env_key: BITRISE_EXPORT_METHOD
             value_map:
               ad-hoc:
-                config: ios-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "10.1":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-version-bump-config
+                      "yes":
+                        config: ios-test-version-bump-config
               app-store:
-                config: ios-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "10.1":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-version-bump-config
+                      "yes":
+                        config: ios-test-version-bump-config
               development:
-                config: ios-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "10.1":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-version-bump-config
+                      "yes":
+                        config: ios-test-version-bump-config
               enterprise:
-                config: ios-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "10.1":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-version-bump-config
+                      "yes":
+                        config: ios-test-version-bump-config
 configs:
   fastlane:
     fastlane-config_ios: |
@@ -131,7 +183,7 @@ configs:
               - work_dir: $FASTLANE_WORK_DIR
           - deploy-to-bitrise-io@%s: {}
   ios:
-    ios-test-config: |
+    ios-test-version-bump-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
@@ -139,7 +191,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           steps:
@@ -154,11 +206,44 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -174,9 +259,38 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   fastlane: []
   ios: []
+summary:
+  fastlane:
+  - 3 options, 3 branches, 1 configs
+  general:
+  - 'Primary languages: Swift (4 files)'
+  ios:
+  - 'BitriseFastlaneSample/BitriseFastlaneSample.xcodeproj: iOS deployment target:
+    10.1'
+  - 'BitriseFastlaneSample/BitriseFastlaneSample.xcodeproj: target languages: BitriseFastlaneSample:
+    Swift, BitriseFastlaneSampleTests: Swift, BitriseFastlaneSampleUITests: Swift'
+  - 'BitriseFastlaneSample/BitriseFastlaneSample.xcodeproj: version source: BitriseFastlaneSample:
+    1.0 (1) from CFBundleShortVersionString and CFBundleVersion of BitriseFastlaneSample/BitriseFastlaneSample/Info.plist'
+  - 11 options, 18 branches, 1 configs
 `, fastlaneVersions...)
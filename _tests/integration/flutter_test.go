@@ -74,7 +74,6 @@ func TestFlutter(t *testing.T) {
 }
 
 var flutterSampleAppVersions = []interface{}{
-	// flutter-config
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -83,7 +82,6 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-android
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -92,6 +90,66 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -100,7 +158,6 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-both
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -111,6 +168,7 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -119,7 +177,6 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -127,19 +184,81 @@ var flutterSampleAppVersions = []interface{}{
 	steps.CertificateAndProfileInstallerVersion,
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.ScriptVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test
 	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -148,15 +267,19 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-android
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterTestVersion,
+	steps.ScriptVersion,
 	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -167,7 +290,6 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-both
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -179,6 +301,7 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -188,7 +311,6 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -197,9 +319,11 @@ var flutterSampleAppVersions = []interface{}{
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterTestVersion,
+	steps.ScriptVersion,
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -231,13 +355,37 @@ var flutterSampleAppResultYML = fmt.Sprintf(`options:
                     env_key: BITRISE_EXPORT_METHOD
                     value_map:
                       ad-hoc:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
                       app-store:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
                       development:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
                       enterprise:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
           "yes":
             title: Project (or Workspace) path
             env_key: BITRISE_PROJECT_PATH
@@ -251,13 +399,37 @@ var flutterSampleAppResultYML = fmt.Sprintf(`options:
                     env_key: BITRISE_EXPORT_METHOD
                     value_map:
                       ad-hoc:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
                       app-store:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
                       development:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
                       enterprise:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
 configs:
   flutter:
     flutter-config: |
@@ -307,6 +479,11 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -320,7 +497,7 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-app-both: |
+    flutter-config-app-android-version-bump: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -337,21 +514,30 @@ configs:
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
-          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s: {}
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: both
-          - xcode-archive@%s:
+              - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
               inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
-              - configuration: Release
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -365,7 +551,7 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-app-ios: |
+    flutter-config-app-both: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -390,13 +576,23 @@ configs:
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: ios
+              - platform: both
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -410,7 +606,7 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-test: |
+    flutter-config-app-both-version-bump: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -420,22 +616,63 @@ configs:
       - pull_request_source_branch: '*'
         workflow: primary
       workflows:
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s: {}
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-test@%s:
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-test-app-android: |
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-app-ios: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -452,17 +689,26 @@ configs:
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s: {}
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-test@%s:
-              inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: android
+              - platform: ios
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -475,11 +721,8 @@ configs:
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-test@%s:
-              inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-test-app-both: |
+    flutter-config-app-ios-version-bump: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -501,20 +744,55 @@ configs:
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-          - flutter-test@%s:
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
               inputs:
-              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: both
+              - platform: ios
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
+    flutter-config-test: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
         primary:
           steps:
           - activate-ssh-key@%s:
@@ -530,7 +808,7 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-test-app-ios: |
+    flutter-config-test-app-android: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -547,7 +825,6 @@ configs:
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
-          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s: {}
           - flutter-analyze@%s:
               inputs:
@@ -558,13 +835,12 @@ configs:
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: ios
-          - xcode-archive@%s:
+              - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
               inputs:
-              - project_path: $BITRISE_PROJECT_PATH
-              - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
-              - configuration: Release
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -581,29 +857,351 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-warnings:
-  flutter: []
-`, flutterSampleAppVersions...)
-
-var flutterSamplePackageVersions = []interface{}{
-	// flutter-config
-	models.FormatVersion,
-	steps.ActivateSSHKeyVersion,
-	steps.GitCloneVersion,
-	steps.ScriptVersion,
-	steps.FlutterInstallVersion,
-	steps.FlutterAnalyzeVersion,
-	steps.DeployToBitriseIoVersion,
-
-	// flutter-config-app-android
-	models.FormatVersion,
-	steps.ActivateSSHKeyVersion,
-	steps.GitCloneVersion,
-	steps.ScriptVersion,
+    flutter-config-test-app-android-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-both: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-both-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-ios: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: ios
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-ios-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: ios
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+warnings:
+  flutter: []
+summary:
+  flutter:
+  - '.: version source: sample_apps_flutter_ios_android: 1.0.0 (1) from version of
+    pubspec.yaml'
+  - 16 options, 31 branches, 2 configs
+  general:
+  - 'Primary languages: Dart (2 files), Swift (2 files), Java (1 file)'
+`, flutterSampleAppVersions...)
+
+var flutterSamplePackageVersions = []interface{}{
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -612,7 +1210,6 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-both
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -623,6 +1220,8 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -631,7 +1230,6 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -642,6 +1240,7 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -650,7 +1249,6 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -660,7 +1258,6 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-android
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -670,6 +1267,7 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -679,7 +1277,6 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-both
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -691,6 +1288,8 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -700,7 +1299,6 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -712,6 +1310,7 @@ var flutterSamplePackageVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -783,6 +1382,11 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -828,6 +1432,16 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -873,6 +1487,11 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -939,6 +1558,11 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -990,6 +1614,16 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1041,6 +1675,11 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1059,10 +1698,16 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   flutter: []
+summary:
+  flutter:
+  - '.: version source: sample_apps_flutter_ios_android_package: 0.0.1 from version
+    of pubspec.yaml'
+  - 2 options, 3 branches, 2 configs
+  general:
+  - 'Primary languages: Dart (2 files)'
 `, flutterSamplePackageVersions...)
 
 var flutterSamplePluginVersions = []interface{}{
-	// flutter-config
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1071,7 +1716,6 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-android
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1080,6 +1724,66 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1088,7 +1792,6 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-both
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1099,6 +1802,7 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1107,7 +1811,6 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterAnalyzeVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-app-ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1115,19 +1818,39 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.CertificateAndProfileInstallerVersion,
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
+	steps.ScriptVersion,
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.DeployToBitriseIoVersion,
 
+	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test
 	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -1136,7 +1859,6 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-android
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1144,8 +1866,10 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterTestVersion,
+	steps.ScriptVersion,
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1155,7 +1879,6 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-both
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1167,6 +1890,8 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1176,7 +1901,6 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterTestVersion,
 	steps.DeployToBitriseIoVersion,
 
-	// flutter-config-test-app-ios
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1185,9 +1909,55 @@ var flutterSamplePluginVersions = []interface{}{
 	steps.FlutterInstallVersion,
 	steps.FlutterAnalyzeVersion,
 	steps.FlutterTestVersion,
+	steps.ScriptVersion,
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.DeployToBitriseIoVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.FlutterInstallVersion,
+	steps.FlutterAnalyzeVersion,
+	steps.FlutterTestVersion,
+	steps.ScriptVersion,
+	steps.FlutterBuildVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -1204,7 +1974,13 @@ var flutterSamplePluginResultYML = fmt.Sprintf(`options:
     env_key: BITRISE_FLUTTER_PROJECT_LOCATION
     value_map:
       .:
-        config: flutter-config-app-android
+        title: Increment the build number before building the app?
+        env_key: VERSION_BUMP
+        value_map:
+          "no":
+            config: flutter-config-app-android-version-bump
+          "yes":
+            config: flutter-config-app-android-version-bump
       example:
         title: Run tests found in the project
         value_map:
@@ -1221,13 +1997,37 @@ var flutterSamplePluginResultYML = fmt.Sprintf(`options:
                     env_key: BITRISE_EXPORT_METHOD
                     value_map:
                       ad-hoc:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
                       app-store:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
                       development:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
                       enterprise:
-                        config: flutter-config-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-app-both-version-bump
+                          "yes":
+                            config: flutter-config-app-both-version-bump
           "yes":
             title: Project (or Workspace) path
             env_key: BITRISE_PROJECT_PATH
@@ -1241,13 +2041,37 @@ var flutterSamplePluginResultYML = fmt.Sprintf(`options:
                     env_key: BITRISE_EXPORT_METHOD
                     value_map:
                       ad-hoc:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
                       app-store:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
                       development:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
                       enterprise:
-                        config: flutter-config-test-app-both
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: flutter-config-test-app-both-version-bump
+                          "yes":
+                            config: flutter-config-test-app-both-version-bump
 configs:
   flutter:
     flutter-config: |
@@ -1272,7 +2096,104 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-app-android: |
+    flutter-config-app-android: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-app-android-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-app-both: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -1289,6 +2210,7 @@ configs:
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
           - flutter-installer@%s: {}
           - flutter-analyze@%s:
               inputs:
@@ -1296,7 +2218,23 @@ configs:
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
-              - platform: android
+              - platform: both
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1310,7 +2248,7 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
           - deploy-to-bitrise-io@%s: {}
-    flutter-config-app-both: |
+    flutter-config-app-both-version-bump: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: flutter
@@ -1332,6 +2270,17 @@ configs:
           - flutter-analyze@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
           - flutter-build@%s:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
@@ -1342,6 +2291,16 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1387,6 +2346,72 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-app-ios-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: ios
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1453,6 +2478,71 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-android-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1504,6 +2594,88 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-both-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: both
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1555,6 +2727,78 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - deploy-to-bitrise-io@%s: {}
+    flutter-config-test-app-ios-version-bump: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: flutter
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        deploy:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - certificate-and-profile-installer@%s: {}
+          - flutter-installer@%s: {}
+          - flutter-analyze@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - flutter-test@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$BITRISE_FLUTTER_PROJECT_LOCATION"
+                  sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml
+                  rm pubspec.yaml.bak
+          - flutter-build@%s:
+              inputs:
+              - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
+              - platform: ios
+          - xcode-archive@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+              - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1573,4 +2817,13 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   flutter: []
+summary:
+  flutter:
+  - '.: version source: sample_apps_flutter_ios_android_plugin: 0.0.1 from version
+    of pubspec.yaml'
+  - 'example: version source: sample_apps_flutter_ios_android_plugin_example: 1.0.0
+    (1) from version of example/pubspec.yaml'
+  - 17 options, 34 branches, 3 configs
+  general:
+  - 'Primary languages: Dart (3 files), Java (2 files), Swift (2 files)'
 `, flutterSamplePluginVersions...)
@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	cmd := command.New("git", args...).SetDir(dir).AppendEnvs(
+		"GIT_AUTHOR_NAME=bitrise-init",
+		"GIT_AUTHOR_EMAIL=bitrise-init@example.com",
+		"GIT_COMMITTER_NAME=bitrise-init",
+		"GIT_COMMITTER_EMAIL=bitrise-init@example.com",
+	)
+	out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+	require.NoError(t, err, out)
+}
+
+// createFixtureRepository creates a local git repository with a fastlane project,
+// the lanes differ on the master and develop branches.
+func createFixtureRepository(t *testing.T, dir string) {
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fastlane"), 0777))
+
+	fastfilePth := filepath.Join(dir, "fastlane", "Fastfile")
+
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "checkout", "-q", "-b", "master")
+	require.NoError(t, fileutil.WriteStringToFile(fastfilePth, "lane :test do\nend\n"))
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "test lane")
+
+	runGit(t, dir, "checkout", "-q", "-b", "develop")
+	require.NoError(t, fileutil.AppendStringToFile(fastfilePth, "lane :beta do\nend\n"))
+	runGit(t, dir, "commit", "-q", "-a", "-m", "beta lane")
+
+	runGit(t, dir, "checkout", "-q", "master")
+}
+
+func TestConfigGit(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__config-git__")
+	require.NoError(t, err)
+
+	repoDir := filepath.Join(tmpDir, "repository")
+	createFixtureRepository(t, repoDir)
+	repoURL := "file://" + repoDir
+
+	workDir := filepath.Join(tmpDir, "work")
+	require.NoError(t, os.MkdirAll(workDir, 0777))
+
+	t.Log("default branch")
+	{
+		cmd := command.New(binPath(), "--ci", "config", "--git", repoURL, "--output-dir", "default").SetDir(workDir)
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		require.NoError(t, err, out)
+
+		result, err := fileutil.ReadStringFromFile(filepath.Join(workDir, "default", "result.yml"))
+		require.NoError(t, err)
+		require.True(t, strings.Contains(result, "fastlane-config"), result)
+		require.True(t, strings.Contains(result, "test:"), result)
+		require.False(t, strings.Contains(result, "beta:"), result)
+	}
+
+	t.Log("branch")
+	{
+		cmd := command.New(binPath(), "--ci", "config", "--git", repoURL, "--branch", "develop", "--output-dir", "develop").SetDir(workDir)
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		require.NoError(t, err, out)
+
+		result, err := fileutil.ReadStringFromFile(filepath.Join(workDir, "develop", "result.yml"))
+		require.NoError(t, err)
+		require.True(t, strings.Contains(result, "beta:"), result)
+	}
+
+	t.Log("not existing branch")
+	{
+		cmd := command.New(binPath(), "--ci", "config", "--git", repoURL, "--branch", "not-existing", "--output-dir", "not-existing").SetDir(workDir)
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		require.Error(t, err, out)
+	}
+
+	t.Log("url starting with -")
+	{
+		optionURL := "--upload-pack=touch " + filepath.Join(tmpDir, "upload-pack-marker")
+		cmd := command.New(binPath(), "--ci", "config", "--git="+optionURL, "--output-dir", "option-url").SetDir(workDir)
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		require.Error(t, err, out)
+		// passed as the repository, not as the upload-pack option
+		require.True(t, strings.Contains(out, "repository '"+optionURL+"' does not exist"), out)
+	}
+
+	t.Log("branch without git")
+	{
+		cmd := command.New(binPath(), "--ci", "config", "--branch", "develop", "--output-dir", "no-git").SetDir(workDir)
+		out, err := cmd.RunAndReturnTrimmedCombinedOutput()
+		require.Error(t, err, out)
+	}
+}
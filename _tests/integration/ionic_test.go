@@ -96,4 +96,9 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   ionic: []
+summary:
+  general:
+  - 'Primary languages: TypeScript (3 files)'
+  ionic:
+  - 2 options, 4 branches, 1 configs
 `, ionic2Versions...)
@@ -97,8 +97,10 @@ var iosNoSharedSchemesVersions = []interface{}{
 	steps.CertificateAndProfileInstallerVersion,
 	steps.RecreateUserSchemesVersion,
 	steps.XcodeTestVersion,
+	steps.ScriptVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -110,6 +112,15 @@ var iosNoSharedSchemesVersions = []interface{}{
 	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.RecreateUserSchemesVersion,
+	steps.XcodeTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var iosNoSharedSchemesResultYML = fmt.Sprintf(`options:
@@ -126,16 +137,60 @@ var iosNoSharedSchemesResultYML = fmt.Sprintf(`options:
             env_key: BITRISE_EXPORT_METHOD
             value_map:
               ad-hoc:
-                config: ios-test-missing-shared-schemes-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-missing-shared-schemes-version-bump-config
+                      "yes":
+                        config: ios-test-missing-shared-schemes-version-bump-config
               app-store:
-                config: ios-test-missing-shared-schemes-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-missing-shared-schemes-version-bump-config
+                      "yes":
+                        config: ios-test-missing-shared-schemes-version-bump-config
               development:
-                config: ios-test-missing-shared-schemes-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-missing-shared-schemes-version-bump-config
+                      "yes":
+                        config: ios-test-missing-shared-schemes-version-bump-config
               enterprise:
-                config: ios-test-missing-shared-schemes-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-test-missing-shared-schemes-version-bump-config
+                      "yes":
+                        config: ios-test-missing-shared-schemes-version-bump-config
 configs:
   ios:
-    ios-test-missing-shared-schemes-config: |
+    ios-test-missing-shared-schemes-version-bump-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
@@ -143,7 +198,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           steps:
@@ -161,11 +216,44 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -184,6 +272,25 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - recreate-user-schemes@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
@@ -192,6 +299,16 @@ warnings:
     No shared schemes found for project: BitriseXcode7Sample.xcodeproj.
     Automatically generated schemes may differ from the ones in your project.
     Make sure to <a href="http://devcenter.bitrise.io/ios/frequent-ios-issues/#xcode-scheme-not-found">share your schemes</a> for the expected behaviour.
+summary:
+  general:
+  - 'Primary languages: Swift (4 files)'
+  ios:
+  - 'BitriseXcode7Sample.xcodeproj: iOS deployment target: 9.0'
+  - 'BitriseXcode7Sample.xcodeproj: target languages: BitriseXcode7Sample: Swift,
+    BitriseXcode7SampleTests: Swift, BitriseXcode7SampleUITests: Swift'
+  - 'BitriseXcode7Sample.xcodeproj: version source: BitriseXcode7Sample: 1.0 (1) from
+    CFBundleShortVersionString and CFBundleVersion of BitriseXcode7Sample/Info.plist'
+  - 11 options, 18 branches, 1 configs
 `, iosNoSharedSchemesVersions...)
 
 var iosCocoapodsAtRootVersions = []interface{}{
@@ -203,8 +320,10 @@ var iosCocoapodsAtRootVersions = []interface{}{
 	steps.CertificateAndProfileInstallerVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestVersion,
+	steps.ScriptVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -216,6 +335,15 @@ var iosCocoapodsAtRootVersions = []interface{}{
 	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.CocoapodsInstallVersion,
+	steps.XcodeTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var iosCocoapodsAtRootResultYML = fmt.Sprintf(`options:
@@ -232,16 +360,60 @@ var iosCocoapodsAtRootResultYML = fmt.Sprintf(`options:
             env_key: BITRISE_EXPORT_METHOD
             value_map:
               ad-hoc:
-                config: ios-pod-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "8.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-pod-test-version-bump-config
+                      "yes":
+                        config: ios-pod-test-version-bump-config
               app-store:
-                config: ios-pod-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "8.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-pod-test-version-bump-config
+                      "yes":
+                        config: ios-pod-test-version-bump-config
               development:
-                config: ios-pod-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "8.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-pod-test-version-bump-config
+                      "yes":
+                        config: ios-pod-test-version-bump-config
               enterprise:
-                config: ios-pod-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "8.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-pod-test-version-bump-config
+                      "yes":
+                        config: ios-pod-test-version-bump-config
 configs:
   ios:
-    ios-pod-test-config: |
+    ios-pod-test-version-bump-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
@@ -249,7 +421,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           steps:
@@ -265,11 +437,44 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -286,10 +491,38 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - cocoapods-install@%s: {}
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   ios: []
+summary:
+  general:
+  - 'Primary languages: Swift (4 files)'
+  ios:
+  - 'iOSMinimalCocoaPodsSample.xcworkspace: iOS deployment target: 8.0'
+  - 'iOSMinimalCocoaPodsSample.xcworkspace: target languages: iOSMinimalCocoaPodsSample:
+    Swift, iOSMinimalCocoaPodsSampleTests: Swift, iOSMinimalCocoaPodsSampleUITests:
+    Swift'
+  - 'iOSMinimalCocoaPodsSample.xcworkspace: version source: iOSMinimalCocoaPodsSample:
+    1.0 (1) from CFBundleShortVersionString and CFBundleVersion of iOSMinimalCocoaPodsSample/Info.plist'
+  - 11 options, 18 branches, 1 configs
 `, iosCocoapodsAtRootVersions...)
 
 var sampleAppsIosWatchkitVersions = []interface{}{
@@ -299,18 +532,19 @@ var sampleAppsIosWatchkitVersions = []interface{}{
 	steps.CachePullVersion,
 	steps.ScriptVersion,
 	steps.CertificateAndProfileInstallerVersion,
+	steps.XcodeTestVersion,
+	steps.ScriptVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
-	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestVersion,
-	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
@@ -318,10 +552,21 @@ var sampleAppsIosWatchkitVersions = []interface{}{
 	steps.GitCloneVersion,
 	steps.CachePullVersion,
 	steps.ScriptVersion,
-	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.CertificateAndProfileInstallerVersion,
+	steps.ScriptVersion,
+	steps.XcodeArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var sampleAppsIosWatchkitResultYML = fmt.Sprintf(`options:
@@ -330,72 +575,219 @@ var sampleAppsIosWatchkitResultYML = fmt.Sprintf(`options:
     env_key: BITRISE_PROJECT_PATH
     value_map:
       watch-test.xcodeproj:
-        title: Scheme name
-        env_key: BITRISE_SCHEME
+        title: App
         value_map:
-          Complication - watch-test WatchKit App:
-            title: ipa export method
-            env_key: BITRISE_EXPORT_METHOD
-            value_map:
-              ad-hoc:
-                config: ios-config
-              app-store:
-                config: ios-config
-              development:
-                config: ios-config
-              enterprise:
-                config: ios-config
-          Glance - watch-test WatchKit App:
-            title: ipa export method
-            env_key: BITRISE_EXPORT_METHOD
-            value_map:
-              ad-hoc:
-                config: ios-config
-              app-store:
-                config: ios-config
-              development:
-                config: ios-config
-              enterprise:
-                config: ios-config
-          Notification - watch-test WatchKit App:
-            title: ipa export method
-            env_key: BITRISE_EXPORT_METHOD
-            value_map:
-              ad-hoc:
-                config: ios-config
-              app-store:
-                config: ios-config
-              development:
-                config: ios-config
-              enterprise:
-                config: ios-config
           watch-test:
-            title: ipa export method
-            env_key: BITRISE_EXPORT_METHOD
+            title: Scheme name
+            env_key: BITRISE_SCHEME
             value_map:
-              ad-hoc:
-                config: ios-test-config
-              app-store:
-                config: ios-test-config
-              development:
-                config: ios-test-config
-              enterprise:
-                config: ios-test-config
+              watch-test:
+                title: ipa export method
+                env_key: BITRISE_EXPORT_METHOD
+                value_map:
+                  ad-hoc:
+                    title: Simulator OS version to test against
+                    env_key: BITRISE_SIMULATOR_OS_VERSION
+                    type: user_input_optional
+                    value_map:
+                      "9.0":
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: ios-test-version-bump-config
+                          "yes":
+                            config: ios-test-version-bump-config
+                  app-store:
+                    title: Simulator OS version to test against
+                    env_key: BITRISE_SIMULATOR_OS_VERSION
+                    type: user_input_optional
+                    value_map:
+                      "9.0":
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: ios-test-version-bump-config
+                          "yes":
+                            config: ios-test-version-bump-config
+                  development:
+                    title: Simulator OS version to test against
+                    env_key: BITRISE_SIMULATOR_OS_VERSION
+                    type: user_input_optional
+                    value_map:
+                      "9.0":
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: ios-test-version-bump-config
+                          "yes":
+                            config: ios-test-version-bump-config
+                  enterprise:
+                    title: Simulator OS version to test against
+                    env_key: BITRISE_SIMULATOR_OS_VERSION
+                    type: user_input_optional
+                    value_map:
+                      "9.0":
+                        title: Increment the build number before building the app?
+                        env_key: VERSION_BUMP
+                        value_map:
+                          "no":
+                            config: ios-test-version-bump-config
+                          "yes":
+                            config: ios-test-version-bump-config
           watch-test WatchKit App:
-            title: ipa export method
-            env_key: BITRISE_EXPORT_METHOD
+            title: Scheme name
+            env_key: BITRISE_SCHEME
             value_map:
-              ad-hoc:
-                config: ios-config
-              app-store:
-                config: ios-config
-              development:
-                config: ios-config
-              enterprise:
-                config: ios-config
+              Complication - watch-test WatchKit App:
+                title: ipa export method
+                env_key: BITRISE_EXPORT_METHOD
+                value_map:
+                  ad-hoc:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  app-store:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  development:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  enterprise:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+              Glance - watch-test WatchKit App:
+                title: ipa export method
+                env_key: BITRISE_EXPORT_METHOD
+                value_map:
+                  ad-hoc:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  app-store:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  development:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  enterprise:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+              Notification - watch-test WatchKit App:
+                title: ipa export method
+                env_key: BITRISE_EXPORT_METHOD
+                value_map:
+                  ad-hoc:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  app-store:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  development:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  enterprise:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+              watch-test WatchKit App:
+                title: ipa export method
+                env_key: BITRISE_EXPORT_METHOD
+                value_map:
+                  ad-hoc:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  app-store:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  development:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
+                  enterprise:
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-version-bump-config
+                      "yes":
+                        config: ios-version-bump-config
 configs:
   ios:
-    ios-config: |
+    ios-test-version-bump-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
@@ -403,9 +795,9 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
-        primary:
+        deploy:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
@@ -414,24 +806,51 @@ configs:
           - script@%s:
               title: Do anything with Script step
           - certificate-and-profile-installer@%s: {}
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
-    ios-test-config: |
-      format_version: "%s"
-      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
-      project_type: ios
-      trigger_map:
-      - push_branch: '*'
-        workflow: primary
-      - pull_request_source_branch: '*'
-        workflow: primary
-      workflows:
-        deploy:
+        primary:
           steps:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
@@ -444,13 +863,34 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-          - xcode-archive@%s:
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - xcode-test@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-              - export_method: $BITRISE_EXPORT_METHOD
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
+    ios-version-bump-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: ios
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
         primary:
           steps:
           - activate-ssh-key@%s:
@@ -460,14 +900,64 @@ configs:
           - script@%s:
               title: Do anything with Script step
           - certificate-and-profile-installer@%s: {}
-          - xcode-test@%s:
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
+          - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   ios: []
+summary:
+  general:
+  - 'Primary languages: Swift (5 files)'
+  ios:
+  - 'watch-test.xcodeproj: iOS deployment target: 9.0'
+  - 'watch-test.xcodeproj: target languages: watch-test: Swift, watch-testTests: Swift,
+    watch-testUITests: Swift'
+  - 'watch-test.xcodeproj: version source: watch-test: 1.0 (1) from CFBundleShortVersionString
+    and CFBundleVersion of watch-test/Info.plist'
+  - 'watch-test.xcodeproj: 2 apps found, schemes grouped by app: watch-test (com.bitrise.watch-test):
+    watch-test; watch-test WatchKit App (com.bitrise.watch-test.watchkitapp): Complication
+    - watch-test WatchKit App, Glance - watch-test WatchKit App, Notification - watch-test
+    WatchKit App, watch-test WatchKit App'
+  - 'watch-test.xcodeproj: no test target found for scheme(s): Complication - watch-test
+    WatchKit App, Glance - watch-test WatchKit App, Notification - watch-test WatchKit
+    App, watch-test WatchKit App, test workflow is not generated'
+  - 33 options, 72 branches, 2 configs
 `, sampleAppsIosWatchkitVersions...)
 
 var sampleAppsCarthageVersions = []interface{}{
@@ -479,8 +969,10 @@ var sampleAppsCarthageVersions = []interface{}{
 	steps.CertificateAndProfileInstallerVersion,
 	steps.CarthageVersion,
 	steps.XcodeTestVersion,
+	steps.ScriptVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -492,6 +984,15 @@ var sampleAppsCarthageVersions = []interface{}{
 	steps.XcodeTestVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.CarthageVersion,
+	steps.XcodeTestVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var sampleAppsCarthageResultYML = fmt.Sprintf(`options:
@@ -508,16 +1009,60 @@ var sampleAppsCarthageResultYML = fmt.Sprintf(`options:
             env_key: BITRISE_EXPORT_METHOD
             value_map:
               ad-hoc:
-                config: ios-carthage-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-carthage-test-version-bump-config
+                      "yes":
+                        config: ios-carthage-test-version-bump-config
               app-store:
-                config: ios-carthage-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-carthage-test-version-bump-config
+                      "yes":
+                        config: ios-carthage-test-version-bump-config
               development:
-                config: ios-carthage-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-carthage-test-version-bump-config
+                      "yes":
+                        config: ios-carthage-test-version-bump-config
               enterprise:
-                config: ios-carthage-test-config
+                title: Simulator OS version to test against
+                env_key: BITRISE_SIMULATOR_OS_VERSION
+                type: user_input_optional
+                value_map:
+                  "9.0":
+                    title: Increment the build number before building the app?
+                    env_key: VERSION_BUMP
+                    value_map:
+                      "no":
+                        config: ios-carthage-test-version-bump-config
+                      "yes":
+                        config: ios-carthage-test-version-bump-config
 configs:
   ios:
-    ios-carthage-test-config: |
+    ios-carthage-test-version-bump-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: ios
@@ -525,7 +1070,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           steps:
@@ -543,11 +1088,44 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
           - xcode-archive@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -566,8 +1144,37 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - carthage@%s:
+              inputs:
+              - carthage_command: bootstrap
+          - xcode-test@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+              - simulator_os_version: $BITRISE_SIMULATOR_OS_VERSION
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
 warnings:
   ios: []
+summary:
+  general:
+  - 'Primary languages: Swift (4 files)'
+  ios:
+  - 'sample-apps-carthage.xcodeproj: iOS deployment target: 9.0'
+  - 'sample-apps-carthage.xcodeproj: target languages: sample-apps-carthage: Swift,
+    sample-apps-carthageTests: Swift, sample-apps-carthageUITests: Swift'
+  - 'sample-apps-carthage.xcodeproj: version source: sample-apps-carthage: 1.0 (1)
+    from CFBundleShortVersionString and CFBundleVersion of sample-apps-carthage/Info.plist'
+  - 11 options, 18 branches, 1 configs
 `, sampleAppsCarthageVersions...)
@@ -44,8 +44,10 @@ var sampleAppsOSX1011Versions = []interface{}{
 	steps.ScriptVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeTestMacVersion,
+	steps.ScriptVersion,
 	steps.XcodeArchiveMacVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -56,6 +58,14 @@ var sampleAppsOSX1011Versions = []interface{}{
 	steps.XcodeTestMacVersion,
 	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
+
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.XcodeTestMacVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var sampleAppsOSX1011ResultYML = fmt.Sprintf(`options:
@@ -74,16 +84,40 @@ var sampleAppsOSX1011ResultYML = fmt.Sprintf(`options:
             env_key: BITRISE_EXPORT_METHOD
             value_map:
               app-store:
-                config: macos-test-config
+                title: Increment the build number before building the app?
+                env_key: VERSION_BUMP
+                value_map:
+                  "no":
+                    config: macos-test-version-bump-config
+                  "yes":
+                    config: macos-test-version-bump-config
               developer-id:
-                config: macos-test-config
+                title: Increment the build number before building the app?
+                env_key: VERSION_BUMP
+                value_map:
+                  "no":
+                    config: macos-test-version-bump-config
+                  "yes":
+                    config: macos-test-version-bump-config
               development:
-                config: macos-test-config
+                title: Increment the build number before building the app?
+                env_key: VERSION_BUMP
+                value_map:
+                  "no":
+                    config: macos-test-version-bump-config
+                  "yes":
+                    config: macos-test-version-bump-config
               none:
-                config: macos-test-config
+                title: Increment the build number before building the app?
+                env_key: VERSION_BUMP
+                value_map:
+                  "no":
+                    config: macos-test-version-bump-config
+                  "yes":
+                    config: macos-test-version-bump-config
 configs:
   macos:
-    macos-test-config: |
+    macos-test-version-bump-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: macos
@@ -91,7 +125,7 @@ configs:
       - push_branch: '*'
         workflow: primary
       - pull_request_source_branch: '*'
-        workflow: primary
+        workflow: test
       workflows:
         deploy:
           steps:
@@ -106,11 +140,43 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
+          - script@%s:
+              title: Increment the build number
+              run_if: '{{enveq "VERSION_BUMP" "yes"}}'
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  project_path="$BITRISE_PROJECT_PATH"
+                  if [[ "$project_path" == *.xcworkspace ]] ; then
+                    workspace_dir="$(dirname "$project_path")"
+                    project_path=""
+                    while IFS= read -r location ; do
+                      if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$BITRISE_SCHEME.xcscheme")" ] ; then
+                        project_path="$workspace_dir/$location"
+                        break
+                      fi
+                    done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$BITRISE_PROJECT_PATH/contents.xcworkspacedata")
+
+                    if [ -z "$project_path" ] ; then
+                      echo "No project of the workspace contains the scheme: $BITRISE_SCHEME"
+                      exit 1
+                    fi
+                  fi
+
+                  cd "$(dirname "$project_path")"
+                  xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
           - xcode-archive-mac@%s:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -128,6 +194,30 @@ configs:
               - scheme: $BITRISE_SCHEME
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
+        test:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - xcode-test-mac@%s:
+              inputs:
+              - project_path: $BITRISE_PROJECT_PATH
+              - scheme: $BITRISE_SCHEME
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s: {}
 warnings:
   macos: []
+summary:
+  general:
+  - 'Primary languages: Swift (23 files)'
+  macos:
+  - 'sample-apps-osx-10-11.xcodeproj: macOS deployment target: 10.11'
+  - 'sample-apps-osx-10-11.xcodeproj: target languages: sample-apps-osx-10-11: Swift,
+    sample-apps-osx-10-11Tests: Swift, sample-apps-osx-10-11UITests: Swift'
+  - 'sample-apps-osx-10-11.xcodeproj: version source: sample-apps-osx-10-11: 1.0 (1)
+    from CFBundleShortVersionString and CFBundleVersion of sample-apps-osx-10-11/Info.plist'
+  - 7 options, 14 branches, 1 configs
 `, sampleAppsOSX1011Versions...)
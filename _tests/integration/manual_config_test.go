@@ -52,6 +52,7 @@ var customConfigVersions = []interface{}{
 	steps.AndroidBuildVersion,
 	steps.SignAPKVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -75,7 +76,17 @@ var customConfigVersions = []interface{}{
 	steps.CordovaArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	// electron
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.ScriptVersion,
+	steps.NpmVersion,
+	steps.ScriptVersion,
+	steps.DeployToBitriseIoVersion,
+
 	// fastlane
+	steps.DeployToBitriseIoVersion,
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -102,6 +113,7 @@ var customConfigVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
 
+	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -120,6 +132,8 @@ var customConfigVersions = []interface{}{
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -137,6 +151,7 @@ var customConfigVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -164,6 +179,7 @@ var customConfigVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.DeployToBitriseIoVersion,
 
+	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -184,6 +200,8 @@ var customConfigVersions = []interface{}{
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
 
+	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -203,6 +221,7 @@ var customConfigVersions = []interface{}{
 	steps.FlutterBuildVersion,
 	steps.XcodeArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
@@ -259,7 +278,6 @@ var customConfigVersions = []interface{}{
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestMacVersion,
 	steps.XcodeArchiveMacVersion,
-	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	steps.ActivateSSHKeyVersion,
@@ -270,7 +288,6 @@ var customConfigVersions = []interface{}{
 	steps.RecreateUserSchemesVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeTestMacVersion,
-	steps.DeployToBitriseIoVersion,
 	steps.CachePushVersion,
 
 	// other
@@ -278,7 +295,6 @@ var customConfigVersions = []interface{}{
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
-	steps.DeployToBitriseIoVersion,
 
 	// react native
 	models.FormatVersion,
@@ -290,14 +306,12 @@ var customConfigVersions = []interface{}{
 	steps.AndroidBuildVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeArchiveVersion,
-	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.DeployToBitriseIoVersion,
 
 	// react native expo with expo kit
 	models.FormatVersion,
@@ -311,14 +325,12 @@ var customConfigVersions = []interface{}{
 	steps.CertificateAndProfileInstallerVersion,
 	steps.CocoapodsInstallVersion,
 	steps.XcodeArchiveVersion,
-	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.DeployToBitriseIoVersion,
 
 	// react native expo (plain)
 	models.FormatVersion,
@@ -331,14 +343,12 @@ var customConfigVersions = []interface{}{
 	steps.AndroidBuildVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XcodeArchiveVersion,
-	steps.DeployToBitriseIoVersion,
 
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
 	steps.NpmVersion,
 	steps.NpmVersion,
-	steps.DeployToBitriseIoVersion,
 
 	// xamarin
 	models.FormatVersion,
@@ -350,7 +360,6 @@ var customConfigVersions = []interface{}{
 	steps.NugetRestoreVersion,
 	steps.XamarinComponentsRestoreVersion,
 	steps.XamarinArchiveVersion,
-	steps.DeployToBitriseIoVersion,
 }
 
 var customConfigResultYML = fmt.Sprintf(`options:
@@ -382,6 +391,20 @@ var customConfigResultYML = fmt.Sprintf(`options:
             config: default-cordova-config
           ios,android:
             config: default-cordova-config
+  electron:
+    title: Directory of the Electron app's package.json
+    env_key: ELECTRON_WORK_DIR
+    value_map:
+      _:
+        title: Platform to build the Electron app for
+        env_key: ELECTRON_TARGET
+        value_map:
+          linux:
+            config: default-electron-config
+          mac:
+            config: default-electron-config
+          win:
+            config: default-electron-config
   fastlane:
     title: Working directory
     env_key: FASTLANE_WORK_DIR
@@ -615,7 +638,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                 env_key: VARIANT
                                 value_map:
                                   Release:
-                                    config: react-native-expo-plain-default-config
+                                    config: default-react-native-expo-plain-config
                   app-store:
                     title: Project root directory (the directory of the project app.json/package.json
                       file)
@@ -634,7 +657,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                 env_key: VARIANT
                                 value_map:
                                   Release:
-                                    config: react-native-expo-plain-default-config
+                                    config: default-react-native-expo-plain-config
                   development:
                     title: Project root directory (the directory of the project app.json/package.json
                       file)
@@ -653,7 +676,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                 env_key: VARIANT
                                 value_map:
                                   Release:
-                                    config: react-native-expo-plain-default-config
+                                    config: default-react-native-expo-plain-config
                   enterprise:
                     title: Project root directory (the directory of the project app.json/package.json
                       file)
@@ -672,7 +695,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                 env_key: VARIANT
                                 value_map:
                                   Release:
-                                    config: react-native-expo-plain-default-config
+                                    config: default-react-native-expo-plain-config
       "yes":
         title: The iOS workspace path generated ny the 'expo eject' process
         env_key: BITRISE_PROJECT_PATH
@@ -711,7 +734,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                         env_key: EXPO_PASSWORD
                                         value_map:
                                           _:
-                                            config: react-native-expo-expo-kit-default-config
+                                            config: default-react-native-expo-expo-kit-config
                   app-store:
                     title: Project root directory (the directory of the project app.json/package.json
                       file)
@@ -738,7 +761,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                         env_key: EXPO_PASSWORD
                                         value_map:
                                           _:
-                                            config: react-native-expo-expo-kit-default-config
+                                            config: default-react-native-expo-expo-kit-config
                   development:
                     title: Project root directory (the directory of the project app.json/package.json
                       file)
@@ -765,7 +788,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                         env_key: EXPO_PASSWORD
                                         value_map:
                                           _:
-                                            config: react-native-expo-expo-kit-default-config
+                                            config: default-react-native-expo-expo-kit-config
                   enterprise:
                     title: Project root directory (the directory of the project app.json/package.json
                       file)
@@ -792,7 +815,7 @@ var customConfigResultYML = fmt.Sprintf(`options:
                                         env_key: EXPO_PASSWORD
                                         value_map:
                                           _:
-                                            config: react-native-expo-expo-kit-default-config
+                                            config: default-react-native-expo-expo-kit-config
   xamarin:
     title: Path to the Xamarin Solution file
     env_key: BITRISE_PROJECT_PATH
@@ -882,6 +905,11 @@ configs:
               - variant: $VARIANT
           - sign-apk@%s:
               run_if: '{{getenv "BITRISEIO_ANDROID_KEYSTORE_URL" | ne ""}}'
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
           - cache-push@%s: {}
         primary:
@@ -937,6 +965,43 @@ configs:
               - platform: $CORDOVA_PLATFORM
               - target: emulator
           - deploy-to-bitrise-io@%s: {}
+  electron:
+    default-electron-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: electron
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - npm@%s:
+              inputs:
+              - command: install
+              - workdir: $ELECTRON_WORK_DIR
+          - script@%s:
+              title: Build with electron-builder
+              inputs:
+              - content: |
+                  #!/usr/bin/env bash
+                  set -ex
+
+                  cd "$ELECTRON_WORK_DIR"
+                  npx electron-builder --"$ELECTRON_TARGET" --publish never
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $ELECTRON_WORK_DIR/dist
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s: {}
   fastlane:
     default-fastlane-config: |
       format_version: "%s"
@@ -1012,6 +1077,11 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1057,6 +1127,16 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1102,6 +1182,11 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1168,6 +1253,11 @@ configs:
               inputs:
               - project_location: $BITRISE_FLUTTER_PROJECT_LOCATION
               - platform: android
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1219,6 +1309,16 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release
+              - is_compress: "true"
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1270,6 +1370,11 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
+          - deploy-to-bitrise-io@%s:
+              title: Deploy build artifacts
+              inputs:
+              - deploy_path: $BITRISE_XCARCHIVE_PATH
+              - is_compress: "true"
           - deploy-to-bitrise-io@%s: {}
         primary:
           steps:
@@ -1403,7 +1508,7 @@ configs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
           - cache-push@%s: {}
         primary:
           steps:
@@ -1422,7 +1527,7 @@ configs:
               inputs:
               - project_path: $BITRISE_PROJECT_PATH
               - scheme: $BITRISE_SCHEME
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
           - cache-push@%s: {}
   other:
     other-config: |
@@ -1442,7 +1547,7 @@ configs:
           - git-clone@%s: {}
           - script@%s:
               title: Do anything with Script step
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
   react-native:
     default-react-native-config: |
       format_version: "%s"
@@ -1477,7 +1582,7 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
         primary:
           steps:
           - activate-ssh-key@%s:
@@ -1491,7 +1596,7 @@ configs:
           - npm@%s:
               inputs:
               - command: test
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
   react-native-expo:
     default-react-native-expo-expo-kit-config: |
       format_version: "%s"
@@ -1565,7 +1670,7 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
         primary:
           steps:
           - activate-ssh-key@%s:
@@ -1581,7 +1686,7 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
     default-react-native-expo-plain-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
@@ -1650,7 +1755,7 @@ configs:
               - scheme: $BITRISE_SCHEME
               - export_method: $BITRISE_EXPORT_METHOD
               - configuration: Release
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
         primary:
           steps:
           - activate-ssh-key@%s:
@@ -1666,7 +1771,7 @@ configs:
               inputs:
               - workdir: $WORKDIR
               - command: test
-          - deploy-to-bitrise-io@%s: {}
+          - deploy-to-bitrise-io@1.3.19: {}
   xamarin:
     default-xamarin-config: |
       format_version: "%s"
@@ -1695,5 +1800,4 @@ configs:
               - xamarin_solution: $BITRISE_PROJECT_PATH
               - xamarin_configuration: $BITRISE_XAMARIN_CONFIGURATION
               - xamarin_platform: $BITRISE_XAMARIN_PLATFORM
-          - deploy-to-bitrise-io@%s: {}
-`, customConfigVersions...)
+          - deploy-to-bitrise-io@1.3.19: {}`, customConfigVersions...)
@@ -62,7 +62,6 @@ func TestReactNativeExpo(t *testing.T) {
 
 var bitriseCRNAVersions = []interface{}{
 	models.FormatVersion,
-
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -222,11 +221,15 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native-expo: []
+summary:
+  general:
+  - 'Primary languages: JavaScript (2 files)'
+  react-native-expo:
+  - 16 options, 19 branches, 1 configs
 `, bitriseCRNAVersions...)
 
 var bitriseExpoKitVersions = []interface{}{
 	models.FormatVersion,
-
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -443,4 +446,9 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native-expo: []
+summary:
+  general:
+  - 'Primary languages: JavaScript (2 files)'
+  react-native-expo:
+  - 24 options, 27 branches, 1 configs
 `, bitriseExpoKitVersions...)
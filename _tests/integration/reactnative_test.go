@@ -57,7 +57,6 @@ func TestReactNative(t *testing.T) {
 
 var sampleAppsReactNativeSubdirVersions = []interface{}{
 	models.FormatVersion,
-
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -90,37 +89,138 @@ var sampleAppsReactNativeSubdirResultYML = fmt.Sprintf(`options:
             env_key: VARIANT
             value_map:
               "":
-                title: Project (or Workspace) path
-                env_key: BITRISE_PROJECT_PATH
+                title: Android SDK components
+                env_key: ANDROID_SDK_COMPONENTS
+                type: info
                 value_map:
-                  project/ios/SampleAppsReactNativeAndroid.xcodeproj:
-                    title: Scheme name
-                    env_key: BITRISE_SCHEME
+                  platforms;android-23 build-tools;23.0.1:
+                    title: Project (or Workspace) path
+                    env_key: BITRISE_PROJECT_PATH
                     value_map:
-                      SampleAppsReactNativeAndroid:
-                        title: ipa export method
-                        env_key: BITRISE_EXPORT_METHOD
-                        value_map:
-                          ad-hoc:
-                            config: react-native-android-ios-test-config
-                          app-store:
-                            config: react-native-android-ios-test-config
-                          development:
-                            config: react-native-android-ios-test-config
-                          enterprise:
-                            config: react-native-android-ios-test-config
-                      SampleAppsReactNativeAndroid-tvOS:
-                        title: ipa export method
-                        env_key: BITRISE_EXPORT_METHOD
+                      project/ios/SampleAppsReactNativeAndroid.xcodeproj:
+                        title: Scheme name
+                        env_key: BITRISE_SCHEME
                         value_map:
-                          ad-hoc:
-                            config: react-native-android-ios-test-config
-                          app-store:
-                            config: react-native-android-ios-test-config
-                          development:
-                            config: react-native-android-ios-test-config
-                          enterprise:
-                            config: react-native-android-ios-test-config
+                          SampleAppsReactNativeAndroid:
+                            title: ipa export method
+                            env_key: BITRISE_EXPORT_METHOD
+                            value_map:
+                              ad-hoc:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              app-store:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              development:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              enterprise:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                          SampleAppsReactNativeAndroid-tvOS:
+                            title: ipa export method
+                            env_key: BITRISE_EXPORT_METHOD
+                            value_map:
+                              ad-hoc:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              app-store:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              development:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              enterprise:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
 configs:
   react-native:
     react-native-android-ios-test-config: |
@@ -176,11 +276,15 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
+summary:
+  general:
+  - 'Primary languages: JavaScript (4 files), Objective-C (4 files), Java (2 files)'
+  react-native:
+  - 24 options, 39 branches, 1 configs
 `, sampleAppsReactNativeSubdirVersions...)
 
 var sampleAppsReactNativeIosAndAndroidVersions = []interface{}{
 	models.FormatVersion,
-
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
 	steps.ScriptVersion,
@@ -213,37 +317,138 @@ var sampleAppsReactNativeIosAndAndroidResultYML = fmt.Sprintf(`options:
             env_key: VARIANT
             value_map:
               "":
-                title: Project (or Workspace) path
-                env_key: BITRISE_PROJECT_PATH
+                title: Android SDK components
+                env_key: ANDROID_SDK_COMPONENTS
+                type: info
                 value_map:
-                  ios/SampleAppsReactNativeAndroid.xcodeproj:
-                    title: Scheme name
-                    env_key: BITRISE_SCHEME
+                  platforms;android-23 build-tools;23.0.1:
+                    title: Project (or Workspace) path
+                    env_key: BITRISE_PROJECT_PATH
                     value_map:
-                      SampleAppsReactNativeAndroid:
-                        title: ipa export method
-                        env_key: BITRISE_EXPORT_METHOD
-                        value_map:
-                          ad-hoc:
-                            config: react-native-android-ios-test-config
-                          app-store:
-                            config: react-native-android-ios-test-config
-                          development:
-                            config: react-native-android-ios-test-config
-                          enterprise:
-                            config: react-native-android-ios-test-config
-                      SampleAppsReactNativeAndroid-tvOS:
-                        title: ipa export method
-                        env_key: BITRISE_EXPORT_METHOD
+                      ios/SampleAppsReactNativeAndroid.xcodeproj:
+                        title: Scheme name
+                        env_key: BITRISE_SCHEME
                         value_map:
-                          ad-hoc:
-                            config: react-native-android-ios-test-config
-                          app-store:
-                            config: react-native-android-ios-test-config
-                          development:
-                            config: react-native-android-ios-test-config
-                          enterprise:
-                            config: react-native-android-ios-test-config
+                          SampleAppsReactNativeAndroid:
+                            title: ipa export method
+                            env_key: BITRISE_EXPORT_METHOD
+                            value_map:
+                              ad-hoc:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              app-store:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              development:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              enterprise:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                          SampleAppsReactNativeAndroid-tvOS:
+                            title: ipa export method
+                            env_key: BITRISE_EXPORT_METHOD
+                            value_map:
+                              ad-hoc:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              app-store:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              development:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
+                              enterprise:
+                                title: Simulator OS version to test against
+                                env_key: BITRISE_SIMULATOR_OS_VERSION
+                                type: user_input_optional
+                                value_map:
+                                  "8.0":
+                                    title: Increment the build number before building
+                                      the app?
+                                    env_key: VERSION_BUMP
+                                    value_map:
+                                      "no":
+                                        config: react-native-android-ios-test-config
+                                      "yes":
+                                        config: react-native-android-ios-test-config
 configs:
   react-native:
     react-native-android-ios-test-config: |
@@ -296,4 +501,9 @@ configs:
           - deploy-to-bitrise-io@%s: {}
 warnings:
   react-native: []
+summary:
+  general:
+  - 'Primary languages: JavaScript (4 files), Objective-C (4 files), Java (2 files)'
+  react-native:
+  - 24 options, 39 branches, 1 configs
 `, sampleAppsReactNativeIosAndAndroidVersions...)
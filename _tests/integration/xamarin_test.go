@@ -74,6 +74,19 @@ var xamarinSampleAppVersions = []interface{}{
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CachePullVersion,
+	steps.ScriptVersion,
+	steps.XamarinUserManagementVersion,
+	steps.NugetRestoreVersion,
+	steps.XamarinComponentsRestoreVersion,
+	steps.XamarinArchiveVersion,
+	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
+
+	models.FormatVersion,
+	steps.ActivateSSHKeyVersion,
+	steps.GitCloneVersion,
+	steps.CachePullVersion,
 	steps.ScriptVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.XamarinUserManagementVersion,
@@ -81,6 +94,7 @@ var xamarinSampleAppVersions = []interface{}{
 	steps.XamarinComponentsRestoreVersion,
 	steps.XamarinArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var xamarinSampleAppResultYML = fmt.Sprintf(`options:
@@ -89,32 +103,52 @@ var xamarinSampleAppResultYML = fmt.Sprintf(`options:
     env_key: BITRISE_PROJECT_PATH
     value_map:
       XamarinSampleApp.sln:
-        title: Xamarin solution configuration
-        env_key: BITRISE_XAMARIN_CONFIGURATION
+        title: Xamarin project type
+        env_key: BITRISE_XAMARIN_PROJECT_TYPE
         value_map:
-          Debug:
-            title: Xamarin solution platform
-            env_key: BITRISE_XAMARIN_PLATFORM
+          android:
+            title: Xamarin solution configuration
+            env_key: BITRISE_XAMARIN_CONFIGURATION
             value_map:
-              Any CPU:
-                config: xamarin-nuget-components-config
-              iPhone:
-                config: xamarin-nuget-components-config
-              iPhoneSimulator:
-                config: xamarin-nuget-components-config
-          Release:
-            title: Xamarin solution platform
-            env_key: BITRISE_XAMARIN_PLATFORM
+              Debug:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-android-nuget-components-config
+              Release:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-android-nuget-components-config
+          ios:
+            title: Xamarin solution configuration
+            env_key: BITRISE_XAMARIN_CONFIGURATION
             value_map:
-              Any CPU:
-                config: xamarin-nuget-components-config
-              iPhone:
-                config: xamarin-nuget-components-config
-              iPhoneSimulator:
-                config: xamarin-nuget-components-config
+              Debug:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-ios-nuget-components-config
+                  iPhone:
+                    config: xamarin-ios-nuget-components-config
+                  iPhoneSimulator:
+                    config: xamarin-ios-nuget-components-config
+              Release:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-ios-nuget-components-config
+                  iPhone:
+                    config: xamarin-ios-nuget-components-config
+                  iPhoneSimulator:
+                    config: xamarin-ios-nuget-components-config
 configs:
   xamarin:
-    xamarin-nuget-components-config: |
+    xamarin-android-nuget-components-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: xamarin
@@ -129,11 +163,48 @@ configs:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
+          - cache-pull@%s: {}
+          - script@%s:
+              title: Do anything with Script step
+          - xamarin-user-management@%s:
+              run_if: .IsCI
+              inputs:
+              - xamarin_android_license: "yes"
+          - nuget-restore@%s: {}
+          - xamarin-components-restore@%s: {}
+          - xamarin-archive@%s:
+              inputs:
+              - xamarin_solution: $BITRISE_PROJECT_PATH
+              - xamarin_configuration: $BITRISE_XAMARIN_CONFIGURATION
+              - xamarin_platform: $BITRISE_XAMARIN_PLATFORM
+              - project_type_whitelist: $BITRISE_XAMARIN_PROJECT_TYPE
+          - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s:
+              inputs:
+              - cache_paths: packages -> Droid/packages.config
+    xamarin-ios-nuget-components-config: |
+      format_version: "%s"
+      default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+      project_type: xamarin
+      trigger_map:
+      - push_branch: '*'
+        workflow: primary
+      - pull_request_source_branch: '*'
+        workflow: primary
+      workflows:
+        primary:
+          steps:
+          - activate-ssh-key@%s:
+              run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
+          - git-clone@%s: {}
+          - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
           - certificate-and-profile-installer@%s: {}
           - xamarin-user-management@%s:
               run_if: .IsCI
+              inputs:
+              - xamarin_ios_license: "yes"
           - nuget-restore@%s: {}
           - xamarin-components-restore@%s: {}
           - xamarin-archive@%s:
@@ -141,20 +212,41 @@ configs:
               - xamarin_solution: $BITRISE_PROJECT_PATH
               - xamarin_configuration: $BITRISE_XAMARIN_CONFIGURATION
               - xamarin_platform: $BITRISE_XAMARIN_PLATFORM
+              - project_type_whitelist: $BITRISE_XAMARIN_PROJECT_TYPE
           - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s:
+              inputs:
+              - cache_paths: packages -> Droid/packages.config
 warnings:
-  xamarin: []
+  xamarin:
+  - The Debug|iPhoneSimulator build of iOS/XamarinSampleApp.iOS.csproj targets only
+    I386, the simulator build needs Rosetta on Apple Silicon stacks, add ARM64 to
+    its MtouchArch to build natively.
+  - The Release|iPhoneSimulator build of iOS/XamarinSampleApp.iOS.csproj targets only
+    I386, the simulator build needs Rosetta on Apple Silicon stacks, add ARM64 to
+    its MtouchArch to build natively.
+summary:
+  general:
+  - 'Primary languages: C# (3 files)'
+  xamarin:
+  - 'NuGet: packages.config restore (Droid/packages.config, iOS/packages.config),
+    the packages are restored by the nuget-restore step'
+  - 'iOS/XamarinSampleApp.iOS.csproj: Debug|iPhoneSimulator architectures: I386'
+  - 'iOS/XamarinSampleApp.iOS.csproj: Release|iPhoneSimulator architectures: I386'
+  - 8 options, 15 branches, 2 configs
 `, xamarinSampleAppVersions...)
 
 var sampleAppsXamarinIosVersions = []interface{}{
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CachePullVersion,
 	steps.ScriptVersion,
 	steps.CertificateAndProfileInstallerVersion,
 	steps.NugetRestoreVersion,
 	steps.XamarinArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var sampleAppsXamarinIosResultYML = fmt.Sprintf(`options:
@@ -163,32 +255,36 @@ var sampleAppsXamarinIosResultYML = fmt.Sprintf(`options:
     env_key: BITRISE_PROJECT_PATH
     value_map:
       CreditCardValidator.iOS.sln:
-        title: Xamarin solution configuration
-        env_key: BITRISE_XAMARIN_CONFIGURATION
+        title: Xamarin project type
+        env_key: BITRISE_XAMARIN_PROJECT_TYPE
         value_map:
-          Debug:
-            title: Xamarin solution platform
-            env_key: BITRISE_XAMARIN_PLATFORM
-            value_map:
-              Any CPU:
-                config: xamarin-nuget-config
-              iPhone:
-                config: xamarin-nuget-config
-              iPhoneSimulator:
-                config: xamarin-nuget-config
-          Release:
-            title: Xamarin solution platform
-            env_key: BITRISE_XAMARIN_PLATFORM
+          ios:
+            title: Xamarin solution configuration
+            env_key: BITRISE_XAMARIN_CONFIGURATION
             value_map:
-              Any CPU:
-                config: xamarin-nuget-config
-              iPhone:
-                config: xamarin-nuget-config
-              iPhoneSimulator:
-                config: xamarin-nuget-config
+              Debug:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-ios-nuget-config
+                  iPhone:
+                    config: xamarin-ios-nuget-config
+                  iPhoneSimulator:
+                    config: xamarin-ios-nuget-config
+              Release:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-ios-nuget-config
+                  iPhone:
+                    config: xamarin-ios-nuget-config
+                  iPhoneSimulator:
+                    config: xamarin-ios-nuget-config
 configs:
   xamarin:
-    xamarin-nuget-config: |
+    xamarin-ios-nuget-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: xamarin
@@ -203,6 +299,7 @@ configs:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
+          - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
           - certificate-and-profile-installer@%s: {}
@@ -212,20 +309,42 @@ configs:
               - xamarin_solution: $BITRISE_PROJECT_PATH
               - xamarin_configuration: $BITRISE_XAMARIN_CONFIGURATION
               - xamarin_platform: $BITRISE_XAMARIN_PLATFORM
+              - project_type_whitelist: $BITRISE_XAMARIN_PROJECT_TYPE
           - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s:
+              inputs:
+              - cache_paths: packages -> CreditCardValidator.iOS.UITests/packages.config
 warnings:
-  xamarin: []
+  xamarin:
+  - The Debug|iPhoneSimulator build of CreditCardValidator.iOS/CreditCardValidator.iOS.csproj
+    targets only I386, the simulator build needs Rosetta on Apple Silicon stacks,
+    add ARM64 to its MtouchArch to build natively.
+  - The Release|iPhoneSimulator build of CreditCardValidator.iOS/CreditCardValidator.iOS.csproj
+    targets only I386, the simulator build needs Rosetta on Apple Silicon stacks,
+    add ARM64 to its MtouchArch to build natively.
+summary:
+  general:
+  - 'Primary languages: C# (2 files)'
+  xamarin:
+  - 'NuGet: packages.config restore (CreditCardValidator.iOS.UITests/packages.config),
+    the packages are restored by the nuget-restore step'
+  - 'CreditCardValidator.iOS/CreditCardValidator.iOS.csproj: Debug|iPhoneSimulator
+    architectures: I386'
+  - 'CreditCardValidator.iOS/CreditCardValidator.iOS.csproj: Release|iPhoneSimulator
+    architectures: I386'
+  - 5 options, 10 branches, 1 configs
 `, sampleAppsXamarinIosVersions...)
 
 var sampleAppsXamarinAndroidVersions = []interface{}{
 	models.FormatVersion,
 	steps.ActivateSSHKeyVersion,
 	steps.GitCloneVersion,
+	steps.CachePullVersion,
 	steps.ScriptVersion,
-	steps.CertificateAndProfileInstallerVersion,
 	steps.NugetRestoreVersion,
 	steps.XamarinArchiveVersion,
 	steps.DeployToBitriseIoVersion,
+	steps.CachePushVersion,
 }
 
 var sampleAppsXamarinAndroidResultYML = fmt.Sprintf(`options:
@@ -234,24 +353,28 @@ var sampleAppsXamarinAndroidResultYML = fmt.Sprintf(`options:
     env_key: BITRISE_PROJECT_PATH
     value_map:
       CreditCardValidator.Droid.sln:
-        title: Xamarin solution configuration
-        env_key: BITRISE_XAMARIN_CONFIGURATION
+        title: Xamarin project type
+        env_key: BITRISE_XAMARIN_PROJECT_TYPE
         value_map:
-          Debug:
-            title: Xamarin solution platform
-            env_key: BITRISE_XAMARIN_PLATFORM
-            value_map:
-              Any CPU:
-                config: xamarin-nuget-config
-          Release:
-            title: Xamarin solution platform
-            env_key: BITRISE_XAMARIN_PLATFORM
+          android:
+            title: Xamarin solution configuration
+            env_key: BITRISE_XAMARIN_CONFIGURATION
             value_map:
-              Any CPU:
-                config: xamarin-nuget-config
+              Debug:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-android-nuget-config
+              Release:
+                title: Xamarin solution platform
+                env_key: BITRISE_XAMARIN_PLATFORM
+                value_map:
+                  Any CPU:
+                    config: xamarin-android-nuget-config
 configs:
   xamarin:
-    xamarin-nuget-config: |
+    xamarin-android-nuget-config: |
       format_version: "%s"
       default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
       project_type: xamarin
@@ -266,16 +389,27 @@ configs:
           - activate-ssh-key@%s:
               run_if: '{{getenv "SSH_RSA_PRIVATE_KEY" | ne ""}}'
           - git-clone@%s: {}
+          - cache-pull@%s: {}
           - script@%s:
               title: Do anything with Script step
-          - certificate-and-profile-installer@%s: {}
           - nuget-restore@%s: {}
           - xamarin-archive@%s:
               inputs:
               - xamarin_solution: $BITRISE_PROJECT_PATH
               - xamarin_configuration: $BITRISE_XAMARIN_CONFIGURATION
               - xamarin_platform: $BITRISE_XAMARIN_PLATFORM
+              - project_type_whitelist: $BITRISE_XAMARIN_PROJECT_TYPE
           - deploy-to-bitrise-io@%s: {}
+          - cache-push@%s:
+              inputs:
+              - cache_paths: packages -> CreditCardValidator.Droid.UITests/packages.config
 warnings:
   xamarin: []
+summary:
+  general:
+  - 'Primary languages: C# (2 files)'
+  xamarin:
+  - 'NuGet: packages.config restore (CreditCardValidator.Droid.UITests/packages.config),
+    the packages are restored by the nuget-restore step'
+  - 5 options, 6 branches, 1 configs
 `, sampleAppsXamarinAndroidVersions...)
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// extractArchive extracts the given zip or gzipped tar archive into a new temporary directory,
+// returns the temporary directory and the project root inside it (the single top-level directory wrapping
+// the archive's entries, if any). The caller is responsible for removing the temporary directory.
+func extractArchive(archivePth string) (string, string, error) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__bitrise-init-archive__")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create temp dir, error: %s", err)
+	}
+
+	removeTmpDir := func() {
+		if removeErr := os.RemoveAll(tmpDir); removeErr != nil {
+			log.TWarnf("Failed to remove (%s), error: %s", tmpDir, removeErr)
+		}
+	}
+
+	if err := utility.ExtractArchive(archivePth, tmpDir); err != nil {
+		removeTmpDir()
+		return "", "", err
+	}
+
+	rootDir, err := utility.ArchiveRoot(tmpDir)
+	if err != nil {
+		removeTmpDir()
+		return "", "", err
+	}
+
+	return tmpDir, rootDir, nil
+}
+
+// removeArchive removes the temporary directory created by extractArchive.
+func removeArchive(tmpDir string) {
+	if err := os.RemoveAll(tmpDir); err != nil {
+		log.TWarnf("Failed to remove (%s), error: %s", tmpDir, err)
+	}
+}
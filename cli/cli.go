@@ -6,6 +6,7 @@ import (
 	"path"
 
 	log "github.com/Sirupsen/logrus"
+	bitriseLog "github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/version"
 	"github.com/urfave/cli"
 )
@@ -27,10 +28,16 @@ func Run() {
 
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
-			Name:   "loglevel, l",
+			Name:   "loglevel, log-level, l",
 			Usage:  "Log level (options: debug, info, warn, error, fatal, panic).",
 			EnvVar: "LOGLEVEL",
 		},
+		cli.StringFlag{
+			Name:   "log-format",
+			Usage:  "Log format (options: text, json).",
+			EnvVar: "LOG_FORMAT",
+			Value:  "text",
+		},
 		cli.BoolFlag{
 			Name:   "ci",
 			Usage:  "If true it indicates that we're used by another tool so don't require any user input!",
@@ -39,25 +46,7 @@ func Run() {
 	}
 
 	app.Before = func(c *cli.Context) error {
-		log.SetFormatter(&log.TextFormatter{
-			FullTimestamp:   true,
-			ForceColors:     true,
-			TimestampFormat: "15:04:05",
-		})
-
-		// Log level
-		logLevelStr := c.String("loglevel")
-		if logLevelStr == "" {
-			logLevelStr = "info"
-		}
-
-		level, err := log.ParseLevel(logLevelStr)
-		if err != nil {
-			return err
-		}
-		log.SetLevel(level)
-
-		return nil
+		return setupLogging(c.GlobalString("loglevel"), c.GlobalString("log-format"))
 	}
 
 	app.Commands = []cli.Command{
@@ -70,3 +59,35 @@ func Run() {
 		log.Fatal(err)
 	}
 }
+
+func setupLogging(levelStr, formatStr string) error {
+	// Log format
+	switch formatStr {
+	case "", "text":
+		log.SetFormatter(&log.TextFormatter{
+			FullTimestamp:   true,
+			ForceColors:     true,
+			TimestampFormat: "15:04:05",
+		})
+	case "json":
+		log.SetFormatter(&log.JSONFormatter{})
+	default:
+		return fmt.Errorf("invalid log format (%s), options: [text, json]", formatStr)
+	}
+	bitriseLog.SetEnableJSONFormat(formatStr == "json")
+
+	// Log level
+	if levelStr == "" {
+		levelStr = "info"
+	}
+
+	level, err := log.ParseLevel(levelStr)
+	if err != nil {
+		return err
+	}
+	log.SetLevel(level)
+
+	bitriseLog.SetLevel(level)
+
+	return nil
+}
@@ -6,12 +6,13 @@ import (
 	"path"
 	"path/filepath"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/output"
 	"github.com/bitrise-core/bitrise-init/scanner"
+	"github.com/bitrise-core/bitrise-init/utility"
 	"github.com/bitrise-io/go-utils/colorstring"
 	"github.com/bitrise-io/go-utils/command"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/urfave/cli"
 )
@@ -36,6 +37,18 @@ var configCommand = cli.Command{
 			Usage: "Directory to scan.",
 			Value: "./",
 		},
+		cli.StringFlag{
+			Name:  "git",
+			Usage: "Public git repository url to scan instead of the dir. The repository is shallow cloned into a temporary directory, which is removed after the scan.",
+		},
+		cli.StringFlag{
+			Name:  "archive",
+			Usage: "Project archive (.zip, .tar.gz or .tgz) to scan instead of the dir. The archive is extracted into a temporary directory, which is removed after the scan.",
+		},
+		cli.StringFlag{
+			Name:  "branch",
+			Usage: "Branch of the git repository to scan, the remote's default branch is used if not set.",
+		},
 		cli.StringFlag{
 			Name:  "output-dir",
 			Usage: "Directory to save scan results.",
@@ -46,6 +59,46 @@ var configCommand = cli.Command{
 			Usage: "Output format, options [json, yaml].",
 			Value: "yaml",
 		},
+		cli.BoolFlag{
+			Name:  "namespace-workflows",
+			Usage: "Prefix the generated workflow IDs with the scanner's namespace (like: ios-primary), so the configs of multiple scanners can be merged without collisions.",
+		},
+		cli.StringFlag{
+			Name:  "default-branch",
+			Usage: "Branch whose pushes trigger the deploy workflow of the written bitrise.yml, detected from the repository (HEAD, origin HEAD, main or master) if not set.",
+		},
+		cli.BoolFlag{
+			Name:  "combined",
+			Usage: "Write one bitrise.yml combining a config of every detected platform, with namespaced workflows. In CI mode the platforms' default configs are combined.",
+		},
+		cli.BoolFlag{
+			Name:  "only-defaults",
+			Usage: "Write the default config of every detected platform to <output-dir>/<platform>/<config>, without asking for the options and without the scan result file.",
+		},
+		cli.IntFlag{
+			Name:  "max-configs",
+			Usage: "Maximum number of configs per scanner, the least likely configs above the limit are collapsed. Unlimited by default (0).",
+		},
+		cli.BoolFlag{
+			Name:  "offline",
+			Usage: "Use only the local files and bundled data for the detection, the network dependent detection is skipped (with a warning).",
+		},
+		cli.StringFlag{
+			Name:  "diff",
+			Usage: "Previous scan result file (json or yaml) to compare the new scan result with, the added, removed and changed options and configs are printed.",
+		},
+		cli.BoolFlag{
+			Name:  "fail-on-diff",
+			Usage: "Exit with an error if the new scan result differs from the previous one (set by diff), after the outputs are saved.",
+		},
+		cli.BoolFlag{
+			Name:  "config-only",
+			Usage: "Write the scan result without the option trees, only with the generated configs (and the warnings, errors and summaries). Allowed in CI mode.",
+		},
+		cli.BoolFlag{
+			Name:  "copy-icons",
+			Usage: "Copy the detected app icons into the output dir (icons/), with a manifest (icons/manifest.json) describing them.",
+		},
 	},
 }
 
@@ -54,20 +107,72 @@ func writeScanResult(scanResult models.ScanResultModel, outputDir string, format
 	return output.WriteToFile(scanResult, format, pth)
 }
 
-func initConfig(c *cli.Context) error {
+// writeCombinedConfig combines the selected configs into the output dir's bitrise.yml, with the default branch trigger, the combine warnings (like env key conflicts) are logged.
+func writeCombinedConfig(selectedConfigs []scanner.SelectedConfig, defaultBranch, outputDir string, format output.Format) (string, error) {
+	config, warnings, err := scanner.CombineConfigs(selectedConfigs)
+	if err != nil {
+		return "", fmt.Errorf("Failed to combine configs, error: %s", err)
+	}
+	for _, warning := range warnings {
+		log.TWarnf(warning)
+	}
+	models.AddDefaultBranchTrigger(&config, defaultBranch)
+
+	outputPth, err := output.WriteToFile(config, format, path.Join(outputDir, "bitrise.yml"))
+	if err != nil {
+		return "", fmt.Errorf("Failed to print result, error: %s", err)
+	}
+	return outputPth, nil
+}
+
+func initConfig(c *cli.Context) (err error) {
 	// Config
 	isCI := c.GlobalBool("ci")
 	searchDir := c.String("dir")
 	outputDir := c.String("output-dir")
 	formatStr := c.String("format")
+	gitURL := c.String("git")
+	branch := c.String("branch")
+	archivePth := c.String("archive")
+	isCopyIcons := c.Bool("copy-icons")
+	isNamespaceWorkflows := c.Bool("namespace-workflows")
+	isCombined := c.Bool("combined")
+	defaultBranch := c.String("default-branch")
+	isOnlyDefaults := c.Bool("only-defaults")
+	maxConfigs := c.Int("max-configs")
+	isOffline := c.Bool("offline")
+	diffPth := c.String("diff")
+	isFailOnDiff := c.Bool("fail-on-diff")
+	isConfigOnly := c.Bool("config-only")
 
 	if isCI {
 		log.TInfof(colorstring.Yellow("CI mode"))
 	}
-	log.TInfof(colorstring.Yellowf("scan dir: %s", searchDir))
+	if isOffline {
+		log.TInfof(colorstring.Yellow("offline mode"))
+	}
+	if isCombined {
+		log.TInfof(colorstring.Yellow("combined mode"))
+	}
+	if isOnlyDefaults {
+		log.TInfof(colorstring.Yellow("only defaults mode"))
+	}
+	if isConfigOnly {
+		log.TInfof(colorstring.Yellow("config only mode"))
+	}
+	if gitURL != "" {
+		log.TInfof(colorstring.Yellowf("git repository: %s", gitURL))
+		if branch != "" {
+			log.TInfof(colorstring.Yellowf("branch: %s", branch))
+		}
+	} else if archivePth != "" {
+		log.TInfof(colorstring.Yellowf("archive: %s", archivePth))
+	} else {
+		log.TInfof(colorstring.Yellowf("scan dir: %s", searchDir))
+	}
 	log.TInfof(colorstring.Yellowf("output dir: %s", outputDir))
 	log.TInfof(colorstring.Yellowf("output format: %s", formatStr))
-	fmt.Println()
+	log.Println()
 
 	currentDir, err := pathutil.AbsPath("./")
 	if err != nil {
@@ -107,9 +212,116 @@ func initConfig(c *cli.Context) error {
 	if format != output.JSONFormat && format != output.YAMLFormat {
 		return fmt.Errorf("Not allowed output format (%s), options: [%s, %s]", format.String(), output.YAMLFormat.String(), output.JSONFormat.String())
 	}
+
+	if gitURL == "" && branch != "" {
+		return fmt.Errorf("Branch (%s) specified without git repository", branch)
+	}
+	if gitURL != "" && c.IsSet("dir") {
+		return fmt.Errorf("Both dir and git repository specified, only one of them is allowed")
+	}
+	if archivePth != "" && (gitURL != "" || c.IsSet("dir")) {
+		return fmt.Errorf("Archive (%s) specified with dir or git repository, only one of them is allowed", archivePth)
+	}
+	if archivePth != "" {
+		absArchivePth, err := pathutil.AbsPath(archivePth)
+		if err != nil {
+			return fmt.Errorf("Failed to expand path (%s), error: %s", archivePth, err)
+		}
+		archivePth = absArchivePth
+		if exist, err := pathutil.IsPathExists(archivePth); err != nil {
+			return err
+		} else if !exist {
+			return fmt.Errorf("Archive (%s) does not exist", archivePth)
+		}
+		if !utility.IsSupportedArchive(archivePth) {
+			return fmt.Errorf("Not supported archive (%s), options: [.zip, .tar.gz, .tgz]", archivePth)
+		}
+	}
+	if gitURL != "" && isOffline {
+		return fmt.Errorf("Git repository (%s) can not be cloned in offline mode", gitURL)
+	}
+	if isOnlyDefaults && isCombined {
+		return fmt.Errorf("Only one of only-defaults and combined modes is allowed")
+	}
+	if isConfigOnly && !isCI {
+		return fmt.Errorf("Config only mode is allowed only in CI mode")
+	}
+	if isConfigOnly && isOnlyDefaults {
+		return fmt.Errorf("Only one of only-defaults and config-only modes is allowed")
+	}
+	if maxConfigs < 0 {
+		return fmt.Errorf("Invalid max configs (%d), should be 0 (unlimited) or greater", maxConfigs)
+	}
+	if isFailOnDiff && diffPth == "" {
+		return fmt.Errorf("Fail on diff specified without the previous scan result (diff)")
+	}
+
+	var previousScanResult models.ScanResultModel
+	if diffPth != "" {
+		previousScanResult, err = readScanResult(diffPth)
+		if err != nil {
+			return fmt.Errorf("Failed to read the previous scan result, error: %s", err)
+		}
+	}
 	// ---
 
-	scanResult := scanner.Config(searchDir)
+	if gitURL != "" {
+		log.TInfof("Cloning repository:")
+
+		cloneDir, err := cloneRepository(gitURL, branch)
+		if err != nil {
+			return fmt.Errorf("Failed to clone repository (%s), error: %s", gitURL, err)
+		}
+		defer removeClone(cloneDir)
+
+		searchDir = cloneDir
+		log.Println()
+	}
+
+	if archivePth != "" {
+		log.TInfof("Extracting archive:")
+
+		tmpDir, rootDir, err := extractArchive(archivePth)
+		if err != nil {
+			return fmt.Errorf("Failed to extract archive (%s), error: %s", archivePth, err)
+		}
+		defer removeArchive(tmpDir)
+
+		log.TPrintf("project root: %s", rootDir)
+		searchDir = rootDir
+		log.Println()
+	}
+
+	scanResult, err := scanner.Scan(searchDir, scanner.ScanOptions{Offline: isOffline})
+	if err != nil {
+		scanResult.AddError("general", err.Error())
+	}
+	scanner.LimitConfigs(&scanResult, maxConfigs)
+
+	if isNamespaceWorkflows || isCombined {
+		if err := scanner.NamespaceWorkflows(&scanResult); err != nil {
+			return fmt.Errorf("Failed to namespace workflows, error: %s", err)
+		}
+	}
+
+	if diffPth != "" {
+		if changes := printScanResultDiff(diffPth, previousScanResult, scanResult); changes > 0 && isFailOnDiff {
+			// the outputs are saved before failing
+			defer func() {
+				if err == nil {
+					err = fmt.Errorf("Scan result changed since the previous scan result (%s), %d changes", diffPth, changes)
+				}
+			}()
+		}
+	}
+
+	if isCopyIcons && len(scanResult.ScannerToIcons) > 0 {
+		if manifestPth, err := copyIcons(scanResult.ScannerToIcons, searchDir, outputDir); err != nil {
+			log.TWarnf("Failed to copy icons, error: %s", err)
+		} else {
+			log.TPrintf("icons manifest: %s", manifestPth)
+		}
+	}
 
 	platforms := []string{}
 	for platform := range scanResult.ScannerToOptionRoot {
@@ -122,7 +334,7 @@ func initConfig(c *cli.Context) error {
 		if err != nil || out == "" {
 			log.TErrorf("tree not installed, can not list files")
 		} else {
-			fmt.Println()
+			log.Println()
 			cmd := command.NewWithStandardOuts("tree", ".", "-L", "3")
 			log.TPrintf("$ %s", cmd.PrintableCommandArgs())
 			if err := cmd.Run(); err != nil {
@@ -142,16 +354,64 @@ func initConfig(c *cli.Context) error {
 		return fmt.Errorf("No known platform detected")
 	}
 
+	if defaultBranch == "" && (!isCI || isCombined) && !isOnlyDefaults {
+		var warnings models.Warnings
+		defaultBranch, warnings = utility.DefaultGitBranch(searchDir)
+		for _, warning := range warnings {
+			log.TWarnf(warning)
+		}
+		log.TPrintf("default branch: %s", defaultBranch)
+	}
+
+	if isOnlyDefaults {
+		log.TInfof("Saving default configs:")
+
+		platformToPth, warnings, err := scanner.WriteDefaultConfigs(scanResult, outputDir, format)
+		for _, warning := range warnings {
+			log.TWarnf(warning)
+		}
+		for _, platform := range platforms {
+			if pth, ok := platformToPth[platform]; ok {
+				log.TPrintf("  %s: %s", platform, pth)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to write default configs, error: %s", err)
+		}
+		return nil
+	}
+
 	// Write output to files
 	if isCI {
 		log.TInfof("Saving outputs:")
 
-		outputPth, err := writeScanResult(scanResult, outputDir, format)
+		result := scanResult
+		if isConfigOnly {
+			result = scanResult.ConfigsOnly()
+		}
+
+		outputPth, err := writeScanResult(result, outputDir, format)
 		if err != nil {
 			return fmt.Errorf("Failed to write output, error: %s", err)
 		}
 
 		log.TPrintf("  scan result: %s", outputPth)
+
+		if isCombined {
+			selectedConfigs, warnings, err := scanner.DefaultPlatformConfigs(scanResult)
+			if err != nil {
+				return fmt.Errorf("Failed to get the default configs, error: %s", err)
+			}
+			for _, warning := range warnings {
+				log.TWarnf(warning)
+			}
+
+			outputPth, err := writeCombinedConfig(selectedConfigs, defaultBranch, outputDir, format)
+			if err != nil {
+				return err
+			}
+			log.TPrintf("  bitrise.yml template: %s", outputPth)
+		}
 		return nil
 	}
 	// ---
@@ -159,10 +419,26 @@ func initConfig(c *cli.Context) error {
 	// Select option
 	log.TInfof("Collecting inputs:")
 
+	if isCombined {
+		selectedConfigs, err := scanner.AskForPlatformConfigs(scanResult)
+		if err != nil {
+			return err
+		}
+
+		outputPth, err := writeCombinedConfig(selectedConfigs, defaultBranch, outputDir, format)
+		if err != nil {
+			return err
+		}
+		log.TInfof("  bitrise.yml template: %s", outputPth)
+		log.Println()
+		return nil
+	}
+
 	config, err := scanner.AskForConfig(scanResult)
 	if err != nil {
 		return err
 	}
+	models.AddDefaultBranchTrigger(&config, defaultBranch)
 
 	if exist, err := pathutil.IsDirExists(outputDir); err != nil {
 		return err
@@ -178,7 +454,7 @@ func initConfig(c *cli.Context) error {
 		return fmt.Errorf("Failed to print result, error: %s", err)
 	}
 	log.TInfof("  bitrise.yml template: %s", outputPth)
-	fmt.Println()
+	log.Println()
 	// ---
 
 	return nil
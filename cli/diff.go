@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// readScanResult reads a scan result file written by the config command, in json (.json extension) or yaml format.
+func readScanResult(pth string) (models.ScanResultModel, error) {
+	bytes, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return models.ScanResultModel{}, err
+	}
+
+	var result models.ScanResultModel
+	if filepath.Ext(pth) == ".json" {
+		err = json.Unmarshal(bytes, &result)
+	} else {
+		err = yaml.Unmarshal(bytes, &result)
+	}
+	if err != nil {
+		return models.ScanResultModel{}, fmt.Errorf("Failed to parse scan result (%s), error: %s", pth, err)
+	}
+	return result, nil
+}
+
+// printScanResultDiff logs the differences of the previous and the new scan results, returns the number of differences.
+func printScanResultDiff(previousPth string, previous, next models.ScanResultModel) int {
+	entries := models.DiffScanResults(previous, next)
+
+	log.TInfof("Changes since the previous scan result (%s):", previousPth)
+	if len(entries) == 0 {
+		log.TPrintf("  no changes")
+	}
+	for _, entry := range entries {
+		log.TPrintf("  %s", entry)
+	}
+	log.Println()
+
+	return len(entries)
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// cloneRepository shallow clones the given repository into a new temporary directory,
+// returns the clone's path. The caller is responsible for removing the directory.
+// Only public repositories are supported, git never prompts for credentials.
+func cloneRepository(url, branch string) (string, error) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__bitrise-init-git__")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp dir, error: %s", err)
+	}
+	cloneDir := filepath.Join(tmpDir, "repository")
+
+	args := []string{"clone", "--depth", "1", "--single-branch"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	// the url is passed after --, so it is not parsed as an option even if it starts with -
+	args = append(args, "--", url, cloneDir)
+
+	cmd := command.New("git", args...).AppendEnvs("GIT_TERMINAL_PROMPT=0")
+	log.TPrintf("$ %s", cmd.PrintableCommandArgs())
+
+	if out, err := cmd.RunAndReturnTrimmedCombinedOutput(); err != nil {
+		if removeErr := os.RemoveAll(tmpDir); removeErr != nil {
+			log.TWarnf("Failed to remove (%s), error: %s", tmpDir, removeErr)
+		}
+		return "", fmt.Errorf("git clone failed, output: %s, error: %s", out, err)
+	}
+
+	return cloneDir, nil
+}
+
+// removeClone removes the temporary directory created by cloneRepository.
+func removeClone(cloneDir string) {
+	tmpDir := filepath.Dir(cloneDir)
+	if err := os.RemoveAll(tmpDir); err != nil {
+		log.TWarnf("Failed to remove (%s), error: %s", tmpDir, err)
+	}
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/output"
+	"github.com/bitrise-io/go-utils/fileutil"
+)
+
+const (
+	iconsDirName         = "icons"
+	iconsManifestName    = "manifest"
+	iconsManifestVersion = 1
+)
+
+// iconsManifest describes the icons copied into the output dir.
+type iconsManifest struct {
+	Version int                 `json:"version"`
+	Icons   []iconsManifestItem `json:"icons"`
+}
+
+type iconsManifestItem struct {
+	Scanner string `json:"scanner"`
+	// Source is the icon's path, relative to the scanned directory
+	Source string `json:"source"`
+	// Path is the copied icon's path, relative to the icons dir
+	Path   string `json:"path"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// copyIcons copies the detected icons into the icons dir of the output dir (icons/SCANNER/INDEX-NAME.png)
+// and writes a manifest (icons/manifest.json) describing them, returns the manifest's path.
+func copyIcons(scannerToIcons map[string]models.Icons, searchDir, outputDir string) (string, error) {
+	iconsDir := filepath.Join(outputDir, iconsDirName)
+
+	scanners := []string{}
+	for scanner := range scannerToIcons {
+		scanners = append(scanners, scanner)
+	}
+	sort.Strings(scanners)
+
+	manifest := iconsManifest{Version: iconsManifestVersion, Icons: []iconsManifestItem{}}
+	for _, scanner := range scanners {
+		scannerDir := filepath.Join(iconsDir, scanner)
+		if err := os.MkdirAll(scannerDir, 0700); err != nil {
+			return "", fmt.Errorf("failed to create (%s), error: %s", scannerDir, err)
+		}
+
+		for i, icon := range scannerToIcons[scanner] {
+			src := icon.Path
+			if !filepath.IsAbs(src) {
+				src = filepath.Join(searchDir, src)
+			}
+
+			content, err := fileutil.ReadBytesFromFile(src)
+			if err != nil {
+				return "", fmt.Errorf("failed to read icon (%s), error: %s", src, err)
+			}
+
+			name := fmt.Sprintf("%d-%s", i, filepath.Base(src))
+			if err := fileutil.WriteBytesToFile(filepath.Join(scannerDir, name), content); err != nil {
+				return "", fmt.Errorf("failed to copy icon (%s), error: %s", src, err)
+			}
+
+			manifest.Icons = append(manifest.Icons, iconsManifestItem{
+				Scanner: scanner,
+				Source:  icon.Path,
+				Path:    filepath.ToSlash(filepath.Join(scanner, name)),
+				Width:   icon.Width,
+				Height:  icon.Height,
+			})
+		}
+	}
+
+	if err := os.MkdirAll(iconsDir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create (%s), error: %s", iconsDir, err)
+	}
+	return output.WriteToFile(manifest, output.JSONFormat, filepath.Join(iconsDir, iconsManifestName))
+}
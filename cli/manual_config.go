@@ -1,21 +1,28 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/output"
 	"github.com/bitrise-core/bitrise-init/scanner"
+	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/colorstring"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/urfave/cli"
 )
 
 const (
 	defaultOutputDir = "_defaults"
+
+	// used for the scan result file, written in CI mode
+	allScannersName = "all"
+	resultName      = "result"
 )
 
 var manualConfigCommand = cli.Command{
@@ -36,24 +43,108 @@ var manualConfigCommand = cli.Command{
 		},
 		cli.StringFlag{
 			Name:  "format",
-			Usage: "Output format, options [json, yaml].",
+			Usage: "Output format, options [json, yaml, env]. The env format writes the collected app envs in dotenv format instead of the bitrise.yml, it is not allowed in CI mode.",
 			Value: "yaml",
 		},
+		cli.StringFlag{
+			Name:  "output-name-template",
+			Usage: "Output file name template, placeholders: [{scanner}, {config}, {format}]. In CI mode {scanner} is 'all' and {config} is 'result'. Defaults to 'result' in CI mode and 'bitrise' otherwise.",
+		},
+		cli.BoolFlag{
+			Name:  "namespace-workflows",
+			Usage: "Prefix the generated workflow IDs with the scanner's namespace (like: ios-primary), so the configs of multiple scanners can be merged without collisions.",
+		},
+		cli.StringFlag{
+			Name:  "envs-out",
+			Usage: "Path to write the collected app envs to. Written as envman envs yml if the path has .yml or .yaml extension, in dotenv format otherwise.",
+		},
+		cli.StringFlag{
+			Name:  "scanners",
+			Usage: "Comma separated list of the scanners to generate the default configs for (like: ios,android), all scanners if empty. The custom config is requested by: other (or custom).",
+		},
+		cli.BoolFlag{
+			Name:  "no-custom",
+			Usage: "Do not add the custom (other) config to the result.",
+		},
 	},
 }
 
+// parseScannerNames splits the comma separated scanner names, empty items are ignored.
+func parseScannerNames(list string) []string {
+	names := []string{}
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// outputName returns the name of the output file, relative to the output dir.
+func outputName(template, defaultName, scanner, config string, format output.Format) (string, error) {
+	if template == "" {
+		return defaultName, nil
+	}
+	return output.ResolveNameTemplate(template, scanner, config, format)
+}
+
+// writeAppEnvs writes the app envs as envman envs yml if the path has yml extension, in dotenv format otherwise.
+func writeAppEnvs(appEnvs []envmanModels.EnvironmentItemModel, pth string) (string, error) {
+	absPth, err := pathutil.AbsPath(pth)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand path (%s), error: %s", pth, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(absPth), 0700); err != nil {
+		return "", fmt.Errorf("failed to create (%s), error: %s", filepath.Dir(absPth), err)
+	}
+
+	switch strings.ToLower(filepath.Ext(absPth)) {
+	case ".yml", ".yaml":
+		return output.WriteToFile(envmanModels.EnvsSerializeModel{Envs: appEnvs}, output.YAMLFormat, absPth)
+	default:
+		return output.WriteToFile(appEnvs, output.EnvFormat, absPth)
+	}
+}
+
 func initManualConfig(c *cli.Context) error {
 	// Config
 	isCI := c.GlobalBool("ci")
 	outputDir := c.String("output-dir")
 	formatStr := c.String("format")
+	nameTemplate := c.String("output-name-template")
+	envsOut := c.String("envs-out")
+	isNamespaceWorkflows := c.Bool("namespace-workflows")
+	scannerNames := parseScannerNames(c.String("scanners"))
+	isNoCustom := c.Bool("no-custom")
 
 	if isCI {
 		log.TInfof(colorstring.Yellow("CI mode"))
 	}
 	log.TInfof(colorstring.Yellowf("output dir: %s", outputDir))
 	log.TInfof(colorstring.Yellowf("output format: %s", formatStr))
-	fmt.Println()
+	if nameTemplate != "" {
+		log.TInfof(colorstring.Yellowf("output name template: %s", nameTemplate))
+	}
+	if envsOut != "" {
+		log.TInfof(colorstring.Yellowf("envs output: %s", envsOut))
+	}
+	if len(scannerNames) > 0 {
+		log.TInfof(colorstring.Yellowf("scanners: %s", strings.Join(scannerNames, ", ")))
+	}
+	if isNoCustom {
+		log.TInfof(colorstring.Yellow("custom config excluded"))
+	}
+	log.Println()
+
+	if isCI && envsOut != "" {
+		return fmt.Errorf("Collecting app envs is not possible in CI mode, do not set envs-out")
+	}
+
+	if nameTemplate != "" {
+		if err := output.ValidateNameTemplate(nameTemplate); err != nil {
+			return fmt.Errorf("Invalid output name template, error: %s", err)
+		}
+	}
 
 	currentDir, err := pathutil.AbsPath("./")
 	if err != nil {
@@ -67,12 +158,8 @@ func initManualConfig(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("Failed to expand path (%s), error: %s", outputDir, err)
 	}
-	if exist, err := pathutil.IsDirExists(outputDir); err != nil {
-		return err
-	} else if !exist {
-		if err := os.MkdirAll(outputDir, 0700); err != nil {
-			return fmt.Errorf("Failed to create (%s), error: %s", outputDir, err)
-		}
+	if err := output.EnsureWritableDir(outputDir); err != nil {
+		return fmt.Errorf("Invalid output dir, set a writable directory with --output-dir, error: %s", err)
 	}
 
 	if formatStr == "" {
@@ -82,25 +169,48 @@ func initManualConfig(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("Failed to parse format, err: %s", err)
 	}
-	if format != output.JSONFormat && format != output.YAMLFormat {
-		return fmt.Errorf("Not allowed output format (%v), options: [%s, %s]", format, output.YAMLFormat.String(), output.JSONFormat.String())
+	if format == output.EnvFormat && isCI {
+		return fmt.Errorf("Not allowed output format (%s) in CI mode, options: [%s, %s]", format.String(), output.YAMLFormat.String(), output.JSONFormat.String())
+	}
+	if format != output.JSONFormat && format != output.YAMLFormat && format != output.EnvFormat {
+		return fmt.Errorf("Not allowed output format (%v), options: [%s, %s, %s]", format, output.YAMLFormat.String(), output.JSONFormat.String(), output.EnvFormat.String())
 	}
 	// ---
 
-	scanResult, err := scanner.ManualConfig()
+	scanResult, err := scanner.ManualConfig(scanner.ManualConfigOptions{
+		Scanners: scannerNames,
+		NoCustom: isNoCustom,
+	})
 	if err != nil {
 		return err
 	}
 
+	if isNamespaceWorkflows {
+		if err := scanner.NamespaceWorkflows(&scanResult); err != nil {
+			return fmt.Errorf("Failed to namespace workflows, error: %s", err)
+		}
+	}
+
+	if data, err := json.MarshalIndent(scanResult, "", "\t"); err != nil {
+		log.TWarnf("Failed to marshal scan result, error: %s", err)
+	} else {
+		log.TDebugf("Scan result:\n%s", data)
+	}
+
 	// Write output to files
 	if isCI {
 		log.TInfof(colorstring.Blue("Saving outputs:"))
 
-		if err := os.MkdirAll(outputDir, 0700); err != nil {
-			return fmt.Errorf("Failed to create (%s), error: %s", outputDir, err)
+		name, err := outputName(nameTemplate, resultName, allScannersName, resultName, format)
+		if err != nil {
+			return fmt.Errorf("Failed to resolve output name, error: %s", err)
+		}
+
+		pth := path.Join(outputDir, name)
+		if err := os.MkdirAll(filepath.Dir(pth), 0700); err != nil {
+			return fmt.Errorf("Failed to create (%s), error: %s", filepath.Dir(pth), err)
 		}
 
-		pth := path.Join(outputDir, "result")
 		outputPth, err := output.WriteToFile(scanResult, format, pth)
 		if err != nil {
 			return fmt.Errorf("Failed to print result, error: %s", err)
@@ -114,18 +224,47 @@ func initManualConfig(c *cli.Context) error {
 	// Select option
 	log.TInfof(colorstring.Blue("Collecting inputs:"))
 
-	config, err := scanner.AskForConfig(scanResult)
+	selected, err := scanner.AskForPlatformConfig(scanResult)
 	if err != nil {
 		return err
 	}
 
-	pth := path.Join(outputDir, "bitrise.yml")
-	outputPth, err := output.WriteToFile(config, format, pth)
+	defaultName := "bitrise.yml"
+	if format == output.EnvFormat {
+		defaultName = "app"
+	}
+	name, err := outputName(nameTemplate, defaultName, selected.Platform, selected.ConfigName, format)
 	if err != nil {
-		return fmt.Errorf("Failed to print result, error: %s", err)
+		return fmt.Errorf("Failed to resolve output name, error: %s", err)
+	}
+
+	pth := path.Join(outputDir, name)
+	if err := os.MkdirAll(filepath.Dir(pth), 0700); err != nil {
+		return fmt.Errorf("Failed to create (%s), error: %s", filepath.Dir(pth), err)
+	}
+
+	if format == output.EnvFormat {
+		outputPth, err := output.WriteToFile(selected.AppEnvs, format, pth)
+		if err != nil {
+			return fmt.Errorf("Failed to print app envs, error: %s", err)
+		}
+		log.TInfof("  app envs: %s", colorstring.Blue(outputPth))
+	} else {
+		outputPth, err := output.WriteToFile(selected.Config, format, pth)
+		if err != nil {
+			return fmt.Errorf("Failed to print result, error: %s", err)
+		}
+		log.TInfof("  bitrise.yml template: %s", colorstring.Blue(outputPth))
+	}
+
+	if envsOut != "" {
+		outputPth, err := writeAppEnvs(selected.AppEnvs, envsOut)
+		if err != nil {
+			return fmt.Errorf("Failed to print app envs, error: %s", err)
+		}
+		log.TInfof("  app envs: %s", colorstring.Blue(outputPth))
 	}
-	log.TInfof("  bitrise.yml template: %s", colorstring.Blue(outputPth))
-	fmt.Println()
+	log.Println()
 	// ---
 
 	return nil
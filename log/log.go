@@ -0,0 +1,145 @@
+// Package log prints the output of the tool by the go-utils logger, filtered by the log level selected in the CLI.
+// If the JSON log format is selected, the messages are printed as JSON lines by logrus instead.
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/Sirupsen/logrus"
+	utilsLog "github.com/bitrise-io/go-utils/log"
+)
+
+var outWriter io.Writer = os.Stdout
+
+var level = logrus.InfoLevel
+
+// jsonLogger prints the messages if the JSON log format is enabled, nil otherwise.
+var jsonLogger *logrus.Logger
+
+// colorRegexp matches the ANSI color codes of the colorized messages, these are removed from the JSON messages.
+var colorRegexp = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// SetOutWriter ...
+func SetOutWriter(writer io.Writer) {
+	outWriter = writer
+	utilsLog.SetOutWriter(writer)
+	if jsonLogger != nil {
+		jsonLogger.Out = writer
+	}
+}
+
+// SetLevel sets the least severe level printed, the debug logs are printed only in debug level.
+func SetLevel(logLevel logrus.Level) {
+	level = logLevel
+	utilsLog.SetEnableDebugLog(logLevel >= logrus.DebugLevel)
+}
+
+// SetEnableJSONFormat switches between the colorized text output (the default) and the JSON lines output.
+func SetEnableJSONFormat(enable bool) {
+	if !enable {
+		jsonLogger = nil
+		return
+	}
+
+	jsonLogger = logrus.New()
+	jsonLogger.Out = outWriter
+	jsonLogger.Formatter = &logrus.JSONFormatter{}
+	jsonLogger.Level = logrus.DebugLevel
+}
+
+// printf takes the args as a slice, so the exported funcs accept prebuilt messages as the format, like the go-utils logger.
+func printf(messageLevel logrus.Level, textPrintf func(string, ...interface{}), format string, v []interface{}) {
+	if messageLevel > level {
+		return
+	}
+
+	if jsonLogger != nil {
+		jsonLogger.Log(messageLevel, colorRegexp.ReplaceAllString(fmt.Sprintf(format, v...), ""))
+		return
+	}
+	textPrintf(format, v...)
+}
+
+// Println prints an empty line, separating the blocks of the text output.
+// Nothing is printed in JSON format, or if the info level messages are not printed.
+func Println() {
+	if jsonLogger != nil || level < logrus.InfoLevel {
+		return
+	}
+	if _, err := fmt.Fprintln(outWriter); err != nil {
+		fmt.Printf("failed to print message, error: %s\n", err)
+	}
+}
+
+// Successf ...
+func Successf(format string, v ...interface{}) {
+	printf(logrus.InfoLevel, utilsLog.Successf, format, v)
+}
+
+// Donef ...
+func Donef(format string, v ...interface{}) {
+	Successf(format, v...)
+}
+
+// Infof ...
+func Infof(format string, v ...interface{}) {
+	printf(logrus.InfoLevel, utilsLog.Infof, format, v)
+}
+
+// Printf ...
+func Printf(format string, v ...interface{}) {
+	printf(logrus.InfoLevel, utilsLog.Printf, format, v)
+}
+
+// Debugf ...
+func Debugf(format string, v ...interface{}) {
+	printf(logrus.DebugLevel, utilsLog.Debugf, format, v)
+}
+
+// Warnf ...
+func Warnf(format string, v ...interface{}) {
+	printf(logrus.WarnLevel, utilsLog.Warnf, format, v)
+}
+
+// Errorf ...
+func Errorf(format string, v ...interface{}) {
+	printf(logrus.ErrorLevel, utilsLog.Errorf, format, v)
+}
+
+// TSuccessf ...
+func TSuccessf(format string, v ...interface{}) {
+	printf(logrus.InfoLevel, utilsLog.TSuccessf, format, v)
+}
+
+// TDonef ...
+func TDonef(format string, v ...interface{}) {
+	TSuccessf(format, v...)
+}
+
+// TInfof ...
+func TInfof(format string, v ...interface{}) {
+	printf(logrus.InfoLevel, utilsLog.TInfof, format, v)
+}
+
+// TPrintf ...
+func TPrintf(format string, v ...interface{}) {
+	printf(logrus.InfoLevel, utilsLog.TPrintf, format, v)
+}
+
+// TDebugf ...
+func TDebugf(format string, v ...interface{}) {
+	printf(logrus.DebugLevel, utilsLog.TDebugf, format, v)
+}
+
+// TWarnf ...
+func TWarnf(format string, v ...interface{}) {
+	printf(logrus.WarnLevel, utilsLog.TWarnf, format, v)
+}
+
+// TErrorf ...
+func TErrorf(format string, v ...interface{}) {
+	printf(logrus.ErrorLevel, utilsLog.TErrorf, format, v)
+}
@@ -0,0 +1,104 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/bitrise-io/go-utils/colorstring"
+	"github.com/stretchr/testify/require"
+)
+
+// captureOutput returns the output printed by the given logging function with the given level and format.
+func captureOutput(t *testing.T, level logrus.Level, jsonFormat bool, logFn func()) string {
+	var buf bytes.Buffer
+	SetOutWriter(&buf)
+	SetEnableJSONFormat(jsonFormat)
+	SetLevel(level)
+	defer func() {
+		SetEnableJSONFormat(false)
+		SetLevel(logrus.InfoLevel)
+		SetOutWriter(os.Stdout)
+	}()
+
+	logFn()
+	return buf.String()
+}
+
+func TestJSONFormat(t *testing.T) {
+	out := captureOutput(t, logrus.InfoLevel, true, func() {
+		TInfof(colorstring.Blue("Running scanners:"))
+		Println()
+		TPrintf("Scanner: %s", "ios")
+		TDebugf("Scan result: %s", "{}")
+		TWarnf("No Gradle Wrapper found in: %s", ".")
+		Errorf("Failed to scan")
+	})
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	require.Equal(t, 4, len(lines), out)
+
+	var entries []map[string]interface{}
+	for _, line := range lines {
+		entry := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+		entries = append(entries, entry)
+	}
+
+	require.Equal(t, "info", entries[0]["level"])
+	require.Equal(t, "Running scanners:", entries[0]["msg"])
+	require.Equal(t, "info", entries[1]["level"])
+	require.Equal(t, "Scanner: ios", entries[1]["msg"])
+	require.Equal(t, "warning", entries[2]["level"])
+	require.Equal(t, "No Gradle Wrapper found in: .", entries[2]["msg"])
+	require.Equal(t, "error", entries[3]["level"])
+	require.Equal(t, "Failed to scan", entries[3]["msg"])
+}
+
+func TestLevel(t *testing.T) {
+	t.Log("warn level prints only the warnings and the errors")
+	{
+		out := captureOutput(t, logrus.WarnLevel, false, func() {
+			TInfof("Running scanners:")
+			Println()
+			TPrintf("Scanner: ios")
+			TWarnf("No Gradle Wrapper found")
+			TErrorf("Failed to scan")
+		})
+
+		require.False(t, strings.HasPrefix(out, "\n"), out)
+		require.NotContains(t, out, "Running scanners:")
+		require.NotContains(t, out, "Scanner: ios")
+		require.Contains(t, out, "No Gradle Wrapper found")
+		require.Contains(t, out, "Failed to scan")
+	}
+
+	t.Log("error level in JSON format")
+	{
+		out := captureOutput(t, logrus.ErrorLevel, true, func() {
+			TPrintf("Scanner: ios")
+			TWarnf("No Gradle Wrapper found")
+			TErrorf("Failed to scan")
+		})
+
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Equal(t, 1, len(lines), out)
+		require.Contains(t, lines[0], `"level":"error"`)
+	}
+
+	t.Log("debug logs are printed only in debug level")
+	{
+		out := captureOutput(t, logrus.InfoLevel, false, func() {
+			TDebugf("Scan result")
+		})
+		require.Equal(t, "", out)
+
+		out = captureOutput(t, logrus.DebugLevel, false, func() {
+			TDebugf("Scan result")
+		})
+		require.Contains(t, out, "Scan result")
+	}
+}
@@ -2,9 +2,12 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
 	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
+	yaml "gopkg.in/yaml.v2"
 )
 
 // WorkflowID ...
@@ -15,6 +18,8 @@ const (
 	PrimaryWorkflowID WorkflowID = "primary"
 	// DeployWorkflowID ...
 	DeployWorkflowID WorkflowID = "deploy"
+	// TestWorkflowID ...
+	TestWorkflowID WorkflowID = "test"
 
 	// FormatVersion ...
 	FormatVersion = bitriseModels.Version
@@ -68,6 +73,12 @@ func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envma
 		workflows[string(workflowID)] = workflowBuilder.generate()
 	}
 
+	// pull requests are checked by the test workflow, if any
+	pullRequestWorkflowID := PrimaryWorkflowID
+	if testWorkflowBuilder, ok := builder.workflowBuilderMap[TestWorkflowID]; ok && testWorkflowBuilder != nil && len(testWorkflowBuilder.Steps) > 0 {
+		pullRequestWorkflowID = TestWorkflowID
+	}
+
 	triggerMap := []bitriseModels.TriggerMapItemModel{
 		bitriseModels.TriggerMapItemModel{
 			PushBranch: "*",
@@ -75,7 +86,7 @@ func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envma
 		},
 		bitriseModels.TriggerMapItemModel{
 			PullRequestSourceBranch: "*",
-			WorkflowID:              string(PrimaryWorkflowID),
+			WorkflowID:              string(pullRequestWorkflowID),
 		},
 	}
 
@@ -92,3 +103,119 @@ func (builder *ConfigBuilderModel) Generate(projectType string, appEnvs ...envma
 		App:                  app,
 	}, nil
 }
+
+// NamespaceWorkflows prefixes the config's workflow IDs and the references to them (trigger map, before_run and after_run),
+// so that configs of multiple scanners can be merged without workflow ID collisions.
+func NamespaceWorkflows(config *bitriseModels.BitriseDataModel, prefix string) {
+	if prefix == "" {
+		return
+	}
+
+	prefixed := func(workflowIDs []string) []string {
+		if workflowIDs == nil {
+			return nil
+		}
+		prefixedIDs := make([]string, len(workflowIDs))
+		for i, workflowID := range workflowIDs {
+			prefixedIDs[i] = prefix + workflowID
+		}
+		return prefixedIDs
+	}
+
+	workflows := map[string]bitriseModels.WorkflowModel{}
+	for workflowID, workflow := range config.Workflows {
+		workflow.BeforeRun = prefixed(workflow.BeforeRun)
+		workflow.AfterRun = prefixed(workflow.AfterRun)
+		workflows[prefix+workflowID] = workflow
+	}
+	if config.Workflows != nil {
+		config.Workflows = workflows
+	}
+
+	for i, item := range config.TriggerMap {
+		if item.WorkflowID != "" {
+			config.TriggerMap[i].WorkflowID = prefix + item.WorkflowID
+		}
+	}
+}
+
+// NamespaceConfigMap returns the configs with namespaced workflows, see NamespaceWorkflows.
+func NamespaceConfigMap(configMap BitriseConfigMap, prefix string) (BitriseConfigMap, error) {
+	namespacedConfigMap := BitriseConfigMap{}
+	for name, configStr := range configMap {
+		var config bitriseModels.BitriseDataModel
+		if err := yaml.Unmarshal([]byte(configStr), &config); err != nil {
+			return BitriseConfigMap{}, fmt.Errorf("failed to unmarshal config (%s), error: %s", name, err)
+		}
+
+		NamespaceWorkflows(&config, prefix)
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return BitriseConfigMap{}, fmt.Errorf("failed to marshal config (%s), error: %s", name, err)
+		}
+		namespacedConfigMap[name] = string(data)
+	}
+	return namespacedConfigMap, nil
+}
+
+// AddAppEnvsToConfigMap appends the app envs to the app env vars of every config in the config map,
+// the envs whose key is already declared by a config are skipped.
+func AddAppEnvsToConfigMap(configMap BitriseConfigMap, appEnvs []envmanModels.EnvironmentItemModel) (BitriseConfigMap, error) {
+	extendedConfigMap := BitriseConfigMap{}
+	for name, configStr := range configMap {
+		var config bitriseModels.BitriseDataModel
+		if err := yaml.Unmarshal([]byte(configStr), &config); err != nil {
+			return BitriseConfigMap{}, fmt.Errorf("failed to unmarshal config (%s), error: %s", name, err)
+		}
+
+		keys := map[string]bool{}
+		for _, env := range config.App.Environments {
+			key, _, err := env.GetKeyValuePair()
+			if err != nil {
+				return BitriseConfigMap{}, fmt.Errorf("invalid app env in config (%s), error: %s", name, err)
+			}
+			keys[key] = true
+		}
+		for _, env := range appEnvs {
+			key, _, err := env.GetKeyValuePair()
+			if err != nil {
+				return BitriseConfigMap{}, fmt.Errorf("invalid app env, error: %s", err)
+			}
+			if !keys[key] {
+				keys[key] = true
+				config.App.Environments = append(config.App.Environments, env)
+			}
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return BitriseConfigMap{}, fmt.Errorf("failed to marshal config (%s), error: %s", name, err)
+		}
+		extendedConfigMap[name] = string(data)
+	}
+	return extendedConfigMap, nil
+}
+
+// AddDefaultBranchTrigger maps the pushes of the default branch to the deploy workflow:
+// a push_branch: <branch> item is inserted before every wildcard push trigger, whose (optionally namespaced) primary workflow has a deploy pair.
+func AddDefaultBranchTrigger(config *bitriseModels.BitriseDataModel, branch string) {
+	if branch == "" {
+		return
+	}
+
+	triggerMap := bitriseModels.TriggerMapModel{}
+	for _, item := range config.TriggerMap {
+		if item.PushBranch == "*" && strings.HasSuffix(item.WorkflowID, string(PrimaryWorkflowID)) {
+			deployWorkflowID := strings.TrimSuffix(item.WorkflowID, string(PrimaryWorkflowID)) + string(DeployWorkflowID)
+			if _, ok := config.Workflows[deployWorkflowID]; ok {
+				triggerMap = append(triggerMap, bitriseModels.TriggerMapItemModel{
+					PushBranch: branch,
+					WorkflowID: deployWorkflowID,
+				})
+			}
+		}
+		triggerMap = append(triggerMap, item)
+	}
+	config.TriggerMap = triggerMap
+}
@@ -0,0 +1,175 @@
+package models
+
+import (
+	"testing"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestGenerateTriggerMap(t *testing.T) {
+	step := bitriseModels.StepListItemModel{"script": stepmanModels.StepModel{}}
+
+	t.Log("pull requests trigger the primary workflow by default")
+	{
+		builder := NewDefaultConfigBuilder()
+		builder.AppendStepListItemsTo(PrimaryWorkflowID, step)
+
+		config, err := builder.Generate("android")
+		require.NoError(t, err)
+		require.Equal(t, 2, len(config.TriggerMap))
+		require.Equal(t, string(PrimaryWorkflowID), config.TriggerMap[0].WorkflowID)
+		require.Equal(t, "*", config.TriggerMap[1].PullRequestSourceBranch)
+		require.Equal(t, string(PrimaryWorkflowID), config.TriggerMap[1].WorkflowID)
+	}
+
+	t.Log("pull requests trigger the test workflow if defined")
+	{
+		builder := NewDefaultConfigBuilder()
+		builder.AppendStepListItemsTo(PrimaryWorkflowID, step)
+		builder.AppendStepListItemsTo(TestWorkflowID, step)
+
+		config, err := builder.Generate("android")
+		require.NoError(t, err)
+		require.Equal(t, string(PrimaryWorkflowID), config.TriggerMap[0].WorkflowID)
+		require.Equal(t, string(TestWorkflowID), config.TriggerMap[1].WorkflowID)
+		require.Contains(t, config.Workflows, string(TestWorkflowID))
+	}
+}
+
+func TestNamespaceWorkflows(t *testing.T) {
+	step := bitriseModels.StepListItemModel{"script": stepmanModels.StepModel{}}
+
+	generate := func(prefix string) bitriseModels.BitriseDataModel {
+		builder := NewDefaultConfigBuilder()
+		builder.AppendStepListItemsTo(PrimaryWorkflowID, step)
+		builder.AppendStepListItemsTo(DeployWorkflowID, step)
+		builder.AppendStepListItemsTo(TestWorkflowID, step)
+
+		config, err := builder.Generate("")
+		require.NoError(t, err)
+
+		deploy := config.Workflows[string(DeployWorkflowID)]
+		deploy.BeforeRun = []string{string(TestWorkflowID)}
+		config.Workflows[string(DeployWorkflowID)] = deploy
+
+		NamespaceWorkflows(&config, prefix)
+		return config
+	}
+
+	t.Log("two scanners' workflows merged into one config")
+	{
+		merged := bitriseModels.BitriseDataModel{Workflows: map[string]bitriseModels.WorkflowModel{}}
+		for _, prefix := range []string{"ios-", "android-"} {
+			config := generate(prefix)
+			for workflowID, workflow := range config.Workflows {
+				require.NotContains(t, merged.Workflows, workflowID)
+				merged.Workflows[workflowID] = workflow
+			}
+			merged.TriggerMap = append(merged.TriggerMap, config.TriggerMap...)
+		}
+
+		require.Equal(t, 6, len(merged.Workflows))
+		require.Contains(t, merged.Workflows, "ios-primary")
+		require.Contains(t, merged.Workflows, "android-primary")
+		require.Equal(t, []string{"ios-test"}, merged.Workflows["ios-deploy"].BeforeRun)
+		require.Equal(t, []string{"android-test"}, merged.Workflows["android-deploy"].BeforeRun)
+
+		for _, item := range merged.TriggerMap {
+			require.Contains(t, merged.Workflows, item.WorkflowID)
+		}
+	}
+
+	t.Log("empty prefix keeps the workflow IDs")
+	{
+		config := generate("")
+		require.Contains(t, config.Workflows, string(PrimaryWorkflowID))
+		require.Equal(t, string(PrimaryWorkflowID), config.TriggerMap[0].WorkflowID)
+	}
+}
+
+func TestNamespaceConfigMap(t *testing.T) {
+	builder := NewDefaultConfigBuilder()
+	builder.AppendStepListItemsTo(PrimaryWorkflowID, bitriseModels.StepListItemModel{"script": stepmanModels.StepModel{}})
+	config, err := builder.Generate("ios")
+	require.NoError(t, err)
+	data, err := yaml.Marshal(config)
+	require.NoError(t, err)
+
+	configMap, err := NamespaceConfigMap(BitriseConfigMap{"ios-config": string(data)}, "ios-")
+	require.NoError(t, err)
+
+	var namespaced bitriseModels.BitriseDataModel
+	require.NoError(t, yaml.Unmarshal([]byte(configMap["ios-config"]), &namespaced))
+	require.Contains(t, namespaced.Workflows, "ios-primary")
+	require.Equal(t, "ios-primary", namespaced.TriggerMap[0].WorkflowID)
+	require.Equal(t, "ios", namespaced.ProjectType)
+
+	_, err = NamespaceConfigMap(BitriseConfigMap{"invalid": "workflows: ["}, "ios-")
+	require.Error(t, err)
+}
+
+func TestAddAppEnvsToConfigMap(t *testing.T) {
+	builder := NewDefaultConfigBuilder()
+	builder.AppendStepListItemsTo(PrimaryWorkflowID, bitriseModels.StepListItemModel{"script": stepmanModels.StepModel{}})
+	config, err := builder.Generate("android", envmanModels.EnvironmentItemModel{"PROJECT_LOCATION": "."})
+	require.NoError(t, err)
+	data, err := yaml.Marshal(config)
+	require.NoError(t, err)
+
+	configMap, err := AddAppEnvsToConfigMap(BitriseConfigMap{"android-config": string(data)}, []envmanModels.EnvironmentItemModel{
+		{"PROJECT_LOCATION": "app"},
+		{"FLAVOR": "staging"},
+	})
+	require.NoError(t, err)
+
+	var extended bitriseModels.BitriseDataModel
+	require.NoError(t, yaml.Unmarshal([]byte(configMap["android-config"]), &extended))
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"PROJECT_LOCATION": "."},
+		{"FLAVOR": "staging"},
+	}, extended.App.Environments)
+
+	_, err = AddAppEnvsToConfigMap(BitriseConfigMap{"invalid": "workflows: ["}, nil)
+	require.Error(t, err)
+}
+
+func TestAddDefaultBranchTrigger(t *testing.T) {
+	step := bitriseModels.StepListItemModel{"script": stepmanModels.StepModel{}}
+
+	t.Log("pushes of the default branch trigger the deploy workflow")
+	{
+		builder := NewDefaultConfigBuilder()
+		builder.AppendStepListItemsTo(PrimaryWorkflowID, step)
+		builder.AppendStepListItemsTo(DeployWorkflowID, step)
+
+		config, err := builder.Generate("android")
+		require.NoError(t, err)
+		NamespaceWorkflows(&config, "android-")
+
+		AddDefaultBranchTrigger(&config, "main")
+		require.Equal(t, bitriseModels.TriggerMapModel{
+			{PushBranch: "main", WorkflowID: "android-deploy"},
+			{PushBranch: "*", WorkflowID: "android-primary"},
+			{PullRequestSourceBranch: "*", WorkflowID: "android-primary"},
+		}, config.TriggerMap)
+	}
+
+	t.Log("no deploy workflow")
+	{
+		builder := NewDefaultConfigBuilder()
+		builder.AppendStepListItemsTo(PrimaryWorkflowID, step)
+
+		config, err := builder.Generate("android")
+		require.NoError(t, err)
+
+		AddDefaultBranchTrigger(&config, "main")
+		require.Equal(t, bitriseModels.TriggerMapModel{
+			{PushBranch: "*", WorkflowID: "primary"},
+			{PullRequestSourceBranch: "*", WorkflowID: "primary"},
+		}, config.TriggerMap)
+	}
+}
@@ -0,0 +1,228 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// DiffKind ...
+type DiffKind string
+
+const (
+	// DiffAdded means the entry is only in the new result.
+	DiffAdded DiffKind = "added"
+	// DiffRemoved means the entry is only in the previous result.
+	DiffRemoved DiffKind = "removed"
+	// DiffChanged means the entry is in both results, with different content.
+	DiffChanged DiffKind = "changed"
+)
+
+// DiffEntry is a difference between two scan results.
+type DiffEntry struct {
+	Kind    DiffKind
+	Scanner string
+	// Path locates the entry in the scanner's result, like: options > BITRISE_PROJECT_PATH=App.xcodeproj > BITRISE_SCHEME=App,
+	// or configs > ios-test-config. It is empty if the whole scanner is added or removed.
+	Path string
+	// Detail describes the change of a changed entry, like: title: "Scheme" -> "Scheme name".
+	Detail string
+}
+
+// String ...
+func (entry DiffEntry) String() string {
+	s := fmt.Sprintf("%s %s", entry.Kind, entry.Scanner)
+	if entry.Path != "" {
+		s += ": " + entry.Path
+	}
+	if entry.Detail != "" {
+		s += " (" + entry.Detail + ")"
+	}
+	return s
+}
+
+// DiffScanResults returns the differences of the options and configs of the previous and the new scan results,
+// ordered by scanner name, then by the position of the entries in the scanner's result.
+// A scanner detected only by one of the results is reported by a single entry.
+// A scanner is detected by a result if the result has its options or its configs, the --config-only results have no options,
+// the options are compared only if both results have them.
+func DiffScanResults(previous, next ScanResultModel) []DiffEntry {
+	scannerNames := []string{}
+	addScannerNames := func(result ScanResultModel) {
+		for name := range result.ScannerToOptionRoot {
+			scannerNames = appendUnique(scannerNames, name)
+		}
+		for name := range result.ScannerToBitriseConfigMap {
+			scannerNames = appendUnique(scannerNames, name)
+		}
+	}
+	addScannerNames(previous)
+	addScannerNames(next)
+	sort.Strings(scannerNames)
+
+	entries := []DiffEntry{}
+	for _, name := range scannerNames {
+		previousOptions, previousHasOptions := previous.ScannerToOptionRoot[name]
+		nextOptions, nextHasOptions := next.ScannerToOptionRoot[name]
+		_, previousHasConfigs := previous.ScannerToBitriseConfigMap[name]
+		_, nextHasConfigs := next.ScannerToBitriseConfigMap[name]
+		inPrevious, inNext := previousHasOptions || previousHasConfigs, nextHasOptions || nextHasConfigs
+		switch {
+		case inPrevious && !inNext:
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, Scanner: name})
+			continue
+		case !inPrevious && inNext:
+			entries = append(entries, DiffEntry{Kind: DiffAdded, Scanner: name})
+			continue
+		}
+
+		if previousHasOptions && nextHasOptions {
+			for _, entry := range DiffOptions(previousOptions, nextOptions) {
+				entry.Scanner = name
+				entry.Path = joinDiffPath("options", entry.Path)
+				entries = append(entries, entry)
+			}
+		}
+		for _, entry := range DiffConfigs(previous.ScannerToBitriseConfigMap[name], next.ScannerToBitriseConfigMap[name]) {
+			entry.Scanner = name
+			entry.Path = joinDiffPath("configs", entry.Path)
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// DiffOptions returns the differences of the option trees, the entries' Path is the chain of the
+// env key=value pairs (the value only, if the option has no env key) leading to the entry (empty for the root option), their Scanner is not set.
+func DiffOptions(previous, next OptionNode) []DiffEntry {
+	return diffOptionNodes(&previous, &next, "")
+}
+
+func diffOptionNodes(previous, next *OptionNode, pth string) []DiffEntry {
+	entries := []DiffEntry{}
+
+	details := []string{}
+	addDetail := func(field, previousValue, nextValue string) {
+		if previousValue != nextValue {
+			details = append(details, fmt.Sprintf("%s: %q -> %q", field, previousValue, nextValue))
+		}
+	}
+	addDetail("title", previous.Title, next.Title)
+	addDetail("env_key", previous.EnvKey, next.EnvKey)
+	addDetail("type", string(previous.Type), string(next.Type))
+	addDetail("config", previous.Config, next.Config)
+	if len(details) > 0 {
+		entries = append(entries, DiffEntry{Kind: DiffChanged, Path: pth, Detail: strings.Join(details, ", ")})
+	}
+
+	values := previous.sortedValues()
+	for _, value := range next.sortedValues() {
+		values = appendUnique(values, value)
+	}
+	sort.Strings(values)
+
+	// the values are located by the new env key, a changed env key is reported above
+	envKey := next.EnvKey
+	if envKey == "" {
+		envKey = previous.EnvKey
+	}
+
+	for _, value := range values {
+		component := value
+		if envKey != "" {
+			component = envKey + "=" + value
+		}
+		valuePth := joinDiffPath(pth, component)
+
+		previousChild, inPrevious := previous.ChildOptionMap[value]
+		nextChild, inNext := next.ChildOptionMap[value]
+		switch {
+		case inPrevious && !inNext:
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: valuePth})
+		case !inPrevious && inNext:
+			entries = append(entries, DiffEntry{Kind: DiffAdded, Path: valuePth})
+		default:
+			if previousChild == nil {
+				previousChild = &OptionNode{}
+			}
+			if nextChild == nil {
+				nextChild = &OptionNode{}
+			}
+			entries = append(entries, diffOptionNodes(previousChild, nextChild, valuePth)...)
+		}
+	}
+
+	return entries
+}
+
+// DiffConfigs returns the differences of the config maps, the entries' Path is the config name, their Scanner is not set.
+func DiffConfigs(previous, next BitriseConfigMap) []DiffEntry {
+	names := []string{}
+	for name := range previous {
+		names = appendUnique(names, name)
+	}
+	for name := range next {
+		names = appendUnique(names, name)
+	}
+	sort.Strings(names)
+
+	entries := []DiffEntry{}
+	for _, name := range names {
+		previousConfig, inPrevious := previous[name]
+		nextConfig, inNext := next[name]
+		switch {
+		case inPrevious && !inNext:
+			entries = append(entries, DiffEntry{Kind: DiffRemoved, Path: name})
+		case !inPrevious && inNext:
+			entries = append(entries, DiffEntry{Kind: DiffAdded, Path: name})
+		case previousConfig != nextConfig:
+			entries = append(entries, DiffEntry{Kind: DiffChanged, Path: name, Detail: configLineDiff(previousConfig, nextConfig)})
+		}
+	}
+	return entries
+}
+
+// configLineDiff summarizes the change of the config content by the number of the removed and added lines.
+func configLineDiff(previous, next string) string {
+	count := func(lines []string) map[string]int {
+		lineToCount := map[string]int{}
+		for _, line := range lines {
+			lineToCount[line]++
+		}
+		return lineToCount
+	}
+	previousLines, nextLines := strings.Split(previous, "\n"), strings.Split(next, "\n")
+	previousCount, nextCount := count(previousLines), count(nextLines)
+
+	removed, added := 0, 0
+	for line, n := range previousCount {
+		if n > nextCount[line] {
+			removed += n - nextCount[line]
+		}
+	}
+	for line, n := range nextCount {
+		if n > previousCount[line] {
+			added += n - previousCount[line]
+		}
+	}
+	return fmt.Sprintf("-%d +%d lines", removed, added)
+}
+
+func joinDiffPath(pth, component string) string {
+	if pth == "" {
+		return component
+	}
+	if component == "" {
+		return pth
+	}
+	return pth + " > " + component
+}
+
+func appendUnique(list []string, item string) []string {
+	if sliceutil.IsStringInSlice(item, list) {
+		return list
+	}
+	return append(list, item)
+}
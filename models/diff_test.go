@@ -0,0 +1,117 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDiffOptions(schemes ...string) OptionNode {
+	projectOption := NewOption("Project (or Workspace) path", "BITRISE_PROJECT_PATH")
+	schemeOption := NewOption("Scheme name", "BITRISE_SCHEME")
+	projectOption.AddOption("App.xcodeproj", schemeOption)
+	for _, scheme := range schemes {
+		schemeOption.AddConfig(scheme, NewConfigOption("ios-test-config"))
+	}
+	return *projectOption
+}
+
+func TestDiffOptions(t *testing.T) {
+	t.Log("same options")
+	{
+		require.Equal(t, []DiffEntry{}, DiffOptions(testDiffOptions("App"), testDiffOptions("App")))
+	}
+
+	t.Log("added and removed values")
+	{
+		require.Equal(t, []DiffEntry{
+			{Kind: DiffRemoved, Path: "BITRISE_PROJECT_PATH=App.xcodeproj > BITRISE_SCHEME=App"},
+			{Kind: DiffAdded, Path: "BITRISE_PROJECT_PATH=App.xcodeproj > BITRISE_SCHEME=App-Staging"},
+			{Kind: DiffAdded, Path: "BITRISE_PROJECT_PATH=App.xcodeproj > BITRISE_SCHEME=App-tvOS"},
+		}, DiffOptions(testDiffOptions("App"), testDiffOptions("App-Staging", "App-tvOS")))
+	}
+
+	t.Log("changed option and config")
+	{
+		next := testDiffOptions("App")
+		schemeOption, ok := next.Child("App.xcodeproj")
+		require.True(t, ok)
+		schemeOption.Title = "Scheme"
+		schemeOption.ChildOptionMap["App"].Config = "ios-config"
+
+		require.Equal(t, []DiffEntry{
+			{Kind: DiffChanged, Path: "BITRISE_PROJECT_PATH=App.xcodeproj", Detail: `title: "Scheme name" -> "Scheme"`},
+			{Kind: DiffChanged, Path: "BITRISE_PROJECT_PATH=App.xcodeproj > BITRISE_SCHEME=App", Detail: `config: "ios-test-config" -> "ios-config"`},
+		}, DiffOptions(testDiffOptions("App"), next))
+	}
+}
+
+func TestDiffConfigs(t *testing.T) {
+	previous := BitriseConfigMap{
+		"ios-config":      "format_version: 4\nworkflows:\n  primary:\n",
+		"ios-test-config": "format_version: 4\n",
+	}
+	next := BitriseConfigMap{
+		"ios-config":     "format_version: 5\nworkflows:\n  primary:\n  deploy:\n",
+		"ios-pod-config": "format_version: 4\n",
+	}
+
+	require.Equal(t, []DiffEntry{
+		{Kind: DiffChanged, Path: "ios-config", Detail: "-1 +2 lines"},
+		{Kind: DiffAdded, Path: "ios-pod-config"},
+		{Kind: DiffRemoved, Path: "ios-test-config"},
+	}, DiffConfigs(previous, next))
+}
+
+func TestDiffScanResults(t *testing.T) {
+	previous := ScanResultModel{
+		ScannerToOptionRoot: map[string]OptionNode{
+			"ios":     testDiffOptions("App"),
+			"android": *NewOption("Project root", "PROJECT_LOCATION"),
+		},
+		ScannerToBitriseConfigMap: map[string]BitriseConfigMap{
+			"ios":     {"ios-test-config": "format_version: 4\n"},
+			"android": {"android-config": "format_version: 4\n"},
+		},
+	}
+	next := ScanResultModel{
+		ScannerToOptionRoot: map[string]OptionNode{
+			"ios":      testDiffOptions("App", "App-Staging"),
+			"fastlane": *NewOption("Fastlane lane", "FASTLANE_LANE"),
+		},
+		ScannerToBitriseConfigMap: map[string]BitriseConfigMap{
+			"ios":      {"ios-test-config": "format_version: 5\n"},
+			"fastlane": {"fastlane-config": "format_version: 4\n"},
+		},
+	}
+
+	entries := DiffScanResults(previous, next)
+	require.Equal(t, []DiffEntry{
+		{Kind: DiffRemoved, Scanner: "android"},
+		{Kind: DiffAdded, Scanner: "fastlane"},
+		{Kind: DiffAdded, Scanner: "ios", Path: "options > BITRISE_PROJECT_PATH=App.xcodeproj > BITRISE_SCHEME=App-Staging"},
+		{Kind: DiffChanged, Scanner: "ios", Path: "configs > ios-test-config", Detail: "-1 +1 lines"},
+	}, entries)
+
+	require.Equal(t, "removed android", entries[0].String())
+	require.Equal(t, "changed ios: configs > ios-test-config (-1 +1 lines)", entries[3].String())
+
+	require.Equal(t, []DiffEntry{}, DiffScanResults(next, next))
+
+	t.Log("previous result without options (--config-only)")
+	{
+		configOnly := ScanResultModel{
+			ScannerToBitriseConfigMap: map[string]BitriseConfigMap{
+				"ios":     {"ios-test-config": "format_version: 4\n"},
+				"android": {"android-config": "format_version: 4\n"},
+			},
+		}
+
+		require.Equal(t, []DiffEntry{
+			{Kind: DiffRemoved, Scanner: "android"},
+			{Kind: DiffAdded, Scanner: "fastlane"},
+			{Kind: DiffChanged, Scanner: "ios", Path: "configs > ios-test-config", Detail: "-1 +1 lines"},
+		}, DiffScanResults(configOnly, next))
+		require.Equal(t, []DiffEntry{}, DiffScanResults(configOnly, configOnly))
+	}
+}
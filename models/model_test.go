@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 
 	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
 )
 
 func TestNewOption(t *testing.T) {
@@ -21,6 +22,28 @@ func TestNewOption(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestNewUserInputOption(t *testing.T) {
+	t.Log("required user input")
+	{
+		actual := NewUserInputOption("Scheme name", "BITRISE_SCHEME", false)
+		require.Equal(t, TypeUserInput, actual.Type)
+		require.Equal(t, "BITRISE_SCHEME", actual.EnvKey)
+	}
+
+	t.Log("optional user input")
+	{
+		actual := NewUserInputOption("Development Team", "DEVELOPMENT_TEAM", true)
+		require.Equal(t, TypeOptionalUserInput, actual.Type)
+	}
+}
+
+func TestNewInfoOption(t *testing.T) {
+	actual := NewInfoOption("Bundle ID", "BITRISE_BUNDLE_ID")
+	require.Equal(t, TypeInfo, actual.Type)
+	require.Equal(t, "BITRISE_BUNDLE_ID", actual.EnvKey)
+	require.Equal(t, 0, len(actual.ChildOptionMap))
+}
+
 func TestGetValues(t *testing.T) {
 	option := OptionNode{
 		ChildOptionMap: map[string]*OptionNode{},
@@ -201,6 +224,36 @@ func TestLastOptions(t *testing.T) {
 	}
 }`])
 	}
+
+	t.Log("last child with a config value and child options")
+	{
+		optionJSON := `{
+	"title": "Project (or Workspace) path",
+	"env_key": "BITRISE_PROJECT_PATH",
+	"value_map": {
+		"BitriseTest.xcodeproj": {
+			"config": "ios-config"
+		},
+		"BitriseTest.xcworkspace": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"BitriseTest": {
+					"config": "ios-pod-config"
+				}
+			}
+		}
+	}
+}`
+
+		var option OptionNode
+		require.NoError(t, json.Unmarshal([]byte(optionJSON), &option))
+
+		lastOptions := option.LastChilds()
+		require.Equal(t, 2, len(lastOptions))
+		require.Equal(t, "BITRISE_PROJECT_PATH", lastOptions[0].EnvKey)
+		require.Equal(t, "BITRISE_SCHEME", lastOptions[1].EnvKey)
+	}
 }
 
 func TestCopy(t *testing.T) {
@@ -348,6 +401,86 @@ func TestParent(t *testing.T) {
 	}
 }
 
+func TestAddOptions(t *testing.T) {
+	// the children are built before they are added
+	opt01 := NewOption("OPT01", "OPT01_KEY")
+	opt011 := NewOption("OPT011", "OPT011_KEY")
+	opt01.AddOption("value1", opt011)
+	opt011.AddConfig("value1", NewConfigOption("config1"))
+
+	opt02 := NewOption("OPT02", "OPT02_KEY")
+
+	opt0 := NewOption("OPT0", "OPT0_KEY")
+	opt00 := NewOption("OPT00", "OPT00_KEY")
+	opt0.AddOption("value0", opt00)
+	opt00.AddOptions(map[string]*OptionNode{
+		"value1": opt01,
+		"value2": opt02,
+	})
+
+	require.Equal(t, []string{"value1", "value2"}, opt00.GetValues())
+	require.Equal(t, []string{"value0", "value1"}, opt01.Components)
+	require.Equal(t, []string{"value0", "value2"}, opt02.Components)
+	require.Equal(t, []string{"value0", "value1", "value1"}, opt011.Components)
+	require.Equal(t, []string{"value0", "value1", "value1", "value1"}, opt011.ChildOptionMap["value1"].Components)
+
+	for _, opt := range []*OptionNode{opt01, opt02, opt011, opt011.ChildOptionMap["value1"]} {
+		require.Equal(t, opt0, opt.Head)
+	}
+
+	parent, underKey, ok := opt011.Parent()
+	require.Equal(t, true, ok)
+	require.Equal(t, opt01, parent)
+	require.Equal(t, "value1", underKey)
+}
+
+func TestAddValues(t *testing.T) {
+	// 1. level
+	opt0 := NewOption("OPT0", "OPT0_KEY")
+
+	// 2. level
+	opt01 := NewOption("OPT01", "OPT01_KEY")
+	opt0.AddOption("value1", opt01)
+
+	// 3. level, the same question for multiple values
+	opt011 := NewOption("OPT011", "OPT011_KEY")
+	opt011.AddConfig("value1", NewConfigOption("config1"))
+	opt01.AddValues(opt011, "scheme1", "scheme2", "scheme3")
+
+	require.Equal(t, []string{"scheme1", "scheme2", "scheme3"}, opt01.GetValues())
+
+	for _, value := range []string{"scheme1", "scheme2", "scheme3"} {
+		child := opt01.ChildOptionMap[value]
+		require.Equal(t, "OPT011", child.Title)
+		require.Equal(t, "OPT011_KEY", child.EnvKey)
+		require.Equal(t, []string{"value1", value}, child.Components)
+		require.Equal(t, opt0, child.Head)
+
+		parent, underKey, ok := child.Parent()
+		require.Equal(t, true, ok)
+		require.Equal(t, opt01, parent)
+		require.Equal(t, value, underKey)
+
+		configOption, ok := opt0.Child("value1", value, "value1")
+		require.Equal(t, true, ok)
+		require.Equal(t, "config1", configOption.Config)
+		require.Equal(t, []string{"value1", value, "value1"}, configOption.Components)
+		require.Equal(t, opt0, configOption.Head)
+
+		parent, underKey, ok = configOption.Parent()
+		require.Equal(t, true, ok)
+		require.Equal(t, child, parent)
+		require.Equal(t, "value1", underKey)
+	}
+
+	// the first value gets the given option, the others get a copy
+	require.True(t, opt011 == opt01.ChildOptionMap["scheme1"])
+	require.False(t, opt011 == opt01.ChildOptionMap["scheme2"])
+
+	opt01.ChildOptionMap["scheme2"].AddOption("value2", NewOption("OPT0112", "OPT0112_KEY"))
+	require.Equal(t, []string{"value1"}, opt011.GetValues())
+}
+
 func TestRemoveConfigs(t *testing.T) {
 	optionJSON := `{
 	"title": "Project (or Workspace) path",
@@ -388,3 +521,316 @@ func TestRemoveConfigs(t *testing.T) {
 	}
 }`, option.String())
 }
+
+func TestAttachToLastChilds(t *testing.T) {
+	optionJSON := `{
+	"title": "Project (or Workspace) path",
+	"env_key": "BITRISE_PROJECT_PATH",
+	"value_map": {
+		"App.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"App": {},
+				"App-Release": {
+					"config": "ios-release-config"
+				}
+			}
+		},
+		"Legacy.xcodeproj": {
+			"config": "ios-legacy-config"
+		},
+		"Tools.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"Tools": {}
+			}
+		}
+	}
+}`
+
+	var option OptionNode
+	require.NoError(t, json.Unmarshal([]byte(optionJSON), &option))
+
+	exportMethodOption := NewOption("Export method", "BITRISE_EXPORT_METHOD")
+	exportMethodOption.AddConfig("app-store", NewConfigOption("ios-config"))
+
+	option.AttachToLastChilds(exportMethodOption)
+
+	require.Equal(t, `{
+	"title": "Project (or Workspace) path",
+	"env_key": "BITRISE_PROJECT_PATH",
+	"value_map": {
+		"App.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"App": {
+					"title": "Export method",
+					"env_key": "BITRISE_EXPORT_METHOD",
+					"value_map": {
+						"app-store": {
+							"config": "ios-config"
+						}
+					}
+				},
+				"App-Release": {
+					"config": "ios-release-config"
+				}
+			}
+		},
+		"Legacy.xcodeproj": {
+			"config": "ios-legacy-config"
+		},
+		"Tools.xcodeproj": {
+			"title": "Scheme name",
+			"env_key": "BITRISE_SCHEME",
+			"value_map": {
+				"Tools": {
+					"title": "Export method",
+					"env_key": "BITRISE_EXPORT_METHOD",
+					"value_map": {
+						"app-store": {
+							"config": "ios-config"
+						}
+					}
+				}
+			}
+		}
+	}
+}`, option.String())
+}
+
+func TestWalk(t *testing.T) {
+	opt0 := NewOption("OPT0", "OPT0_KEY")
+
+	opt01 := NewOption("OPT01", "OPT01_KEY")
+	opt0.AddOption("value2", opt01)
+	opt01.AddConfig("config", NewConfigOption("config1"))
+
+	opt02 := NewOption("OPT02", "OPT02_KEY")
+	opt0.AddOption("value1", opt02)
+
+	opt021 := NewOption("OPT021", "OPT021_KEY")
+	opt02.AddOption("value1", opt021)
+	opt021.AddConfig("config", NewConfigOption("config2"))
+
+	t.Log("visits every option depth-first in sorted order, the path matches the Components")
+	{
+		visited := []string{}
+		require.NoError(t, opt0.Walk(func(opt *OptionNode, path []string) error {
+			require.Equal(t, opt.Components, path)
+
+			name := opt.Title
+			if opt.IsConfigOption() {
+				name = opt.Config
+			}
+			visited = append(visited, name)
+			return nil
+		}))
+
+		require.Equal(t, []string{"OPT0", "OPT02", "OPT021", "config2", "OPT01", "config1"}, visited)
+	}
+
+	t.Log("error aborts the walk")
+	{
+		visited := []string{}
+		err := opt0.Walk(func(opt *OptionNode, path []string) error {
+			visited = append(visited, opt.Title)
+			if opt.Title == "OPT021" {
+				return fmt.Errorf("abort")
+			}
+			return nil
+		})
+
+		require.EqualError(t, err, "abort")
+		require.Equal(t, []string{"OPT0", "OPT02", "OPT021"}, visited)
+	}
+}
+
+func TestCount(t *testing.T) {
+	t.Log("shared config names are counted once")
+	{
+		projectOption := NewOption("Project path", "PROJECT_PATH")
+
+		schemeOption := NewOption("Scheme", "SCHEME")
+		projectOption.AddOption("App.xcodeproj", schemeOption)
+		schemeOption.AddConfig("App", NewConfigOption("ios-test-config"))
+		schemeOption.AddConfig("AppUITests", NewConfigOption("ios-test-config"))
+		schemeOption.AddConfig("Framework", NewConfigOption("ios-config"))
+
+		otherSchemeOption := NewOption("Scheme", "SCHEME")
+		projectOption.AddOption("Other.xcodeproj", otherSchemeOption)
+		otherSchemeOption.AddConfig("Other", NewConfigOption("ios-test-config"))
+
+		options, values, configs := projectOption.Count()
+		require.Equal(t, 3, options)
+		require.Equal(t, 6, values)
+		require.Equal(t, 2, configs)
+
+		options, values, configs = otherSchemeOption.Count()
+		require.Equal(t, 1, options)
+		require.Equal(t, 1, values)
+		require.Equal(t, 1, configs)
+	}
+
+	t.Log("subtree reachable via multiple values")
+	{
+		moduleOption := NewOption("Module", "MODULE")
+		variantOption := NewOption("Variant", "VARIANT")
+		variantOption.AddConfig("debug", NewConfigOption("android-config"))
+		variantOption.AddConfig("release", NewConfigOption("android-config"))
+		moduleOption.AddOption("app", variantOption)
+		moduleOption.AddOption("wear", variantOption)
+
+		options, values, configs := moduleOption.Count()
+		require.Equal(t, 3, options)
+		require.Equal(t, 6, values)
+		require.Equal(t, 1, configs)
+	}
+
+	t.Log("empty option")
+	{
+		options, values, configs := (&OptionNode{}).Count()
+		require.Equal(t, 0, options)
+		require.Equal(t, 0, values)
+		require.Equal(t, 0, configs)
+	}
+}
+
+func TestPathToConfig(t *testing.T) {
+	opt0 := NewOption("OPT0", "OPT0_KEY")
+
+	opt01 := NewOption("OPT01", "OPT01_KEY")
+	opt0.AddOption("value2", opt01)
+	opt01.AddConfig("value", NewConfigOption("config1"))
+
+	opt02 := NewOption("OPT02", "OPT02_KEY")
+	opt0.AddOption("value1", opt02)
+
+	opt021 := NewOption("OPT021", "OPT021_KEY")
+	opt02.AddOption("value1", opt021)
+	opt021.AddConfig("value1", NewConfigOption("config1"))
+	opt021.AddConfig("value2", NewConfigOption("config2"))
+
+	t.Log("config reachable via two paths, the first path in sorted order is returned")
+	{
+		options, ok := opt0.PathToConfig("config1")
+		require.True(t, ok)
+		require.Equal(t, []*OptionNode{opt0, opt02, opt021}, options)
+	}
+
+	t.Log("config reachable via one path")
+	{
+		options, ok := opt01.PathToConfig("config1")
+		require.True(t, ok)
+		require.Equal(t, []*OptionNode{opt01}, options)
+	}
+
+	t.Log("not existing config")
+	{
+		options, ok := opt0.PathToConfig("config3")
+		require.False(t, ok)
+		require.Equal(t, 0, len(options))
+	}
+}
+
+func TestCanonicalMarshal(t *testing.T) {
+	newTree := func() *OptionNode {
+		projectOption := NewOption("Project", "PROJECT")
+		for _, project := range []string{"c.xcodeproj", "a.xcodeproj", "b.xcodeproj", "_", ""} {
+			schemeOption := NewOption("Scheme", "SCHEME")
+			projectOption.AddOption(project, schemeOption)
+
+			for _, scheme := range []string{"Z", "X", "Y", "W"} {
+				exportMethodOption := NewUserInputOption("Export method", "EXPORT_METHOD", true)
+				schemeOption.AddOption(scheme, exportMethodOption)
+				exportMethodOption.AddConfig("_", NewConfigOption("config-"+scheme))
+			}
+		}
+		projectOption.AddOption("nil", nil)
+		return projectOption
+	}
+
+	t.Log("json")
+	{
+		expected, err := json.Marshal(newTree())
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			actual, err := json.Marshal(newTree())
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(actual))
+		}
+
+		// the canonical form is a valid JSON of the option
+		var option OptionNode
+		require.NoError(t, json.Unmarshal(expected, &option))
+		require.Equal(t, []string{"", "_", "a.xcodeproj", "b.xcodeproj", "c.xcodeproj", "nil"}, option.GetValues())
+		require.Nil(t, option.ChildOptionMap["nil"])
+		require.Equal(t, "config-W", option.ChildOptionMap["a.xcodeproj"].ChildOptionMap["W"].ChildOptionMap["_"].Config)
+	}
+
+	t.Log("yaml")
+	{
+		expected, err := yaml.Marshal(newTree())
+		require.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			actual, err := yaml.Marshal(newTree())
+			require.NoError(t, err)
+			require.Equal(t, string(expected), string(actual))
+		}
+
+		var option OptionNode
+		require.NoError(t, yaml.Unmarshal(expected, &option))
+		require.Equal(t, newTree().String(), option.String())
+	}
+
+	t.Log("String and Copy")
+	{
+		expected := newTree().String()
+		for i := 0; i < 100; i++ {
+			tree := newTree()
+			require.Equal(t, expected, tree.String())
+			require.Equal(t, expected, tree.Copy().String())
+		}
+	}
+
+	t.Log("value_map keys are sorted")
+	{
+		option := NewOption("Scheme", "SCHEME")
+		option.AddConfig("b", NewConfigOption("config-b"))
+		option.AddConfig("a", NewConfigOption("config-a"))
+
+		actual, err := json.Marshal(option)
+		require.NoError(t, err)
+		require.Equal(t, `{"title":"Scheme","env_key":"SCHEME","value_map":{"a":{"config":"config-a"},"b":{"config":"config-b"}}}`, string(actual))
+
+		actualYML, err := yaml.Marshal(option)
+		require.NoError(t, err)
+		require.Equal(t, "title: Scheme\nenv_key: SCHEME\nvalue_map:\n  a:\n    config: config-a\n  b:\n    config: config-b\n", string(actualYML))
+	}
+}
+
+func TestPrettify(t *testing.T) {
+	projectOption := NewOption("Project", "PROJECT")
+	for _, project := range []string{"b.xcodeproj", "a.xcodeproj"} {
+		schemeOption := NewOption("Scheme", "SCHEME")
+		projectOption.AddOption(project, schemeOption)
+
+		developmentTeamOption := NewUserInputOption("Development team", "TEAM", true)
+		schemeOption.AddOption("App", developmentTeamOption)
+		developmentTeamOption.AddConfig("_", NewConfigOption("ios-config"))
+	}
+	projectOption.AddOption("nil", nil)
+
+	require.Equal(t, `Project (PROJECT, selector)
+  "a.xcodeproj" => Scheme (SCHEME, selector)
+    "App" => Development team (TEAM, user_input_optional)
+      "_" => config: ios-config
+  "b.xcodeproj" => Scheme (SCHEME, selector)
+    "App" => Development team (TEAM, user_input_optional)
+      "_" => config: ios-config
+  "nil" => <nil>
+`, projectOption.Prettify())
+}
@@ -9,12 +9,28 @@ type Warnings []string
 // Errors ...
 type Errors []string
 
+// Summary contains informational hints collected by a scanner, which do not affect the generated configs directly.
+type Summary []string
+
+// Icon is an app icon candidate detected by a scanner.
+type Icon struct {
+	// Path is relative to the scanned directory
+	Path   string `json:"path" yaml:"path"`
+	Width  int    `json:"width" yaml:"width"`
+	Height int    `json:"height" yaml:"height"`
+}
+
+// Icons are the app icon candidates, the best candidate first.
+type Icons []Icon
+
 // ScanResultModel ...
 type ScanResultModel struct {
 	ScannerToOptionRoot       map[string]OptionNode       `json:"options,omitempty" yaml:"options,omitempty"`
 	ScannerToBitriseConfigMap map[string]BitriseConfigMap `json:"configs,omitempty" yaml:"configs,omitempty"`
 	ScannerToWarnings         map[string]Warnings         `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 	ScannerToErrors           map[string]Errors           `json:"errors,omitempty" yaml:"errors,omitempty"`
+	ScannerToSummary          map[string]Summary          `json:"summary,omitempty" yaml:"summary,omitempty"`
+	ScannerToIcons            map[string]Icons            `json:"icons,omitempty" yaml:"icons,omitempty"`
 }
 
 // AddError ...
@@ -27,3 +43,10 @@ func (result *ScanResultModel) AddError(platform string, errorMessage string) {
 	}
 	result.ScannerToErrors[platform] = append(result.ScannerToErrors[platform], errorMessage)
 }
+
+// ConfigsOnly returns the scan result without the option trees, for the consumers needing only the generated configs.
+// The configs, warnings, errors, summaries and icons are kept.
+func (result ScanResultModel) ConfigsOnly() ScanResultModel {
+	result.ScannerToOptionRoot = nil
+	return result
+}
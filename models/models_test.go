@@ -0,0 +1,37 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestConfigsOnly(t *testing.T) {
+	scanResult := ScanResultModel{
+		ScannerToOptionRoot:       map[string]OptionNode{"ios": testDiffOptions("App")},
+		ScannerToBitriseConfigMap: map[string]BitriseConfigMap{"ios": {"ios-test-config": "format_version: 4"}},
+		ScannerToWarnings:         map[string]Warnings{"ios": {"warning"}},
+	}
+
+	configsOnly := scanResult.ConfigsOnly()
+	require.Nil(t, configsOnly.ScannerToOptionRoot)
+	require.Equal(t, scanResult.ScannerToBitriseConfigMap, configsOnly.ScannerToBitriseConfigMap)
+	require.Equal(t, scanResult.ScannerToWarnings, configsOnly.ScannerToWarnings)
+	require.Equal(t, 1, len(scanResult.ScannerToOptionRoot))
+
+	jsonBytes, err := json.Marshal(configsOnly)
+	require.NoError(t, err)
+	require.Equal(t, `{"configs":{"ios":{"ios-test-config":"format_version: 4"}},"warnings":{"ios":["warning"]}}`, string(jsonBytes))
+
+	yamlBytes, err := yaml.Marshal(configsOnly)
+	require.NoError(t, err)
+	require.Equal(t, `configs:
+  ios:
+    ios-test-config: 'format_version: 4'
+warnings:
+  ios:
+  - warning
+`, string(yamlBytes))
+}
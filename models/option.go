@@ -1,14 +1,36 @@
 package models
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// OptionType ...
+type OptionType string
+
+const (
+	// TypeSelector means the value has to be selected from the option's values.
+	TypeSelector OptionType = "selector"
+	// TypeUserInput means the value has to be provided by the user.
+	TypeUserInput OptionType = "user_input"
+	// TypeOptionalUserInput means the value can be provided by the user, but it can be left empty as well.
+	TypeOptionalUserInput OptionType = "user_input_optional"
+	// TypeInfo means the value is detected and displayed to the user, but it can not be changed.
+	TypeInfo OptionType = "info"
 )
 
 // OptionNode ...
 type OptionNode struct {
-	Title  string `json:"title,omitempty" yaml:"title,omitempty"`
-	EnvKey string `json:"env_key,omitempty" yaml:"env_key,omitempty"`
+	Title  string     `json:"title,omitempty" yaml:"title,omitempty"`
+	EnvKey string     `json:"env_key,omitempty" yaml:"env_key,omitempty"`
+	Type   OptionType `json:"type,omitempty" yaml:"type,omitempty"`
 
 	ChildOptionMap map[string]*OptionNode `json:"value_map,omitempty" yaml:"value_map,omitempty"`
 	Config         string                 `json:"config,omitempty" yaml:"config,omitempty"`
@@ -27,6 +49,25 @@ func NewOption(title, envKey string) *OptionNode {
 	}
 }
 
+// NewUserInputOption creates an option whose value has to be provided by the user,
+// the option's only value should be "_".
+func NewUserInputOption(title, envKey string, isOptional bool) *OptionNode {
+	option := NewOption(title, envKey)
+	option.Type = TypeUserInput
+	if isOptional {
+		option.Type = TypeOptionalUserInput
+	}
+	return option
+}
+
+// NewInfoOption creates a read-only option, which displays a detected value (like the bundle ID) without asking the user,
+// the option's only value should be the detected value.
+func NewInfoOption(title, envKey string) *OptionNode {
+	option := NewOption(title, envKey)
+	option.Type = TypeInfo
+	return option
+}
+
 // NewConfigOption ...
 func NewConfigOption(name string) *OptionNode {
 	return &OptionNode{
@@ -36,6 +77,42 @@ func NewConfigOption(name string) *OptionNode {
 	}
 }
 
+// sortedValues returns the option's values (the keys of its ChildOptionMap) in sorted order.
+func (option OptionNode) sortedValues() []string {
+	values := make([]string, 0, len(option.ChildOptionMap))
+	for value := range option.ChildOptionMap {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+// MarshalYAML marshals the option in the canonical form: the fields in declaration order and the value_map keys sorted,
+// so the generated result files do not churn between runs (encoding/json sorts the map keys by itself).
+func (option OptionNode) MarshalYAML() (interface{}, error) {
+	fields := yaml.MapSlice{}
+	if option.Title != "" {
+		fields = append(fields, yaml.MapItem{Key: "title", Value: option.Title})
+	}
+	if option.EnvKey != "" {
+		fields = append(fields, yaml.MapItem{Key: "env_key", Value: option.EnvKey})
+	}
+	if option.Type != "" {
+		fields = append(fields, yaml.MapItem{Key: "type", Value: option.Type})
+	}
+	if len(option.ChildOptionMap) > 0 {
+		valueMap := yaml.MapSlice{}
+		for _, value := range option.sortedValues() {
+			valueMap = append(valueMap, yaml.MapItem{Key: value, Value: option.ChildOptionMap[value]})
+		}
+		fields = append(fields, yaml.MapItem{Key: "value_map", Value: valueMap})
+	}
+	if option.Config != "" {
+		fields = append(fields, yaml.MapItem{Key: "config", Value: option.Config})
+	}
+	return fields, nil
+}
+
 func (option *OptionNode) String() string {
 	bytes, err := json.MarshalIndent(option, "", "\t")
 	if err != nil {
@@ -44,6 +121,49 @@ func (option *OptionNode) String() string {
 	return string(bytes)
 }
 
+// Prettify renders the option tree as an indented outline, for debugging:
+// each value is followed by the option it leads to, the values are listed in sorted order.
+//
+//	Project path (PROJECT_PATH, selector)
+//	  "App.xcodeproj" => Scheme (SCHEME, selector)
+//	    "App" => config: ios-config
+func (option *OptionNode) Prettify() string {
+	var buf bytes.Buffer
+	buf.WriteString(option.prettifyNode())
+	buf.WriteString("\n")
+	option.prettifyValues(&buf, 1)
+	return buf.String()
+}
+
+func (option *OptionNode) prettifyNode() string {
+	if option == nil {
+		return "<nil>"
+	}
+	if option.IsConfigOption() {
+		return "config: " + option.Config
+	}
+
+	optionType := option.Type
+	if optionType == "" {
+		optionType = TypeSelector
+	}
+	if option.EnvKey == "" {
+		return fmt.Sprintf("%s (%s)", option.Title, optionType)
+	}
+	return fmt.Sprintf("%s (%s, %s)", option.Title, option.EnvKey, optionType)
+}
+
+func (option *OptionNode) prettifyValues(buf *bytes.Buffer, depth int) {
+	if option == nil {
+		return
+	}
+	for _, value := range option.sortedValues() {
+		child := option.ChildOptionMap[value]
+		buf.WriteString(fmt.Sprintf("%s%q => %s\n", strings.Repeat("  ", depth), value, child.prettifyNode()))
+		child.prettifyValues(buf, depth+1)
+	}
+}
+
 // IsConfigOption ...
 func (option *OptionNode) IsConfigOption() bool {
 	return option.Config != ""
@@ -59,12 +179,61 @@ func (option *OptionNode) IsEmpty() bool {
 	return !option.IsValueOption() && !option.IsConfigOption()
 }
 
+// The option walk rules below are shared by the interactive (scanner.AskForOptions) and the non-interactive (Prefill) option walks.
+
+// IsAutoSelected returns true if the option's value is selected without asking: a read-only info option,
+// or an option with a single value (other than "_"), which is not an optional user input.
+func (option *OptionNode) IsAutoSelected() bool {
+	values := option.GetValues()
+	if option.Type == TypeInfo {
+		return len(values) == 1
+	}
+	return len(values) == 1 && values[0] != "_" && option.Type != TypeOptionalUserInput
+}
+
+// IsUserInput returns true if the option's value is provided by the user, instead of selected from its values:
+// the optional user inputs, and the options whose only value is "_".
+func (option *OptionNode) IsUserInput() bool {
+	return len(option.GetValues()) == 1 && !option.IsAutoSelected()
+}
+
+// DefaultValue returns the option's first value, the value used if nothing is provided: the only value of an auto selected option,
+// the detected value of an optional user input, or the first value of a selector. Empty if the value is the "_" placeholder.
+func (option *OptionNode) DefaultValue() string {
+	values := option.GetValues()
+	if len(values) == 0 || values[0] == "_" {
+		return ""
+	}
+	return values[0]
+}
+
+// NextOption returns the option following the selected value: the only child of an option (like a user input's),
+// or the selected value's child. Returns false if there is no such option.
+func (option *OptionNode) NextOption(selectedValue string) (*OptionNode, bool) {
+	if len(option.ChildOptionMap) == 1 {
+		for _, child := range option.ChildOptionMap {
+			return child, true
+		}
+	}
+	child, ok := option.ChildOptionMap[selectedValue]
+	return child, ok
+}
+
+// AppEnv returns the app env of the option's selected value. Returns false if the option has no env key,
+// or it is an optional user input left empty.
+func (option *OptionNode) AppEnv(selectedValue string) (envmanModels.EnvironmentItemModel, bool) {
+	if option.EnvKey == "" || (selectedValue == "" && option.Type == TypeOptionalUserInput) {
+		return nil, false
+	}
+	return envmanModels.EnvironmentItemModel{option.EnvKey: selectedValue}, true
+}
+
 // AddOption ...
 func (option *OptionNode) AddOption(forValue string, newOption *OptionNode) {
 	option.ChildOptionMap[forValue] = newOption
 
 	if newOption != nil {
-		newOption.Components = append(option.Components, forValue)
+		newOption.Components = option.childComponents(forValue)
 
 		if option.Head == nil {
 			// first option's head is nil
@@ -80,7 +249,7 @@ func (option *OptionNode) AddConfig(forValue string, newConfigOption *OptionNode
 	option.ChildOptionMap[forValue] = newConfigOption
 
 	if newConfigOption != nil {
-		newConfigOption.Components = append(option.Components, forValue)
+		newConfigOption.Components = option.childComponents(forValue)
 
 		if option.Head == nil {
 			// first option's head is nil
@@ -91,6 +260,71 @@ func (option *OptionNode) AddConfig(forValue string, newConfigOption *OptionNode
 	}
 }
 
+// AddOptions adds the child options for their values, in sorted value order.
+// Unlike AddOption, the Components and Head of the children's descendants are updated too,
+// so the children may be built before they are added.
+func (option *OptionNode) AddOptions(children map[string]*OptionNode) {
+	values := make([]string, 0, len(children))
+	for value := range children {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		option.AddOption(value, children[value])
+		option.ChildOptionMap[value].relink()
+	}
+}
+
+// AddValues adds the child option for all of the given values, like when multiple schemes lead to the same question.
+// The first value gets the given option, the other values get a copy of it, so every child knows its own Components and Head.
+func (option *OptionNode) AddValues(envKeyChild *OptionNode, values ...string) {
+	children := map[string]*OptionNode{}
+	for i, value := range values {
+		child := envKeyChild
+		if i > 0 && envKeyChild != nil {
+			child = envKeyChild.Copy()
+		}
+		children[value] = child
+	}
+	option.AddOptions(children)
+}
+
+// relink sets the Components and Head of the option's descendants from the option's own Components and Head.
+func (option *OptionNode) relink() {
+	if option == nil {
+		return
+	}
+	if option.ChildOptionMap == nil {
+		// options copied from an option without values have no ChildOptionMap
+		option.ChildOptionMap = map[string]*OptionNode{}
+	}
+
+	head := option.Head
+	if head == nil {
+		head = option
+	}
+
+	for value, child := range option.ChildOptionMap {
+		if child == nil {
+			continue
+		}
+
+		child.Components = option.childComponents(value)
+		child.Head = head
+
+		child.relink()
+	}
+}
+
+// childComponents returns the Components of the option's child for the given value.
+// The option's Components are copied, appending to them directly could share the backing array between the siblings.
+func (option *OptionNode) childComponents(forValue string) []string {
+	components := make([]string, len(option.Components), len(option.Components)+1)
+	copy(components, option.Components)
+	return append(components, forValue)
+}
+
 // Parent ...
 func (option *OptionNode) Parent() (*OptionNode, string, bool) {
 	if option.Head == nil {
@@ -119,42 +353,128 @@ func (option *OptionNode) Child(components ...string) (*OptionNode, bool) {
 	return currentOption, true
 }
 
-// LastChilds ...
-func (option *OptionNode) LastChilds() []*OptionNode {
-	lastOptions := []*OptionNode{}
+// Walk visits the option and its descendants depth-first, the child options are visited in sorted value order.
+// fn gets the visited option and the values leading to it from the walk's root.
+// The walk stops at the first error returned by fn, and Walk returns that error.
+func (option *OptionNode) Walk(fn func(opt *OptionNode, path []string) error) error {
+	var walk func(opt *OptionNode, path []string) error
+	walk = func(opt *OptionNode, path []string) error {
+		if err := fn(opt, path); err != nil {
+			return err
+		}
 
-	var walk func(*OptionNode)
-	walk = func(opt *OptionNode) {
-		if len(opt.ChildOptionMap) == 0 {
-			lastOptions = append(lastOptions, opt)
-			return
+		values := make([]string, 0, len(opt.ChildOptionMap))
+		for value := range opt.ChildOptionMap {
+			values = append(values, value)
 		}
+		sort.Strings(values)
 
-		for _, childOption := range opt.ChildOptionMap {
+		for _, value := range values {
+			childOption := opt.ChildOptionMap[value]
 			if childOption == nil {
-				lastOptions = append(lastOptions, opt)
-				return
+				continue
 			}
 
-			if childOption.IsConfigOption() {
-				lastOptions = append(lastOptions, opt)
-				return
-			}
+			childPath := make([]string, len(path), len(path)+1)
+			copy(childPath, path)
+			childPath = append(childPath, value)
 
-			if childOption.IsEmpty() {
-				lastOptions = append(lastOptions, opt)
-				return
+			if err := walk(childOption, childPath); err != nil {
+				return err
 			}
+		}
+		return nil
+	}
+
+	return walk(option, []string{})
+}
 
-			walk(childOption)
+// Count returns the number of the option nodes (the config options not included), the number of their value branches,
+// and the number of the distinct configs in the option's subtree. A subtree reachable via multiple values is counted at each of them.
+func (option *OptionNode) Count() (options int, values int, configs int) {
+	configNames := map[string]bool{}
+	if err := option.Walk(func(opt *OptionNode, path []string) error {
+		if opt.IsConfigOption() {
+			configNames[opt.Config] = true
+			return nil
+		}
+		if opt.IsEmpty() {
+			return nil
 		}
+
+		options++
+		values += len(opt.ChildOptionMap)
+		return nil
+	}); err != nil {
+		// the walk function never returns an error
+		return 0, 0, 0
 	}
 
-	walk(option)
+	return options, values, len(configNames)
+}
+
+// isLastChild returns true if the option has no child options, or its child options hold the configs.
+func (option *OptionNode) isLastChild() bool {
+	if len(option.ChildOptionMap) == 0 {
+		return true
+	}
+
+	for _, childOption := range option.ChildOptionMap {
+		if childOption == nil || childOption.IsConfigOption() || childOption.IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
+
+// LastChilds returns the options having no child option, or having a child option which holds a config or is empty, in the order of Walk.
+// The walk goes on below a last child too: its other values can lead to further options, whose last childs are returned as well.
+func (option *OptionNode) LastChilds() []*OptionNode {
+	lastOptions := []*OptionNode{}
+
+	if err := option.Walk(func(opt *OptionNode, path []string) error {
+		if len(path) > 0 && (opt.IsConfigOption() || opt.IsEmpty()) {
+			return nil
+		}
+
+		if opt.isLastChild() {
+			lastOptions = append(lastOptions, opt)
+		}
+		return nil
+	}); err != nil {
+		// the walk function never returns an error
+		return []*OptionNode{}
+	}
 
 	return lastOptions
 }
 
+// errConfigFound stops the walk of PathToConfig.
+var errConfigFound = errors.New("config found")
+
+// PathToConfig returns the chain of options from the root (this option) to the option whose child carries the given config.
+// If the config is reachable via multiple paths, the first one is returned, in the (sorted value) order of Walk.
+func (option *OptionNode) PathToConfig(configName string) ([]*OptionNode, bool) {
+	var configPath []string
+	if err := option.Walk(func(opt *OptionNode, path []string) error {
+		if len(path) > 0 && opt.IsConfigOption() && opt.Config == configName {
+			configPath = path
+			return errConfigFound
+		}
+		return nil
+	}); err != errConfigFound {
+		return nil, false
+	}
+
+	options := []*OptionNode{option}
+	currentOption := option
+	for _, value := range configPath[:len(configPath)-1] {
+		currentOption = currentOption.ChildOptionMap[value]
+		options = append(options, currentOption)
+	}
+	return options, true
+}
+
 // RemoveConfigs ...
 func (option *OptionNode) RemoveConfigs() {
 	lastChilds := option.LastChilds()
@@ -165,18 +485,23 @@ func (option *OptionNode) RemoveConfigs() {
 	}
 }
 
-// AttachToLastChilds ...
+// AttachToLastChilds adds the option under the open values of the last child options (see LastChilds).
+// A value is open if it has no child option, or its child option is empty (like the ones left by RemoveConfigs).
+// The values leading to a config or to further options are kept as they are, the option is not attached under them.
 func (option *OptionNode) AttachToLastChilds(opt *OptionNode) {
 	childs := option.LastChilds()
 	for _, child := range childs {
 		values := child.GetValues()
 		for _, value := range values {
+			if valueOption := child.ChildOptionMap[value]; valueOption != nil && !valueOption.IsEmpty() {
+				continue
+			}
 			child.AddOption(value, opt)
 		}
 	}
 }
 
-// Copy ...
+// Copy returns a deep copy of the option, made by a round-trip of the canonical JSON form.
 func (option *OptionNode) Copy() *OptionNode {
 	bytes, err := json.Marshal(*option)
 	if err != nil {
@@ -191,15 +516,11 @@ func (option *OptionNode) Copy() *OptionNode {
 	return &optionCopy
 }
 
-// GetValues ...
+// GetValues returns the option's values in sorted order.
 func (option *OptionNode) GetValues() []string {
 	if option.Config != "" {
 		return []string{option.Config}
 	}
 
-	values := []string{}
-	for value := range option.ChildOptionMap {
-		values = append(values, value)
-	}
-	return values
+	return option.sortedValues()
 }
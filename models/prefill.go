@@ -0,0 +1,71 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// Prefill walks the option tree non-interactively, choosing the branches by the given answers (option env key -> value),
+// the options without env key are answered by their title. Returns the selected config and the app envs,
+// by the rules of the interactive option walk (see IsAutoSelected, IsUserInput, DefaultValue, NextOption and AppEnv):
+// - an auto selected option takes its only value, an answer has to match it
+// - a user input option takes the answer, the optional ones default to their default value
+// - a selector option takes the answer, which has to be one of its values
+// An option without answer fails the walk, naming its key.
+func (option *OptionNode) Prefill(answers map[string]string) (string, []envmanModels.EnvironmentItemModel, error) {
+	appEnvs := []envmanModels.EnvironmentItemModel{}
+
+	opt := option
+	for {
+		if opt.IsConfigOption() {
+			return opt.Config, appEnvs, nil
+		}
+
+		values := opt.GetValues()
+		if len(values) == 0 {
+			return "", nil, errors.New("no config selected")
+		}
+
+		key := opt.EnvKey
+		if key == "" {
+			key = opt.Title
+		}
+		answer, answered := answers[key]
+
+		selectedValue := answer
+		switch {
+		case opt.IsAutoSelected():
+			if answered && answer != values[0] {
+				return "", nil, fmt.Errorf("invalid answer for: %s (%s), the only value is: %s", key, answer, values[0])
+			}
+			selectedValue = values[0]
+		case opt.IsUserInput():
+			if !answered && opt.Type == TypeOptionalUserInput {
+				selectedValue = opt.DefaultValue()
+			} else if !answered {
+				return "", nil, fmt.Errorf("missing answer for: %s", key)
+			}
+		default:
+			if !answered {
+				return "", nil, fmt.Errorf("missing answer for: %s, values: %s", key, strings.Join(values, ", "))
+			}
+			if !sliceutil.IsStringInSlice(answer, values) {
+				return "", nil, fmt.Errorf("invalid answer for: %s (%s), values: %s", key, answer, strings.Join(values, ", "))
+			}
+		}
+
+		if appEnv, ok := opt.AppEnv(selectedValue); ok {
+			appEnvs = append(appEnvs, appEnv)
+		}
+
+		next, ok := opt.NextOption(selectedValue)
+		if !ok {
+			return "", nil, errors.New("no config selected")
+		}
+		opt = next
+	}
+}
@@ -0,0 +1,170 @@
+package models
+
+import (
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testPrefillOptions() *OptionNode {
+	projectOption := NewOption("Project (or Workspace) path", "BITRISE_PROJECT_PATH")
+
+	schemeOption := NewOption("Scheme name", "BITRISE_SCHEME")
+	projectOption.AddOption("App.xcodeproj", schemeOption)
+
+	simulatorOption := NewUserInputOption("Simulator OS version", "SIMULATOR_OS_VERSION", true)
+	schemeOption.AddOption("App", simulatorOption)
+	simulatorOption.AddConfig("latest", NewConfigOption("ios-test-config"))
+
+	schemeOption.AddConfig("AppUITests", NewConfigOption("ios-ui-test-config"))
+
+	otherSchemeOption := NewOption("Scheme name", "BITRISE_SCHEME")
+	projectOption.AddOption("Other.xcodeproj", otherSchemeOption)
+	otherSchemeOption.AddConfig("Other", NewConfigOption("ios-other-config"))
+
+	return projectOption
+}
+
+func TestPrefill(t *testing.T) {
+	t.Log("complete answers")
+	{
+		config, appEnvs, err := testPrefillOptions().Prefill(map[string]string{
+			"BITRISE_PROJECT_PATH": "App.xcodeproj",
+			"BITRISE_SCHEME":       "App",
+			"SIMULATOR_OS_VERSION": "13.0",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ios-test-config", config)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"BITRISE_PROJECT_PATH": "App.xcodeproj"},
+			{"BITRISE_SCHEME": "App"},
+			{"SIMULATOR_OS_VERSION": "13.0"},
+		}, appEnvs)
+	}
+
+	t.Log("optional user input defaults to the prefilled value")
+	{
+		config, appEnvs, err := testPrefillOptions().Prefill(map[string]string{
+			"BITRISE_PROJECT_PATH": "App.xcodeproj",
+			"BITRISE_SCHEME":       "App",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ios-test-config", config)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"BITRISE_PROJECT_PATH": "App.xcodeproj"},
+			{"BITRISE_SCHEME": "App"},
+			{"SIMULATOR_OS_VERSION": "latest"},
+		}, appEnvs)
+	}
+
+	t.Log("single value is selected automatically")
+	{
+		config, appEnvs, err := testPrefillOptions().Prefill(map[string]string{
+			"BITRISE_PROJECT_PATH": "Other.xcodeproj",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "ios-other-config", config)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"BITRISE_PROJECT_PATH": "Other.xcodeproj"},
+			{"BITRISE_SCHEME": "Other"},
+		}, appEnvs)
+	}
+
+	t.Log("info option is selected automatically")
+	{
+		bundleIDOption := NewInfoOption("Bundle ID", "BITRISE_BUNDLE_ID")
+		bundleIDOption.AddConfig("io.bitrise.app", NewConfigOption("ios-config"))
+
+		config, appEnvs, err := bundleIDOption.Prefill(map[string]string{})
+		require.NoError(t, err)
+		require.Equal(t, "ios-config", config)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{{"BITRISE_BUNDLE_ID": "io.bitrise.app"}}, appEnvs)
+
+		_, _, err = bundleIDOption.Prefill(map[string]string{"BITRISE_BUNDLE_ID": "io.bitrise.other"})
+		require.EqualError(t, err, "invalid answer for: BITRISE_BUNDLE_ID (io.bitrise.other), the only value is: io.bitrise.app")
+	}
+
+	t.Log("partial answers")
+	{
+		_, _, err := testPrefillOptions().Prefill(map[string]string{
+			"BITRISE_PROJECT_PATH": "App.xcodeproj",
+		})
+		require.EqualError(t, err, "missing answer for: BITRISE_SCHEME, values: App, AppUITests")
+	}
+
+	t.Log("invalid answer")
+	{
+		_, _, err := testPrefillOptions().Prefill(map[string]string{
+			"BITRISE_PROJECT_PATH": "Missing.xcodeproj",
+		})
+		require.EqualError(t, err, "invalid answer for: BITRISE_PROJECT_PATH (Missing.xcodeproj), values: App.xcodeproj, Other.xcodeproj")
+	}
+
+	t.Log("required user input")
+	{
+		option := NewUserInputOption("Module", "MODULE", false)
+		option.AddConfig("_", NewConfigOption("android-config"))
+
+		_, _, err := option.Prefill(map[string]string{})
+		require.EqualError(t, err, "missing answer for: MODULE")
+
+		config, appEnvs, err := option.Prefill(map[string]string{"MODULE": "app"})
+		require.NoError(t, err)
+		require.Equal(t, "android-config", config)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{{"MODULE": "app"}}, appEnvs)
+	}
+}
+
+func TestOptionWalkRules(t *testing.T) {
+	t.Log("info option and single value selector are auto selected")
+	{
+		infoOption := NewInfoOption("Bundle ID", "BITRISE_BUNDLE_ID")
+		infoOption.AddConfig("io.bitrise.app", NewConfigOption("ios-config"))
+		require.True(t, infoOption.IsAutoSelected())
+		require.False(t, infoOption.IsUserInput())
+
+		schemeOption := NewOption("Scheme name", "BITRISE_SCHEME")
+		schemeOption.AddConfig("App", NewConfigOption("ios-config"))
+		require.True(t, schemeOption.IsAutoSelected())
+		require.Equal(t, "App", schemeOption.DefaultValue())
+	}
+
+	t.Log("user inputs are asked, the optional ones offer their detected value")
+	{
+		moduleOption := NewUserInputOption("Module", "MODULE", false)
+		moduleOption.AddConfig("_", NewConfigOption("android-config"))
+		require.False(t, moduleOption.IsAutoSelected())
+		require.True(t, moduleOption.IsUserInput())
+		require.Equal(t, "", moduleOption.DefaultValue())
+
+		next, ok := moduleOption.NextOption("app")
+		require.True(t, ok)
+		require.Equal(t, "android-config", next.Config)
+
+		simulatorOption := NewUserInputOption("Simulator OS version", "SIMULATOR_OS_VERSION", true)
+		simulatorOption.AddConfig("latest", NewConfigOption("ios-test-config"))
+		require.True(t, simulatorOption.IsUserInput())
+		require.Equal(t, "latest", simulatorOption.DefaultValue())
+
+		_, ok = simulatorOption.AppEnv("")
+		require.False(t, ok)
+		appEnv, ok := simulatorOption.AppEnv("13.0")
+		require.True(t, ok)
+		require.Equal(t, envmanModels.EnvironmentItemModel{"SIMULATOR_OS_VERSION": "13.0"}, appEnv)
+	}
+
+	t.Log("selector continues on the selected value's child")
+	{
+		options := testPrefillOptions()
+		require.False(t, options.IsAutoSelected())
+		require.False(t, options.IsUserInput())
+
+		next, ok := options.NextOption("Other.xcodeproj")
+		require.True(t, ok)
+		require.Equal(t, []string{"Other"}, next.GetValues())
+
+		_, ok = options.NextOption("Missing.xcodeproj")
+		require.False(t, ok)
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+)
+
+var (
+	stepIDRegexp      = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	stepVersionRegexp = regexp.MustCompile(`^\d+(\.\d+){0,2}([-+][0-9A-Za-z.-]+)?$`)
+	// https://github.com/org/step.git, ssh://git@github.com/org/step.git, git@github.com:org/step.git or file:///path/to/step
+	gitURLRegexp = regexp.MustCompile(`^((https?|ssh|git|file)://\S+|[A-Za-z0-9_.-]+@[A-Za-z0-9_.-]+:\S+)$`)
+)
+
+// ValidateStepReference checks the format of a step reference (the key of a workflow's step list item):
+// - a step of the default step library: stepid or stepid@version
+// - a step of another step library: steplib-url::stepid@version
+// - a local step: path::./path/to/step
+// - a git step: git::https://github.com/org/step.git@branch (or _::url@version for a step library independent step)
+// The reachability of the step is not checked.
+func ValidateStepReference(reference, defaultStepLibSource string) error {
+	if reference == "" {
+		return fmt.Errorf("empty step reference")
+	}
+	if strings.ContainsAny(reference, " \t\n") {
+		return fmt.Errorf("step reference contains whitespace")
+	}
+	if strings.Count(reference, "::") > 1 {
+		return fmt.Errorf("step reference contains more than one '::' separator")
+	}
+
+	if strings.HasPrefix(reference, "path::") {
+		if strings.TrimPrefix(reference, "path::") == "" {
+			return fmt.Errorf("local step reference without path")
+		}
+		return nil
+	}
+
+	stepIDData, err := bitriseModels.CreateStepIDDataFromString(reference, defaultStepLibSource)
+	if err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(reference, "@") {
+		return fmt.Errorf("step reference with empty version")
+	}
+
+	switch stepIDData.SteplibSource {
+	case "git", "_":
+		if !gitURLRegexp.MatchString(stepIDData.IDorURI) {
+			return fmt.Errorf("invalid git url (%s)", stepIDData.IDorURI)
+		}
+	default:
+		if !gitURLRegexp.MatchString(stepIDData.SteplibSource) {
+			return fmt.Errorf("invalid step library source (%s)", stepIDData.SteplibSource)
+		}
+		if !stepIDRegexp.MatchString(stepIDData.IDorURI) {
+			return fmt.Errorf("invalid step ID (%s)", stepIDData.IDorURI)
+		}
+		if stepIDData.Version != "" && !stepVersionRegexp.MatchString(stepIDData.Version) {
+			return fmt.Errorf("invalid step version (%s)", stepIDData.Version)
+		}
+	}
+	return nil
+}
+
+// StepReferenceWarnings returns a warning for each malformed step reference of the config's workflows (see ValidateStepReference),
+// ordered by workflow ID and step position.
+// The steps without step library source are resolved from the bitrise steplib, if the config has no default step library source.
+func StepReferenceWarnings(config bitriseModels.BitriseDataModel) Warnings {
+	defaultStepLibSource := config.DefaultStepLibSource
+	if defaultStepLibSource == "" {
+		defaultStepLibSource = defaultSteplibSource
+	}
+
+	workflowIDs := make([]string, 0, len(config.Workflows))
+	for workflowID := range config.Workflows {
+		workflowIDs = append(workflowIDs, workflowID)
+	}
+	sort.Strings(workflowIDs)
+
+	warnings := Warnings{}
+	for _, workflowID := range workflowIDs {
+		for i, stepListItem := range config.Workflows[workflowID].Steps {
+			for reference := range stepListItem {
+				if err := ValidateStepReference(reference, defaultStepLibSource); err != nil {
+					warnings = append(warnings, fmt.Sprintf("Step #%d (%s) of workflow %s is malformed: %s", i+1, reference, workflowID, err))
+				}
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	stepmanModels "github.com/bitrise-io/stepman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStepReference(t *testing.T) {
+	t.Log("valid references")
+	{
+		for _, reference := range []string{
+			"script",
+			"script@1",
+			"git-clone@4.0.14",
+			"xcode-test@2.1.1-beta",
+			"https://github.com/bitrise-io/bitrise-steplib.git::script@1.1.5",
+			"git@github.com:my-org/steplib.git::my-step@1.0",
+			"path::./steps/my-step",
+			"path::~/steps/my-step",
+			"git::https://github.com/bitrise-io/steps-timestamp.git@master",
+			"git::https://github.com/bitrise-io/steps-timestamp.git",
+			"git::git@github.com:bitrise-io/steps-timestamp.git@develop",
+			"_::https://github.com/bitrise-io/steps-bash-script.git@2.0.0",
+		} {
+			require.NoError(t, ValidateStepReference(reference, defaultSteplibSource), reference)
+		}
+	}
+
+	t.Log("malformed references")
+	{
+		for reference, expectedErr := range map[string]string{
+			"":                       "empty step reference",
+			"script @1":              "step reference contains whitespace",
+			"path::":                 "local step reference without path",
+			"script@":                "step reference with empty version",
+			"script@v1.1":            "invalid step version (v1.1)",
+			"script@1.1.5.2":         "invalid step version (1.1.5.2)",
+			"-script@1":              "invalid step ID (-script)",
+			"scr!pt@1":               "invalid step ID (scr!pt)",
+			"git::github.com/step@1": "invalid git url (github.com/step)",
+			"my-steplib::script@1":   "invalid step library source (my-steplib)",
+			"git::https://github.com/org/step.git::master": "step reference contains more than one '::' separator",
+		} {
+			require.EqualError(t, ValidateStepReference(reference, defaultSteplibSource), expectedErr, reference)
+		}
+	}
+}
+
+func TestStepReferenceWarnings(t *testing.T) {
+	config := bitriseModels.BitriseDataModel{
+		Workflows: map[string]bitriseModels.WorkflowModel{
+			"primary": {
+				Steps: []bitriseModels.StepListItemModel{
+					{"git-clone@4": stepmanModels.StepModel{}},
+					{"scirpt@@1": stepmanModels.StepModel{}},
+				},
+			},
+			"deploy": {
+				Steps: []bitriseModels.StepListItemModel{
+					{"path::": stepmanModels.StepModel{}},
+					{"deploy-to-bitrise-io@1": stepmanModels.StepModel{}},
+				},
+			},
+		},
+	}
+
+	require.Equal(t, Warnings{
+		"Step #1 (path::) of workflow deploy is malformed: local step reference without path",
+		"Step #2 (scirpt@@1) of workflow primary is malformed: invalid step ID (scirpt@)",
+	}, StepReferenceWarnings(config))
+
+	config.Workflows["primary"].Steps[1] = bitriseModels.StepListItemModel{"script@1": stepmanModels.StepModel{}}
+	config.Workflows["deploy"].Steps[0] = bitriseModels.StepListItemModel{"path::./steps/deploy": stepmanModels.StepModel{}}
+	require.Equal(t, Warnings{}, StepReferenceWarnings(config))
+}
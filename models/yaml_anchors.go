@@ -0,0 +1,88 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+var (
+	// an anchor (&name) or alias (*name) token, at the start of a value, a sequence item or a flow collection item
+	yamlAnchorRegexp = regexp.MustCompile(`(?:^|[\s\[{,])([&*][^\s,\[\]{}]+)`)
+	// a merge key: <<: *name
+	yamlMergeKeyRegexp = regexp.MustCompile(`(?:^|[\s{,])<<\s*:`)
+	// a block scalar indicator ending the line, like: content: |, content: >-
+	yamlBlockScalarRegexp = regexp.MustCompile(`[:\-]\s*[|>][0-9+\-]*\s*$`)
+	// the single and double quoted scalars, their content is not inspected
+	yamlQuotedRegexp = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^']|'')*'`)
+	// the opening quote of a quoted scalar continued in the next lines
+	yamlOpenQuoteRegexp = regexp.MustCompile(`(?:^|[\s\[{,:])(["'])`)
+	// the closing quote of a multiline double or single quoted scalar
+	yamlDoubleQuoteEndRegexp = regexp.MustCompile(`^(?:[^"\\]|\\.)*"`)
+	yamlSingleQuoteEndRegexp = regexp.MustCompile(`^(?:[^']|'')*'`)
+)
+
+// YAMLAnchors returns the anchors (&name), aliases (*name) and merge keys (<<) of the YAML content, in order of appearance.
+// These are expanded when the content is unmarshalled, so they are lost if the content is marshalled again.
+// The block scalars (like the script contents), the quoted scalars (including the multiline ones, like the long workflow descriptions)
+// and the comments are skipped.
+func YAMLAnchors(content string) []string {
+	tokens := []string{}
+	add := func(token string) {
+		if !sliceutil.IsStringInSlice(token, tokens) {
+			tokens = append(tokens, token)
+		}
+	}
+
+	blockScalarIndent := -1
+	var quoteEndRegexp *regexp.Regexp
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if blockScalarIndent != -1 {
+			if trimmed == "" || indent > blockScalarIndent {
+				continue
+			}
+			blockScalarIndent = -1
+		}
+
+		if quoteEndRegexp != nil {
+			end := quoteEndRegexp.FindStringIndex(line)
+			if end == nil {
+				continue
+			}
+			line = line[end[1]:]
+			quoteEndRegexp = nil
+		}
+
+		line = yamlQuotedRegexp.ReplaceAllString(line, "_")
+		commentIdx := -1
+		if idx := strings.Index(line, "#"); idx != -1 && (idx == 0 || line[idx-1] == ' ' || line[idx-1] == '\t') {
+			commentIdx = idx
+		}
+		if match := yamlOpenQuoteRegexp.FindStringSubmatchIndex(line); match != nil && (commentIdx == -1 || match[2] < commentIdx) {
+			if line[match[2]] == '"' {
+				quoteEndRegexp = yamlDoubleQuoteEndRegexp
+			} else {
+				quoteEndRegexp = yamlSingleQuoteEndRegexp
+			}
+			line = line[:match[2]]
+		} else if commentIdx != -1 {
+			line = line[:commentIdx]
+		}
+
+		if yamlMergeKeyRegexp.MatchString(line) {
+			add("<<")
+		}
+		for _, match := range yamlAnchorRegexp.FindAllStringSubmatch(line, -1) {
+			add(match[1])
+		}
+
+		if yamlBlockScalarRegexp.MatchString(line) {
+			blockScalarIndent = indent
+		}
+	}
+	return tokens
+}
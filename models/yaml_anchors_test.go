@@ -0,0 +1,69 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestYAMLAnchors(t *testing.T) {
+	t.Log("anchors, aliases and merge keys")
+	{
+		content := `format_version: "5"
+app:
+  envs:
+  - &project_path
+    BITRISE_PROJECT_PATH: App.xcodeproj
+workflows:
+  _setup: &setup
+    steps:
+    - activate-ssh-key@4: {}
+    - git-clone@4: {}
+  primary:
+    <<: *setup
+    envs:
+    - *project_path
+  deploy: {<<: *setup, title: Deploy}
+`
+		require.Equal(t, []string{"&project_path", "&setup", "<<", "*setup", "*project_path"}, YAMLAnchors(content))
+	}
+
+	t.Log("scripts, quoted scalars and comments are skipped")
+	{
+		content := `format_version: "5"
+# &not_an_anchor
+workflows:
+  primary:
+    steps:
+    - script@1:
+        title: "Run *all* tests & lint"
+        inputs:
+        - content: |-
+            #!/bin/bash
+            set -ex
+            ls *.apk && echo &done
+            cat <<: EOF
+        - pattern: '*.ipa'
+    - deploy-to-bitrise-io@1:
+        inputs:
+        - deploy_path: $BITRISE_DEPLOY_DIR/*.apk # *comment
+`
+		require.Equal(t, []string{}, YAMLAnchors(content))
+	}
+
+	t.Log("multiline quoted scalars are skipped")
+	{
+		content := `format_version: "5"
+workflows:
+  deploy: &deploy
+    description: "## Configure the deploy workflow\n\n1. Open the **Workflow** tab\n1.
+      Click on **Code Signing** tab, \"**Save**\"\n"
+    title: 'Deploy the *signed*
+      app, it''s *done*'
+    steps:
+    - activate-ssh-key@4: {}
+  primary: *deploy
+`
+		require.Equal(t, []string{"&deploy", "*deploy"}, YAMLAnchors(content))
+	}
+}
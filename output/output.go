@@ -3,11 +3,15 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"gopkg.in/yaml.v2"
 
+	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/fileutil"
 )
 
@@ -21,6 +25,8 @@ const (
 	JSONFormat
 	// YAMLFormat ...
 	YAMLFormat
+	// EnvFormat writes a list of envman environment items as a dotenv file (KEY=value per line).
+	EnvFormat
 )
 
 // ParseFormat ...
@@ -32,6 +38,8 @@ func ParseFormat(format string) (Format, error) {
 		return JSONFormat, nil
 	case "yaml":
 		return YAMLFormat, nil
+	case "env":
+		return EnvFormat, nil
 	}
 
 	var f Format
@@ -47,11 +55,135 @@ func (format Format) String() string {
 		return "json"
 	case YAMLFormat:
 		return "yaml"
+	case EnvFormat:
+		return "env"
 	}
 
 	return "unknown"
 }
 
+const (
+	// ScannerPlaceholder ...
+	ScannerPlaceholder = "{scanner}"
+	// ConfigPlaceholder ...
+	ConfigPlaceholder = "{config}"
+	// FormatPlaceholder ...
+	FormatPlaceholder = "{format}"
+)
+
+var namePlaceholderRegexp = regexp.MustCompile(`{[^{}]*}`)
+
+// ValidateNameTemplate checks if the output file name template contains only known placeholders
+// and does not point outside of the output directory.
+func ValidateNameTemplate(template string) error {
+	if template == "" {
+		return fmt.Errorf("empty output name template")
+	}
+
+	for _, placeholder := range namePlaceholderRegexp.FindAllString(template, -1) {
+		switch placeholder {
+		case ScannerPlaceholder, ConfigPlaceholder, FormatPlaceholder:
+		default:
+			return fmt.Errorf("unknown placeholder (%s) in output name template: %s", placeholder, template)
+		}
+	}
+
+	if strings.ContainsAny(namePlaceholderRegexp.ReplaceAllString(template, ""), "{}") {
+		return fmt.Errorf("unbalanced braces in output name template: %s", template)
+	}
+
+	return validateRelativeName(template)
+}
+
+// ResolveNameTemplate replaces the placeholders of a validated output file name template.
+func ResolveNameTemplate(template, scanner, config string, format Format) (string, error) {
+	name := strings.NewReplacer(
+		ScannerPlaceholder, scanner,
+		ConfigPlaceholder, config,
+		FormatPlaceholder, format.String(),
+	).Replace(template)
+
+	if err := validateRelativeName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+func validateRelativeName(name string) error {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return fmt.Errorf("output name should be relative to the output dir: %s", name)
+	}
+	for _, component := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == filepath.Separator }) {
+		if component == ".." {
+			return fmt.Errorf("output name should not point outside of the output dir: %s", name)
+		}
+	}
+	return nil
+}
+
+var dotenvKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// dotenvPlainValueRegexp matches the values which can be written without quoting.
+var dotenvPlainValueRegexp = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+
+// MarshalDotenv returns the environment items in dotenv format, one KEY=value per line.
+// Values containing special characters are double quoted, with the \, ", $, ` characters and new lines escaped.
+func MarshalDotenv(envs []envmanModels.EnvironmentItemModel) (string, error) {
+	lines := []string{}
+	for _, env := range envs {
+		key, value, err := env.GetKeyValuePair()
+		if err != nil {
+			return "", fmt.Errorf("invalid environment item (%v), error: %s", env, err)
+		}
+		if !dotenvKeyRegexp.MatchString(key) {
+			return "", fmt.Errorf("invalid environment key: %s", key)
+		}
+
+		lines = append(lines, key+"="+quoteDotenvValue(value))
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+func quoteDotenvValue(value string) string {
+	if dotenvPlainValueRegexp.MatchString(value) {
+		return value
+	}
+
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"`", "\\`",
+		"\n", `\n`,
+		"\r", `\r`,
+	).Replace(value) + `"`
+}
+
+// EnsureWritableDir creates the output directory if it does not exist and checks if files can be written into it,
+// so the output errors are reported before scanning the project and asking for the inputs.
+func EnsureWritableDir(pth string) error {
+	if info, err := os.Stat(pth); err != nil {
+		if err := os.MkdirAll(pth, 0700); err != nil {
+			return fmt.Errorf("failed to create %s, error: %s", pth, err)
+		}
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s exists, but it is a file, not a directory", pth)
+	}
+
+	file, err := ioutil.TempFile(pth, ".bitrise-init-")
+	if err != nil {
+		return fmt.Errorf("%s is not writable, error: %s", pth, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("%s is not writable, error: %s", pth, err)
+	}
+	return os.Remove(file.Name())
+}
+
 // WriteToFile ...
 func WriteToFile(a interface{}, format Format, pth string) (string, error) {
 	str := ""
@@ -75,11 +207,26 @@ func WriteToFile(a interface{}, format Format, pth string) (string, error) {
 		}
 		str = string(bytes)
 		ext = ".yml"
+	case EnvFormat:
+		envs, ok := a.([]envmanModels.EnvironmentItemModel)
+		if !ok {
+			return "", fmt.Errorf("%s format requires environment items, got: %T", format, a)
+		}
+		var err error
+		str, err = MarshalDotenv(envs)
+		if err != nil {
+			return "", err
+		}
+		ext = ".env"
 	default:
 		return "", fmt.Errorf("not a valid format: %s", format)
 	}
 
 	fileExt := filepath.Ext(pth)
+	if format == YAMLFormat && strings.ToLower(fileExt) == ".yaml" {
+		// the other extension of YAML files is kept
+		ext = fileExt
+	}
 	if fileExt != "" {
 		pth = strings.TrimSuffix(pth, fileExt)
 	}
@@ -111,6 +258,16 @@ func Print(a interface{}, format Format) error {
 			return err
 		}
 		str = string(bytes)
+	case EnvFormat:
+		envs, ok := a.([]envmanModels.EnvironmentItemModel)
+		if !ok {
+			return fmt.Errorf("%s format requires environment items, got: %T", format, a)
+		}
+		var err error
+		str, err = MarshalDotenv(envs)
+		if err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("not a valid format: %s", format)
 	}
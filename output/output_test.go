@@ -0,0 +1,175 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNameTemplate(t *testing.T) {
+	t.Log("valid templates")
+	{
+		for _, template := range []string{
+			"result",
+			"bitrise-init-{scanner}-{config}",
+			"{scanner}/{config}.{format}",
+		} {
+			require.NoError(t, ValidateNameTemplate(template), template)
+		}
+	}
+
+	t.Log("invalid templates")
+	{
+		for _, template := range []string{
+			"",
+			"{project}-{config}",
+			"{scanner",
+			"../{config}",
+			"{scanner}/../../{config}",
+			"/tmp/{config}",
+		} {
+			require.Error(t, ValidateNameTemplate(template), template)
+		}
+	}
+}
+
+func TestResolveNameTemplate(t *testing.T) {
+	t.Log("replaces placeholders")
+	{
+		name, err := ResolveNameTemplate("bitrise-init-{scanner}-{config}.{format}", "android", "android-config", YAMLFormat)
+		require.NoError(t, err)
+		require.Equal(t, "bitrise-init-android-android-config.yaml", name)
+	}
+
+	t.Log("resolved name can not point outside of the output dir")
+	{
+		_, err := ResolveNameTemplate("{scanner}/{config}", "..", "..", JSONFormat)
+		require.Error(t, err)
+	}
+}
+
+func TestMarshalDotenv(t *testing.T) {
+	t.Log("plain and quoted values")
+	{
+		envs := []envmanModels.EnvironmentItemModel{
+			{"PROJECT_PATH": "ios/App.xcworkspace"},
+			{"EMPTY": ""},
+			{"SCHEME": "My App"},
+			{"SPECIAL": `a"b\c$d` + "`e`"},
+			{"MULTILINE": "first\nsecond"},
+		}
+
+		str, err := MarshalDotenv(envs)
+		require.NoError(t, err)
+		require.Equal(t, `PROJECT_PATH=ios/App.xcworkspace
+EMPTY=
+SCHEME="My App"
+SPECIAL="a\"b\\c\$d\`+"`"+`e\`+"`"+`"
+MULTILINE="first\nsecond"
+`, str)
+	}
+
+	t.Log("no envs")
+	{
+		str, err := MarshalDotenv([]envmanModels.EnvironmentItemModel{})
+		require.NoError(t, err)
+		require.Equal(t, "", str)
+	}
+
+	t.Log("invalid key")
+	{
+		_, err := MarshalDotenv([]envmanModels.EnvironmentItemModel{{"MY-KEY": "value"}})
+		require.Error(t, err)
+	}
+}
+
+func TestWriteToFileEnvFormat(t *testing.T) {
+	t.Log("requires environment items")
+	{
+		_, err := WriteToFile(map[string]string{"KEY": "value"}, EnvFormat, "app")
+		require.Error(t, err)
+	}
+}
+
+func TestWriteToFileExtension(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__output__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	t.Log("the .yaml extension is kept")
+	{
+		pth, err := WriteToFile(map[string]string{"KEY": "value"}, YAMLFormat, filepath.Join(tmpDir, "envs.yaml"))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(tmpDir, "envs.yaml"), pth)
+
+		exist, err := pathutil.IsPathExists(pth)
+		require.NoError(t, err)
+		require.True(t, exist)
+	}
+
+	t.Log("the extension of an other format is replaced")
+	{
+		pth, err := WriteToFile(map[string]string{"KEY": "value"}, YAMLFormat, filepath.Join(tmpDir, "envs.json"))
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(tmpDir, "envs.yml"), pth)
+	}
+}
+
+func TestEnsureWritableDir(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__output__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	t.Log("creates the missing dir")
+	{
+		pth := filepath.Join(tmpDir, "results", "nested")
+		require.NoError(t, EnsureWritableDir(pth))
+
+		exist, err := pathutil.IsDirExists(pth)
+		require.NoError(t, err)
+		require.True(t, exist)
+
+		files, err := ioutil.ReadDir(pth)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(files))
+	}
+
+	t.Log("file where the dir is expected")
+	{
+		pth := filepath.Join(tmpDir, "file")
+		require.NoError(t, fileutil.WriteStringToFile(pth, ""))
+
+		err := EnsureWritableDir(pth)
+		require.Error(t, err)
+		require.Equal(t, pth+" exists, but it is a file, not a directory", err.Error())
+
+		err = EnsureWritableDir(filepath.Join(pth, "results"))
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "failed to create "+filepath.Join(pth, "results")), err.Error())
+	}
+
+	t.Log("not writable dir")
+	{
+		pth := filepath.Join(tmpDir, "read-only")
+		require.NoError(t, os.MkdirAll(pth, 0500))
+
+		// the permissions are not enforced for root
+		if os.Geteuid() != 0 {
+			err := EnsureWritableDir(pth)
+			require.Error(t, err)
+			require.True(t, strings.HasPrefix(err.Error(), pth+" is not writable"), err.Error())
+		}
+		require.NoError(t, os.Chmod(pth, 0700))
+	}
+}
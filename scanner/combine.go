@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"fmt"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+)
+
+// AskForPlatformConfigs asks for the options of every detected platform (in alphabetical order),
+// used to combine the configs of the platforms into one config.
+func AskForPlatformConfigs(scanResult models.ScanResultModel) ([]SelectedConfig, error) {
+	selectedConfigs := []SelectedConfig{}
+	for _, platform := range detectedPlatforms(scanResult) {
+		log.Infof("%s:", platform)
+
+		configPth, appEnvs, err := AskForOptions(scanResult.ScannerToOptionRoot[platform])
+		if err != nil {
+			return nil, err
+		}
+
+		selected, err := newSelectedConfig(scanResult, platform, configPth, appEnvs)
+		if err != nil {
+			return nil, err
+		}
+		selectedConfigs = append(selectedConfigs, selected)
+	}
+	return selectedConfigs, nil
+}
+
+// CombineConfigs merges the selected configs into one config: the workflows, the trigger map items and the app envs of the configs are merged.
+// The workflows are expected to be namespaced (see NamespaceWorkflows), workflow ID collisions are returned as an error.
+// An app env (or a trigger) defined by more than one config is kept once, the first one wins, the conflicting values are reported in the warnings.
+// The malformed step references of the combined config are reported in the warnings too.
+func CombineConfigs(selectedConfigs []SelectedConfig) (bitriseModels.BitriseDataModel, models.Warnings, error) {
+	if len(selectedConfigs) == 0 {
+		return bitriseModels.BitriseDataModel{}, nil, fmt.Errorf("no config to combine")
+	}
+
+	combined := bitriseModels.BitriseDataModel{
+		FormatVersion:        selectedConfigs[0].Config.FormatVersion,
+		DefaultStepLibSource: selectedConfigs[0].Config.DefaultStepLibSource,
+		ProjectType:          selectedConfigs[0].Config.ProjectType,
+		Workflows:            map[string]bitriseModels.WorkflowModel{},
+	}
+	warnings := models.Warnings{}
+
+	workflowToPlatform := map[string]string{}
+	envKeyToValue := map[string]string{}
+	envKeyToPlatform := map[string]string{}
+	for _, selected := range selectedConfigs {
+		config := selected.Config
+
+		if config.ProjectType != combined.ProjectType {
+			combined.ProjectType = scanners.CustomProjectType
+		}
+
+		for workflowID, workflow := range config.Workflows {
+			if platform, ok := workflowToPlatform[workflowID]; ok {
+				return bitriseModels.BitriseDataModel{}, warnings, fmt.Errorf("workflow (%s) is defined by both %s and %s configs, the workflows have to be namespaced", workflowID, platform, selected.Platform)
+			}
+			workflowToPlatform[workflowID] = selected.Platform
+			combined.Workflows[workflowID] = workflow
+		}
+
+		for _, item := range config.TriggerMap {
+			if workflowID, ok := triggerWorkflow(combined.TriggerMap, item); ok {
+				warnings = append(warnings, fmt.Sprintf("Trigger (%s) of %s is already mapped to %s, %s is not triggered", triggerDescription(item), selected.Platform, workflowID, item.WorkflowID))
+				continue
+			}
+			combined.TriggerMap = append(combined.TriggerMap, item)
+		}
+
+		for _, env := range config.App.Environments {
+			key, value, err := env.GetKeyValuePair()
+			if err != nil {
+				return bitriseModels.BitriseDataModel{}, warnings, fmt.Errorf("invalid app env in %s config, error: %s", selected.Platform, err)
+			}
+
+			if firstValue, ok := envKeyToValue[key]; ok {
+				if firstValue != value {
+					warnings = append(warnings, fmt.Sprintf("Env key conflict: %s is %s in %s and %s in %s config, the first one is used", key, firstValue, envKeyToPlatform[key], value, selected.Platform))
+				}
+				continue
+			}
+			envKeyToValue[key] = value
+			envKeyToPlatform[key] = selected.Platform
+			combined.App.Environments = append(combined.App.Environments, env)
+		}
+	}
+
+	warnings = append(warnings, models.StepReferenceWarnings(combined)...)
+
+	return combined, warnings, nil
+}
+
+// triggerWorkflow returns the workflow of the trigger map item with the same trigger as the given item.
+func triggerWorkflow(triggerMap bitriseModels.TriggerMapModel, item bitriseModels.TriggerMapItemModel) (string, bool) {
+	for _, mapped := range triggerMap {
+		if mapped.PushBranch == item.PushBranch &&
+			mapped.PullRequestSourceBranch == item.PullRequestSourceBranch &&
+			mapped.PullRequestTargetBranch == item.PullRequestTargetBranch &&
+			mapped.Tag == item.Tag &&
+			mapped.Pattern == item.Pattern {
+			return mapped.WorkflowID, true
+		}
+	}
+	return "", false
+}
+
+func triggerDescription(item bitriseModels.TriggerMapItemModel) string {
+	switch {
+	case item.PushBranch != "":
+		return "push_branch: " + item.PushBranch
+	case item.PullRequestSourceBranch != "":
+		return "pull_request_source_branch: " + item.PullRequestSourceBranch
+	case item.PullRequestTargetBranch != "":
+		return "pull_request_target_branch: " + item.PullRequestTargetBranch
+	case item.Tag != "":
+		return "tag: " + item.Tag
+	}
+	return "pattern: " + item.Pattern
+}
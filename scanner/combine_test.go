@@ -0,0 +1,83 @@
+package scanner
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/android"
+	"github.com/bitrise-core/bitrise-init/scanners/fastlane"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/stretchr/testify/require"
+)
+
+func testSelectedConfig(t *testing.T, platform, namespace string, configMap models.BitriseConfigMap, appEnvs ...envmanModels.EnvironmentItemModel) SelectedConfig {
+	require.Equal(t, 1, len(configMap))
+
+	for configName, configStr := range configMap {
+		var config bitriseModels.BitriseDataModel
+		require.NoError(t, yaml.Unmarshal([]byte(configStr), &config))
+		models.NamespaceWorkflows(&config, namespace)
+		config.App.Environments = append(config.App.Environments, appEnvs...)
+
+		return SelectedConfig{Platform: platform, ConfigName: configName, AppEnvs: appEnvs, Config: config}
+	}
+	return SelectedConfig{}
+}
+
+func TestCombineConfigs(t *testing.T) {
+	androidConfigs, err := android.NewScanner().DefaultConfigs()
+	require.NoError(t, err)
+	fastlaneConfigs, err := fastlane.NewScanner().DefaultConfigs()
+	require.NoError(t, err)
+
+	androidConfig := testSelectedConfig(t, "android", "android-", androidConfigs,
+		envmanModels.EnvironmentItemModel{"PROJECT_LOCATION": "."},
+		envmanModels.EnvironmentItemModel{"FASTLANE_WORK_DIR": "."},
+	)
+	fastlaneConfig := testSelectedConfig(t, "fastlane", "fastlane-", fastlaneConfigs,
+		envmanModels.EnvironmentItemModel{"PROJECT_LOCATION": "."},
+		envmanModels.EnvironmentItemModel{"FASTLANE_WORK_DIR": "./android"},
+		envmanModels.EnvironmentItemModel{"FASTLANE_LANE": "android beta"},
+	)
+
+	combined, warnings, err := CombineConfigs([]SelectedConfig{androidConfig, fastlaneConfig})
+	require.NoError(t, err)
+
+	require.Equal(t, "other", combined.ProjectType)
+
+	workflowIDs := []string{}
+	for workflowID := range combined.Workflows {
+		workflowIDs = append(workflowIDs, workflowID)
+	}
+	require.ElementsMatch(t, []string{"android-primary", "android-deploy", "fastlane-primary"}, workflowIDs)
+
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"PROJECT_LOCATION": "."},
+		{"FASTLANE_WORK_DIR": "."},
+		{"FASTLANE_XCODE_LIST_TIMEOUT": "120"},
+		{"FASTLANE_LANE": "android beta"},
+	}, combined.App.Environments)
+
+	require.Equal(t, bitriseModels.TriggerMapModel{
+		{PushBranch: "*", WorkflowID: "android-primary"},
+		{PullRequestSourceBranch: "*", WorkflowID: "android-primary"},
+	}, combined.TriggerMap)
+
+	require.Equal(t, models.Warnings{
+		"Trigger (push_branch: *) of fastlane is already mapped to android-primary, fastlane-primary is not triggered",
+		"Trigger (pull_request_source_branch: *) of fastlane is already mapped to android-primary, fastlane-primary is not triggered",
+		"Env key conflict: FASTLANE_WORK_DIR is . in android and ./android in fastlane config, the first one is used",
+	}, warnings)
+
+	t.Log("not namespaced workflows")
+	{
+		_, _, err := CombineConfigs([]SelectedConfig{
+			testSelectedConfig(t, "android", "", androidConfigs),
+			testSelectedConfig(t, "fastlane", "", fastlaneConfigs),
+		})
+		require.EqualError(t, err, "workflow (primary) is defined by both android and fastlane configs, the workflows have to be namespaced")
+	}
+}
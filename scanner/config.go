@@ -3,13 +3,19 @@ package scanner
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/scanners"
+	"github.com/bitrise-core/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/colorstring"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-io/go-utils/sliceutil"
+	yaml "gopkg.in/yaml.v2"
 )
 
 const otherProjectType = "other"
@@ -36,22 +42,66 @@ type scannerOutput struct {
 	// errors returned by Config()
 	errors models.Errors
 
+	// can be set if scanResultStatus is scanResultDetected
+	// summary returned by scanners implementing SummaryProvider
+	summary models.Summary
+
+	// can be set if scanResultStatus is scanResultDetected
+	// icons returned by scanners implementing IconProvider
+	icons models.Icons
+
 	// set if scanResultStatus is scanResultDetected
 	options          models.OptionNode
 	configs          models.BitriseConfigMap
 	excludedScanners []string
 }
 
+// Phases of a scanner's run, reported to ScanOptions.OnProgress.
+const (
+	// PhaseDetectStarted is reported before the scanner's platform detection.
+	PhaseDetectStarted = "detect_started"
+	// PhaseDetectFinished is reported after the scanner's platform detection.
+	PhaseDetectFinished = "detect_finished"
+	// PhaseConfigsStarted is reported before the detected scanner's options and configs generation.
+	PhaseConfigsStarted = "configs_started"
+	// PhaseConfigsFinished is reported after the detected scanner's options and configs generation, even if it failed.
+	PhaseConfigsFinished = "configs_finished"
+)
+
+// ScanOptions ...
+type ScanOptions struct {
+	// OnProgress is called as each scanner starts and finishes the detection and the config generation, it is optional.
+	// It is called on the scanning goroutine, a slow callback slows down the scan.
+	OnProgress func(scannerName string, phase string)
+	// Offline disables the network dependent detection, the scanners implementing scanners.NetworkAwareScanner
+	// use only the local files and bundled data.
+	Offline bool
+}
+
+func (opts ScanOptions) progress(scannerName, phase string) {
+	if opts.OnProgress != nil {
+		opts.OnProgress(scannerName, phase)
+	}
+}
+
 // Config ...
 func Config(searchDir string) models.ScanResultModel {
-	result := models.ScanResultModel{}
+	result, err := Scan(searchDir, ScanOptions{})
+	if err != nil {
+		result.AddError("general", err.Error())
+	}
+	return result
+}
 
+// Scan runs the project and automation tool scanners on the search dir (the current dir if empty)
+// and returns the collected options, configs, warnings and errors.
+// The returned error is set if the scan could not be started, the errors of the scanners are part of the result.
+func Scan(searchDir string, opts ScanOptions) (models.ScanResultModel, error) {
 	//
 	// Setup
 	currentDir, err := os.Getwd()
 	if err != nil {
-		result.AddError("general", fmt.Sprintf("Failed to expand current directory path, error: %s", err))
-		return result
+		return models.ScanResultModel{}, fmt.Errorf("Failed to expand current directory path, error: %s", err)
 	}
 
 	if searchDir == "" {
@@ -59,16 +109,14 @@ func Config(searchDir string) models.ScanResultModel {
 	} else {
 		absScerach, err := pathutil.AbsPath(searchDir)
 		if err != nil {
-			result.AddError("general", fmt.Sprintf("Failed to expand path (%s), error: %s", searchDir, err))
-			return result
+			return models.ScanResultModel{}, fmt.Errorf("Failed to expand path (%s), error: %s", searchDir, err)
 		}
 		searchDir = absScerach
 	}
 
 	if searchDir != currentDir {
 		if err := os.Chdir(searchDir); err != nil {
-			result.AddError("general", fmt.Sprintf("Failed to change dir, to (%s), error: %s", searchDir, err))
-			return result
+			return models.ScanResultModel{}, fmt.Errorf("Failed to change dir, to (%s), error: %s", searchDir, err)
 		}
 		defer func() {
 			if err := os.Chdir(currentDir); err != nil {
@@ -78,18 +126,26 @@ func Config(searchDir string) models.ScanResultModel {
 	}
 	// ---
 
+	// The language census breaks the ties between the detected project types
+	languageCounts, err := utility.LanguageCensus(searchDir)
+	if err != nil {
+		log.TWarnf("Failed to count the source files by language, error: %s", err)
+	}
+
 	//
 	// Scan
 	log.TInfof(colorstring.Blue("Running scanners:"))
-	fmt.Println()
+	log.Println()
 
 	// Collect scanner outputs, by scanner name
 	scannerToOutput := map[string]scannerOutput{}
+	pluginScanners, pluginToErrors := scanners.PluginScanners()
 	{
-		projectScannerToOutputs := runScanners(scanners.ProjectScanners, searchDir)
-		detectedProjectTypes := getDetectedScannerNames(projectScannerToOutputs)
+		projectScanners := append(append([]scanners.ScannerInterface{}, scanners.ProjectScanners...), pluginScanners...)
+		projectScannerToOutputs := runScanners(projectScanners, searchDir, opts)
+		detectedProjectTypes := orderByLanguages(getDetectedScannerNames(projectScannerToOutputs), languageCounts)
 		log.Printf("Detected project types: %s", detectedProjectTypes)
-		fmt.Println()
+		log.Println()
 
 		// Project types are needed by tool scanners, to create decision tree on which project type
 		// to actually use in bitrise.yml
@@ -100,10 +156,10 @@ func Config(searchDir string) models.ScanResultModel {
 			toolScanner.(scanners.AutomationToolScanner).SetDetectedProjectTypes(detectedProjectTypes)
 		}
 
-		toolScannerToOutputs := runScanners(scanners.AutomationToolScanners, searchDir)
+		toolScannerToOutputs := runScanners(scanners.AutomationToolScanners, searchDir, opts)
 		detectedAutomationToolScanners := getDetectedScannerNames(toolScannerToOutputs)
 		log.Printf("Detected automation tools: %s", detectedAutomationToolScanners)
-		fmt.Println()
+		log.Println()
 
 		// Merge project and tool scanner outputs
 		scannerToOutput = toolScannerToOutputs
@@ -116,6 +172,27 @@ func Config(searchDir string) models.ScanResultModel {
 	scannerToErrors := map[string]models.Errors{}
 	scannerToOptions := map[string]models.OptionNode{}
 	scannerToConfigMap := map[string]models.BitriseConfigMap{}
+	scannerToSummary := map[string]models.Summary{}
+	scannerToIcons := map[string]models.Icons{}
+	for plugin, errors := range pluginToErrors {
+		scannerToErrors[plugin] = errors
+	}
+	if summary := languageSummary(languageCounts); summary != "" {
+		scannerToSummary["general"] = models.Summary{summary}
+	}
+
+	// the env vars of a previous CI provider's config are carried over to the app env vars, the secrets have to be added manually
+	ciAppEnvs := []envmanModels.EnvironmentItemModel{}
+	if ciEnvs, err := utility.DetectCIEnvs(searchDir); err != nil {
+		log.TWarnf("Failed to read the env vars of the CI configs, error: %s", err)
+	} else {
+		for _, declarations := range ciEnvs {
+			ciAppEnvs = append(ciAppEnvs, declarations.AppEnvs()...)
+			if warning := declarations.Warning(); warning != "" {
+				scannerToWarnings["general"] = append(scannerToWarnings["general"], warning)
+			}
+		}
+	}
 	for scanner, scannerOutput := range scannerToOutput {
 		// Currently the tests except an empty warning list if no warnings
 		// are created in the not detect case.
@@ -125,11 +202,25 @@ func Config(searchDir string) models.ScanResultModel {
 		}
 		if len(scannerOutput.errors) > 0 &&
 			(scannerOutput.status == detected || scannerOutput.status == detectedWithErrors) {
-			scannerToErrors[scanner] = scannerOutput.errors
+			// appended, the errors of a plugin skipped for its reserved name are recorded by the same name
+			scannerToErrors[scanner] = append(scannerToErrors[scanner], scannerOutput.errors...)
 		}
 		if len(scannerOutput.configs) > 0 && scannerOutput.status == detected {
 			scannerToOptions[scanner] = scannerOutput.options
 			scannerToConfigMap[scanner] = scannerOutput.configs
+			if len(ciAppEnvs) > 0 {
+				configMap, err := models.AddAppEnvsToConfigMap(scannerOutput.configs, ciAppEnvs)
+				if err != nil {
+					return models.ScanResultModel{}, fmt.Errorf("Failed to add the env vars of the CI configs to the %s configs, error: %s", scanner, err)
+				}
+				scannerToConfigMap[scanner] = configMap
+			}
+			if len(scannerOutput.summary) > 0 {
+				scannerToSummary[scanner] = scannerOutput.summary
+			}
+			if len(scannerOutput.icons) > 0 {
+				scannerToIcons[scanner] = scannerOutput.icons
+			}
 		}
 	}
 	return models.ScanResultModel{
@@ -137,26 +228,28 @@ func Config(searchDir string) models.ScanResultModel {
 		ScannerToBitriseConfigMap: scannerToConfigMap,
 		ScannerToWarnings:         scannerToWarnings,
 		ScannerToErrors:           scannerToErrors,
-	}
+		ScannerToSummary:          scannerToSummary,
+		ScannerToIcons:            scannerToIcons,
+	}, nil
 }
 
-func runScanners(scannerList []scanners.ScannerInterface, searchDir string) map[string]scannerOutput {
+func runScanners(scannerList []scanners.ScannerInterface, searchDir string, opts ScanOptions) map[string]scannerOutput {
 	scannerOutputs := map[string]scannerOutput{}
 	var excludedScannerNames []string
 	for _, scanner := range scannerList {
 		log.TInfof("Scanner: %s", colorstring.Blue(scanner.Name()))
 		if sliceutil.IsStringInSlice(scanner.Name(), excludedScannerNames) {
 			log.TWarnf("scanner is marked as excluded, skipping...")
-			fmt.Println()
+			log.Println()
 			continue
 		}
 
 		log.TPrintf("+------------------------------------------------------------------------------+")
 		log.TPrintf("|                                                                              |")
-		scannerOutput := runScanner(scanner, searchDir)
+		scannerOutput := runScanner(scanner, searchDir, opts)
 		log.TPrintf("|                                                                              |")
 		log.TPrintf("+------------------------------------------------------------------------------+")
-		fmt.Println()
+		log.Println()
 
 		scannerOutputs[scanner.Name()] = scannerOutput
 		excludedScannerNames = append(excludedScannerNames, scannerOutput.excludedScanners...)
@@ -165,11 +258,19 @@ func runScanners(scannerList []scanners.ScannerInterface, searchDir string) map[
 }
 
 // Collect output of a specific scanner
-func runScanner(detector scanners.ScannerInterface, searchDir string) scannerOutput {
+func runScanner(detector scanners.ScannerInterface, searchDir string, opts ScanOptions) scannerOutput {
 	var detectorWarnings models.Warnings
 	var detectorErrors []string
 
-	if isDetect, err := detector.DetectPlatform(searchDir); err != nil {
+	if networkAware, ok := detector.(scanners.NetworkAwareScanner); ok {
+		networkAware.SetOffline(opts.Offline)
+	}
+
+	opts.progress(detector.Name(), PhaseDetectStarted)
+	isDetect, err := detector.DetectPlatform(searchDir)
+	opts.progress(detector.Name(), PhaseDetectFinished)
+
+	if err != nil {
 		log.TErrorf("Scanner failed, error: %s", err)
 		return scannerOutput{
 			status:   notDetected,
@@ -181,6 +282,9 @@ func runScanner(detector scanners.ScannerInterface, searchDir string) scannerOut
 		}
 	}
 
+	opts.progress(detector.Name(), PhaseConfigsStarted)
+	defer opts.progress(detector.Name(), PhaseConfigsFinished)
+
 	options, projectWarnings, err := detector.Options()
 	detectorWarnings = append(detectorWarnings, projectWarnings...)
 
@@ -206,26 +310,98 @@ func runScanner(detector scanners.ScannerInterface, searchDir string) scannerOut
 		}
 	}
 
+	detectorWarnings = append(detectorWarnings, yamlAnchorWarnings(configs)...)
+	detectorWarnings = append(detectorWarnings, stepReferenceWarnings(configs)...)
+
 	scannerExcludedScanners := detector.ExcludedScannerNames()
 	if len(scannerExcludedScanners) > 0 {
 		log.TWarnf("Scanner will exclude scanners: %v", scannerExcludedScanners)
 	}
 
+	var summary models.Summary
+	if summaryProvider, ok := detector.(scanners.SummaryProvider); ok {
+		summary = summaryProvider.Summary()
+	}
+	summary = append(summary, optionStatistics(options))
+
+	var icons models.Icons
+	if iconProvider, ok := detector.(scanners.IconProvider); ok {
+		icons = iconProvider.Icons()
+	}
+
 	return scannerOutput{
 		status:           detected,
 		warnings:         detectorWarnings,
 		errors:           detectorErrors,
+		summary:          summary,
+		icons:            icons,
 		options:          options,
 		configs:          configs,
 		excludedScanners: scannerExcludedScanners,
 	}
 }
 
+// optionStatistics describes the size of the scanner's option tree, like: 12 options, 34 branches, 6 configs.
+func optionStatistics(options models.OptionNode) string {
+	optionCount, valueCount, configCount := options.Count()
+	return fmt.Sprintf("%d options, %d branches, %d configs", optionCount, valueCount, configCount)
+}
+
+// yamlAnchorWarnings warns about the configs using YAML anchors, aliases or merge keys (like the configs of the plugin scanners):
+// the configs are unmarshalled when selected, merged or namespaced, so these are expanded in the written bitrise.yml.
+func yamlAnchorWarnings(configs models.BitriseConfigMap) models.Warnings {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	warnings := models.Warnings{}
+	for _, name := range names {
+		if anchors := models.YAMLAnchors(configs[name]); len(anchors) > 0 {
+			warning := fmt.Sprintf("Config (%s) uses YAML anchors, aliases or merge keys (%s), they are expanded in the generated bitrise.yml", name, strings.Join(anchors, ", "))
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// stepReferenceWarnings warns about the malformed step references of the configs (like the configs of the plugin scanners),
+// which would fail the build at runtime.
+func stepReferenceWarnings(configs models.BitriseConfigMap) models.Warnings {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	warnings := models.Warnings{}
+	for _, name := range names {
+		var config bitriseModels.BitriseDataModel
+		if err := yaml.Unmarshal([]byte(configs[name]), &config); err != nil {
+			warning := fmt.Sprintf("Failed to parse config (%s) to validate its step references, error: %s", name, err)
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+			continue
+		}
+
+		for _, stepWarning := range models.StepReferenceWarnings(config) {
+			warning := fmt.Sprintf("Config (%s): %s", name, stepWarning)
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
+
+// getDetectedScannerNames returns the names of the detected scanners, sorted by name.
 func getDetectedScannerNames(scannerOutputs map[string]scannerOutput) (names []string) {
 	for scanner, scannerOutput := range scannerOutputs {
 		if scannerOutput.status == detected {
 			names = append(names, scanner)
 		}
 	}
+	sort.Strings(names)
 	return
 }
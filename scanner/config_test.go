@@ -0,0 +1,269 @@
+package scanner
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/android"
+	"github.com/bitrise-core/bitrise-init/scanners/ios"
+	"github.com/bitrise-core/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestScan(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("scan")
+	require.NoError(t, err)
+
+	for _, pth := range []string{"build.gradle", "settings.gradle", "app/build.gradle"} {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(pth, ""))
+	}
+	require.NoError(t, fileutil.WriteStringToFileWithPermission(filepath.Join(tmpDir, "gradlew"), "", 0755))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+
+	scannerToPhases := map[string][]string{}
+	result, err := Scan(tmpDir, ScanOptions{
+		OnProgress: func(scannerName string, phase string) {
+			scannerToPhases[scannerName] = append(scannerToPhases[scannerName], phase)
+		},
+	})
+	require.NoError(t, err)
+
+	t.Log("the working dir is restored")
+	{
+		currentDir, err := os.Getwd()
+		require.NoError(t, err)
+		require.Equal(t, wd, currentDir)
+	}
+
+	t.Log("the detected scanner's result")
+	{
+		require.Contains(t, result.ScannerToBitriseConfigMap, android.ScannerName)
+		require.Contains(t, result.ScannerToOptionRoot, android.ScannerName)
+		require.Equal(t, []string{PhaseDetectStarted, PhaseDetectFinished, PhaseConfigsStarted, PhaseConfigsFinished}, scannerToPhases[android.ScannerName])
+	}
+
+	t.Log("not detected scanners only report the detection")
+	{
+		require.NotContains(t, result.ScannerToBitriseConfigMap, "xamarin")
+		require.Equal(t, []string{PhaseDetectStarted, PhaseDetectFinished}, scannerToPhases["xamarin"])
+	}
+
+	t.Log("invalid search dir")
+	{
+		_, err := Scan(filepath.Join(tmpDir, "not-exist"), ScanOptions{})
+		require.Error(t, err)
+	}
+}
+
+// failingTransport fails and records every http request.
+type failingTransport struct {
+	requests *[]string
+}
+
+func (transport failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	*transport.requests = append(*transport.requests, req.URL.String())
+	return nil, errors.New("network access in offline mode")
+}
+
+func TestScanOffline(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("scan-offline")
+	require.NoError(t, err)
+
+	// an iOS project with a Podfile: parsing the Podfile with CocoaPods requires installing gems
+	for pth, content := range map[string]string{
+		"Podfile":                       "platform :ios, '11.0'\nproject 'App'\n\ntarget 'App' do\n  pod 'Alamofire'\nend\n",
+		"App.xcodeproj/project.pbxproj": testOfflinePbxprojContent,
+		// a project without scheme is not offered
+		"App.xcodeproj/xcuserdata/bitrise.xcuserdatad/xcschemes/App.xcscheme": "",
+		"build.gradle":     "",
+		"settings.gradle":  "",
+		"app/build.gradle": "",
+	} {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+	require.NoError(t, fileutil.WriteStringToFileWithPermission(filepath.Join(tmpDir, "gradlew"), "", 0755))
+
+	networkAccesses := []string{}
+	utility.NetworkAccessHook = func(description string) {
+		networkAccesses = append(networkAccesses, description)
+	}
+	originalTransport := http.DefaultTransport
+	http.DefaultTransport = failingTransport{requests: &networkAccesses}
+	defer func() {
+		utility.NetworkAccessHook = nil
+		http.DefaultTransport = originalTransport
+	}()
+
+	result, err := Scan(tmpDir, ScanOptions{Offline: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{}, networkAccesses)
+
+	t.Log("the Podfile is parsed without CocoaPods")
+	{
+		offlineWarningFound := false
+		for _, warning := range result.ScannerToWarnings[string(ios.XcodeProjectTypeIOS)] {
+			if strings.HasPrefix(warning, "Offline mode:") {
+				offlineWarningFound = true
+			}
+		}
+		require.True(t, offlineWarningFound)
+
+		options, ok := result.ScannerToOptionRoot[string(ios.XcodeProjectTypeIOS)]
+		require.True(t, ok)
+		require.Equal(t, []string{"App.xcworkspace"}, options.GetValues())
+	}
+}
+
+func TestScanCIEnvs(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("scan-ci-envs")
+	require.NoError(t, err)
+	for pth, content := range map[string]string{
+		".travis.yml":      "env:\n  global:\n    - LANG=en_US.UTF-8 SONAR_TOKEN=abc\n  matrix:\n    - FLAVOR=staging\n",
+		"build.gradle":     "",
+		"settings.gradle":  "",
+		"app/build.gradle": "",
+	} {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+	require.NoError(t, fileutil.WriteStringToFileWithPermission(filepath.Join(tmpDir, "gradlew"), "", 0755))
+
+	result, err := Scan(tmpDir, ScanOptions{Offline: true})
+	require.NoError(t, err)
+
+	t.Log("the env vars are added to the configs, the secrets and the build matrix env vars are listed in the warnings")
+	{
+		configMap := result.ScannerToBitriseConfigMap[android.ScannerName]
+		require.NotEqual(t, 0, len(configMap))
+		for _, configStr := range configMap {
+			var config bitriseModels.BitriseDataModel
+			require.NoError(t, yaml.Unmarshal([]byte(configStr), &config))
+			require.Contains(t, config.App.Environments, envmanModels.EnvironmentItemModel{"LANG": "en_US.UTF-8"})
+			require.NotContains(t, config.App.Environments, envmanModels.EnvironmentItemModel{"SONAR_TOKEN": "abc"})
+			require.NotContains(t, config.App.Environments, envmanModels.EnvironmentItemModel{"FLAVOR": "staging"})
+		}
+
+		require.Equal(t, models.Warnings{
+			".travis.yml declares secrets: SONAR_TOKEN, add them to the app's secrets (.bitrise.secrets.yml). " +
+				".travis.yml declares build matrix env vars: FLAVOR, their values differ by job: add them to the workflows' env vars.",
+		}, result.ScannerToWarnings["general"])
+	}
+}
+
+const testOfflinePbxprojContent = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	objectVersion = 50;
+	objects = {
+
+/* Begin PBXNativeTarget section */
+		13C4D5A81F5E4C2B00A1B2C3 /* App */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 13C4D5AA1F5E4C2B00A1B2C3 /* Build configuration list for PBXNativeTarget "App" */;
+			buildPhases = (
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = App;
+			productName = App;
+			productReference = 13C4D5A91F5E4C2B00A1B2C3 /* App.app */;
+			productType = "com.apple.product-type.application";
+		};
+/* End PBXNativeTarget section */
+
+/* Begin XCBuildConfiguration section */
+		13C4D5AC1F5E4C2B00A1B2C3 /* Release */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				SDKROOT = iphoneos;
+			};
+			name = Release;
+		};
+/* End XCBuildConfiguration section */
+	};
+	rootObject = 13C4D5921F5E4C2B00A1B2C3 /* Project object */;
+}
+`
+
+func TestYAMLAnchorWarnings(t *testing.T) {
+	anchoredConfig := `format_version: "5"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  _setup: &setup
+    steps:
+    - git-clone@4: {}
+  primary:
+    <<: *setup
+`
+	warnings := yamlAnchorWarnings(models.BitriseConfigMap{
+		"plain-config":    "format_version: \"5\"\n",
+		"anchored-config": anchoredConfig,
+	})
+	require.Equal(t, models.Warnings{"Config (anchored-config) uses YAML anchors, aliases or merge keys (&setup, <<, *setup), they are expanded in the generated bitrise.yml"}, warnings)
+
+	// the anchors are expanded, when the config is selected
+	selected, err := newSelectedConfig(models.ScanResultModel{
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{"plugin": {"anchored-config": anchoredConfig}},
+	}, "plugin", "anchored-config", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(selected.Config.Workflows["primary"].Steps))
+}
+
+func TestOptionStatistics(t *testing.T) {
+	projectOption := models.NewOption("Project path", "PROJECT_PATH")
+	schemeOption := models.NewOption("Scheme", "SCHEME")
+	projectOption.AddOption("App.xcodeproj", schemeOption)
+	schemeOption.AddConfig("App", models.NewConfigOption("ios-test-config"))
+	schemeOption.AddConfig("Framework", models.NewConfigOption("ios-config"))
+
+	require.Equal(t, "2 options, 3 branches, 2 configs", optionStatistics(*projectOption))
+}
+
+func TestStepReferenceWarnings(t *testing.T) {
+	warnings := stepReferenceWarnings(models.BitriseConfigMap{
+		"valid-config": `format_version: "5"
+default_step_lib_source: https://github.com/bitrise-io/bitrise-steplib.git
+workflows:
+  primary:
+    steps:
+    - git-clone@4: {}
+    - path::./steps/lint: {}
+`,
+		"typo-config": `format_version: "5"
+workflows:
+  primary:
+    steps:
+    - git-clone@4 .0: {}
+`,
+	})
+	require.Equal(t, models.Warnings{"Config (typo-config): Step #1 (git-clone@4 .0) of workflow primary is malformed: step reference contains whitespace"}, warnings)
+}
+
+func TestGetDetectedScannerNames(t *testing.T) {
+	require.Equal(t, []string{"android", "flutter", "ios"}, getDetectedScannerNames(map[string]scannerOutput{
+		"ios":     {status: detected},
+		"xamarin": {status: notDetected},
+		"flutter": {status: detected},
+		"android": {status: detected},
+		"cordova": {status: notDetected},
+	}))
+}
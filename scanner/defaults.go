@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/output"
+	envmanModels "github.com/bitrise-io/envman/models"
+)
+
+// defaultPlatformConfig returns the default config of the platform: the config reached by the first values of the platform's detected options,
+// filled with the app envs of the selected values. The scan result's config map is used, so the limited and namespaced configs are respected.
+// The returned env keys are the user inputs without a detected value, referenced by the config without values.
+// Returns false if the platform has no config reached this way (like the scanner plugins without options).
+func defaultPlatformConfig(scanResult models.ScanResultModel, platform string) (SelectedConfig, []string, bool, error) {
+	envKeys := []string{}
+	appEnvs := []envmanModels.EnvironmentItemModel{}
+
+	// the detected options are followed by their first values, to the default config
+	opt := scanResult.ScannerToOptionRoot[platform]
+	for !opt.IsConfigOption() {
+		value := opt.DefaultValue()
+		if value == "" && opt.IsUserInput() && opt.Type != models.TypeOptionalUserInput {
+			envKeys = append(envKeys, opt.EnvKey)
+		} else if appEnv, ok := opt.AppEnv(value); ok {
+			appEnvs = append(appEnvs, appEnv)
+		}
+
+		next, ok := opt.NextOption(value)
+		if !ok || next == nil {
+			return SelectedConfig{}, nil, false, nil
+		}
+		opt = *next
+	}
+
+	if _, ok := scanResult.ScannerToBitriseConfigMap[platform][opt.Config]; !ok {
+		return SelectedConfig{}, nil, false, nil
+	}
+
+	selected, err := newSelectedConfig(scanResult, platform, opt.Config, appEnvs)
+	if err != nil {
+		return SelectedConfig{}, nil, false, fmt.Errorf("failed to create the default config of %s, error: %s", platform, err)
+	}
+	return selected, envKeys, true, nil
+}
+
+// detectedPlatforms returns the platforms of the scan result in alphabetical order.
+func detectedPlatforms(scanResult models.ScanResultModel) []string {
+	platforms := []string{}
+	for platform := range scanResult.ScannerToOptionRoot {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// DefaultPlatformConfigs returns the default config (see defaultPlatformConfig) of every detected platform,
+// used to combine the configs of the platforms without user interaction, so the scan result's workflows should be namespaced (see NamespaceWorkflows).
+// The warnings list the envs referenced by the default configs without values, to provide.
+func DefaultPlatformConfigs(scanResult models.ScanResultModel) ([]SelectedConfig, models.Warnings, error) {
+	selectedConfigs := []SelectedConfig{}
+	warnings := models.Warnings{}
+	for _, platform := range detectedPlatforms(scanResult) {
+		selected, envKeys, ok, err := defaultPlatformConfig(scanResult, platform)
+		if err != nil {
+			return nil, warnings, err
+		}
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("No default config found for %s, the platform is skipped", platform))
+			continue
+		}
+
+		selectedConfigs = append(selectedConfigs, selected)
+
+		if len(envKeys) > 0 {
+			warnings = append(warnings, fmt.Sprintf("The default config of %s is used, the following envs have to be provided: %s", platform, strings.Join(envKeys, ", ")))
+		}
+	}
+	return selectedConfigs, warnings, nil
+}
+
+// WriteDefaultConfigs writes the default config of every detected platform to: <outputDir>/<platform>/<config name>,
+// without asking for the options. Returns the written files by platform.
+func WriteDefaultConfigs(scanResult models.ScanResultModel, outputDir string, format output.Format) (map[string]string, models.Warnings, error) {
+	platformToPth := map[string]string{}
+	warnings := models.Warnings{}
+	for _, platform := range detectedPlatforms(scanResult) {
+		selected, envKeys, ok, err := defaultPlatformConfig(scanResult, platform)
+		if err != nil {
+			return platformToPth, warnings, err
+		}
+		if !ok {
+			warnings = append(warnings, fmt.Sprintf("No default config found for %s, the platform is skipped", platform))
+			continue
+		}
+
+		pth := filepath.Join(outputDir, platform, selected.ConfigName)
+		if err := os.MkdirAll(filepath.Dir(pth), 0700); err != nil {
+			return platformToPth, warnings, fmt.Errorf("failed to create (%s), error: %s", filepath.Dir(pth), err)
+		}
+
+		outputPth, err := output.WriteToFile(selected.Config, format, pth)
+		if err != nil {
+			return platformToPth, warnings, fmt.Errorf("failed to write the default config of %s, error: %s", platform, err)
+		}
+		platformToPth[platform] = outputPth
+
+		if len(envKeys) > 0 {
+			warnings = append(warnings, fmt.Sprintf("The default config of %s references the following envs, they have to be provided: %s", platform, strings.Join(envKeys, ", ")))
+		}
+	}
+	return platformToPth, warnings, nil
+}
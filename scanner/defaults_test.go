@@ -0,0 +1,120 @@
+package scanner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/output"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testDefaultsScanResult returns a scan result of 2 platforms:
+// the android options lead to release-config by 2 paths and to debug-config by 1 path, through a user input without detected value.
+func testDefaultsScanResult() models.ScanResultModel {
+	projectOption := models.NewOption("Project", "PROJECT_LOCATION")
+	for _, project := range []string{"lib", "app"} {
+		moduleOption := models.NewUserInputOption("Module", "MODULE", false)
+		projectOption.AddOption(project, moduleOption)
+
+		variantOption := models.NewOption("Variant", "VARIANT")
+		moduleOption.AddOption("_", variantOption)
+
+		variantOption.AddConfig("Release", models.NewConfigOption("release-config"))
+		if project == "app" {
+			variantOption.AddConfig("Debug", models.NewConfigOption("debug-config"))
+		}
+	}
+
+	workDirOption := models.NewOption("Fastlane work dir", "FASTLANE_WORK_DIR")
+	workDirOption.AddConfig(".", models.NewConfigOption("fastlane-config"))
+
+	configStr := `format_version: "5"
+workflows:
+  primary: {}
+`
+	return models.ScanResultModel{
+		ScannerToOptionRoot: map[string]models.OptionNode{
+			"android":  *projectOption,
+			"fastlane": *workDirOption,
+		},
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{
+			"android":  {"release-config": configStr, "debug-config": configStr},
+			"fastlane": {"fastlane-config": configStr},
+		},
+	}
+}
+
+func TestDefaultPlatformConfigs(t *testing.T) {
+	t.Log("the first values of the detected options are selected")
+	{
+		scanResult := testDefaultsScanResult()
+		require.NoError(t, NamespaceWorkflows(&scanResult))
+
+		selectedConfigs, warnings, err := DefaultPlatformConfigs(scanResult)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(selectedConfigs))
+
+		require.Equal(t, "android", selectedConfigs[0].Platform)
+		require.Equal(t, "debug-config", selectedConfigs[0].ConfigName)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"PROJECT_LOCATION": "app"},
+			{"VARIANT": "Debug"},
+		}, selectedConfigs[0].AppEnvs)
+		require.Contains(t, selectedConfigs[0].Config.Workflows, "android-primary")
+
+		require.Equal(t, "fastlane", selectedConfigs[1].Platform)
+		require.Equal(t, "fastlane-config", selectedConfigs[1].ConfigName)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{{"FASTLANE_WORK_DIR": "."}}, selectedConfigs[1].AppEnvs)
+		require.Contains(t, selectedConfigs[1].Config.Workflows, "fastlane-primary")
+
+		require.Equal(t, models.Warnings{"The default config of android is used, the following envs have to be provided: MODULE"}, warnings)
+	}
+
+	t.Log("the limited configs are respected")
+	{
+		scanResult := testDefaultsScanResult()
+		LimitConfigs(&scanResult, 1)
+
+		selectedConfigs, _, err := DefaultPlatformConfigs(scanResult)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(selectedConfigs))
+		require.Equal(t, "release-config", selectedConfigs[0].ConfigName)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"PROJECT_LOCATION": "app"},
+			{"VARIANT": "Release"},
+		}, selectedConfigs[0].AppEnvs)
+		require.Contains(t, selectedConfigs[0].Config.Workflows, "primary")
+	}
+}
+
+func TestWriteDefaultConfigs(t *testing.T) {
+	outputDir, err := pathutil.NormalizedOSTempDirPath("__defaults__")
+	require.NoError(t, err)
+
+	scanResult := testDefaultsScanResult()
+	scanResult.ScannerToOptionRoot["my-plugin"] = models.OptionNode{}
+
+	platformToPth, warnings, err := WriteDefaultConfigs(scanResult, outputDir, output.YAMLFormat)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"android":  filepath.Join(outputDir, "android", "debug-config.yml"),
+		"fastlane": filepath.Join(outputDir, "fastlane", "fastlane-config.yml"),
+	}, platformToPth)
+	require.Contains(t, warnings, "No default config found for my-plugin, the platform is skipped")
+	require.Contains(t, warnings, "The default config of android references the following envs, they have to be provided: MODULE")
+
+	for platform, pth := range platformToPth {
+		content, err := fileutil.ReadStringFromFile(pth)
+		require.NoError(t, err, platform)
+		require.Contains(t, content, "format_version:")
+		require.Contains(t, content, "  primary:")
+	}
+
+	content, err := fileutil.ReadStringFromFile(platformToPth["android"])
+	require.NoError(t, err)
+	require.Contains(t, content, "PROJECT_LOCATION: app")
+}
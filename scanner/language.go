@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/utility"
+)
+
+// primaryLanguageCount is the number of the languages listed in the scan result's general summary.
+const primaryLanguageCount = 3
+
+// scannerToLanguages maps the project scanners to the languages of the projects they detect.
+var scannerToLanguages = map[string][]string{
+	"android":           {utility.LanguageKotlin, utility.LanguageJava},
+	"ios":               {utility.LanguageSwift, utility.LanguageObjectiveC},
+	"macos":             {utility.LanguageSwift, utility.LanguageObjectiveC},
+	"flutter":           {utility.LanguageDart},
+	"xamarin":           {utility.LanguageCSharp},
+	"react-native":      {utility.LanguageJavaScript, utility.LanguageTypeScript},
+	"react-native-expo": {utility.LanguageJavaScript, utility.LanguageTypeScript},
+	"cordova":           {utility.LanguageJavaScript, utility.LanguageTypeScript},
+	"ionic":             {utility.LanguageJavaScript, utility.LanguageTypeScript},
+	"electron":          {utility.LanguageJavaScript, utility.LanguageTypeScript},
+}
+
+// languageSummary returns the primary languages of the language census, like: Primary languages: Kotlin (120 files), Java (4 files).
+// It returns an empty string if no source file was counted.
+func languageSummary(counts []utility.LanguageCount) string {
+	if len(counts) > primaryLanguageCount {
+		counts = counts[:primaryLanguageCount]
+	}
+
+	languages := []string{}
+	for _, count := range counts {
+		unit := "files"
+		if count.Files == 1 {
+			unit = "file"
+		}
+		languages = append(languages, fmt.Sprintf("%s (%d %s)", count.Language, count.Files, unit))
+	}
+	if len(languages) == 0 {
+		return ""
+	}
+	return "Primary languages: " + strings.Join(languages, ", ")
+}
+
+// orderByLanguages orders the scanner names by the rank of their projects' languages in the language census,
+// the scanner of the primary language comes first. The scanners without counted languages come last,
+// the ties are ordered by name.
+func orderByLanguages(scannerNames []string, counts []utility.LanguageCount) []string {
+	languageToRank := map[string]int{}
+	for i, count := range counts {
+		languageToRank[count.Language] = i
+	}
+
+	rank := func(scannerName string) int {
+		best := len(counts)
+		for _, language := range scannerToLanguages[scannerName] {
+			if r, ok := languageToRank[language]; ok && r < best {
+				best = r
+			}
+		}
+		return best
+	}
+
+	ordered := append([]string{}, scannerNames...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ri, rj := rank(ordered[i]), rank(ordered[j]); ri != rj {
+			return ri < rj
+		}
+		return ordered[i] < ordered[j]
+	})
+	return ordered
+}
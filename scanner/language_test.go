@@ -0,0 +1,45 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageSummary(t *testing.T) {
+	require.Equal(t, "", languageSummary([]utility.LanguageCount{}))
+
+	require.Equal(t, "Primary languages: Dart (12 files), Swift (1 file), Kotlin (1 file)", languageSummary([]utility.LanguageCount{
+		{Language: utility.LanguageDart, Files: 12},
+		{Language: utility.LanguageSwift, Files: 1},
+		{Language: utility.LanguageKotlin, Files: 1},
+		{Language: utility.LanguageJavaScript, Files: 1},
+	}))
+}
+
+func TestOrderByLanguages(t *testing.T) {
+	t.Log("Flutter project with native Android and iOS projects")
+	{
+		counts := []utility.LanguageCount{
+			{Language: utility.LanguageDart, Files: 40},
+			{Language: utility.LanguageSwift, Files: 2},
+			{Language: utility.LanguageKotlin, Files: 1},
+		}
+		require.Equal(t, []string{"flutter", "ios", "android"}, orderByLanguages([]string{"android", "flutter", "ios"}, counts))
+	}
+
+	t.Log("the ties and the scanners without counted languages are ordered by name")
+	{
+		counts := []utility.LanguageCount{
+			{Language: utility.LanguageTypeScript, Files: 10},
+			{Language: utility.LanguageJava, Files: 3},
+		}
+		require.Equal(t, []string{"ionic", "react-native", "android", "ios", "my-plugin"}, orderByLanguages([]string{"my-plugin", "react-native", "ios", "android", "ionic"}, counts))
+	}
+
+	t.Log("empty language census")
+	{
+		require.Equal(t, []string{"android", "ios"}, orderByLanguages([]string{"ios", "android"}, nil))
+	}
+}
@@ -0,0 +1,105 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+)
+
+// LimitConfigs limits the number of configs per scanner, maxConfigs < 1 means unlimited.
+// If a scanner generated more configs, the most likely configs are kept: the ones reachable by the most option paths,
+// in case of a tie the one with the lower name, so the result does not depend on the order of the option tree's maps.
+// The option branches leading only to the collapsed configs are removed,
+// a warning summarizing the collapsed configs is added to the scanner's warnings.
+func LimitConfigs(scanResult *models.ScanResultModel, maxConfigs int) {
+	if maxConfigs < 1 {
+		return
+	}
+
+	for scannerName, options := range scanResult.ScannerToOptionRoot {
+		configs := scanResult.ScannerToBitriseConfigMap[scannerName]
+
+		limitedOptions, limitedConfigs, collapsed := limitConfigs(options, configs, maxConfigs)
+		if len(collapsed) == 0 {
+			continue
+		}
+
+		scanResult.ScannerToOptionRoot[scannerName] = limitedOptions
+		scanResult.ScannerToBitriseConfigMap[scannerName] = limitedConfigs
+
+		if scanResult.ScannerToWarnings == nil {
+			scanResult.ScannerToWarnings = map[string]models.Warnings{}
+		}
+		warning := fmt.Sprintf("%d configs generated, which is more than the limit (%d), collapsed configs: %s", len(collapsed)+len(limitedConfigs), maxConfigs, strings.Join(collapsed, ", "))
+		scanResult.ScannerToWarnings[scannerName] = append(scanResult.ScannerToWarnings[scannerName], warning)
+	}
+}
+
+// limitConfigs returns a copy of the options and configs with at most maxConfigs configs, and the collapsed config names.
+func limitConfigs(options models.OptionNode, configs models.BitriseConfigMap, maxConfigs int) (models.OptionNode, models.BitriseConfigMap, []string) {
+	if _, _, configCount := options.Count(); configCount <= maxConfigs {
+		return options, configs, nil
+	}
+
+	// count the option paths leading to the configs
+	configNames := []string{}
+	configToPathCount := map[string]int{}
+	if err := options.Walk(func(opt *models.OptionNode, path []string) error {
+		if !opt.IsConfigOption() {
+			return nil
+		}
+		if _, ok := configToPathCount[opt.Config]; !ok {
+			configNames = append(configNames, opt.Config)
+		}
+		configToPathCount[opt.Config]++
+		return nil
+	}); err != nil {
+		return options, configs, nil
+	}
+
+	sort.Slice(configNames, func(i, j int) bool {
+		if configToPathCount[configNames[i]] != configToPathCount[configNames[j]] {
+			return configToPathCount[configNames[i]] > configToPathCount[configNames[j]]
+		}
+		return configNames[i] < configNames[j]
+	})
+
+	kept := map[string]bool{}
+	for _, configName := range configNames[:maxConfigs] {
+		kept[configName] = true
+	}
+	collapsed := append([]string{}, configNames[maxConfigs:]...)
+	sort.Strings(collapsed)
+
+	limitedOptions := options.Copy()
+	pruneOptions(limitedOptions, kept)
+
+	limitedConfigs := models.BitriseConfigMap{}
+	for configName, config := range configs {
+		if kept[configName] || configToPathCount[configName] == 0 {
+			limitedConfigs[configName] = config
+		}
+	}
+
+	return *limitedOptions, limitedConfigs, collapsed
+}
+
+// pruneOptions removes the option branches not leading to any of the kept configs,
+// returns true if the option leads to a kept config.
+func pruneOptions(option *models.OptionNode, kept map[string]bool) bool {
+	if option.IsConfigOption() {
+		return kept[option.Config]
+	}
+
+	hasKept := false
+	for value, childOption := range option.ChildOptionMap {
+		if childOption == nil || !pruneOptions(childOption, kept) {
+			delete(option.ChildOptionMap, value)
+			continue
+		}
+		hasKept = true
+	}
+	return hasKept
+}
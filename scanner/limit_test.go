@@ -0,0 +1,98 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+// testLimitScanResult returns a scan result with 3 configs:
+// release-config is reachable by 3 paths, debug-config by 2 paths and test-config by 1 path.
+func testLimitScanResult() models.ScanResultModel {
+	projectOption := models.NewOption("Project", "PROJECT")
+
+	for _, project := range []string{"a", "b"} {
+		variantOption := models.NewOption("Variant", "VARIANT")
+		projectOption.AddOption(project, variantOption)
+
+		variantOption.AddConfig("Release", models.NewConfigOption("release-config"))
+		variantOption.AddConfig("Debug", models.NewConfigOption("debug-config"))
+	}
+
+	testOption := models.NewOption("Variant", "VARIANT")
+	projectOption.AddOption("c", testOption)
+	testOption.AddConfig("Release", models.NewConfigOption("release-config"))
+	testOption.AddConfig("Test", models.NewConfigOption("test-config"))
+
+	return models.ScanResultModel{
+		ScannerToOptionRoot: map[string]models.OptionNode{"android": *projectOption},
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{
+			"android": {"release-config": "release", "debug-config": "debug", "test-config": "test"},
+		},
+	}
+}
+
+func TestLimitConfigs(t *testing.T) {
+	t.Log("unlimited")
+	{
+		scanResult := testLimitScanResult()
+		LimitConfigs(&scanResult, 0)
+		require.Equal(t, testLimitScanResult(), scanResult)
+	}
+
+	t.Log("under the limit")
+	{
+		scanResult := testLimitScanResult()
+		LimitConfigs(&scanResult, 3)
+		require.Equal(t, testLimitScanResult(), scanResult)
+	}
+
+	t.Log("the least likely config is collapsed")
+	{
+		scanResult := testLimitScanResult()
+		LimitConfigs(&scanResult, 2)
+
+		require.Equal(t, models.BitriseConfigMap{"release-config": "release", "debug-config": "debug"}, scanResult.ScannerToBitriseConfigMap["android"])
+		require.Equal(t, models.Warnings{"3 configs generated, which is more than the limit (2), collapsed configs: test-config"}, scanResult.ScannerToWarnings["android"])
+
+		options := scanResult.ScannerToOptionRoot["android"]
+		cOption, ok := options.Child("c")
+		require.True(t, ok)
+		require.Equal(t, []string{"Release"}, cOption.GetValues())
+	}
+
+	t.Log("options leading only to collapsed configs are removed")
+	{
+		scanResult := testLimitScanResult()
+		LimitConfigs(&scanResult, 1)
+
+		require.Equal(t, models.BitriseConfigMap{"release-config": "release"}, scanResult.ScannerToBitriseConfigMap["android"])
+
+		options := scanResult.ScannerToOptionRoot["android"]
+		require.Equal(t, []string{"a", "b", "c"}, options.GetValues())
+		for _, project := range []string{"a", "b", "c"} {
+			variantOption, ok := options.Child(project)
+			require.True(t, ok)
+			require.Equal(t, []string{"Release"}, variantOption.GetValues())
+		}
+	}
+
+	t.Log("ties are collapsed by name")
+	{
+		variantOption := models.NewOption("Variant", "VARIANT")
+		variantOption.AddConfig("Release", models.NewConfigOption("release-config"))
+		variantOption.AddConfig("Debug", models.NewConfigOption("debug-config"))
+
+		for i := 0; i < 20; i++ {
+			scanResult := models.ScanResultModel{
+				ScannerToOptionRoot:       map[string]models.OptionNode{"android": *variantOption.Copy()},
+				ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{"android": {"release-config": "release", "debug-config": "debug"}},
+			}
+			LimitConfigs(&scanResult, 1)
+
+			require.Equal(t, models.BitriseConfigMap{"debug-config": "debug"}, scanResult.ScannerToBitriseConfigMap["android"])
+			require.Equal(t, models.Warnings{"2 configs generated, which is more than the limit (1), collapsed configs: release-config"}, scanResult.ScannerToWarnings["android"])
+		}
+	}
+}
@@ -2,18 +2,83 @@ package scanner
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/scanners"
+	"github.com/bitrise-io/go-utils/sliceutil"
 )
 
+// customConfigAlias requests the custom config in the scanner list, like its project type (other) does.
+const customConfigAlias = "custom"
+
+// ManualConfigOptions ...
+type ManualConfigOptions struct {
+	// Scanners limits the result to the given scanners (like: ios, android), all scanners are included if empty.
+	// The custom config is requested by its project type: other, or by its alias: custom.
+	Scanners []string
+	// NoCustom excludes the custom config from the result.
+	NoCustom bool
+}
+
+// isIncluded returns true if the scanner is requested by the options.
+func (opts ManualConfigOptions) isIncluded(scannerName string) bool {
+	if scannerName == scanners.CustomProjectType && opts.NoCustom {
+		return false
+	}
+	return len(opts.Scanners) == 0 || sliceutil.IsStringInSlice(scannerName, opts.Scanners)
+}
+
 // ManualConfig ...
-func ManualConfig() (models.ScanResultModel, error) {
-	scannerList := append(scanners.ProjectScanners, scanners.AutomationToolScanners...)
+func ManualConfig(opts ManualConfigOptions) (models.ScanResultModel, error) {
+	scannerList := append(append([]scanners.ScannerInterface{}, scanners.ProjectScanners...), scanners.AutomationToolScanners...)
+	return manualConfig(scannerList, opts)
+}
+
+// validateConfigReferences checks if every config referenced by the option tree exists in the config map.
+func validateConfigReferences(scannerName string, options models.OptionNode, configs models.BitriseConfigMap) error {
+	return options.Walk(func(opt *models.OptionNode, path []string) error {
+		if !opt.IsConfigOption() {
+			return nil
+		}
+		if _, ok := configs[opt.Config]; !ok {
+			return fmt.Errorf("%s scanner's options reference config (%s) at: %s, but the config is not defined", scannerName, opt.Config, strings.Join(path, " > "))
+		}
+		return nil
+	})
+}
+
+func manualConfig(scannerList []scanners.ScannerInterface, opts ManualConfigOptions) (models.ScanResultModel, error) {
 	scannerToOptionRoot := map[string]models.OptionNode{}
 	scannerToBitriseConfigMap := map[string]models.BitriseConfigMap{}
+	scannerToWarnings := map[string]models.Warnings{}
+
+	pluginScanners, scannerToErrors := scanners.PluginScanners()
+
+	requestedScanners := []string{}
+	for _, name := range opts.Scanners {
+		if name == customConfigAlias {
+			name = scanners.CustomProjectType
+		}
+		requestedScanners = append(requestedScanners, name)
+	}
+	opts.Scanners = requestedScanners
+
+	knownNames := []string{scanners.CustomProjectType}
+	for _, scanner := range append(append([]scanners.ScannerInterface{}, scannerList...), pluginScanners...) {
+		knownNames = append(knownNames, scanner.Name())
+	}
+	for _, name := range opts.Scanners {
+		if !sliceutil.IsStringInSlice(name, knownNames) {
+			return models.ScanResultModel{}, fmt.Errorf("Unknown scanner: %s, available scanners: %s (or %s)", name, strings.Join(knownNames, ", "), customConfigAlias)
+		}
+	}
 
 	for _, scanner := range scannerList {
+		if !opts.isIncluded(scanner.Name()) {
+			continue
+		}
+
 		option := scanner.DefaultOptions()
 		scannerToOptionRoot[scanner.Name()] = option
 
@@ -22,17 +87,54 @@ func ManualConfig() (models.ScanResultModel, error) {
 			return models.ScanResultModel{}, fmt.Errorf("Failed create default configs, error: %s", err)
 		}
 		scannerToBitriseConfigMap[scanner.Name()] = configs
+
+		if err := validateConfigReferences(scanner.Name(), option, configs); err != nil {
+			return models.ScanResultModel{}, fmt.Errorf("Invalid default options, error: %s", err)
+		}
+	}
+
+	if opts.isIncluded(scanners.CustomProjectType) {
+		customConfig, err := scanners.CustomConfig()
+		if err != nil {
+			return models.ScanResultModel{}, fmt.Errorf("Failed create default custom configs, error: %s", err)
+		}
+
+		scannerToBitriseConfigMap[scanners.CustomProjectType] = customConfig
 	}
 
-	customConfig, err := scanners.CustomConfig()
-	if err != nil {
-		return models.ScanResultModel{}, fmt.Errorf("Failed create default custom configs, error: %s", err)
+	// plugin errors are recorded per plugin, instead of failing the whole manual config
+	for _, scanner := range pluginScanners {
+		if !opts.isIncluded(scanner.Name()) {
+			continue
+		}
+
+		configs, err := scanner.DefaultConfigs()
+		if err != nil {
+			scannerToErrors[scanner.Name()] = append(scannerToErrors[scanner.Name()], fmt.Sprintf("Failed create default configs, error: %s", err))
+			continue
+		}
+		scannerToOptionRoot[scanner.Name()] = scanner.DefaultOptions()
+		scannerToBitriseConfigMap[scanner.Name()] = configs
+
+		// the plugins' configs are written by hand, they may use YAML anchors
+		if warnings := yamlAnchorWarnings(configs); len(warnings) > 0 {
+			scannerToWarnings[scanner.Name()] = warnings
+		}
 	}
 
-	scannerToBitriseConfigMap[scanners.CustomProjectType] = customConfig
+	if len(scannerToBitriseConfigMap) == 0 && len(scannerToErrors) == 0 {
+		return models.ScanResultModel{}, fmt.Errorf("No scanner selected, the only requested scanner (%s) is the excluded custom config", scanners.CustomProjectType)
+	}
 
-	return models.ScanResultModel{
+	result := models.ScanResultModel{
 		ScannerToOptionRoot:       scannerToOptionRoot,
 		ScannerToBitriseConfigMap: scannerToBitriseConfigMap,
-	}, nil
+	}
+	if len(scannerToErrors) > 0 {
+		result.ScannerToErrors = scannerToErrors
+	}
+	if len(scannerToWarnings) > 0 {
+		result.ScannerToWarnings = scannerToWarnings
+	}
+	return result, nil
 }
@@ -0,0 +1,130 @@
+package scanner
+
+import (
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners"
+	"github.com/stretchr/testify/require"
+)
+
+// stubScanner returns the given default options and configs.
+type stubScanner struct {
+	name    string
+	options models.OptionNode
+	configs models.BitriseConfigMap
+}
+
+func (s stubScanner) Name() string                      { return s.name }
+func (stubScanner) DetectPlatform(string) (bool, error) { return false, nil }
+func (stubScanner) ExcludedScannerNames() []string      { return nil }
+func (s stubScanner) Options() (models.OptionNode, models.Warnings, error) {
+	return s.options, nil, nil
+}
+func (s stubScanner) DefaultOptions() models.OptionNode                { return s.options }
+func (s stubScanner) Configs() (models.BitriseConfigMap, error)        { return s.configs, nil }
+func (s stubScanner) DefaultConfigs() (models.BitriseConfigMap, error) { return s.configs, nil }
+
+func newStubScanner(name, referencedConfig, definedConfig string) stubScanner {
+	option := models.NewOption("Project", "PROJECT")
+	option.AddConfig("_", models.NewConfigOption(referencedConfig))
+	return stubScanner{
+		name:    name,
+		options: *option,
+		configs: models.BitriseConfigMap{definedConfig: "format_version: \"7\"\n"},
+	}
+}
+
+func TestManualConfigConfigReferences(t *testing.T) {
+	t.Log("every referenced config is defined")
+	{
+		result, err := manualConfig([]scanners.ScannerInterface{newStubScanner("stub", "stub-config", "stub-config")}, ManualConfigOptions{})
+		require.NoError(t, err)
+		require.Contains(t, result.ScannerToOptionRoot, "stub")
+	}
+
+	t.Log("mismatched config name")
+	{
+		_, err := manualConfig([]scanners.ScannerInterface{
+			newStubScanner("stub", "stub-config", "stub-config"),
+			newStubScanner("mismatched", "mismatched-config", "default-mismatched-config"),
+		}, ManualConfigOptions{})
+		require.EqualError(t, err, "Invalid default options, error: mismatched scanner's options reference config (mismatched-config) at: _, but the config is not defined")
+	}
+
+	t.Log("built-in scanners")
+	{
+		_, err := ManualConfig(ManualConfigOptions{})
+		require.NoError(t, err)
+	}
+}
+
+func TestManualConfigCustomConfig(t *testing.T) {
+	scannerList := []scanners.ScannerInterface{
+		newStubScanner("stub", "stub-config", "stub-config"),
+		newStubScanner("other-stub", "other-stub-config", "other-stub-config"),
+	}
+
+	t.Log("custom config included by default")
+	{
+		result, err := manualConfig(scannerList, ManualConfigOptions{})
+		require.NoError(t, err)
+		require.Contains(t, result.ScannerToBitriseConfigMap, scanners.CustomProjectType)
+		require.Contains(t, result.ScannerToBitriseConfigMap[scanners.CustomProjectType], scanners.CustomConfigName)
+		require.Contains(t, result.ScannerToOptionRoot, "stub")
+		require.Contains(t, result.ScannerToOptionRoot, "other-stub")
+	}
+
+	t.Log("--no-custom")
+	{
+		result, err := manualConfig(scannerList, ManualConfigOptions{NoCustom: true})
+		require.NoError(t, err)
+		require.NotContains(t, result.ScannerToBitriseConfigMap, scanners.CustomProjectType)
+		require.NotContains(t, result.ScannerToOptionRoot, scanners.CustomProjectType)
+		require.Contains(t, result.ScannerToBitriseConfigMap, "stub")
+		require.Contains(t, result.ScannerToBitriseConfigMap, "other-stub")
+	}
+
+	t.Log("--scanners=other requests only the custom config")
+	{
+		result, err := manualConfig(scannerList, ManualConfigOptions{Scanners: []string{scanners.CustomProjectType}})
+		require.NoError(t, err)
+		require.Equal(t, []string{scanners.CustomProjectType}, mapKeys(result.ScannerToBitriseConfigMap))
+		require.Equal(t, 0, len(result.ScannerToOptionRoot))
+	}
+
+	t.Log("--scanners=custom is an alias of other")
+	{
+		result, err := manualConfig(scannerList, ManualConfigOptions{Scanners: []string{"custom", "stub"}})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{scanners.CustomProjectType, "stub"}, mapKeys(result.ScannerToBitriseConfigMap))
+		require.Contains(t, result.ScannerToBitriseConfigMap[scanners.CustomProjectType], scanners.CustomConfigName)
+	}
+
+	t.Log("--scanners without the custom config")
+	{
+		result, err := manualConfig(scannerList, ManualConfigOptions{Scanners: []string{"stub"}})
+		require.NoError(t, err)
+		require.Equal(t, []string{"stub"}, mapKeys(result.ScannerToBitriseConfigMap))
+	}
+
+	t.Log("--scanners=other with --no-custom")
+	{
+		_, err := manualConfig(scannerList, ManualConfigOptions{Scanners: []string{scanners.CustomProjectType}, NoCustom: true})
+		require.Error(t, err)
+	}
+
+	t.Log("unknown scanner")
+	{
+		_, err := manualConfig(scannerList, ManualConfigOptions{Scanners: []string{"unknown"}})
+		require.EqualError(t, err, "Unknown scanner: unknown, available scanners: other, stub, other-stub (or custom)")
+	}
+}
+
+func mapKeys(configMaps map[string]models.BitriseConfigMap) []string {
+	keys := []string{}
+	for key := range configMaps {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,21 @@
+package scanner
+
+import (
+	"fmt"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners"
+)
+
+// NamespaceWorkflows prefixes the workflow IDs of every config in the scan result with the namespace of the related scanner,
+// so that the configs of multiple scanners can be merged into one config without workflow ID collisions.
+func NamespaceWorkflows(scanResult *models.ScanResultModel) error {
+	for scannerName, configMap := range scanResult.ScannerToBitriseConfigMap {
+		namespacedConfigMap, err := models.NamespaceConfigMap(configMap, scanners.WorkflowNamespace(scannerName))
+		if err != nil {
+			return fmt.Errorf("failed to namespace the workflows of %s configs, error: %s", scannerName, err)
+		}
+		scanResult.ScannerToBitriseConfigMap[scannerName] = namespacedConfigMap
+	}
+	return nil
+}
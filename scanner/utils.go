@@ -1,93 +1,170 @@
 package scanner
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
 
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/sliceutil"
 	"github.com/bitrise-io/goinp/goinp"
 )
 
-func askForOptionValue(option models.OptionNode) (string, string, error) {
+// askForOptionalString returns an empty string if no answer provided.
+func askForOptionalString(question string) (string, error) {
+	fmt.Printf("%s (optional) : ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to get input, error: %s", err)
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// BackKeyword can be provided as an answer to go back to the previous option.
+const BackKeyword = "back"
+
+// errBack is returned by the option value asker, if the user wants to go back to the previous option.
+var errBack = errors.New("back to the previous option")
+
+// optionValueAsker asks for the value of the given option, returns errBack if the user wants to go back.
+type optionValueAsker func(option models.OptionNode, canGoBack bool) (string, string, error)
+
+func askForOptionValue(option models.OptionNode, canGoBack bool) (string, string, error) {
 	optionValues := option.GetValues()
 
+	backHint := ""
+	if canGoBack {
+		backHint = fmt.Sprintf(" (type %s to go back)", BackKeyword)
+	}
+
 	selectedValue := ""
-	if len(optionValues) == 1 {
-		if optionValues[0] == "_" {
+	if option.IsAutoSelected() {
+		if option.Type == models.TypeInfo {
+			// display the detected value, it can not be changed
+			log.Printf("%s: %s", option.Title, optionValues[0])
+		}
+		// auto select the only one value
+		return option.EnvKey, optionValues[0], nil
+	} else if option.IsUserInput() {
+		if option.Type == models.TypeOptionalUserInput {
+			// provide optional option value, a detected value is offered as default
+			defaultValue := option.DefaultValue()
+
+			question := fmt.Sprintf("Provide: %s%s", option.Title, backHint)
+			if defaultValue != "" {
+				question = fmt.Sprintf("Provide: %s [%s]%s", option.Title, defaultValue, backHint)
+			}
+			answer, err := askForOptionalString(question)
+			if err != nil {
+				return "", "", err
+			}
+
+			selectedValue = answer
+			if selectedValue == "" {
+				selectedValue = defaultValue
+			}
+		} else {
 			// provide option value
-			question := fmt.Sprintf("Provide: %s", option.Title)
+			question := fmt.Sprintf("Provide: %s%s", option.Title, backHint)
 			answer, err := goinp.AskForString(question)
 			if err != nil {
 				return "", "", err
 			}
 
 			selectedValue = answer
-		} else {
-			// auto select the only one value
-			selectedValue = optionValues[0]
 		}
 	} else {
-		// select from values
+		// select from values, the back keyword is offered as the last item
+		values := optionValues
+		if canGoBack && !sliceutil.IsStringInSlice(BackKeyword, optionValues) {
+			values = append(append([]string{}, optionValues...), BackKeyword)
+		}
+
 		question := fmt.Sprintf("Select: %s", option.Title)
-		answer, err := goinp.SelectFromStrings(question, optionValues)
+		answer, err := goinp.SelectFromStrings(question, values)
 		if err != nil {
 			return "", "", err
 		}
 
-		selectedValue = answer
+		if answer == BackKeyword && !sliceutil.IsStringInSlice(BackKeyword, optionValues) {
+			return "", "", errBack
+		}
+		return option.EnvKey, answer, nil
 	}
 
+	if canGoBack && selectedValue == BackKeyword {
+		return "", "", errBack
+	}
 	return option.EnvKey, selectedValue, nil
 }
 
 // AskForOptions ...
 func AskForOptions(options models.OptionNode) (string, []envmanModels.EnvironmentItemModel, error) {
+	return askForOptions(options, askForOptionValue)
+}
+
+func askForOptions(options models.OptionNode, ask optionValueAsker) (string, []envmanModels.EnvironmentItemModel, error) {
+	// askedOption is an option answered by the user, with the number of app envs collected before it
+	type askedOption struct {
+		option     models.OptionNode
+		appEnvsLen int
+	}
+
 	configPth := ""
 	appEnvs := []envmanModels.EnvironmentItemModel{}
+	history := []askedOption{}
 
-	var walkDepth func(models.OptionNode) error
-	walkDepth = func(opt models.OptionNode) error {
-		optionEnvKey, selectedValue, err := askForOptionValue(opt)
-		if err != nil {
-			return fmt.Errorf("Failed to ask for value, error: %s", err)
+	opt := options
+	for {
+		_, selectedValue, err := ask(opt, len(history) > 0)
+		if err == errBack {
+			if len(history) == 0 {
+				log.TWarnf("Nothing to go back to, this is the first option")
+				continue
+			}
+
+			// auto selected options are skipped, go back to the last option answered by the user
+			previous := history[len(history)-1]
+			history = history[:len(history)-1]
+			appEnvs = appEnvs[:previous.appEnvsLen]
+			opt = previous.option
+			continue
+		} else if err != nil {
+			return "", []envmanModels.EnvironmentItemModel{}, fmt.Errorf("Failed to ask for value, error: %s", err)
 		}
 
 		if opt.Title == "" {
 			// last option selected, config got
 			configPth = selectedValue
-			return nil
-		} else if optionEnvKey != "" {
-			// env's value selected
-			appEnvs = append(appEnvs, envmanModels.EnvironmentItemModel{
-				optionEnvKey: selectedValue,
-			})
+			break
 		}
 
-		var nestedOptions *models.OptionNode
-		if len(opt.ChildOptionMap) == 1 {
-			// auto select the next option
-			for _, childOption := range opt.ChildOptionMap {
-				nestedOptions = childOption
-				break
-			}
-		} else {
-			// go to the next option, based on the selected value
-			childOptions, found := opt.ChildOptionMap[selectedValue]
-			if !found {
-				return nil
-			}
-			nestedOptions = childOptions
+		if !opt.IsAutoSelected() {
+			history = append(history, askedOption{option: opt, appEnvsLen: len(appEnvs)})
 		}
 
-		return walkDepth(*nestedOptions)
-	}
+		// env's value selected, empty optional inputs are skipped
+		if appEnv, ok := opt.AppEnv(selectedValue); ok {
+			appEnvs = append(appEnvs, appEnv)
+		}
 
-	if err := walkDepth(options); err != nil {
-		return "", []envmanModels.EnvironmentItemModel{}, err
+		// go to the next option, based on the selected value
+		nestedOptions, found := opt.NextOption(selectedValue)
+		if !found {
+			break
+		}
+		opt = *nestedOptions
 	}
 
 	if configPth == "" {
@@ -99,6 +176,22 @@ func AskForOptions(options models.OptionNode) (string, []envmanModels.Environmen
 
 // AskForConfig ...
 func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataModel, error) {
+	selected, err := AskForPlatformConfig(scanResult)
+	return selected.Config, err
+}
+
+// SelectedConfig ...
+type SelectedConfig struct {
+	Platform   string
+	ConfigName string
+	// AppEnvs are the app envs collected while walking the options
+	AppEnvs []envmanModels.EnvironmentItemModel
+	// Config is the selected config, filled with the collected app envs
+	Config bitriseModels.BitriseDataModel
+}
+
+// AskForPlatformConfig asks for the platform and its options.
+func AskForPlatformConfig(scanResult models.ScanResultModel) (SelectedConfig, error) {
 
 	//
 	// Select platform
@@ -109,14 +202,14 @@ func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataM
 
 	platform := ""
 	if len(platforms) == 0 {
-		return bitriseModels.BitriseDataModel{}, errors.New("no platform detected")
+		return SelectedConfig{}, errors.New("no platform detected")
 	} else if len(platforms) == 1 {
 		platform = platforms[0]
 	} else {
 		var err error
 		platform, err = goinp.SelectFromStrings("Select platform", platforms)
 		if err != nil {
-			return bitriseModels.BitriseDataModel{}, err
+			return SelectedConfig{}, err
 		}
 	}
 	// ---
@@ -125,27 +218,93 @@ func AskForConfig(scanResult models.ScanResultModel) (bitriseModels.BitriseDataM
 	// Select config
 	options, ok := scanResult.ScannerToOptionRoot[platform]
 	if !ok {
-		return bitriseModels.BitriseDataModel{}, fmt.Errorf("invalid platform selected: %s", platform)
+		return SelectedConfig{}, fmt.Errorf("invalid platform selected: %s", platform)
 	}
 
 	configPth, appEnvs, err := AskForOptions(options)
 	if err != nil {
-		return bitriseModels.BitriseDataModel{}, err
+		return SelectedConfig{}, err
 	}
 	// --
 
-	//
-	// Build config
+	return newSelectedConfig(scanResult, platform, configPth, appEnvs)
+}
+
+// envReferenceRegexp matches the env references of an env value, like: $PROJECT_LOCATION, ${PROJECT_LOCATION}
+var envReferenceRegexp = regexp.MustCompile(`\$\{?([a-zA-Z_][a-zA-Z0-9_]*)\}?`)
+
+// sortedAppEnvs returns the app envs sorted by their key, so regenerating a config does not reorder its envs.
+// An env referencing other collected envs is kept after them, even if its key comes first.
+// The circularly referencing envs are added by their key.
+func sortedAppEnvs(appEnvs []envmanModels.EnvironmentItemModel) []envmanModels.EnvironmentItemModel {
+	type appEnv struct {
+		env        envmanModels.EnvironmentItemModel
+		key        string
+		references []string
+	}
+
+	remaining := []appEnv{}
+	collected := map[string]bool{}
+	for _, env := range appEnvs {
+		key, value, _ := env.GetKeyValuePair()
+		references := []string{}
+		for _, match := range envReferenceRegexp.FindAllStringSubmatch(value, -1) {
+			if match[1] != key {
+				references = append(references, match[1])
+			}
+		}
+		remaining = append(remaining, appEnv{env: env, key: key, references: references})
+		collected[key] = true
+	}
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return remaining[i].key < remaining[j].key
+	})
+
+	sorted := []envmanModels.EnvironmentItemModel{}
+	added := map[string]bool{}
+	for len(remaining) > 0 {
+		next := 0
+		for i, env := range remaining {
+			ready := true
+			for _, reference := range env.references {
+				if collected[reference] && !added[reference] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				next = i
+				break
+			}
+		}
+
+		sorted = append(sorted, remaining[next].env)
+		added[remaining[next].key] = true
+		remaining = append(remaining[:next], remaining[next+1:]...)
+	}
+	return sorted
+}
+
+// newSelectedConfig returns the platform's selected config, filled with the collected app envs sorted by their key (see sortedAppEnvs).
+func newSelectedConfig(scanResult models.ScanResultModel, platform, configPth string, appEnvs []envmanModels.EnvironmentItemModel) (SelectedConfig, error) {
 	configMap := scanResult.ScannerToBitriseConfigMap[platform]
-	configStr := configMap[configPth]
+	configStr, ok := configMap[configPth]
+	if !ok {
+		return SelectedConfig{}, fmt.Errorf("no config (%s) found for platform (%s)", configPth, platform)
+	}
 
 	var config bitriseModels.BitriseDataModel
 	if err := yaml.Unmarshal([]byte(configStr), &config); err != nil {
-		return bitriseModels.BitriseDataModel{}, fmt.Errorf("failed to unmarshal config, error: %s", err)
+		return SelectedConfig{}, fmt.Errorf("failed to unmarshal config, error: %s", err)
 	}
 
+	appEnvs = sortedAppEnvs(appEnvs)
 	config.App.Environments = append(config.App.Environments, appEnvs...)
-	// ---
 
-	return config, nil
+	return SelectedConfig{
+		Platform:   platform,
+		ConfigName: configPth,
+		AppEnvs:    appEnvs,
+		Config:     config,
+	}, nil
 }
@@ -0,0 +1,210 @@
+package scanner
+
+import (
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedAsker answers the options with the given answers, BackKeyword answers go back.
+func scriptedAsker(t *testing.T, answers ...string) (optionValueAsker, *[]string) {
+	asked := []string{}
+	return func(option models.OptionNode, canGoBack bool) (string, string, error) {
+		if option.IsAutoSelected() {
+			return option.EnvKey, option.GetValues()[0], nil
+		}
+
+		require.NotEqual(t, 0, len(answers), "unexpected question: %s", option.Title)
+		answer := answers[0]
+		answers = answers[1:]
+		asked = append(asked, option.Title)
+
+		if answer == BackKeyword {
+			return "", "", errBack
+		}
+		return option.EnvKey, answer, nil
+	}, &asked
+}
+
+func testOptionTree() models.OptionNode {
+	projectOption := models.NewOption("Project", "PROJECT")
+
+	schemeOption := models.NewOption("Scheme", "SCHEME")
+	projectOption.AddOption("App.xcodeproj", schemeOption)
+
+	// single value options are auto selected
+	autoOption := models.NewOption("Auto", "AUTO")
+	schemeOption.AddOption("App", autoOption)
+	schemeOption.AddOption("AppTests", autoOption)
+
+	exportMethodOption := models.NewOption("Export method", "EXPORT_METHOD")
+	autoOption.AddOption("auto-value", exportMethodOption)
+
+	exportMethodOption.AddConfig("app-store", models.NewConfigOption("ios-config"))
+	exportMethodOption.AddConfig("development", models.NewConfigOption("ios-config"))
+
+	otherProjectOption := models.NewOption("Scheme", "SCHEME")
+	projectOption.AddOption("Other.xcodeproj", otherProjectOption)
+	otherProjectOption.AddConfig("Other", models.NewConfigOption("other-config"))
+
+	return *projectOption
+}
+
+func TestAskForOptions(t *testing.T) {
+	t.Log("walk without going back")
+	{
+		ask, _ := scriptedAsker(t, "App.xcodeproj", "App", "development")
+
+		configName, appEnvs, err := askForOptions(testOptionTree(), ask)
+		require.NoError(t, err)
+		require.Equal(t, "ios-config", configName)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"PROJECT": "App.xcodeproj"},
+			{"SCHEME": "App"},
+			{"AUTO": "auto-value"},
+			{"EXPORT_METHOD": "development"},
+		}, appEnvs)
+	}
+
+	t.Log("going back skips the auto selected options and removes the collected envs")
+	{
+		ask, asked := scriptedAsker(t, "App.xcodeproj", "App", BackKeyword, "AppTests", "app-store")
+
+		configName, appEnvs, err := askForOptions(testOptionTree(), ask)
+		require.NoError(t, err)
+		require.Equal(t, "ios-config", configName)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"PROJECT": "App.xcodeproj"},
+			{"SCHEME": "AppTests"},
+			{"AUTO": "auto-value"},
+			{"EXPORT_METHOD": "app-store"},
+		}, appEnvs)
+		require.Equal(t, []string{"Project", "Scheme", "Export method", "Scheme", "Export method"}, *asked)
+	}
+
+	t.Log("going back to the root and selecting another branch")
+	{
+		ask, _ := scriptedAsker(t, "App.xcodeproj", BackKeyword, "Other.xcodeproj")
+
+		configName, appEnvs, err := askForOptions(testOptionTree(), ask)
+		require.NoError(t, err)
+		require.Equal(t, "other-config", configName)
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"PROJECT": "Other.xcodeproj"},
+			{"SCHEME": "Other"},
+		}, appEnvs)
+	}
+
+	t.Log("going back at the root asks again")
+	{
+		ask, asked := scriptedAsker(t, BackKeyword, "Other.xcodeproj")
+
+		configName, _, err := askForOptions(testOptionTree(), ask)
+		require.NoError(t, err)
+		require.Equal(t, "other-config", configName)
+		require.Equal(t, []string{"Project", "Project"}, *asked)
+	}
+}
+
+func TestAskForOptionsInfo(t *testing.T) {
+	xcodeVersionOption := models.NewInfoOption("Xcode version", "XCODE_VERSION")
+
+	bundleIDOption := models.NewInfoOption("Bundle ID", "BUNDLE_ID")
+	xcodeVersionOption.AddOption("15.2", bundleIDOption)
+	bundleIDOption.AddConfig("io.bitrise.app", models.NewConfigOption("ios-config"))
+
+	require.True(t, xcodeVersionOption.IsAutoSelected())
+
+	// the info options are displayed without reading an answer from the input
+	configName, appEnvs, err := askForOptions(*xcodeVersionOption, askForOptionValue)
+	require.NoError(t, err)
+	require.Equal(t, "ios-config", configName)
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"XCODE_VERSION": "15.2"},
+		{"BUNDLE_ID": "io.bitrise.app"},
+	}, appEnvs)
+}
+
+func TestNewSelectedConfigAppEnvsOrder(t *testing.T) {
+	scanResult := models.ScanResultModel{
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{"ios": {"ios-config": `format_version: "5"
+app:
+  envs:
+  - FASTLANE_XCODE_LIST_TIMEOUT: "120"
+`}},
+	}
+
+	generate := func() (string, []envmanModels.EnvironmentItemModel) {
+		ask, _ := scriptedAsker(t, "App.xcodeproj", "App", "development")
+		configName, appEnvs, err := askForOptions(testOptionTree(), ask)
+		require.NoError(t, err)
+
+		selected, err := newSelectedConfig(scanResult, "ios", configName, appEnvs)
+		require.NoError(t, err)
+
+		data, err := yaml.Marshal(selected.Config)
+		require.NoError(t, err)
+		return string(data), selected.Config.App.Environments
+	}
+
+	config, envs := generate()
+	regeneratedConfig, _ := generate()
+	require.Equal(t, config, regeneratedConfig)
+
+	// the config's own envs are kept in front of the collected ones, which are sorted by key
+	require.Equal(t, []envmanModels.EnvironmentItemModel{
+		{"FASTLANE_XCODE_LIST_TIMEOUT": "120"},
+		{"AUTO": "auto-value"},
+		{"EXPORT_METHOD": "development"},
+		{"PROJECT": "App.xcodeproj"},
+		{"SCHEME": "App"},
+	}, envs)
+}
+
+func TestSortedAppEnvs(t *testing.T) {
+	t.Log("sorted by key")
+	{
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"MODULE": "app"},
+			{"PROJECT_LOCATION": "android"},
+			{"VARIANT": "release"},
+		}, sortedAppEnvs([]envmanModels.EnvironmentItemModel{
+			{"PROJECT_LOCATION": "android"},
+			{"VARIANT": "release"},
+			{"MODULE": "app"},
+		}))
+	}
+
+	t.Log("referencing envs are kept after the referenced ones")
+	{
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"MODULE": "app"},
+			{"PROJECT_LOCATION": "android"},
+			{"GRADLEW_PATH": "$PROJECT_LOCATION/gradlew"},
+			{"WORKDIR": "$BITRISE_SOURCE_DIR"},
+			{"XCODE_PROJECT": "${WORKDIR}/ios/App.xcodeproj"},
+		}, sortedAppEnvs([]envmanModels.EnvironmentItemModel{
+			{"XCODE_PROJECT": "${WORKDIR}/ios/App.xcodeproj"},
+			{"PROJECT_LOCATION": "android"},
+			{"MODULE": "app"},
+			{"GRADLEW_PATH": "$PROJECT_LOCATION/gradlew"},
+			{"WORKDIR": "$BITRISE_SOURCE_DIR"},
+		}))
+	}
+}
+
+func TestNewSelectedConfigMissingConfig(t *testing.T) {
+	scanResult := models.ScanResultModel{
+		ScannerToBitriseConfigMap: map[string]models.BitriseConfigMap{"ios": {"ios-config": `format_version: "5"`}},
+	}
+
+	_, err := newSelectedConfig(scanResult, "ios", "ios-pod-config", nil)
+	require.EqualError(t, err, "no config (ios-pod-config) found for platform (ios)")
+
+	_, err = newSelectedConfig(scanResult, "android", "android-config", nil)
+	require.EqualError(t, err, "no config (android-config) found for platform (android)")
+}
@@ -3,10 +3,13 @@ package android
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/e2e"
 )
 
 // Scanner ...
@@ -14,6 +17,10 @@ type Scanner struct {
 	SearchDir    string
 	ProjectRoots []string
 	ExcludeTest  bool
+
+	configDescriptors []ConfigDescriptor
+	summary           models.Summary
+	icons             models.Icons
 }
 
 // NewScanner ...
@@ -54,29 +61,165 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (_ bool, err error) {
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	projectLocationOption := models.NewOption(ProjectLocationInputTitle, ProjectLocationInputEnvKey)
 	warnings := models.Warnings{}
+	scanner.configDescriptors = []ConfigDescriptor{}
+	scanner.summary = models.Summary{}
+	scanner.icons = models.Icons{}
 
 	for _, projectRoot := range scanner.ProjectRoots {
-		if err := checkGradlew(projectRoot); err != nil {
+		relProjectRoot, err := filepath.Rel(scanner.SearchDir, projectRoot)
+		if err != nil {
 			return models.OptionNode{}, warnings, err
 		}
 
-		relProjectRoot, err := filepath.Rel(scanner.SearchDir, projectRoot)
+		wrapper, err := inspectGradleWrapper(projectRoot)
 		if err != nil {
-			return models.OptionNode{}, warnings, err
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to inspect Gradle Wrapper, error: %s", err)
 		}
 
-		configOption := models.NewConfigOption(ConfigName)
-		moduleOption := models.NewOption(ModuleInputTitle, ModuleInputEnvKey)
-		variantOption := models.NewOption(VariantInputTitle, VariantInputEnvKey)
+		descriptor := ConfigDescriptor{}
+		if !wrapper.exists {
+			log.TWarnf("No Gradle Wrapper found in: %s", relProjectRoot)
+			warnings = append(warnings, fmt.Sprintf(noGradlewWarning, relProjectRoot))
+			descriptor.MissingGradlew = true
+		} else {
+			descriptor.GradlewNotExecutable = !wrapper.executable
+
+			version := wrapper.version
+			if version == "" {
+				version = "unknown"
+			}
+			log.TPrintf("Gradle Wrapper version: %s", version)
+			scanner.summary = append(scanner.summary, fmt.Sprintf("%s: Gradle Wrapper version: %s", relProjectRoot, version))
+		}
+
+		sdkVersionSummary, sdkVersionWarnings, err := inspectSDKVersions(projectRoot, relProjectRoot)
+		if err != nil {
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to inspect SDK versions, error: %s", err)
+		}
+		scanner.summary = append(scanner.summary, sdkVersionSummary...)
+		warnings = append(warnings, sdkVersionWarnings...)
+
+		applicationModules, err := ApplicationModules(projectRoot)
+		if err != nil {
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to search for application modules, error: %s", err)
+		}
+		if len(applicationModules) > 0 {
+			log.TPrintf("Application modules: %s", strings.Join(applicationModules, ", "))
+			scanner.summary = append(scanner.summary, fmt.Sprintf("%s: application modules: %s", relProjectRoot, strings.Join(applicationModules, ", ")))
+		}
+
+		versionSummary, versionWarnings, err := inspectVersionSources(projectRoot, relProjectRoot, applicationModules)
+		if err != nil {
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to inspect version sources, error: %s", err)
+		}
+		scanner.summary = append(scanner.summary, versionSummary...)
+		warnings = append(warnings, versionWarnings...)
+
+		sdkComponents, unresolved, err := ParseSDKComponents(projectRoot)
+		if err != nil {
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to inspect SDK components, error: %s", err)
+		}
+		if len(unresolved) > 0 {
+			warning := fmt.Sprintf("Failed to parse the SDK versions of %s (%s), the required SDK components are installed by the install-missing-android-tools step", relProjectRoot, strings.Join(unresolved, ", "))
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		} else if packages := sdkComponents.Packages(); len(packages) > 0 {
+			log.TPrintf("SDK components: %s", strings.Join(packages, ", "))
+			scanner.summary = append(scanner.summary, fmt.Sprintf("%s: SDK components: %s", relProjectRoot, strings.Join(packages, ", ")))
+			descriptor.HasSDKComponents = true
+		}
+
+		descriptor.HasTest, err = hasTest(projectRoot)
+		if err != nil {
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to search for tests, error: %s", err)
+		}
+		if !descriptor.HasTest {
+			log.TPrintf("No tests found")
+			scanner.summary = append(scanner.summary, fmt.Sprintf("%s: no unit or instrumented tests found, test workflow is not generated", relProjectRoot))
+		}
+
+		if !scanner.ExcludeTest {
+			descriptor.HasUITest, err = e2e.DetectAndroidInstrumentation(projectRoot)
+			if err != nil {
+				return models.OptionNode{}, warnings, fmt.Errorf("failed to search for instrumentation tests, error: %s", err)
+			}
+			if descriptor.HasUITest {
+				log.TPrintf("Instrumentation tests found")
+				scanner.summary = append(scanner.summary, e2e.Summary(relProjectRoot, []e2e.Framework{e2e.AndroidInstrumentation}))
+			}
+		}
+
+		icons, err := FindLauncherIcons(projectRoot, scanner.SearchDir)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to search for launcher icon in: %s, error: %s", relProjectRoot, err)
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		}
+		scanner.icons = appendIcons(scanner.icons, icons...)
+
+		scanner.configDescriptors = append(scanner.configDescriptors, descriptor)
+
+		modules, err := buildModules(projectRoot, applicationModules)
+		if err != nil {
+			return models.OptionNode{}, warnings, fmt.Errorf("failed to search for the included modules, error: %s", err)
+		}
 
+		moduleOption := models.NewOption(ModuleInputTitle, ModuleInputEnvKey)
 		projectLocationOption.AddOption(relProjectRoot, moduleOption)
-		moduleOption.AddOption("app", variantOption)
-		variantOption.AddConfig("", configOption)
+
+		for _, module := range modules {
+			configOption := models.NewConfigOption(descriptor.ConfigName())
+			variantOption := models.NewOption(VariantInputTitle, VariantInputEnvKey)
+			moduleOption.AddOption(module, variantOption)
+
+			if descriptor.MissingGradlew {
+				gradlewPathOption := models.NewUserInputOption(GradlewPathInputTitle, GradlewPathInputEnvKey, false)
+				variantOption.AddOption("", gradlewPathOption)
+				gradlewPathOption.AddConfig("_", configOption)
+			} else {
+				variantOption.AddConfig("", configOption)
+			}
+
+			if descriptor.HasSDKComponents {
+				addSDKComponentsOption(variantOption, sdkComponents)
+			}
+
+			if descriptor.HasUITest {
+				e2e.AddRunUITestsOption(variantOption)
+			}
+		}
 	}
 
 	return *projectLocationOption, warnings, nil
 }
 
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
+// Icons ...
+func (scanner *Scanner) Icons() models.Icons {
+	return scanner.icons
+}
+
+// appendIcons appends the icons not yet in the list, nested project roots may find the same icon.
+func appendIcons(icons models.Icons, newIcons ...models.Icon) models.Icons {
+	for _, newIcon := range newIcons {
+		found := false
+		for _, icon := range icons {
+			if icon.Path == newIcon.Path {
+				found = true
+				break
+			}
+		}
+		if !found {
+			icons = append(icons, newIcon)
+		}
+	}
+	return icons
+}
+
 // DefaultOptions ...
 func (scanner *Scanner) DefaultOptions() models.OptionNode {
 	projectLocationOption := models.NewOption(ProjectLocationInputTitle, ProjectLocationInputEnvKey)
@@ -93,26 +236,33 @@ func (scanner *Scanner) DefaultOptions() models.OptionNode {
 
 // Configs ...
 func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	configBuilder := scanner.generateConfigBuilder()
+	bitriseDataMap := models.BitriseConfigMap{}
+	for _, descriptor := range scanner.configDescriptors {
+		if _, ok := bitriseDataMap[descriptor.ConfigName()]; ok {
+			continue
+		}
 
-	config, err := configBuilder.Generate(ScannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+		configBuilder := scanner.generateConfigBuilder(descriptor)
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
+		config, err := configBuilder.Generate(ScannerName)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		bitriseDataMap[descriptor.ConfigName()] = string(data)
 	}
 
-	return models.BitriseConfigMap{
-		ConfigName: string(data),
-	}, nil
+	return bitriseDataMap, nil
 }
 
 // DefaultConfigs ...
 func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
-	configBuilder := scanner.generateConfigBuilder()
+	configBuilder := scanner.generateConfigBuilder(ConfigDescriptor{})
 
 	config, err := configBuilder.Generate(ScannerName)
 	if err != nil {
@@ -0,0 +1,671 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+const gradleWrapperPropertiesContent = `distributionBase=GRADLE_USER_HOME
+distributionPath=wrapper/dists
+zipStoreBase=GRADLE_USER_HOME
+zipStorePath=wrapper/dists
+distributionUrl=https\://services.gradle.org/distributions/gradle-4.4-all.zip
+`
+
+func writeAndroidProject(t *testing.T, projectDir string, gradlewMode os.FileMode) {
+	require.NoError(t, os.MkdirAll(projectDir, 0700))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectDir, "build.gradle"), ""))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectDir, "settings.gradle"), "include ':app'"))
+
+	if gradlewMode != 0 {
+		gradlewPth := filepath.Join(projectDir, "gradlew")
+		require.NoError(t, fileutil.WriteStringToFile(gradlewPth, "#!/usr/bin/env sh"))
+		require.NoError(t, os.Chmod(gradlewPth, gradlewMode))
+
+		propertiesPth := filepath.Join(projectDir, gradleWrapperPropertiesPth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(propertiesPth), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(propertiesPth, gradleWrapperPropertiesContent))
+	}
+}
+
+func TestParseGradleWrapperVersion(t *testing.T) {
+	require.Equal(t, "4.4", parseGradleWrapperVersion(gradleWrapperPropertiesContent))
+	require.Equal(t, "5.1.1", parseGradleWrapperVersion("distributionUrl=https://services.gradle.org/distributions/gradle-5.1.1-bin.zip"))
+	require.Equal(t, "", parseGradleWrapperVersion("distributionBase=GRADLE_USER_HOME"))
+}
+
+func TestOptionsGradleWrapper(t *testing.T) {
+	t.Log("wrapper present")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0755)
+
+		scanner := NewScanner()
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.True(t, detected)
+
+		options, warnings, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, 0, len(warnings))
+		require.Equal(t, models.Summary{".: Gradle Wrapper version: 4.4", ".: no unit or instrumented tests found, test workflow is not generated"}, scanner.Summary())
+
+		configOption, ok := options.Child(".", "app", "")
+		require.True(t, ok)
+		require.Equal(t, ConfigName, configOption.Config)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		require.Contains(t, configs, ConfigName)
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+
+	t.Log("wrapper present, but not executable")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0644)
+
+		scanner := NewScanner()
+		_, err = scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+
+		options, _, err := scanner.Options()
+		require.NoError(t, err)
+		configOption, ok := options.Child(".", "app", "")
+		require.True(t, ok)
+		require.Equal(t, "android-chmod-gradlew-config", configOption.Config)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		require.Contains(t, configs["android-chmod-gradlew-config"], "chmod +x $PROJECT_LOCATION/gradlew")
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+
+	t.Log("wrapper absent")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0)
+
+		scanner := NewScanner()
+		_, err = scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+
+		options, warnings, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(warnings))
+		require.Equal(t, models.Summary{".: no unit or instrumented tests found, test workflow is not generated"}, scanner.Summary())
+
+		gradlewPathOption, ok := options.Child(".", "app", "")
+		require.True(t, ok)
+		require.Equal(t, GradlewPathInputEnvKey, gradlewPathOption.EnvKey)
+		require.Equal(t, models.TypeUserInput, gradlewPathOption.Type)
+		require.Equal(t, "android-no-gradlew-config", gradlewPathOption.ChildOptionMap["_"].Config)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		require.Contains(t, configs["android-no-gradlew-config"], "gradle-runner@")
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+}
+
+func TestOptionsTest(t *testing.T) {
+	t.Log("unit tests")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0755)
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "app", "src", "test", "java"), 0700))
+
+		scanner := NewScanner()
+		_, err = scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+
+		options, _, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, models.Summary{".: Gradle Wrapper version: 4.4"}, scanner.Summary())
+
+		configOption, ok := options.Child(".", "app", "")
+		require.True(t, ok)
+		require.Equal(t, "android-test-config", configOption.Config)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		require.Contains(t, configs["android-test-config"], "pull_request_source_branch: '*'\n  workflow: test")
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+
+	t.Log("test dependencies")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0755)
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "app"), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "app", "build.gradle"), `dependencies {
+    testImplementation 'junit:junit:4.12'
+}`))
+
+		found, err := hasTest(tmpDir)
+		require.NoError(t, err)
+		require.True(t, found)
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+
+	t.Log("no tests")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0755)
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "app", "build", "src", "test"), 0700))
+
+		found, err := hasTest(tmpDir)
+		require.NoError(t, err)
+		require.False(t, found)
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+}
+
+func TestOptionsSDKVersions(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+	require.NoError(t, err)
+	writeAndroidProject(t, tmpDir, 0755)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "app"), 0700))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "app", "build.gradle"), `android {
+    defaultConfig {
+        minSdkVersion 21
+        targetSdkVersion 28
+    }
+}`))
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "lib"), 0700))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "lib", "build.gradle.kts"), `android {
+    defaultConfig {
+        minSdk = 19
+        targetSdkVersion(28)
+    }
+}`))
+	// build outputs are skipped
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "app", "build"), 0700))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "app", "build", "build.gradle"), "minSdkVersion 14"))
+
+	versions, err := SDKVersions(tmpDir)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]int{"minSdkVersion": []int{19, 21}, "targetSdkVersion": []int{28}}, versions)
+
+	scanner := NewScanner()
+	_, err = scanner.DetectPlatform(tmpDir)
+	require.NoError(t, err)
+
+	_, warnings, err := scanner.Options()
+	require.NoError(t, err)
+	require.Contains(t, scanner.Summary(), ".: minSdkVersion: 19, targetSdkVersion: 28")
+	require.Equal(t, models.Warnings{"The modules of . declare different minSdkVersion values (19, 21), the minimum (19) is used"}, warnings)
+
+	require.NoError(t, os.RemoveAll(tmpDir))
+}
+
+func TestParseSDKComponents(t *testing.T) {
+	for _, flavor := range []struct {
+		name    string
+		files   map[string]string
+		wantErr bool
+	}{
+		{
+			name: "groovy",
+			files: map[string]string{"app/build.gradle": `android {
+    compileSdkVersion 28
+    buildToolsVersion "28.0.3" // pinned
+}`},
+		},
+		{
+			name: "kotlin dsl",
+			files: map[string]string{"app/build.gradle.kts": `android {
+    compileSdkVersion(28)
+    buildToolsVersion = "28.0.3"
+}`},
+		},
+		{
+			name: "version catalog",
+			files: map[string]string{
+				"gradle/libs.versions.toml": `[versions]
+compile-sdk = "28"
+buildTools = "28.0.3"
+
+[libraries]
+junit = { module = "junit:junit", version = "4.12" }
+`,
+				"app/build.gradle.kts": `android {
+    compileSdk = libs.versions.compile.sdk.get().toInt()
+    buildToolsVersion = libs.versions.buildTools.get()
+}`,
+			},
+		},
+	} {
+		t.Log(flavor.name)
+		{
+			tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+			require.NoError(t, err)
+			writeAndroidProject(t, tmpDir, 0755)
+			for pth, content := range flavor.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, pth)), 0700))
+				require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, pth), content))
+			}
+
+			components, unresolved, err := ParseSDKComponents(tmpDir)
+			require.NoError(t, err)
+			require.Equal(t, []string{}, unresolved)
+			require.Equal(t, SDKComponents{CompileSdkVersions: []string{"28"}, BuildToolsVersions: []string{"28.0.3"}}, components)
+
+			scanner := NewScanner()
+			_, err = scanner.DetectPlatform(tmpDir)
+			require.NoError(t, err)
+
+			options, warnings, err := scanner.Options()
+			require.NoError(t, err)
+			require.Equal(t, 0, len(warnings))
+			require.Contains(t, scanner.Summary(), `.: SDK components: platforms;android-28, build-tools;28.0.3`)
+
+			sdkComponentsOption, ok := options.Child(".", "app", "")
+			require.True(t, ok)
+			require.Equal(t, models.TypeInfo, sdkComponentsOption.Type)
+			require.Equal(t, SDKComponentsInputEnvKey, sdkComponentsOption.EnvKey)
+
+			configOption, ok := sdkComponentsOption.Child("platforms;android-28 build-tools;28.0.3")
+			require.True(t, ok)
+			require.Equal(t, "android-sdk-components-config", configOption.Config)
+
+			configs, err := scanner.Configs()
+			require.NoError(t, err)
+			require.Contains(t, configs[configOption.Config], `yes | "$sdkmanager" $ANDROID_SDK_COMPONENTS`)
+			testhelper.AssertConfigEnvKeys(t, configs[configOption.Config], SDKComponentsInputEnvKey)
+
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}
+	}
+
+	t.Log("unparseable versions")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		writeAndroidProject(t, tmpDir, 0755)
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "app"), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "app", "build.gradle"), `android {
+    compileSdkVersion rootProject.ext.compileSdkVersion
+}`))
+
+		scanner := NewScanner()
+		_, err = scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+
+		options, warnings, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, models.Warnings{"Failed to parse the SDK versions of . (compileSdkVersion rootProject.ext.compileSdkVersion), the required SDK components are installed by the install-missing-android-tools step"}, warnings)
+
+		configOption, ok := options.Child(".", "app", "")
+		require.True(t, ok)
+		require.Equal(t, "android-config", configOption.Config)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		require.NotContains(t, configs["android-config"], "sdkmanager")
+		require.Contains(t, configs["android-config"], "install-missing-android-tools")
+
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}
+}
+
+func TestParseVersionCatalog(t *testing.T) {
+	catalog := parseVersionCatalog(`# versions of the project
+[versions]
+agp = "8.1.0"
+compile-sdk = "34"
+min_sdk = '24'
+kotlin = { strictly = "1.9.0" }
+
+[libraries]
+junit = { module = "junit:junit", version = "4.13.2" }
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+android_library = { id = "com.android.library", version = "8.1.0" }
+kotlin-android = "org.jetbrains.kotlin.android:1.9.0"
+`)
+
+	require.Equal(t, map[string]string{"agp": "8.1.0", "compile.sdk": "34", "min.sdk": "24"}, catalog.versions)
+	require.Equal(t, map[string]string{
+		"android.application": "com.android.application",
+		"android.library":     "com.android.library",
+		"kotlin.android":      "org.jetbrains.kotlin.android",
+	}, catalog.plugins)
+}
+
+func TestAppliedPlugins(t *testing.T) {
+	catalogPlugins := map[string]string{"android.application": "com.android.application", "kotlin.android": "org.jetbrains.kotlin.android"}
+
+	t.Log("groovy")
+	{
+		require.Equal(t, []string{"com.android.application", "kotlin-android"}, appliedPlugins(`apply plugin: 'com.android.application'
+apply plugin: "kotlin-android"`, catalogPlugins))
+		require.Equal(t, []string{"com.android.application"}, appliedPlugins(`plugins {
+    id 'com.android.application'
+}`, catalogPlugins))
+	}
+
+	t.Log("kotlin dsl")
+	{
+		require.Equal(t, []string{"com.android.application"}, appliedPlugins(`plugins {
+    id("com.android.application")
+}`, catalogPlugins))
+	}
+
+	t.Log("version catalog aliases")
+	{
+		require.Equal(t, []string{"com.android.application", "org.jetbrains.kotlin.android"}, appliedPlugins(`plugins {
+    alias(libs.plugins.android.application)
+    alias(libs.plugins.kotlin.android) // kotlin
+    alias(libs.plugins.unknown)
+}`, catalogPlugins))
+	}
+
+	t.Log("plugins declared with apply false")
+	{
+		require.Equal(t, []string{}, appliedPlugins(`plugins {
+    alias(libs.plugins.android.application) apply false
+    id("com.android.application") version "8.1.0" apply false
+    id 'com.android.library' version '8.1.0' apply(false)
+}`, catalogPlugins))
+	}
+}
+
+func TestOptionsVersionCatalog(t *testing.T) {
+	scan := func(files map[string]string) (models.OptionNode, models.BitriseConfigMap, models.Summary, models.Warnings) {
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+		require.NoError(t, err)
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		writeAndroidProject(t, tmpDir, 0755)
+		for pth, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, pth)), 0700))
+			require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, pth), content))
+		}
+
+		scanner := NewScanner()
+		_, err = scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+
+		options, warnings, err := scanner.Options()
+		require.NoError(t, err)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+
+		return options, configs, scanner.Summary(), warnings
+	}
+
+	inlineOptions, inlineConfigs, inlineSummary, inlineWarnings := scan(map[string]string{
+		"build.gradle.kts": `plugins {
+    id("com.android.application") version "8.1.0" apply false
+    id("com.android.library") version "8.1.0" apply false
+}`,
+		"app/build.gradle.kts": `plugins {
+    id("com.android.application")
+}
+
+android {
+    compileSdk = 34
+    buildToolsVersion = "34.0.0"
+
+    defaultConfig {
+        minSdk = 24
+        targetSdk = 34
+    }
+}`,
+		"lib/build.gradle.kts": `plugins {
+    id("com.android.library")
+}
+
+android {
+    compileSdk = 34
+
+    defaultConfig {
+        minSdk = 21
+    }
+}`,
+	})
+
+	catalogOptions, catalogConfigs, catalogSummary, catalogWarnings := scan(map[string]string{
+		"gradle/libs.versions.toml": `[versions]
+agp = "8.1.0"
+compileSdk = "34"
+buildTools = "34.0.0"
+minSdk = "24"
+libMinSdk = "21"
+targetSdk = "34"
+
+[plugins]
+android-application = { id = "com.android.application", version.ref = "agp" }
+android-library = { id = "com.android.library", version.ref = "agp" }
+`,
+		"build.gradle.kts": `plugins {
+    alias(libs.plugins.android.application) apply false
+    alias(libs.plugins.android.library) apply false
+}`,
+		"app/build.gradle.kts": `plugins {
+    alias(libs.plugins.android.application)
+}
+
+android {
+    compileSdk = libs.versions.compileSdk.get().toInt()
+    buildToolsVersion = libs.versions.buildTools.get()
+
+    defaultConfig {
+        minSdk = libs.versions.minSdk.get().toInt()
+        targetSdk = libs.versions.targetSdk.get().toInt()
+    }
+}`,
+		"lib/build.gradle.kts": `plugins {
+    alias(libs.plugins.android.library)
+}
+
+android {
+    compileSdk = libs.versions.compileSdk.get().toInt()
+
+    defaultConfig {
+        minSdk = libs.versions.libMinSdk.get().toInt()
+    }
+}`,
+	})
+
+	require.Equal(t, inlineOptions.String(), catalogOptions.String())
+	require.Equal(t, inlineConfigs, catalogConfigs)
+	require.Equal(t, inlineSummary, catalogSummary)
+	require.Equal(t, inlineWarnings, catalogWarnings)
+
+	require.Contains(t, catalogSummary, ".: application modules: app")
+	require.Contains(t, catalogSummary, ".: minSdkVersion: 21, targetSdkVersion: 34")
+	require.Contains(t, catalogSummary, ".: SDK components: platforms;android-34, build-tools;34.0.0")
+	require.Equal(t, models.Warnings{"The modules of . declare different minSdkVersion values (21, 24), the minimum (21) is used"}, catalogWarnings)
+}
+
+func TestParseIncludedModules(t *testing.T) {
+	require.Equal(t, []string{"app", "wear", "core"}, parseIncludedModules(`rootProject.name = 'Sample'
+include ':app', ':wear'
+// include ':legacy'
+include ':core'`))
+
+	require.Equal(t, []string{"app", "feature:tv"}, parseIncludedModules(`rootProject.name = "Sample"
+include(":app")
+include(":feature:tv", ":app")`))
+
+	require.Equal(t, []string{}, parseIncludedModules(`rootProject.name = 'Sample'`))
+}
+
+func TestOptionsMultipleApplicationModules(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	writeAndroidProject(t, tmpDir, 0755)
+	for pth, content := range map[string]string{
+		"settings.gradle": `include ':app', ':wear', ':tv'
+include ':lib'`,
+		"app/build.gradle": `apply plugin: 'com.android.application'`,
+		"wear/build.gradle.kts": `plugins {
+    id("com.android.application")
+}`,
+		"lib/build.gradle": `plugins {
+    id 'com.android.library'
+}`,
+		// not included by the settings file
+		"tv-legacy/build.gradle": `apply plugin: 'com.android.application'`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, pth)), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, pth), content))
+	}
+
+	modules, err := IncludedModules(tmpDir)
+	require.NoError(t, err)
+	require.Equal(t, []string{"app", "wear", "tv", "lib"}, modules)
+
+	scanner := NewScanner()
+	_, err = scanner.DetectPlatform(tmpDir)
+	require.NoError(t, err)
+
+	options, _, err := scanner.Options()
+	require.NoError(t, err)
+
+	moduleOption, ok := options.Child(".")
+	require.True(t, ok)
+	require.Equal(t, ModuleInputEnvKey, moduleOption.EnvKey)
+	require.Equal(t, []string{"app", "wear"}, moduleOption.GetValues())
+
+	for _, module := range []string{"app", "wear"} {
+		configOption, ok := options.Child(".", module, "")
+		require.True(t, ok)
+		require.Equal(t, "android-config", configOption.Config)
+		require.Equal(t, []string{".", module, ""}, configOption.Components)
+	}
+}
+
+func TestVersionSources(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__android__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	writeAndroidProject(t, tmpDir, 0755)
+	for pth, content := range map[string]string{
+		"settings.gradle": `include ':app', ':wear', ':tv', ':auto'`,
+		"app/build.gradle": `apply plugin: 'com.android.application'
+
+android {
+    defaultConfig {
+        versionCode 42
+        versionName "1.2.0"
+    }
+}`,
+		"wear/build.gradle.kts": `plugins {
+    id("com.android.application")
+}
+
+android {
+    defaultConfig {
+        versionCode = 7
+        versionName = libs.versions.wearVersion.get()
+    }
+}`,
+		"tv/build.gradle": `apply plugin: 'com.android.application'
+
+def versionProperties = new Properties()
+versionProperties.load(new FileInputStream(file("version.properties")))
+
+android {
+    defaultConfig {
+        versionCode versionProperties['code'].toInteger()
+        versionName versionProperties['name']
+    }
+}`,
+		// no version declared
+		"auto/build.gradle": `apply plugin: 'com.android.application'`,
+		"gradle/libs.versions.toml": `[versions]
+wearVersion = "1.1.0"
+`,
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(tmpDir, pth)), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, pth), content))
+	}
+
+	sources, unresolved, err := VersionSources(tmpDir, []string{"app", "wear", "tv", "auto"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"tv"}, unresolved)
+	require.Equal(t, 2, len(sources))
+	require.Equal(t, "app: 1.2.0 (42) from versionName and versionCode of app/build.gradle", sources[0].String())
+	require.Equal(t, "wear: 1.1.0 (7) from versionName and versionCode of wear/build.gradle.kts", sources[1].String())
+
+	scanner := NewScanner()
+	_, err = scanner.DetectPlatform(tmpDir)
+	require.NoError(t, err)
+
+	_, warnings, err := scanner.Options()
+	require.NoError(t, err)
+	require.Contains(t, warnings, "The versionName of . (tv) is not a literal value, it probably comes from a properties or a generated file")
+	require.Contains(t, scanner.Summary(), ".: version sources: app: 1.2.0 (42) from versionName and versionCode of app/build.gradle, wear: 1.1.0 (7) from versionName and versionCode of wear/build.gradle.kts")
+}
+
+func TestGenerateConfigBuilderArtifactDeploy(t *testing.T) {
+	deployPaths := func(descriptor ConfigDescriptor, workflowID models.WorkflowID) []string {
+		configBuilder := NewScanner().generateConfigBuilder(descriptor)
+		config, err := configBuilder.Generate(ScannerName)
+		require.NoError(t, err)
+		data, err := yaml.Marshal(config)
+		require.NoError(t, err)
+
+		paths, err := testhelper.StepInputValues(string(data), string(workflowID), steps.DeployToBitriseIoID, steps.DeployToBitriseIoDeployPathInputKey)
+		require.NoError(t, err)
+		return paths
+	}
+
+	t.Log("the APKs of the selected variant and the test reports are deployed")
+	{
+		descriptor := ConfigDescriptor{HasTest: true}
+		require.Equal(t, []string{"$PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT", "$PROJECT_LOCATION/$MODULE/build/test-results"}, deployPaths(descriptor, models.DeployWorkflowID))
+		require.Equal(t, []string{"$PROJECT_LOCATION/$MODULE/build/test-results"}, deployPaths(descriptor, models.PrimaryWorkflowID))
+		require.Equal(t, []string{"$PROJECT_LOCATION/$MODULE/build/test-results"}, deployPaths(descriptor, models.TestWorkflowID))
+	}
+
+	t.Log("no test reports without tests")
+	{
+		descriptor := ConfigDescriptor{}
+		require.Equal(t, []string{"$PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT"}, deployPaths(descriptor, models.DeployWorkflowID))
+		require.Equal(t, []string{}, deployPaths(descriptor, models.PrimaryWorkflowID))
+	}
+
+	t.Log("project without Gradle Wrapper")
+	{
+		descriptor := ConfigDescriptor{MissingGradlew: true, HasTest: true}
+		require.Equal(t, []string{"$PROJECT_LOCATION/$MODULE/build/outputs/apk/$VARIANT", "$PROJECT_LOCATION/$MODULE/build/test-results"}, deployPaths(descriptor, models.DeployWorkflowID))
+	}
+}
@@ -0,0 +1,67 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const (
+	gradlewBase                = "gradlew"
+	gradleWrapperPropertiesPth = "gradle/wrapper/gradle-wrapper.properties"
+)
+
+const noGradlewWarning = `<b>No Gradle Wrapper (gradlew) found in: %s</b>
+Using a Gradle Wrapper (gradlew) is strongly recommended, as the wrapper is what makes sure
+that the right Gradle version is installed and used for the build. More info/guide: <a>https://docs.gradle.org/current/userguide/gradle_wrapper.html</a>`
+
+// distributionUrl=https\://services.gradle.org/distributions/gradle-4.4-all.zip
+var gradleDistributionURLRegexp = regexp.MustCompile(`(?m)^\s*distributionUrl\s*[=:].*gradle-([^-/\s]+)-(?:all|bin)\.zip\s*$`)
+
+// gradleWrapper describes the Gradle Wrapper of a project.
+type gradleWrapper struct {
+	exists     bool
+	executable bool
+	// version is the Gradle version declared in gradle-wrapper.properties, empty if not found
+	version string
+}
+
+func parseGradleWrapperVersion(propertiesContent string) string {
+	match := gradleDistributionURLRegexp.FindStringSubmatch(propertiesContent)
+	if len(match) != 2 {
+		return ""
+	}
+	return match[1]
+}
+
+func inspectGradleWrapper(projectDir string) (gradleWrapper, error) {
+	wrapper := gradleWrapper{}
+
+	info, err := os.Stat(filepath.Join(projectDir, gradlewBase))
+	if os.IsNotExist(err) {
+		return wrapper, nil
+	} else if err != nil {
+		return gradleWrapper{}, err
+	}
+
+	wrapper.exists = true
+	wrapper.executable = info.Mode()&0111 != 0
+
+	propertiesPth := filepath.Join(projectDir, gradleWrapperPropertiesPth)
+	if exist, err := pathutil.IsPathExists(propertiesPth); err != nil {
+		return gradleWrapper{}, err
+	} else if !exist {
+		return wrapper, nil
+	}
+
+	content, err := fileutil.ReadStringFromFile(propertiesPth)
+	if err != nil {
+		return gradleWrapper{}, err
+	}
+	wrapper.version = parseGradleWrapperVersion(content)
+
+	return wrapper, nil
+}
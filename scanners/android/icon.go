@@ -0,0 +1,122 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	manifestFileName        = "AndroidManifest.xml"
+	defaultLauncherIconType = "mipmap"
+	defaultLauncherIconName = "ic_launcher"
+)
+
+var (
+	// android:icon="@mipmap/ic_launcher"
+	manifestIconRegexp = regexp.MustCompile(`<application[^>]*\sandroid:icon\s*=\s*"@(mipmap|drawable)/([^"]+)"`)
+	// <foreground android:drawable="@mipmap/ic_launcher_foreground"/>
+	adaptiveIconForegroundRegexp = regexp.MustCompile(`<foreground[^>]*\sandroid:drawable\s*=\s*"@(mipmap|drawable)/([^"]+)"`)
+)
+
+// resource is an android resource reference, like @mipmap/ic_launcher.
+type resource struct {
+	resourceType string
+	name         string
+}
+
+func parseResource(re *regexp.Regexp, content string) (resource, bool) {
+	match := re.FindStringSubmatch(content)
+	if len(match) != 3 {
+		return resource{}, false
+	}
+	return resource{resourceType: match[1], name: match[2]}, true
+}
+
+// resourceImages returns the png images of the resource in the given res dir, like res/mipmap-xxxhdpi/ic_launcher.png.
+func resourceImages(resDir string, res resource) ([]string, error) {
+	return filepath.Glob(filepath.Join(resDir, res.resourceType+"*", res.name+".png"))
+}
+
+// launcherIconCandidates returns the launcher icon images of a source set's res dir.
+// Adaptive icons (res/mipmap-anydpi-v26/NAME.xml) are resolved to their foreground images.
+func launcherIconCandidates(resDir string, icon resource) ([]string, error) {
+	candidates, err := resourceImages(resDir, icon)
+	if err != nil {
+		return nil, err
+	}
+
+	adaptiveIcons, err := filepath.Glob(filepath.Join(resDir, icon.resourceType+"-anydpi*", icon.name+".xml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, adaptiveIcon := range adaptiveIcons {
+		content, err := fileutil.ReadStringFromFile(adaptiveIcon)
+		if err != nil {
+			return nil, err
+		}
+
+		foreground, ok := parseResource(adaptiveIconForegroundRegexp, content)
+		if !ok {
+			continue
+		}
+		images, err := resourceImages(resDir, foreground)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range images {
+			if !sliceutil.IsStringInSlice(image, candidates) {
+				candidates = append(candidates, image)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+// FindLauncherIcons returns the highest resolution launcher icon of the android project found in projectRoot,
+// the icon path is relative to relDir.
+// The launcher icon is read from the AndroidManifest.xml files, ic_launcher is used if not set.
+func FindLauncherIcons(projectRoot, relDir string) (models.Icons, error) {
+	candidates := []string{}
+	err := walk(projectRoot, func(pth string, info os.FileInfo) error {
+		if info.IsDir() {
+			if sliceutil.IsStringInSlice(info.Name(), testSearchSkipDirNames) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() != manifestFileName {
+			return nil
+		}
+
+		content, err := fileutil.ReadStringFromFile(pth)
+		if err != nil {
+			return err
+		}
+		icon, ok := parseResource(manifestIconRegexp, content)
+		if !ok {
+			icon = resource{resourceType: defaultLauncherIconType, name: defaultLauncherIconName}
+		}
+
+		images, err := launcherIconCandidates(filepath.Join(filepath.Dir(pth), "res"), icon)
+		if err != nil {
+			return err
+		}
+		candidates = append(candidates, images...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if icon, found := utility.LargestIcon(candidates, relDir); found {
+		return models.Icons{icon}, nil
+	}
+	return models.Icons{}, nil
+}
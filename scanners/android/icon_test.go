@@ -0,0 +1,88 @@
+package android
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writePNG(t *testing.T, pth string, width, height int) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0777))
+	f, err := os.Create(pth)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))))
+	require.NoError(t, f.Close())
+}
+
+const adaptiveIconContent = `<?xml version="1.0" encoding="utf-8"?>
+<adaptive-icon xmlns:android="http://schemas.android.com/apk/res/android">
+    <background android:drawable="@color/ic_launcher_background"/>
+    <foreground android:drawable="@mipmap/ic_app_foreground"/>
+</adaptive-icon>`
+
+func TestFindLauncherIcons(t *testing.T) {
+	t.Log("default launcher icon")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android_icon__")
+		require.NoError(t, err)
+
+		mainDir := filepath.Join(tmpDir, "app", "src", "main")
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "build.gradle"), ""))
+		require.NoError(t, os.MkdirAll(mainDir, 0777))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(mainDir, manifestFileName), `<manifest><application android:label="App"></application></manifest>`))
+		writePNG(t, filepath.Join(mainDir, "res", "mipmap-mdpi", "ic_launcher.png"), 48, 48)
+		writePNG(t, filepath.Join(mainDir, "res", "mipmap-xxxhdpi", "ic_launcher.png"), 192, 192)
+		writePNG(t, filepath.Join(mainDir, "res", "mipmap-xxxhdpi", "ic_launcher_round.png"), 512, 512)
+		writePNG(t, filepath.Join(tmpDir, "app", "build", "res", "mipmap-xxxhdpi", "ic_launcher.png"), 1024, 1024)
+
+		icons, err := FindLauncherIcons(tmpDir, tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, models.Icons{
+			{Path: "app/src/main/res/mipmap-xxxhdpi/ic_launcher.png", Width: 192, Height: 192},
+		}, icons)
+	}
+
+	t.Log("adaptive icon, set in the manifest")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android_icon__")
+		require.NoError(t, err)
+
+		mainDir := filepath.Join(tmpDir, "app", "src", "main")
+		require.NoError(t, os.MkdirAll(mainDir, 0777))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(mainDir, manifestFileName), `<manifest>
+    <application
+        android:allowBackup="true"
+        android:icon="@mipmap/ic_app"
+        android:label="App">
+    </application>
+</manifest>`))
+		require.NoError(t, os.MkdirAll(filepath.Join(mainDir, "res", "mipmap-anydpi-v26"), 0777))
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(mainDir, "res", "mipmap-anydpi-v26", "ic_app.xml"), adaptiveIconContent))
+		writePNG(t, filepath.Join(mainDir, "res", "mipmap-xxxhdpi", "ic_app.png"), 192, 192)
+		writePNG(t, filepath.Join(mainDir, "res", "mipmap-xxxhdpi", "ic_app_foreground.png"), 432, 432)
+		writePNG(t, filepath.Join(mainDir, "res", "mipmap-xxxhdpi", "ic_launcher.png"), 1024, 1024)
+
+		icons, err := FindLauncherIcons(tmpDir, tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, models.Icons{
+			{Path: "app/src/main/res/mipmap-xxxhdpi/ic_app_foreground.png", Width: 432, Height: 432},
+		}, icons)
+	}
+
+	t.Log("no icon")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("__android_icon__")
+		require.NoError(t, err)
+
+		icons, err := FindLauncherIcons(tmpDir, tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, models.Icons{}, icons)
+	}
+}
@@ -0,0 +1,103 @@
+package android
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const applicationPluginID = "com.android.application"
+
+var (
+	// id("com.android.application"), id 'com.android.application'
+	pluginIDRegexp = regexp.MustCompile(`\bid\s*\(?\s*["']([^"']+)["']`)
+	// apply plugin: 'com.android.application'
+	applyPluginRegexp = regexp.MustCompile(`\bapply\s+plugin\s*:\s*["']([^"']+)["']`)
+	// alias(libs.plugins.android.application)
+	pluginAliasRegexp = regexp.MustCompile(`\balias\s*\(\s*(libs\.plugins\.[A-Za-z0-9_.]+)\s*\)`)
+	applyFalseRegexp  = regexp.MustCompile(`\bapply\s*\(?\s*false\b`)
+)
+
+// appliedPlugins returns the IDs of the plugins applied by the build file (Groovy or Kotlin DSL),
+// the version catalog plugin aliases are resolved. The plugins declared with apply false are not applied by the build file.
+func appliedPlugins(content string, catalogPlugins map[string]string) []string {
+	plugins := []string{}
+	add := func(id string) {
+		if !sliceutil.IsStringInSlice(id, plugins) {
+			plugins = append(plugins, id)
+		}
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+		if applyFalseRegexp.MatchString(line) {
+			continue
+		}
+
+		for _, match := range pluginIDRegexp.FindAllStringSubmatch(line, -1) {
+			add(match[1])
+		}
+		for _, match := range applyPluginRegexp.FindAllStringSubmatch(line, -1) {
+			add(match[1])
+		}
+		for _, match := range pluginAliasRegexp.FindAllStringSubmatch(line, -1) {
+			if id, ok := resolveCatalogPluginReference(match[1], catalogPlugins); ok {
+				add(id)
+			}
+		}
+	}
+	return plugins
+}
+
+// ApplicationModules returns the modules of the project applying the Android application plugin,
+// by their directory relative to the project root, in alphabetical order.
+func ApplicationModules(projectRoot string) ([]string, error) {
+	catalog, err := readVersionCatalog(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []string{}
+	err = walk(projectRoot, func(path string, info os.FileInfo) error {
+		if info.IsDir() {
+			if sliceutil.IsStringInSlice(info.Name(), testSearchSkipDirNames) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Name() != "build.gradle" && info.Name() != "build.gradle.kts" {
+			return nil
+		}
+
+		content, err := fileutil.ReadStringFromFile(path)
+		if err != nil {
+			return err
+		}
+		if !sliceutil.IsStringInSlice(applicationPluginID, appliedPlugins(content, catalog.plugins)) {
+			return nil
+		}
+
+		module, err := filepath.Rel(projectRoot, filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+		if !sliceutil.IsStringInSlice(module, modules) {
+			modules = append(modules, module)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(modules)
+	return modules, nil
+}
@@ -0,0 +1,242 @@
+package android
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// sdkVersionSettings are the inspected SDK version settings of the module build files,
+// matching both the Groovy (minSdkVersion 21, minSdk 21) and the Kotlin DSL (minSdk = 21, minSdkVersion(21)) syntax.
+// The value expressions are resolved by resolveGradleValue.
+var sdkVersionSettings = []struct {
+	name   string
+	regexp *regexp.Regexp
+}{
+	{"minSdkVersion", regexp.MustCompile(`(?m)^\s*minSdk(?:Version)?\b\s*(?:=\s*)?(.+?)\s*$`)},
+	{"targetSdkVersion", regexp.MustCompile(`(?m)^\s*targetSdk(?:Version)?\b\s*(?:=\s*)?(.+?)\s*$`)},
+}
+
+// SDKVersions returns the SDK versions declared by the build files of the project's modules, by setting name, in ascending order.
+// The version catalog (gradle/libs.versions.toml) references are resolved.
+func SDKVersions(projectRoot string) (map[string][]int, error) {
+	catalog, err := readVersionCatalog(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string][]int{}
+	err = walk(projectRoot, func(path string, info os.FileInfo) error {
+		if info.IsDir() {
+			if sliceutil.IsStringInSlice(info.Name(), testSearchSkipDirNames) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Name() != "build.gradle" && info.Name() != "build.gradle.kts" {
+			return nil
+		}
+
+		content, err := fileutil.ReadStringFromFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, setting := range sdkVersionSettings {
+			for _, match := range setting.regexp.FindAllStringSubmatch(content, -1) {
+				value, ok := resolveGradleValue(match[1], catalog.versions)
+				if !ok {
+					continue
+				}
+				version, err := strconv.Atoi(value)
+				if err != nil {
+					continue
+				}
+				if !containsInt(versions[setting.name], version) {
+					versions[setting.name] = append(versions[setting.name], version)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, settingVersions := range versions {
+		sort.Ints(settingVersions)
+	}
+	return versions, nil
+}
+
+func containsInt(slice []int, value int) bool {
+	for _, item := range slice {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectSDKVersions summarizes the SDK versions of the project,
+// if the modules declare different versions, the minimum is reported with a warning.
+func inspectSDKVersions(projectRoot, relProjectRoot string) (models.Summary, models.Warnings, error) {
+	versions, err := SDKVersions(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+	descriptions := []string{}
+	for _, setting := range sdkVersionSettings {
+		settingVersions := versions[setting.name]
+		if len(settingVersions) == 0 {
+			continue
+		}
+
+		minVersion := settingVersions[0]
+		descriptions = append(descriptions, fmt.Sprintf("%s: %d", setting.name, minVersion))
+
+		if len(settingVersions) > 1 {
+			values := []string{}
+			for _, version := range settingVersions {
+				values = append(values, strconv.Itoa(version))
+			}
+
+			warning := fmt.Sprintf("The modules of %s declare different %s values (%s), the minimum (%d) is used", relProjectRoot, setting.name, strings.Join(values, ", "), minVersion)
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		}
+	}
+
+	if len(descriptions) > 0 {
+		log.TPrintf("SDK versions: %s", strings.Join(descriptions, ", "))
+		summary = append(summary, fmt.Sprintf("%s: %s", relProjectRoot, strings.Join(descriptions, ", ")))
+	}
+
+	return summary, warnings, nil
+}
+
+var (
+	compileSdkVersionRegexp = regexp.MustCompile(`(?m)^\s*compileSdk(?:Version)?\b\s*(?:=\s*)?(.+?)\s*$`)
+	buildToolsVersionRegexp = regexp.MustCompile(`(?m)^\s*buildToolsVersion\b\s*(?:=\s*)?(.+?)\s*$`)
+
+	apiLevelRegexp           = regexp.MustCompile(`^(?:android-)?(\d+)$`)
+	buildToolsRevisionRegexp = regexp.MustCompile(`^\d+(\.\d+)*(-rc\d+)?$`)
+)
+
+// resolveGradleValue returns the literal value of a build file setting's value expression,
+// resolving the version catalog references, like: 28, "28.0.3", ("android-28"), libs.versions.compileSdk.get().toInt()
+func resolveGradleValue(expression string, catalogVersions map[string]string) (string, bool) {
+	if idx := strings.Index(expression, "//"); idx != -1 {
+		expression = expression[:idx]
+	}
+	expression = strings.TrimSpace(expression)
+	if strings.HasPrefix(expression, "(") && strings.HasSuffix(expression, ")") {
+		expression = strings.TrimSpace(expression[1 : len(expression)-1])
+	}
+
+	if len(expression) >= 2 && strings.ContainsAny(expression[:1], `"'`) && expression[len(expression)-1] == expression[0] {
+		return expression[1 : len(expression)-1], true
+	}
+	if version, ok := resolveCatalogVersionReference(expression, catalogVersions); ok {
+		return version, true
+	}
+	if apiLevelRegexp.MatchString(expression) || buildToolsRevisionRegexp.MatchString(expression) {
+		return expression, true
+	}
+	return "", false
+}
+
+// SDKComponents are the Android SDK components required to build the project.
+type SDKComponents struct {
+	CompileSdkVersions []string
+	BuildToolsVersions []string
+}
+
+// Packages returns the sdkmanager package paths of the components.
+func (components SDKComponents) Packages() []string {
+	packages := []string{}
+	for _, version := range components.CompileSdkVersions {
+		packages = append(packages, "platforms;android-"+version)
+	}
+	for _, version := range components.BuildToolsVersions {
+		packages = append(packages, "build-tools;"+version)
+	}
+	return packages
+}
+
+// ParseSDKComponents returns the compileSdkVersion and buildToolsVersion values declared by the module build files (Groovy or Kotlin DSL),
+// the version catalog (gradle/libs.versions.toml) references are resolved.
+// The returned unresolved list contains the value expressions which could not be parsed, like: rootProject.ext.compileSdkVersion
+func ParseSDKComponents(projectRoot string) (SDKComponents, []string, error) {
+	catalog, err := readVersionCatalog(projectRoot)
+	if err != nil {
+		return SDKComponents{}, nil, err
+	}
+
+	components := SDKComponents{}
+	unresolved := []string{}
+	err = walk(projectRoot, func(path string, info os.FileInfo) error {
+		if info.IsDir() {
+			if sliceutil.IsStringInSlice(info.Name(), testSearchSkipDirNames) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if info.Name() != "build.gradle" && info.Name() != "build.gradle.kts" {
+			return nil
+		}
+
+		content, err := fileutil.ReadStringFromFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range compileSdkVersionRegexp.FindAllStringSubmatch(content, -1) {
+			value, ok := resolveGradleValue(match[1], catalog.versions)
+			if apiLevel := apiLevelRegexp.FindStringSubmatch(value); ok && len(apiLevel) == 2 {
+				if !sliceutil.IsStringInSlice(apiLevel[1], components.CompileSdkVersions) {
+					components.CompileSdkVersions = append(components.CompileSdkVersions, apiLevel[1])
+				}
+				continue
+			}
+			unresolved = append(unresolved, "compileSdkVersion "+match[1])
+		}
+
+		for _, match := range buildToolsVersionRegexp.FindAllStringSubmatch(content, -1) {
+			value, ok := resolveGradleValue(match[1], catalog.versions)
+			if ok && buildToolsRevisionRegexp.MatchString(value) {
+				if !sliceutil.IsStringInSlice(value, components.BuildToolsVersions) {
+					components.BuildToolsVersions = append(components.BuildToolsVersions, value)
+				}
+				continue
+			}
+			unresolved = append(unresolved, "buildToolsVersion "+match[1])
+		}
+		return nil
+	})
+	if err != nil {
+		return SDKComponents{}, nil, err
+	}
+
+	sort.Slice(components.CompileSdkVersions, func(i, j int) bool {
+		a, _ := strconv.Atoi(components.CompileSdkVersions[i])
+		b, _ := strconv.Atoi(components.CompileSdkVersions[j])
+		return a < b
+	})
+	sort.Strings(components.BuildToolsVersions)
+	return components, unresolved, nil
+}
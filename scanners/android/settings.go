@@ -0,0 +1,103 @@
+package android
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// defaultModule is offered for building if no application module is detected.
+const defaultModule = "app"
+
+var (
+	// include ':app', ':wear' or include(":app", ":feature:tv")
+	includeRegexp = regexp.MustCompile(`^\s*include\b\s*\(?(.*)`)
+	// a quoted Gradle project path, like ':app' or ":feature:tv"
+	projectPathRegexp = regexp.MustCompile(`["'](:?[A-Za-z0-9_.\-:]+)["']`)
+)
+
+// parseIncludedModules returns the modules included by the settings file (Groovy or Kotlin DSL), in order of appearance,
+// by their Gradle project path without the leading colon (like app, or feature:tv for a nested module).
+func parseIncludedModules(content string) []string {
+	modules := []string{}
+	for _, line := range strings.Split(content, "\n") {
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = line[:idx]
+		}
+
+		match := includeRegexp.FindStringSubmatch(line)
+		if len(match) != 2 {
+			continue
+		}
+
+		for _, pathMatch := range projectPathRegexp.FindAllStringSubmatch(match[1], -1) {
+			module := strings.TrimPrefix(pathMatch[1], ":")
+			if module != "" && !sliceutil.IsStringInSlice(module, modules) {
+				modules = append(modules, module)
+			}
+		}
+	}
+	return modules
+}
+
+// IncludedModules returns the modules included by the project's settings.gradle or settings.gradle.kts,
+// by their Gradle project path without the leading colon.
+func IncludedModules(projectRoot string) ([]string, error) {
+	modules := []string{}
+	for _, name := range []string{"settings.gradle", "settings.gradle.kts"} {
+		pth := filepath.Join(projectRoot, name)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return nil, err
+		} else if !exist {
+			continue
+		}
+
+		content, err := fileutil.ReadStringFromFile(pth)
+		if err != nil {
+			return nil, err
+		}
+		for _, module := range parseIncludedModules(content) {
+			if !sliceutil.IsStringInSlice(module, modules) {
+				modules = append(modules, module)
+			}
+		}
+	}
+	return modules, nil
+}
+
+// buildModules returns the modules offered for building, as MODULE values: the modules included by the settings file
+// which apply the Android application plugin (the given application modules, by their directory), in alphabetical order.
+// The library modules are not offered. If the settings file includes no modules, every application module is offered,
+// if no application module is detected, the app module is offered.
+func buildModules(projectRoot string, applicationModules []string) ([]string, error) {
+	includedModules, err := IncludedModules(projectRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	modules := []string{}
+	if len(includedModules) == 0 {
+		for _, module := range applicationModules {
+			if module != "." {
+				modules = append(modules, strings.Replace(filepath.ToSlash(module), "/", ":", -1))
+			}
+		}
+	}
+	for _, module := range includedModules {
+		moduleDir := filepath.FromSlash(strings.Replace(module, ":", "/", -1))
+		if sliceutil.IsStringInSlice(moduleDir, applicationModules) {
+			modules = append(modules, module)
+		}
+	}
+
+	if len(modules) == 0 {
+		return []string{defaultModule}, nil
+	}
+	sort.Strings(modules)
+	return modules, nil
+}
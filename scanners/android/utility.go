@@ -1,12 +1,15 @@
 package android
 
 import (
-	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/e2e"
 	"github.com/bitrise-core/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
@@ -34,6 +37,36 @@ const (
 	GradlewPathInputKey    = "gradlew_path"
 	GradlewPathInputEnvKey = "GRADLEW_PATH"
 	GradlewPathInputTitle  = "Gradlew file path"
+
+	GradleFileInputKey = "gradle_file"
+	GradleTaskInputKey = "gradle_task"
+
+	SDKComponentsInputEnvKey = "ANDROID_SDK_COMPONENTS"
+	SDKComponentsInputTitle  = "Android SDK components"
+)
+
+const (
+	installSDKComponentsStepTitle     = "Install Android SDK components"
+	installSDKComponentsScriptContent = `#!/usr/bin/env bash
+set -ex
+
+sdkmanager="$ANDROID_HOME/tools/bin/sdkmanager"
+if [ -x "$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager" ] ; then
+  sdkmanager="$ANDROID_HOME/cmdline-tools/latest/bin/sdkmanager"
+fi
+
+# the sdkmanager packages are separated by spaces
+yes | "$sdkmanager" $` + SDKComponentsInputEnvKey + `
+`
+)
+
+const (
+	chmodGradlewStepTitle     = "Make gradlew executable"
+	chmodGradlewScriptContent = `#!/usr/bin/env bash
+set -ex
+
+chmod +x %s
+`
 )
 
 func walk(src string, fn func(path string, info os.FileInfo) error) error {
@@ -86,27 +119,115 @@ func walkMultipleFiles(searchDir string, files ...string) (matches []string, err
 	})
 }
 
-func checkGradlew(projectDir string) error {
-	gradlewPth := filepath.Join(projectDir, "gradlew")
-	exist, err := pathutil.IsPathExists(gradlewPth)
-	if err != nil {
-		return err
+var (
+	testSourceSetDirNames = []string{"test", "androidTest"}
+	testDependencyConfigs = []string{"testImplementation", "androidTestImplementation", "testCompile", "androidTestCompile"}
+	// testSearchSkipDirNames are shared with the UI test detection
+	testSearchSkipDirNames = e2e.AndroidSkipDirNames
+)
+
+// hasTest checks if the project contains unit or instrumented test source sets (src/test, src/androidTest),
+// or declares test dependencies.
+func hasTest(projectRoot string) (bool, error) {
+	return e2e.HasAndroidTest(projectRoot, testSourceSetDirNames, testDependencyConfigs)
+}
+
+// ConfigDescriptor ...
+type ConfigDescriptor struct {
+	MissingGradlew       bool
+	GradlewNotExecutable bool
+	HasTest              bool
+	HasUITest            bool
+	// HasSDKComponents means the SDK components declared in the build files are installed before the install-missing-android-tools step,
+	// the components are read from the app env of the SDK components info option.
+	HasSDKComponents bool
+}
+
+// ConfigName ...
+func (descriptor ConfigDescriptor) ConfigName() string {
+	qualifiers := ""
+	if descriptor.MissingGradlew {
+		qualifiers += "-no-gradlew"
+	} else if descriptor.GradlewNotExecutable {
+		qualifiers += "-chmod-gradlew"
 	}
-	if !exist {
-		return errors.New(`<b>No Gradle Wrapper (gradlew) found.</b> 
-Using a Gradle Wrapper (gradlew) is required, as the wrapper is what makes sure
-that the right Gradle version is installed and used for the build. More info/guide: <a>https://docs.gradle.org/current/userguide/gradle_wrapper.html</a>`)
+	if descriptor.HasSDKComponents {
+		qualifiers += "-sdk-components"
+	}
+	if descriptor.HasTest {
+		qualifiers += "-test"
+	}
+	if descriptor.HasUITest {
+		qualifiers += "-ui-test"
+	}
+	return ScannerName + qualifiers + "-config"
+}
+
+// addSDKComponentsOption inserts the read-only info option of the detected SDK components in front of the config options,
+// its value (the sdkmanager packages separated by spaces) is read by the install script step from the app envs.
+func addSDKComponentsOption(rootOption *models.OptionNode, components SDKComponents) {
+	for _, lastChild := range rootOption.LastChilds() {
+		for value, child := range lastChild.ChildOptionMap {
+			if child == nil || !child.IsConfigOption() {
+				continue
+			}
+
+			sdkComponentsOption := models.NewInfoOption(SDKComponentsInputTitle, SDKComponentsInputEnvKey)
+			lastChild.AddOption(value, sdkComponentsOption)
+			sdkComponentsOption.AddConfig(strings.Join(components.Packages(), " "), child)
+		}
+	}
+}
+
+// installSDKComponentsStepListItems returns the script step installing the SDK components declared in the build files,
+// empty if no components detected.
+func installSDKComponentsStepListItems(descriptor ConfigDescriptor) []bitriseModels.StepListItemModel {
+	if !descriptor.HasSDKComponents {
+		return nil
+	}
+	return []bitriseModels.StepListItemModel{
+		steps.ScriptSteplistItem(installSDKComponentsStepTitle,
+			envmanModels.EnvironmentItemModel{"content": installSDKComponentsScriptContent},
+		),
+	}
+}
+
+// apkOutputPath returns the directory of the APKs built for the selected variant, like: app/build/outputs/apk/release
+func apkOutputPath(projectLocationEnv, moduleEnv, variantEnv string) string {
+	return filepath.Join(projectLocationEnv, moduleEnv, "build", "outputs", "apk", variantEnv)
+}
+
+// testReportPaths returns the JUnit test report directory of the module, if the project has unit tests.
+func testReportPaths(descriptor ConfigDescriptor, projectLocationEnv, moduleEnv string) []string {
+	if !descriptor.HasTest {
+		return nil
 	}
-	return nil
+	return []string{filepath.Join(projectLocationEnv, moduleEnv, "build", "test-results")}
 }
 
-func (scanner *Scanner) generateConfigBuilder() models.ConfigBuilderModel {
+func (scanner *Scanner) generateConfigBuilder(descriptor ConfigDescriptor) models.ConfigBuilderModel {
+	if descriptor.MissingGradlew {
+		return generateNoGradlewConfigBuilder(descriptor)
+	}
+
 	configBuilder := models.NewDefaultConfigBuilder()
 
 	projectLocationEnv, gradlewPath, moduleEnv, variantEnv := "$"+ProjectLocationInputEnvKey, "$"+ProjectLocationInputEnvKey+"/gradlew", "$"+ModuleInputEnvKey, "$"+VariantInputEnvKey
+	apkPaths, reportPaths := []string{apkOutputPath(projectLocationEnv, moduleEnv, variantEnv)}, testReportPaths(descriptor, projectLocationEnv, moduleEnv)
+
+	sdkComponentsStepListItems := installSDKComponentsStepListItems(descriptor)
+
+	var chmodGradlewStepListItems []bitriseModels.StepListItemModel
+	if descriptor.GradlewNotExecutable {
+		chmodGradlewStepListItems = append(chmodGradlewStepListItems, steps.ScriptSteplistItem(chmodGradlewStepTitle,
+			envmanModels.EnvironmentItemModel{"content": fmt.Sprintf(chmodGradlewScriptContent, gradlewPath)},
+		))
+	}
 
 	//-- primary
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, chmodGradlewStepListItems...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, sdkComponentsStepListItems...)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
 		envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
 	))
@@ -132,10 +253,13 @@ func (scanner *Scanner) generateConfigBuilder() models.ConfigBuilderModel {
 			VariantInputKey: variantEnv,
 		},
 	))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ArtifactDeployStepList(nil, reportPaths)...)
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true)...)
 
 	//-- deploy
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, chmodGradlewStepListItems...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, sdkComponentsStepListItems...)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
 		envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
 	))
@@ -179,9 +303,93 @@ func (scanner *Scanner) generateConfigBuilder() models.ConfigBuilderModel {
 		},
 	))
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.SignAPKStepListItem())
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ArtifactDeployStepList(apkPaths, reportPaths)...)
 	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true)...)
 
 	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
 
+	//-- test
+	if descriptor.HasTest || descriptor.HasUITest {
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultPrepareStepList(true)...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, chmodGradlewStepListItems...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, sdkComponentsStepListItems...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.InstallMissingAndroidToolsStepListItem(
+			envmanModels.EnvironmentItemModel{GradlewPathInputKey: gradlewPath},
+		))
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.AndroidUnitTestStepListItem(
+			envmanModels.EnvironmentItemModel{
+				ProjectLocationInputKey: projectLocationEnv,
+			},
+			envmanModels.EnvironmentItemModel{
+				ModuleInputKey: moduleEnv,
+			},
+			envmanModels.EnvironmentItemModel{
+				VariantInputKey: variantEnv,
+			},
+		))
+		if descriptor.HasUITest {
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, e2e.AndroidStepList(projectLocationEnv, moduleEnv, variantEnv)...)
+		}
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.ArtifactDeployStepList(nil, reportPaths)...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultDeployStepList(true)...)
+	}
+
+	return *configBuilder
+}
+
+// generateNoGradlewConfigBuilder generates config for projects without Gradle Wrapper,
+// the Gradle executable is provided by the user (GRADLEW_PATH app env), the steps are used with their defaults.
+func generateNoGradlewConfigBuilder(descriptor ConfigDescriptor) models.ConfigBuilderModel {
+	configBuilder := models.NewDefaultConfigBuilder()
+
+	projectLocationEnv, moduleEnv, variantEnv := "$"+ProjectLocationInputEnvKey, "$"+ModuleInputEnvKey, "$"+VariantInputEnvKey
+	apkPaths, reportPaths := []string{apkOutputPath(projectLocationEnv, moduleEnv, variantEnv)}, testReportPaths(descriptor, projectLocationEnv, moduleEnv)
+	gradleFile := filepath.Join(projectLocationEnv, "build.gradle")
+	sdkComponentsStepListItems := installSDKComponentsStepListItems(descriptor)
+
+	//-- primary
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, sdkComponentsStepListItems...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.InstallMissingAndroidToolsStepListItem())
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.GradleRunnerStepListItem(
+		envmanModels.EnvironmentItemModel{GradleFileInputKey: gradleFile},
+		envmanModels.EnvironmentItemModel{GradleTaskInputKey: ":" + moduleEnv + ":test" + variantEnv},
+	))
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ArtifactDeployStepList(nil, reportPaths)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(true)...)
+
+	//-- deploy
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(true)...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, sdkComponentsStepListItems...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.InstallMissingAndroidToolsStepListItem())
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ChangeAndroidVersionCodeAndVersionNameStepListItem(
+		envmanModels.EnvironmentItemModel{ModuleBuildGradlePathInputKey: filepath.Join(projectLocationEnv, moduleEnv, "build.gradle")},
+	))
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.GradleRunnerStepListItem(
+		envmanModels.EnvironmentItemModel{GradleFileInputKey: gradleFile},
+		envmanModels.EnvironmentItemModel{GradleTaskInputKey: ":" + moduleEnv + ":assemble" + variantEnv},
+	))
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.SignAPKStepListItem())
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ArtifactDeployStepList(apkPaths, reportPaths)...)
+	configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(true)...)
+
+	configBuilder.SetWorkflowDescriptionTo(models.DeployWorkflowID, deployWorkflowDescription)
+
+	//-- test
+	if descriptor.HasTest || descriptor.HasUITest {
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultPrepareStepList(true)...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, sdkComponentsStepListItems...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.InstallMissingAndroidToolsStepListItem())
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.GradleRunnerStepListItem(
+			envmanModels.EnvironmentItemModel{GradleFileInputKey: gradleFile},
+			envmanModels.EnvironmentItemModel{GradleTaskInputKey: ":" + moduleEnv + ":test" + variantEnv},
+		))
+		if descriptor.HasUITest {
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, e2e.AndroidStepList(projectLocationEnv, moduleEnv, variantEnv)...)
+		}
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.ArtifactDeployStepList(nil, reportPaths)...)
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultDeployStepList(true)...)
+	}
+
 	return *configBuilder
 }
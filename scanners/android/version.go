@@ -0,0 +1,125 @@
+package android
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/version"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const (
+	versionNameKey = "versionName"
+	versionCodeKey = "versionCode"
+)
+
+var (
+	// versionName "1.0", versionName = "1.0" or versionName("1.0")
+	versionNameRegexp = regexp.MustCompile(`(?m)^\s*versionName\b\s*(?:=\s*)?(.+?)\s*$`)
+	// versionCode 1, versionCode = 1 or versionCode(1)
+	versionCodeRegexp = regexp.MustCompile(`(?m)^\s*versionCode\b\s*(?:=\s*)?(.+?)\s*$`)
+)
+
+// moduleBuildFile returns the build file of the module (build.gradle or build.gradle.kts), empty if the module has none.
+func moduleBuildFile(moduleDir string) (string, error) {
+	for _, name := range []string{"build.gradle", "build.gradle.kts"} {
+		pth := filepath.Join(moduleDir, name)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return "", err
+		} else if exist {
+			return pth, nil
+		}
+	}
+	return "", nil
+}
+
+// gradleSetting returns the literal value of the first match of the setting in the build file's content, and whether the setting is declared.
+// The value is empty if it is an expression, like a function call or a property read from a generated file.
+func gradleSetting(content string, settingRegexp *regexp.Regexp, catalogVersions map[string]string) (string, bool) {
+	match := settingRegexp.FindStringSubmatch(content)
+	if len(match) != 2 {
+		return "", false
+	}
+	value, _ := resolveGradleValue(match[1], catalogVersions)
+	return value, true
+}
+
+// VersionSources returns where the versionName and versionCode of the given application modules are declared.
+// The modules declaring their version by an expression (like a property read from a generated file) are returned as unresolved,
+// the modules without versionName are skipped.
+func VersionSources(projectRoot string, applicationModules []string) ([]version.Source, []string, error) {
+	catalog, err := readVersionCatalog(projectRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := []version.Source{}
+	unresolved := []string{}
+	for _, module := range applicationModules {
+		buildFile, err := moduleBuildFile(filepath.Join(projectRoot, module))
+		if err != nil {
+			return nil, nil, err
+		} else if buildFile == "" {
+			continue
+		}
+
+		content, err := fileutil.ReadStringFromFile(buildFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		versionName, declared := gradleSetting(content, versionNameRegexp, catalog.versions)
+		if !declared {
+			continue
+		} else if versionName == "" {
+			unresolved = append(unresolved, module)
+			continue
+		}
+		versionCode, _ := gradleSetting(content, versionCodeRegexp, catalog.versions)
+
+		relBuildFile, err := filepath.Rel(projectRoot, buildFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		sources = append(sources, version.Source{
+			App:            module,
+			File:           relBuildFile,
+			VersionKey:     versionNameKey,
+			Version:        versionName,
+			BuildNumberKey: versionCodeKey,
+			BuildNumber:    versionCode,
+		})
+	}
+
+	return sources, unresolved, nil
+}
+
+// inspectVersionSources summarizes where the versions of the project's application modules are declared,
+// the modules with an unresolved version are reported with a warning.
+func inspectVersionSources(projectRoot, relProjectRoot string, applicationModules []string) (models.Summary, models.Warnings, error) {
+	sources, unresolved, err := VersionSources(projectRoot, applicationModules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+	if len(sources) > 0 {
+		versionSummary := version.Summary(relProjectRoot, sources)
+		log.TPrintf(versionSummary)
+		summary = append(summary, versionSummary)
+	}
+	if len(unresolved) > 0 {
+		warning := fmt.Sprintf("The versionName of %s (%s) is not a literal value, it probably comes from a properties or a generated file", relProjectRoot, strings.Join(unresolved, ", "))
+		log.TWarnf(warning)
+		warnings = append(warnings, warning)
+	}
+
+	return summary, warnings, nil
+}
@@ -0,0 +1,110 @@
+package android
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const versionCatalogPth = "gradle/libs.versions.toml"
+
+var (
+	tomlTableRegexp         = regexp.MustCompile(`^\[([^\]]+)\]$`)
+	tomlStringEntryRegexp   = regexp.MustCompile(`^([A-Za-z0-9_.-]+)\s*=\s*["']([^"']*)["']`)
+	tomlPluginIDRegexp      = regexp.MustCompile(`^([A-Za-z0-9_.-]+)\s*=\s*\{.*\bid\s*=\s*["']([^"']+)["']`)
+	catalogVersionRefRegexp = regexp.MustCompile(`^libs\.versions\.([A-Za-z0-9_.]+?)(?:\.get\(\))?(?:\.toInt\(\))?$`)
+	catalogPluginRefRegexp  = regexp.MustCompile(`^libs\.plugins\.([A-Za-z0-9_.]+?)(?:\.get\(\))?$`)
+)
+
+// versionCatalog holds the entries of the version catalog (gradle/libs.versions.toml) used by the scanner,
+// by their accessor key (the -, _ separators of the alias are replaced by dots, like in the generated libs accessors).
+type versionCatalog struct {
+	// versions are the plain string versions of the [versions] table
+	versions map[string]string
+	// plugins are the plugin IDs of the [plugins] table
+	plugins map[string]string
+}
+
+// parseVersionCatalog parses the [versions] and [plugins] tables of the version catalog.
+// The plugins are declared either as a table (android-application = { id = "com.android.application", version.ref = "agp" })
+// or as a "<id>:<version>" string.
+func parseVersionCatalog(content string) versionCatalog {
+	catalog := versionCatalog{
+		versions: map[string]string{},
+		plugins:  map[string]string{},
+	}
+
+	table := ""
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if match := tomlTableRegexp.FindStringSubmatch(line); len(match) == 2 {
+			table = strings.TrimSpace(match[1])
+			continue
+		}
+
+		switch table {
+		case "versions":
+			if match := tomlStringEntryRegexp.FindStringSubmatch(line); len(match) == 3 {
+				catalog.versions[catalogAccessorKey(match[1])] = match[2]
+			}
+		case "plugins":
+			if match := tomlPluginIDRegexp.FindStringSubmatch(line); len(match) == 3 {
+				catalog.plugins[catalogAccessorKey(match[1])] = match[2]
+			} else if match := tomlStringEntryRegexp.FindStringSubmatch(line); len(match) == 3 {
+				catalog.plugins[catalogAccessorKey(match[1])] = strings.Split(match[2], ":")[0]
+			}
+		}
+	}
+
+	return catalog
+}
+
+func catalogAccessorKey(alias string) string {
+	return strings.NewReplacer("-", ".", "_", ".").Replace(alias)
+}
+
+// readVersionCatalog returns the project's version catalog (gradle/libs.versions.toml),
+// empty if the project has no version catalog.
+func readVersionCatalog(projectRoot string) (versionCatalog, error) {
+	pth := filepath.Join(projectRoot, versionCatalogPth)
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		return versionCatalog{}, err
+	} else if !exist {
+		return parseVersionCatalog(""), nil
+	}
+
+	content, err := fileutil.ReadStringFromFile(pth)
+	if err != nil {
+		return versionCatalog{}, err
+	}
+	return parseVersionCatalog(content), nil
+}
+
+// resolveCatalogVersionReference returns the catalog version referenced by the given expression,
+// like: libs.versions.compileSdk.get().toInt()
+func resolveCatalogVersionReference(expression string, catalogVersions map[string]string) (string, bool) {
+	match := catalogVersionRefRegexp.FindStringSubmatch(expression)
+	if len(match) != 2 {
+		return "", false
+	}
+	version, ok := catalogVersions[catalogAccessorKey(match[1])]
+	return version, ok
+}
+
+// resolveCatalogPluginReference returns the ID of the catalog plugin referenced by the given expression,
+// like: libs.plugins.android.application
+func resolveCatalogPluginReference(expression string, catalogPlugins map[string]string) (string, bool) {
+	match := catalogPluginRefRegexp.FindStringSubmatch(expression)
+	if len(match) != 2 {
+		return "", false
+	}
+	id, ok := catalogPlugins[catalogAccessorKey(match[1])]
+	return id, ok
+}
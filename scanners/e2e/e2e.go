@@ -0,0 +1,243 @@
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-core/bitrise-init/utility"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/pointers"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// Framework is a UI testing framework.
+type Framework string
+
+const (
+	// Appium ...
+	Appium Framework = "Appium"
+	// Detox ...
+	Detox Framework = "Detox"
+	// AndroidInstrumentation ...
+	AndroidInstrumentation Framework = "Android instrumentation tests"
+)
+
+const (
+	// RunUITestsInputTitle ...
+	RunUITestsInputTitle = "Run the UI tests on a virtual device? (type yes to enable)"
+	// RunUITestsInputEnvKey ...
+	RunUITestsInputEnvKey = "RUN_UI_TESTS"
+	// RunUITestsEnabledValue ...
+	RunUITestsEnabledValue = "yes"
+)
+
+// runUITestsRunIf runs the UI testing steps only if the user enabled them.
+var runUITestsRunIf = fmt.Sprintf(`{{enveq "%s" "%s"}}`, RunUITestsInputEnvKey, RunUITestsEnabledValue)
+
+var (
+	detoxConfigFileNames  = []string{".detoxrc", ".detoxrc.js", ".detoxrc.json", "detox.config.js"}
+	androidBuildFileNames = []string{"build.gradle", "build.gradle.kts"}
+)
+
+// AndroidSkipDirNames are the build output and dependency dirs, not searched in the android projects.
+var AndroidSkipDirNames = []string{"build", ".gradle", ".git", "node_modules"}
+
+const (
+	androidTestSourceSetDirName = "androidTest"
+	testInstrumentationRunner   = "testInstrumentationRunner"
+)
+
+// DetectJSFrameworks returns the UI testing frameworks set up in the JS project of the given package.json:
+// Detox, if the package depends on detox or has a Detox config, Appium, if the package depends on appium.
+func DetectJSFrameworks(packageJSONPth string) ([]Framework, error) {
+	packages, err := utility.ParsePackagesJSON(packageJSONPth)
+	if err != nil {
+		return nil, err
+	}
+
+	hasDependency := func(name string) bool {
+		if _, found := packages.Dependencies[name]; found {
+			return true
+		}
+		_, found := packages.DevDependencies[name]
+		return found
+	}
+
+	frameworks := []Framework{}
+
+	hasDetox := hasDependency("detox") || len(packages.Detox) > 0
+	if !hasDetox {
+		projectDir := filepath.Dir(packageJSONPth)
+		for _, name := range detoxConfigFileNames {
+			exist, err := pathutil.IsPathExists(filepath.Join(projectDir, name))
+			if err != nil {
+				return nil, err
+			}
+			if exist {
+				hasDetox = true
+				break
+			}
+		}
+	}
+	if hasDetox {
+		frameworks = append(frameworks, Detox)
+	}
+
+	if hasDependency("appium") {
+		frameworks = append(frameworks, Appium)
+	}
+
+	return frameworks, nil
+}
+
+var errAndroidTestFound = errors.New("android test found")
+
+// HasAndroidTest checks if the android project in projectRoot has any of the given source sets (src/<name>),
+// or any of its build.gradle files contains any of the given build file contents.
+func HasAndroidTest(projectRoot string, sourceSetDirNames, buildFileContents []string) (bool, error) {
+	err := filepath.Walk(projectRoot, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if pth == projectRoot {
+			return nil
+		}
+
+		if info.IsDir() {
+			if sliceutil.IsStringInSlice(info.Name(), AndroidSkipDirNames) {
+				return filepath.SkipDir
+			}
+			if sliceutil.IsStringInSlice(info.Name(), sourceSetDirNames) && filepath.Base(filepath.Dir(pth)) == "src" {
+				return errAndroidTestFound
+			}
+			return nil
+		}
+
+		if !sliceutil.IsStringInSlice(info.Name(), androidBuildFileNames) {
+			return nil
+		}
+
+		content, err := fileutil.ReadStringFromFile(pth)
+		if err != nil {
+			return err
+		}
+		for _, buildFileContent := range buildFileContents {
+			if strings.Contains(content, buildFileContent) {
+				return errAndroidTestFound
+			}
+		}
+		return nil
+	})
+	if err == errAndroidTestFound {
+		return true, nil
+	}
+	return false, err
+}
+
+// DetectAndroidInstrumentation checks if the android project in projectRoot has instrumentation tests:
+// an androidTest source set (src/androidTest) or a testInstrumentationRunner set in a build.gradle file.
+func DetectAndroidInstrumentation(projectRoot string) (bool, error) {
+	return HasAndroidTest(projectRoot, []string{androidTestSourceSetDirName}, []string{testInstrumentationRunner})
+}
+
+// Summary returns the summary line of the UI testing frameworks detected in the given project dir.
+func Summary(relDir string, frameworks []Framework) string {
+	names := []string{}
+	for _, framework := range frameworks {
+		names = append(names, string(framework))
+	}
+	return fmt.Sprintf("%s: UI testing detected: %s", relDir, strings.Join(names, ", "))
+}
+
+// AddRunUITestsOption inserts the optional user input, enabling the UI testing steps, in front of the config options.
+func AddRunUITestsOption(rootOption *models.OptionNode) {
+	for _, lastChild := range rootOption.LastChilds() {
+		for value, child := range lastChild.ChildOptionMap {
+			if child == nil || !child.IsConfigOption() {
+				continue
+			}
+
+			runUITestsOption := models.NewUserInputOption(RunUITestsInputTitle, RunUITestsInputEnvKey, true)
+			lastChild.AddOption(value, runUITestsOption)
+			runUITestsOption.AddConfig("_", child)
+		}
+	}
+}
+
+// StepList returns the given steps, running only if the UI tests are enabled by the user.
+func StepList(stepList ...bitriseModels.StepListItemModel) []bitriseModels.StepListItemModel {
+	for _, stepListItem := range stepList {
+		for id, step := range stepListItem {
+			step.RunIf = pointers.NewStringPtr(runUITestsRunIf)
+			stepListItem[id] = step
+		}
+	}
+	return stepList
+}
+
+// JSStepList returns the steps running the UI tests of a JS project with the given frameworks,
+// workdir is the project's dir, relative to the repository root.
+func JSStepList(frameworks []Framework, workdir string, hasAndroidProject bool) []bitriseModels.StepListItemModel {
+	stepList := []bitriseModels.StepListItemModel{}
+	if hasAndroidProject {
+		stepList = append(stepList, steps.AVDManagerStepListItem(), steps.WaitForAndroidEmulatorStepListItem())
+	}
+
+	for _, framework := range frameworks {
+		content := ""
+		switch framework {
+		case Detox:
+			content = detoxScriptContent
+		case Appium:
+			content = appiumScriptContent
+		default:
+			continue
+		}
+
+		inputs := []envmanModels.EnvironmentItemModel{{"content": content}}
+		if workdir != "" {
+			inputs = append(inputs, envmanModels.EnvironmentItemModel{"working_dir": workdir})
+		}
+		stepList = append(stepList, steps.ScriptSteplistItem(fmt.Sprintf("Run %s tests", framework), inputs...))
+	}
+
+	return StepList(stepList...)
+}
+
+// AndroidStepList returns the steps building and running the instrumentation tests of an android project on a virtual device.
+func AndroidStepList(projectLocation, module, variant string) []bitriseModels.StepListItemModel {
+	return StepList(
+		steps.AndroidBuildForUITestingStepListItem(
+			envmanModels.EnvironmentItemModel{"project_location": projectLocation},
+			envmanModels.EnvironmentItemModel{"module": module},
+			envmanModels.EnvironmentItemModel{"variant": variant},
+		),
+		steps.VirtualDeviceTestingForAndroidStepListItem(
+			envmanModels.EnvironmentItemModel{"test_type": "instrumentation"},
+		),
+	)
+}
+
+const detoxScriptContent = `#!/usr/bin/env bash
+set -ex
+
+npx detox build
+npx detox test --cleanup
+`
+
+const appiumScriptContent = `#!/usr/bin/env bash
+set -ex
+
+npx appium --log appium.log &
+sleep 10
+
+npm test
+`
@@ -0,0 +1,152 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, pth, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+	require.NoError(t, fileutil.WriteStringToFile(pth, content))
+}
+
+func TestDetectJSFrameworks(t *testing.T) {
+	t.Log("no UI testing framework")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		packageJSONPth := filepath.Join(tmpDir, "package.json")
+		writeFile(t, packageJSONPth, `{"dependencies": {"react-native": "0.57.0"}, "devDependencies": {"jest": "23.6.0"}}`)
+
+		frameworks, err := DetectJSFrameworks(packageJSONPth)
+		require.NoError(t, err)
+		require.Equal(t, []Framework{}, frameworks)
+	}
+
+	t.Log("detox and appium dev dependencies")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		packageJSONPth := filepath.Join(tmpDir, "package.json")
+		writeFile(t, packageJSONPth, `{"devDependencies": {"appium": "1.9.1", "detox": "9.0.4"}}`)
+
+		frameworks, err := DetectJSFrameworks(packageJSONPth)
+		require.NoError(t, err)
+		require.Equal(t, []Framework{Detox, Appium}, frameworks)
+	}
+
+	t.Log("detox config in package.json")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		packageJSONPth := filepath.Join(tmpDir, "package.json")
+		writeFile(t, packageJSONPth, `{"detox": {"configurations": {}}}`)
+
+		frameworks, err := DetectJSFrameworks(packageJSONPth)
+		require.NoError(t, err)
+		require.Equal(t, []Framework{Detox}, frameworks)
+	}
+
+	t.Log("detox config file")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		packageJSONPth := filepath.Join(tmpDir, "package.json")
+		writeFile(t, packageJSONPth, `{}`)
+		writeFile(t, filepath.Join(tmpDir, ".detoxrc.json"), `{}`)
+
+		frameworks, err := DetectJSFrameworks(packageJSONPth)
+		require.NoError(t, err)
+		require.Equal(t, []Framework{Detox}, frameworks)
+	}
+}
+
+func TestDetectAndroidInstrumentation(t *testing.T) {
+	t.Log("no instrumentation tests")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		writeFile(t, filepath.Join(tmpDir, "app", "build.gradle"), `dependencies { testImplementation 'junit:junit:4.12' }`)
+		writeFile(t, filepath.Join(tmpDir, "app", "src", "test", "ExampleUnitTest.java"), "")
+		// build outputs are skipped
+		writeFile(t, filepath.Join(tmpDir, "app", "build", "src", "androidTest", "Generated.java"), "")
+
+		found, err := DetectAndroidInstrumentation(tmpDir)
+		require.NoError(t, err)
+		require.False(t, found)
+	}
+
+	t.Log("androidTest source set")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		writeFile(t, filepath.Join(tmpDir, "app", "src", "androidTest", "ExampleInstrumentedTest.java"), "")
+
+		found, err := DetectAndroidInstrumentation(tmpDir)
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+
+	t.Log("test instrumentation runner")
+	{
+		tmpDir, err := pathutil.NormalizedOSTempDirPath("e2e")
+		require.NoError(t, err)
+
+		writeFile(t, filepath.Join(tmpDir, "app", "build.gradle.kts"), `testInstrumentationRunner = "androidx.test.runner.AndroidJUnitRunner"`)
+
+		found, err := DetectAndroidInstrumentation(tmpDir)
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+}
+
+func TestAddRunUITestsOption(t *testing.T) {
+	projectOption := models.NewOption("Project", "PROJECT")
+	schemeOption := models.NewOption("Scheme", "SCHEME")
+	projectOption.AddOption("App.xcodeproj", schemeOption)
+	schemeOption.AddConfig("App", models.NewConfigOption("config"))
+	schemeOption.AddConfig("AppTests", models.NewConfigOption("config"))
+
+	AddRunUITestsOption(projectOption)
+
+	paths := []string{}
+	require.NoError(t, projectOption.Walk(func(opt *models.OptionNode, path []string) error {
+		if opt.IsConfigOption() {
+			paths = append(paths, opt.String())
+			require.Equal(t, 3, len(path))
+			require.Equal(t, "_", path[2])
+		}
+		return nil
+	}))
+	require.Equal(t, 2, len(paths))
+
+	runUITestsOption := schemeOption.ChildOptionMap["App"]
+	require.Equal(t, RunUITestsInputEnvKey, runUITestsOption.EnvKey)
+	require.Equal(t, models.TypeOptionalUserInput, runUITestsOption.Type)
+	require.Equal(t, "config", runUITestsOption.ChildOptionMap["_"].Config)
+}
+
+func TestStepList(t *testing.T) {
+	for _, stepListItem := range JSStepList([]Framework{Detox}, "mobile", true) {
+		for _, step := range stepListItem {
+			require.NotNil(t, step.RunIf)
+			require.Equal(t, `{{enveq "RUN_UI_TESTS" "yes"}}`, *step.RunIf)
+		}
+	}
+
+	require.Equal(t, 3, len(JSStepList([]Framework{Detox}, "", true)))
+	require.Equal(t, 2, len(JSStepList([]Framework{Detox, Appium}, "", false)))
+	require.Equal(t, 2, len(AndroidStepList("$PROJECT_LOCATION", "$MODULE", "$VARIANT")))
+}
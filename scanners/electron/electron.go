@@ -0,0 +1,330 @@
+package electron
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-core/bitrise-init/utility"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// ScannerName ...
+const ScannerName = "electron"
+
+const (
+	configName        = "electron-config"
+	defaultConfigName = "default-electron-config"
+)
+
+// Step Inputs
+const (
+	workDirInputKey    = "workdir"
+	workDirInputTitle  = "Directory of the Electron app's package.json"
+	workDirInputEnvKey = "ELECTRON_WORK_DIR"
+)
+
+const (
+	targetInputTitle  = "Platform to build the Electron app for"
+	targetInputEnvKey = "ELECTRON_TARGET"
+)
+
+const buildStepTitle = "Build with electron-builder"
+
+// defaultOutputDir is where electron-builder writes the built app, if the config does not set directories.output.
+const defaultOutputDir = "dist"
+
+// targets are the platforms supported by the electron-builder CLI (--mac, --linux, --win).
+var targets = []string{"mac", "linux", "win"}
+
+// builderConfigBaseNames are the electron-builder config files, used if the package.json has no build key.
+var builderConfigBaseNames = []string{
+	"electron-builder.yml",
+	"electron-builder.yaml",
+	"electron-builder.json",
+	"electron-builder.json5",
+	"electron-builder.toml",
+}
+
+//------------------
+// ScannerInterface
+//------------------
+
+// Scanner ...
+type Scanner struct {
+	packageJSONPth string
+	relProjectDir  string
+	searchDir      string
+	packageManager utility.JSPackageManager
+	outputDir      string
+}
+
+// NewScanner ...
+func NewScanner() *Scanner {
+	return &Scanner{}
+}
+
+// Name ...
+func (Scanner) Name() string {
+	return ScannerName
+}
+
+// isElectronProject returns true if the package.json declares an electron or electron-builder dev dependency.
+func isElectronProject(packages utility.PackagesModel) bool {
+	for _, dependency := range []string{"electron", "electron-builder"} {
+		if _, found := packages.DevDependencies[dependency]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// builderConfig returns the electron-builder config of the project: package.json if it has a build key,
+// otherwise the electron-builder config file next to it. Returns an empty string if the project has no electron-builder config.
+func builderConfig(packageJSONPth string, packages utility.PackagesModel) (string, error) {
+	if len(packages.Build) > 0 {
+		return packageJSONPth, nil
+	}
+
+	projectDir := filepath.Dir(packageJSONPth)
+	for _, baseName := range builderConfigBaseNames {
+		pth := filepath.Join(projectDir, baseName)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return "", err
+		} else if exist {
+			return pth, nil
+		}
+	}
+	return "", nil
+}
+
+// builderConfigModel is the part of the electron-builder config used by the scanner.
+type builderConfigModel struct {
+	Directories struct {
+		Output string `json:"output" yaml:"output"`
+	} `json:"directories" yaml:"directories"`
+}
+
+// builderOutputDir returns the output directory set by the electron-builder config, relative to the project dir.
+// The default (dist) is returned if it is not set or the config format (json5, toml) is not parsed.
+func builderOutputDir(configPth string, packages utility.PackagesModel) (string, error) {
+	var config builderConfigModel
+	if filepath.Base(configPth) == "package.json" {
+		if err := json.Unmarshal(packages.Build, &config); err != nil {
+			return "", err
+		}
+	} else if ext := filepath.Ext(configPth); ext == ".yml" || ext == ".yaml" || ext == ".json" {
+		content, err := fileutil.ReadBytesFromFile(configPth)
+		if err != nil {
+			return "", err
+		}
+		// the yaml parser reads the json configs too
+		if err := yaml.Unmarshal(content, &config); err != nil {
+			return "", err
+		}
+	}
+
+	if config.Directories.Output == "" {
+		return defaultOutputDir, nil
+	}
+	return filepath.Clean(config.Directories.Output), nil
+}
+
+// DetectPlatform ...
+func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
+	fileList, err := utility.ListPathInDirSortedByComponents(searchDir, false)
+	if err != nil {
+		return false, fmt.Errorf("failed to search for files in (%s), error: %s", searchDir, err)
+	}
+
+	log.TInfof("Searching for package.json files")
+
+	packageJSONPths, err := utility.FilterPaths(fileList,
+		utility.BaseFilter("package.json", true),
+		utility.ComponentFilter("node_modules", false))
+	if err != nil {
+		return false, err
+	}
+
+	log.TPrintf("%d package.json file detected", len(packageJSONPths))
+
+	for _, packageJSONPth := range packageJSONPths {
+		log.TPrintf("checking: %s", packageJSONPth)
+
+		packages, err := utility.ParsePackagesJSON(packageJSONPth)
+		if err != nil {
+			log.TWarnf("failed to parse package.json, error: %s", err)
+			continue
+		}
+
+		if !isElectronProject(packages) {
+			log.TPrintf("no electron dev dependency found")
+			continue
+		}
+
+		configPth, err := builderConfig(packageJSONPth, packages)
+		if err != nil {
+			return false, err
+		} else if configPth == "" {
+			log.TWarnf("no electron-builder config found, skipping package.json file")
+			continue
+		}
+
+		log.TPrintf("electron-builder config: %s", configPth)
+
+		outputDir, err := builderOutputDir(configPth, packages)
+		if err != nil {
+			log.TWarnf("failed to read the output directory of the electron-builder config, using %s, error: %s", defaultOutputDir, err)
+			outputDir = defaultOutputDir
+		}
+		log.TPrintf("output directory: %s", outputDir)
+		log.TSuccessf("Platform detected")
+
+		scanner.packageJSONPth = packageJSONPth
+		scanner.searchDir = searchDir
+		scanner.outputDir = outputDir
+
+		return true, nil
+	}
+
+	log.TPrintf("platform not detected")
+
+	return false, nil
+}
+
+// ExcludedScannerNames ...
+func (*Scanner) ExcludedScannerNames() []string {
+	return []string{}
+}
+
+// Options ...
+func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
+	warnings := models.Warnings{}
+
+	projectDir := filepath.Dir(scanner.packageJSONPth)
+	relProjectDir, err := utility.RelPath(scanner.searchDir, projectDir)
+	if err != nil {
+		return models.OptionNode{}, warnings, fmt.Errorf("Failed to get relative package.json dir path, error: %s", err)
+	}
+	if relProjectDir == "." {
+		// package.json placed in the search dir, no need to change-dir in the workflows
+		relProjectDir = ""
+	}
+	scanner.relProjectDir = relProjectDir
+
+	packageManager, err := utility.DetectJSPackageManager(projectDir)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to check if yarn.lock file exists in %s, using npm, error: %s", projectDir, err))
+	}
+	log.TPrintf("Dependency manager: %s", packageManager)
+	scanner.packageManager = packageManager
+
+	targetOption := models.NewOption(targetInputTitle, targetInputEnvKey)
+	for _, target := range targets {
+		configOption := models.NewConfigOption(configName)
+		targetOption.AddConfig(target, configOption)
+	}
+
+	if relProjectDir == "" {
+		return *targetOption, warnings, nil
+	}
+
+	workDirOption := models.NewOption(workDirInputTitle, workDirInputEnvKey)
+	workDirOption.AddOption(relProjectDir, targetOption)
+
+	return *workDirOption, warnings, nil
+}
+
+// DefaultOptions ...
+func (*Scanner) DefaultOptions() models.OptionNode {
+	workDirOption := models.NewOption(workDirInputTitle, workDirInputEnvKey)
+
+	targetOption := models.NewOption(targetInputTitle, targetInputEnvKey)
+	workDirOption.AddOption("_", targetOption)
+
+	for _, target := range targets {
+		configOption := models.NewConfigOption(defaultConfigName)
+		targetOption.AddConfig(target, configOption)
+	}
+
+	return *workDirOption
+}
+
+// buildScriptContent returns the script building the app for the selected target with electron-builder, without publishing it.
+func buildScriptContent(hasWorkDir bool) string {
+	content := "#!/usr/bin/env bash\nset -ex\n\n"
+	if hasWorkDir {
+		content += `cd "$` + workDirInputEnvKey + `"` + "\n"
+	}
+	return content + `npx electron-builder --"$` + targetInputEnvKey + `" --publish never` + "\n"
+}
+
+// generateConfig returns the config installing the dependencies with the package manager, building the app with electron-builder
+// and deploying its output directory.
+func generateConfig(packageManager utility.JSPackageManager, hasWorkDir bool, outputDir string) (string, error) {
+	installInputs := []envmanModels.EnvironmentItemModel{envmanModels.EnvironmentItemModel{"command": "install"}}
+	if hasWorkDir {
+		installInputs = append(installInputs, envmanModels.EnvironmentItemModel{workDirInputKey: "$" + workDirInputEnvKey})
+	}
+
+	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+
+	if packageManager == utility.JSPackageManagerYarn {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.YarnStepListItem(installInputs...))
+	} else {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(installInputs...))
+	}
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ScriptSteplistItem(buildStepTitle,
+		envmanModels.EnvironmentItemModel{"content": buildScriptContent(hasWorkDir)}))
+
+	if hasWorkDir {
+		outputDir = filepath.Join("$"+workDirInputEnvKey, outputDir)
+	}
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ArtifactDeployStepList([]string{outputDir}, nil)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+
+	config, err := configBuilder.Generate(ScannerName)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Configs ...
+func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
+	config, err := generateConfig(scanner.packageManager, scanner.relProjectDir != "", scanner.outputDir)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	return models.BitriseConfigMap{
+		configName: config,
+	}, nil
+}
+
+// DefaultConfigs ...
+func (*Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+	config, err := generateConfig(utility.JSPackageManagerNpm, true, defaultOutputDir)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	return models.BitriseConfigMap{
+		defaultConfigName: config,
+	}, nil
+}
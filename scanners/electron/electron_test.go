@@ -0,0 +1,265 @@
+package electron
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestProject writes the given files (relative path to content) into a new temp dir.
+func writeTestProject(t *testing.T, files map[string]string) string {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__electron_test__")
+	require.NoError(t, err)
+
+	for pth, content := range files {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+	return tmpDir
+}
+
+func TestDetectPlatform(t *testing.T) {
+	t.Log("build config in package.json")
+	{
+		tmpDir := writeTestProject(t, map[string]string{"package.json": testPackageJSONContent})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		scanner := NewScanner()
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.True(t, detected)
+		require.Equal(t, filepath.Join(tmpDir, "package.json"), scanner.packageJSONPth)
+	}
+
+	t.Log("build config in electron-builder.yml")
+	{
+		tmpDir := writeTestProject(t, map[string]string{
+			"desktop/package.json":         testPackageJSONWithoutBuildContent,
+			"desktop/electron-builder.yml": testElectronBuilderYMLContent,
+		})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		scanner := NewScanner()
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.True(t, detected)
+		require.Equal(t, filepath.Join(tmpDir, "desktop", "package.json"), scanner.packageJSONPth)
+	}
+
+	t.Log("no electron-builder config")
+	{
+		tmpDir := writeTestProject(t, map[string]string{"package.json": testPackageJSONWithoutBuildContent})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		detected, err := NewScanner().DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.False(t, detected)
+	}
+
+	t.Log("electron only in node_modules")
+	{
+		tmpDir := writeTestProject(t, map[string]string{
+			"package.json":                       testWebPackageJSONContent,
+			"node_modules/some-app/package.json": testPackageJSONContent,
+		})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		detected, err := NewScanner().DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.False(t, detected)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	t.Log("project in the search dir")
+	{
+		tmpDir := writeTestProject(t, map[string]string{"package.json": testPackageJSONContent})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		scanner := NewScanner()
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.True(t, detected)
+
+		options, warnings, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, 0, len(warnings))
+		require.Equal(t, targetInputEnvKey, options.EnvKey)
+		require.Equal(t, []string{"linux", "mac", "win"}, options.GetValues())
+		require.Equal(t, configName, options.ChildOptionMap["mac"].Config)
+		require.Equal(t, utility.JSPackageManagerNpm, scanner.packageManager)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		config := configs[configName]
+		require.True(t, strings.Contains(config, "project_type: electron"), config)
+		require.True(t, strings.Contains(config, "- npm@"), config)
+		require.True(t, strings.Contains(config, `npx electron-builder --"$ELECTRON_TARGET" --publish never`), config)
+		require.False(t, strings.Contains(config, "ELECTRON_WORK_DIR"), config)
+
+		paths, err := testhelper.StepInputValues(config, string(models.PrimaryWorkflowID), steps.DeployToBitriseIoID, steps.DeployToBitriseIoDeployPathInputKey)
+		require.NoError(t, err)
+		require.Equal(t, []string{"dist"}, paths)
+	}
+
+	t.Log("yarn project in a subdir")
+	{
+		tmpDir := writeTestProject(t, map[string]string{
+			"desktop/package.json":         testPackageJSONWithoutBuildContent,
+			"desktop/electron-builder.yml": testElectronBuilderYMLContent + testElectronBuilderOutputYMLContent,
+			"desktop/yarn.lock":            "",
+		})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		scanner := NewScanner()
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.True(t, detected)
+
+		options, _, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, workDirInputEnvKey, options.EnvKey)
+		require.Equal(t, []string{"desktop"}, options.GetValues())
+		require.Equal(t, targetInputEnvKey, options.ChildOptionMap["desktop"].EnvKey)
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		config := configs[configName]
+		require.True(t, strings.Contains(config, "- yarn@"), config)
+		require.True(t, strings.Contains(config, "workdir: $ELECTRON_WORK_DIR"), config)
+		require.True(t, strings.Contains(config, `cd "$ELECTRON_WORK_DIR"`), config)
+
+		paths, err := testhelper.StepInputValues(config, string(models.PrimaryWorkflowID), steps.DeployToBitriseIoID, steps.DeployToBitriseIoDeployPathInputKey)
+		require.NoError(t, err)
+		require.Equal(t, []string{"$ELECTRON_WORK_DIR/release/build"}, paths)
+	}
+}
+
+func TestBuilderOutputDir(t *testing.T) {
+	t.Log("output dir in package.json")
+	{
+		outputDir, err := builderOutputDir("package.json", utility.PackagesModel{Build: []byte(`{"directories": {"output": "out/"}}`)})
+		require.NoError(t, err)
+		require.Equal(t, "out", outputDir)
+	}
+
+	t.Log("default output dir")
+	{
+		outputDir, err := builderOutputDir("package.json", utility.PackagesModel{Build: []byte(`{"appId": "io.bitrise.electron-sample"}`)})
+		require.NoError(t, err)
+		require.Equal(t, defaultOutputDir, outputDir)
+	}
+
+	t.Log("output dir in electron-builder.json")
+	{
+		tmpDir := writeTestProject(t, map[string]string{"electron-builder.json": `{"directories": {"output": "release"}}`})
+		defer func() {
+			require.NoError(t, os.RemoveAll(tmpDir))
+		}()
+
+		outputDir, err := builderOutputDir(filepath.Join(tmpDir, "electron-builder.json"), utility.PackagesModel{})
+		require.NoError(t, err)
+		require.Equal(t, "release", outputDir)
+	}
+
+	t.Log("not parsed config format")
+	{
+		outputDir, err := builderOutputDir("electron-builder.toml", utility.PackagesModel{})
+		require.NoError(t, err)
+		require.Equal(t, defaultOutputDir, outputDir)
+	}
+}
+
+func TestBuildScriptContent(t *testing.T) {
+	require.Equal(t, `#!/usr/bin/env bash
+set -ex
+
+npx electron-builder --"$ELECTRON_TARGET" --publish never
+`, buildScriptContent(false))
+
+	require.Equal(t, `#!/usr/bin/env bash
+set -ex
+
+cd "$ELECTRON_WORK_DIR"
+npx electron-builder --"$ELECTRON_TARGET" --publish never
+`, buildScriptContent(true))
+}
+
+const testPackageJSONContent = `{
+  "name": "electron-sample",
+  "version": "1.0.0",
+  "main": "main.js",
+  "scripts": {
+    "start": "electron .",
+    "dist": "electron-builder"
+  },
+  "devDependencies": {
+    "electron": "^28.1.0",
+    "electron-builder": "^24.9.1"
+  },
+  "build": {
+    "appId": "io.bitrise.electron-sample",
+    "mac": {
+      "target": "dmg"
+    },
+    "linux": {
+      "target": "AppImage"
+    },
+    "win": {
+      "target": "nsis"
+    }
+  }
+}`
+
+const testPackageJSONWithoutBuildContent = `{
+  "name": "electron-sample",
+  "version": "1.0.0",
+  "main": "main.js",
+  "devDependencies": {
+    "electron": "^28.1.0",
+    "electron-builder": "^24.9.1"
+  }
+}`
+
+const testElectronBuilderYMLContent = `appId: io.bitrise.electron-sample
+mac:
+  target: dmg
+linux:
+  target: AppImage
+win:
+  target: nsis
+`
+
+const testElectronBuilderOutputYMLContent = `directories:
+  output: release/build
+`
+
+const testWebPackageJSONContent = `{
+  "name": "web-sample",
+  "version": "1.0.0",
+  "dependencies": {
+    "express": "^4.18.2"
+  }
+}`
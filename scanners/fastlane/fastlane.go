@@ -5,12 +5,13 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/steps"
 	"github.com/bitrise-core/bitrise-init/toolscanner"
 	"github.com/bitrise-core/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
-	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/sliceutil"
 )
 
 const scannerName = "fastlane"
@@ -51,6 +52,7 @@ const (
 type Scanner struct {
 	Fastfiles    []string
 	projectTypes []string
+	summary      models.Summary
 }
 
 // NewScanner ...
@@ -133,6 +135,19 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 
 		isValidFastfileFound = true
 
+		matchConfig, err := InspectMatch(fastfile)
+		if err != nil {
+			log.TWarnf("Failed to inspect fastlane match usage, error: %s", err)
+			warnings = append(warnings, fmt.Sprintf("Failed to inspect fastlane match usage of Fastfile (%s), error: %s", fastfile, err))
+		} else if matchConfig.IsUsed() {
+			log.TPrintf("fastlane match used")
+			scanner.summary = append(scanner.summary, matchSummary(fastfile, matchConfig))
+			if !sliceutil.IsStringInSlice(matchPasswordWarning, warnings) {
+				log.TWarnf(matchPasswordWarning)
+				warnings = append(warnings, matchPasswordWarning)
+			}
+		}
+
 		laneOption := models.NewOption(laneInputTitle, laneInputEnvKey)
 		workDirOption.AddOption(workDir, laneOption)
 
@@ -140,7 +155,11 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 			log.TPrintf("- %s", lane)
 
 			configOption := models.NewConfigOption(configName)
-			laneOption.AddConfig(lane, configOption)
+			if matchConfig.IsUsed() {
+				addMatchOptions(laneOption, lane, matchConfig, configOption)
+			} else {
+				laneOption.AddConfig(lane, configOption)
+			}
 		}
 	}
 
@@ -156,6 +175,11 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	return optionWithProjectType, warnings, nil
 }
 
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
 // DefaultOptions ...
 func (*Scanner) DefaultOptions() models.OptionNode {
 	workDirOption := models.NewOption(workDirInputTitle, workDirInputEnvKey)
@@ -1,6 +1,7 @@
 package flutter
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -8,13 +9,14 @@ import (
 
 	"github.com/bitrise-io/go-utils/pathutil"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/scanners/android"
 	"github.com/bitrise-core/bitrise-init/scanners/ios"
+	"github.com/bitrise-core/bitrise-init/scanners/version"
 	"github.com/bitrise-core/bitrise-init/steps"
 	"github.com/bitrise-core/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-tools/xcode-project/xcworkspace"
 	yaml "gopkg.in/yaml.v2"
 )
@@ -31,6 +33,8 @@ const (
 	projectTypeInputTitle      = "Project Type"
 	testsInputTitle            = "Run tests found in the project"
 	platformInputTitle         = "Platform"
+	versionBumpConfigSuffix    = "-version-bump"
+	pubspecVersionKey          = "version"
 )
 
 var (
@@ -54,6 +58,8 @@ var (
 // Scanner ...
 type Scanner struct {
 	projects []project
+	icons    models.Icons
+	summary  models.Summary
 }
 
 type project struct {
@@ -62,10 +68,50 @@ type project struct {
 	hasTest           bool
 	hasIosProject     bool
 	hasAndroidProject bool
+	versionSource     *version.Source
 }
 
 type pubspec struct {
-	Name string `yaml:"name"`
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+}
+
+// pubspecVersionSource returns the version source of the pubspec's version (like 1.2.0+42), nil if the pubspec has no version.
+func pubspecVersionSource(pubspecPath string, ps pubspec) *version.Source {
+	if ps.Version == "" {
+		return nil
+	}
+
+	versionName, buildNumber := ps.Version, ""
+	if i := strings.Index(ps.Version, "+"); i != -1 {
+		versionName, buildNumber = ps.Version[:i], ps.Version[i+1:]
+	}
+
+	return &version.Source{
+		App:            ps.Name,
+		File:           pubspecPath,
+		VersionKey:     pubspecVersionKey,
+		Version:        versionName,
+		BuildNumberKey: pubspecVersionKey,
+		BuildNumber:    buildNumber,
+	}
+}
+
+// versionBump returns true if the build number incrementing is offered for the project:
+// if it builds an app and its pubspec declares the version.
+func (proj project) versionBump() bool {
+	return proj.versionSource != nil && (proj.hasIosProject || proj.hasAndroidProject)
+}
+
+// addConfigOption adds the config option to the parent with the given value,
+// behind the build number incrementing selector if versionBump is true.
+func addConfigOption(parent *models.OptionNode, value string, configOption *models.OptionNode, versionBump bool) {
+	if !versionBump {
+		parent.AddConfig(value, configOption)
+		return
+	}
+
+	parent.AddOption(value, version.NewBumpOption(configOption))
 }
 
 // NewScanner ...
@@ -193,6 +239,7 @@ projects:
 		log.TPrintf("  HasIosProject: %t", proj.hasIosProject)
 
 		proj.path = projectLocation
+		proj.versionSource = pubspecVersionSource(pubspecPath, ps)
 
 		if proj.hasIosProject {
 			if workspaceLocations, err := findWorkspaceLocations(filepath.Join(projectLocation, "ios")); err != nil {
@@ -248,6 +295,29 @@ func (Scanner) ExcludedScannerNames() []string {
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	flutterProjectLocationOption := models.NewOption(projectLocationInputTitle, projectLocationInputEnvKey)
 
+	var warnings models.Warnings
+	scanner.icons = models.Icons{}
+	scanner.summary = models.Summary{}
+	for _, project := range scanner.projects {
+		icons, err := findIcons(project)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to search for app icons in: %s, error: %s", project.path, err)
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		}
+		scanner.icons = append(scanner.icons, icons...)
+
+		if project.versionSource != nil {
+			versionSummary := version.Summary(project.path, []version.Source{*project.versionSource})
+			log.TPrintf(versionSummary)
+			scanner.summary = append(scanner.summary, versionSummary)
+		} else if project.hasIosProject || project.hasAndroidProject {
+			warning := fmt.Sprintf("The version of %s is not declared in its pubspec.yaml, the app is built as 1.0.0+1 unless the version is passed to the build", project.path)
+			log.TWarnf(warning)
+			warnings = append(warnings, warning)
+		}
+	}
+
 	for _, project := range scanner.projects {
 		if project.hasTest {
 			flutterProjectHasTestOption := models.NewOption(testsInputTitle, "")
@@ -258,6 +328,10 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 				if v == "yes" {
 					cfg += "-test"
 				}
+				appCfg := cfg + "-app-" + getBuildablePlatform(project.hasAndroidProject, project.hasIosProject)
+				if project.versionBump() {
+					appCfg += versionBumpConfigSuffix
+				}
 
 				if project.hasIosProject || project.hasAndroidProject {
 					if project.hasIosProject {
@@ -273,14 +347,14 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 								schemeOption.AddOption(scheme, exportMethodOption)
 
 								for _, exportMethod := range ios.IosExportMethods {
-									configOption := models.NewConfigOption(cfg + "-app-" + getBuildablePlatform(project.hasAndroidProject, project.hasIosProject))
-									exportMethodOption.AddConfig(exportMethod, configOption)
+									configOption := models.NewConfigOption(appCfg)
+									addConfigOption(exportMethodOption, exportMethod, configOption, project.versionBump())
 								}
 							}
 						}
 					} else {
-						configOption := models.NewConfigOption(cfg + "-app-" + getBuildablePlatform(project.hasAndroidProject, project.hasIosProject))
-						flutterProjectHasTestOption.AddOption(v, configOption)
+						configOption := models.NewConfigOption(appCfg)
+						addConfigOption(flutterProjectHasTestOption, v, configOption, project.versionBump())
 					}
 				} else {
 					configOption := models.NewConfigOption(cfg)
@@ -289,6 +363,10 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 			}
 		} else {
 			cfg := configName
+			appCfg := cfg + "-app-" + getBuildablePlatform(project.hasAndroidProject, project.hasIosProject)
+			if project.versionBump() {
+				appCfg += versionBumpConfigSuffix
+			}
 
 			if project.hasIosProject || project.hasAndroidProject {
 				if project.hasIosProject {
@@ -304,14 +382,14 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 							schemeOption.AddOption(scheme, exportMethodOption)
 
 							for _, exportMethod := range ios.IosExportMethods {
-								configOption := models.NewConfigOption(cfg + "-app-" + getBuildablePlatform(project.hasAndroidProject, project.hasIosProject))
-								exportMethodOption.AddConfig(exportMethod, configOption)
+								configOption := models.NewConfigOption(appCfg)
+								addConfigOption(exportMethodOption, exportMethod, configOption, project.versionBump())
 							}
 						}
 					}
 				} else {
-					configOption := models.NewConfigOption(cfg + "-app-" + getBuildablePlatform(project.hasAndroidProject, project.hasIosProject))
-					flutterProjectLocationOption.AddOption(project.path, configOption)
+					configOption := models.NewConfigOption(appCfg)
+					addConfigOption(flutterProjectLocationOption, project.path, configOption, project.versionBump())
 				}
 			} else {
 				configOption := models.NewConfigOption(cfg)
@@ -320,7 +398,37 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 		}
 	}
 
-	return *flutterProjectLocationOption, nil, nil
+	return *flutterProjectLocationOption, warnings, nil
+}
+
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
+// Icons ...
+func (scanner *Scanner) Icons() models.Icons {
+	return scanner.icons
+}
+
+// findIcons returns the launcher icon of the android project and the app icons of the ios project.
+func findIcons(proj project) (models.Icons, error) {
+	icons := models.Icons{}
+	if proj.hasAndroidProject {
+		androidIcons, err := android.FindLauncherIcons(filepath.Join(proj.path, "android"), "")
+		if err != nil {
+			return icons, err
+		}
+		icons = append(icons, androidIcons...)
+	}
+	if proj.hasIosProject {
+		iosIcons, err := ios.FindAppIcons(filepath.Join(proj.path, "ios"), "")
+		if err != nil {
+			return icons, err
+		}
+		icons = append(icons, iosIcons...)
+	}
+	return icons, nil
 }
 
 func getBuildablePlatform(hasAndroidProject, hasIosProject bool) string {
@@ -379,100 +487,161 @@ func (Scanner) DefaultOptions() models.OptionNode {
 	return *flutterProjectLocationOption
 }
 
+// configVariant describes a generated config.
+type configVariant struct {
+	configID string
+	test     bool
+	deploy   bool
+	platform string
+}
+
+var configVariants = []configVariant{
+	{test: false, deploy: false, configID: configName},
+	{test: true, deploy: false, configID: configName + "-test"},
+	{test: false, deploy: true, platform: "both", configID: configName + "-app-both"},
+	{test: true, deploy: true, platform: "both", configID: configName + "-test-app-both"},
+	{test: false, deploy: true, platform: "android", configID: configName + "-app-android"},
+	{test: true, deploy: true, platform: "android", configID: configName + "-test-app-android"},
+	{test: false, deploy: true, platform: "ios", configID: configName + "-app-ios"},
+	{test: true, deploy: true, platform: "ios", configID: configName + "-test-app-ios"},
+}
+
 // Configs ...
 func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	return scanner.DefaultConfigs()
+	configs, err := scanner.DefaultConfigs()
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	versionBump := false
+	for _, project := range scanner.projects {
+		if project.versionBump() {
+			versionBump = true
+			break
+		}
+	}
+	if !versionBump {
+		return configs, nil
+	}
+
+	for _, variant := range configVariants {
+		if !variant.deploy {
+			continue
+		}
+
+		data, err := generateConfig(variant, true)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[variant.configID+versionBumpConfigSuffix] = data
+	}
+
+	return configs, nil
 }
 
 // DefaultConfigs ...
 func (scanner Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	configs := models.BitriseConfigMap{}
 
-	for _, variant := range []struct {
-		configID string
-		test     bool
-		deploy   bool
-		platform string
-	}{
-		{test: false, deploy: false, configID: configName},
-		{test: true, deploy: false, configID: configName + "-test"},
-		{test: false, deploy: true, platform: "both", configID: configName + "-app-both"},
-		{test: true, deploy: true, platform: "both", configID: configName + "-test-app-both"},
-		{test: false, deploy: true, platform: "android", configID: configName + "-app-android"},
-		{test: true, deploy: true, platform: "android", configID: configName + "-test-app-android"},
-		{test: false, deploy: true, platform: "ios", configID: configName + "-app-ios"},
-		{test: true, deploy: true, platform: "ios", configID: configName + "-test-app-ios"},
-	} {
-		configBuilder := models.NewDefaultConfigBuilder()
-
-		// primary
-
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
-
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterInstallStepListItem())
-
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterAnalyzeStepListItem(
+	for _, variant := range configVariants {
+		data, err := generateConfig(variant, false)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+		configs[variant.configID] = data
+	}
+
+	return configs, nil
+}
+
+// artifactPaths returns the build outputs of the platform: the release APKs built by flutter for android,
+// the archive of the xcode-archive step for ios.
+func artifactPaths(platform string) []string {
+	paths := []string{}
+	if platform != "ios" {
+		paths = append(paths, "$"+projectLocationInputEnvKey+"/build/app/outputs/apk/release")
+	}
+	if platform != "android" {
+		paths = append(paths, ios.XcarchivePath)
+	}
+	return paths
+}
+
+// generateConfig returns the config of the variant, its deploy workflow increments the build number if versionBump is true.
+func generateConfig(variant configVariant, versionBump bool) (string, error) {
+	configBuilder := models.NewDefaultConfigBuilder()
+
+	// primary
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterInstallStepListItem())
+
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterAnalyzeStepListItem(
+		envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+	))
+
+	if variant.test {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterTestStepListItem(
 			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
 		))
+	}
 
-		if variant.test {
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.FlutterTestStepListItem(
-				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-			))
-		}
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+	// deploy
 
-		// deploy
+	if variant.deploy {
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
 
-		if variant.deploy {
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
+		if variant.platform != "android" {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+		}
 
-			if variant.platform != "android" {
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
-			}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterInstallStepListItem())
 
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterInstallStepListItem())
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterAnalyzeStepListItem(
+			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+		))
 
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterAnalyzeStepListItem(
+		if variant.test {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterTestStepListItem(
 				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
 			))
+		}
 
-			if variant.test {
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterTestStepListItem(
-					envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-				))
-			}
-
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterBuildStepListItem(
-				envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
-				envmanModels.EnvironmentItemModel{platformInputKey: variant.platform},
-			))
+		if versionBump {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, version.FlutterStepList(projectLocationInputEnvKey)...)
+		}
 
-			if variant.platform != "android" {
-				configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
-					envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
-					envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: defaultIOSConfiguration},
-				))
-			}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.FlutterBuildStepListItem(
+			envmanModels.EnvironmentItemModel{projectLocationInputKey: "$" + projectLocationInputEnvKey},
+			envmanModels.EnvironmentItemModel{platformInputKey: variant.platform},
+		))
 
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+		if variant.platform != "android" {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(
+				envmanModels.EnvironmentItemModel{ios.ProjectPathInputKey: "$" + ios.ProjectPathInputEnvKey},
+				envmanModels.EnvironmentItemModel{ios.SchemeInputKey: "$" + ios.SchemeInputEnvKey},
+				envmanModels.EnvironmentItemModel{ios.ExportMethodInputKey: "$" + ios.ExportMethodInputEnvKey},
+				envmanModels.EnvironmentItemModel{ios.ConfigurationInputKey: defaultIOSConfiguration},
+			))
 		}
 
-		config, err := configBuilder.Generate(scannerName)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ArtifactDeployStepList(artifactPaths(variant.platform), nil)...)
 
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			return models.BitriseConfigMap{}, err
-		}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+	}
 
-		configs[variant.configID] = string(data)
+	config, err := configBuilder.Generate(scannerName)
+	if err != nil {
+		return "", err
 	}
 
-	return configs, nil
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
 }
@@ -0,0 +1,84 @@
+package flutter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/version"
+	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubspecVersionSource(t *testing.T) {
+	require.Nil(t, pubspecVersionSource("pubspec.yaml", pubspec{Name: "sample"}))
+
+	source := pubspecVersionSource("sample/pubspec.yaml", pubspec{Name: "sample", Version: "1.2.0+42"})
+	require.NotNil(t, source)
+	require.Equal(t, "sample: 1.2.0 (42) from version of sample/pubspec.yaml", source.String())
+
+	source = pubspecVersionSource("pubspec.yaml", pubspec{Name: "sample", Version: "1.0"})
+	require.NotNil(t, source)
+	require.Equal(t, "1.0", source.Version)
+	require.Equal(t, "", source.BuildNumber)
+}
+
+func TestOptionsVersionBump(t *testing.T) {
+	scanner := NewScanner()
+	scanner.projects = []project{
+		{
+			path:              "app",
+			hasAndroidProject: true,
+			versionSource:     pubspecVersionSource("app/pubspec.yaml", pubspec{Name: "app", Version: "1.2.0+42"}),
+		},
+		{
+			path:              "unversioned",
+			hasAndroidProject: true,
+		},
+		{
+			path:          "package",
+			versionSource: pubspecVersionSource("package/pubspec.yaml", pubspec{Name: "package", Version: "0.1.0"}),
+		},
+	}
+
+	options, warnings, err := scanner.Options()
+	require.NoError(t, err)
+	require.Contains(t, warnings, "The version of unversioned is not declared in its pubspec.yaml, the app is built as 1.0.0+1 unless the version is passed to the build")
+	require.Equal(t, 2, len(scanner.Summary()))
+
+	bumpOption := options.ChildOptionMap["app"]
+	require.Equal(t, version.BumpInputEnvKey, bumpOption.EnvKey)
+	require.Equal(t, configName+"-app-android"+versionBumpConfigSuffix, bumpOption.ChildOptionMap[version.BumpEnabledValue].Config)
+	require.Equal(t, configName+"-app-android", options.ChildOptionMap["unversioned"].Config)
+	require.Equal(t, configName, options.ChildOptionMap["package"].Config)
+
+	configs, err := scanner.Configs()
+	require.NoError(t, err)
+	require.True(t, strings.Contains(configs[configName+"-app-android"+versionBumpConfigSuffix], "Increment the build number"))
+	require.False(t, strings.Contains(configs[configName+"-app-android"], "Increment the build number"))
+
+	defaultConfigs, err := scanner.DefaultConfigs()
+	require.NoError(t, err)
+	require.Equal(t, len(configVariants), len(defaultConfigs))
+}
+
+func TestConfigsArtifactDeploy(t *testing.T) {
+	configs, err := NewScanner().DefaultConfigs()
+	require.NoError(t, err)
+
+	for configID, expected := range map[string][]string{
+		configName + "-app-android":      {"$BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release"},
+		configName + "-test-app-ios":     {"$BITRISE_XCARCHIVE_PATH"},
+		configName + "-app-both":         {"$BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release", "$BITRISE_XCARCHIVE_PATH"},
+		configName + "-test-app-android": {"$BITRISE_FLUTTER_PROJECT_LOCATION/build/app/outputs/apk/release"},
+	} {
+		paths, err := testhelper.StepInputValues(configs[configID], string(models.DeployWorkflowID), steps.DeployToBitriseIoID, steps.DeployToBitriseIoDeployPathInputKey)
+		require.NoError(t, err)
+		require.Equal(t, expected, paths, configID)
+	}
+
+	paths, err := testhelper.StepInputValues(configs[configName], string(models.PrimaryWorkflowID), steps.DeployToBitriseIoID, steps.DeployToBitriseIoDeployPathInputKey)
+	require.NoError(t, err)
+	require.Equal(t, []string{}, paths)
+}
@@ -0,0 +1,220 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-tools/xcode-project/serialized"
+	projectXcodeproj "github.com/bitrise-tools/xcode-project/xcodeproj"
+	"github.com/bitrise-tools/xcode-project/xcscheme"
+)
+
+const (
+	// AppInputTitle ...
+	AppInputTitle = "App"
+)
+
+// App is an app target with the shared schemes archiving it.
+type App struct {
+	Name     string
+	Project  string
+	BundleID string
+	Schemes  []string
+	// SchemeProjects are the paths of the projects containing the schemes, in the order of Schemes.
+	SchemeProjects []string
+}
+
+// appKey identifies an app target: the project's path and the target's id.
+type appKey struct {
+	project  string
+	targetID string
+}
+
+// schemeKey identifies a shared scheme: the path of the project containing it and its name,
+// as the projects of a workspace may contain schemes with the same name.
+type schemeKey struct {
+	project string
+	name    string
+}
+
+// GroupSchemesByApp groups the shared schemes of the given projects by the app target they archive.
+// The second return value is false if any of the shared schemes does not archive an app,
+// in this case the schemes can not be grouped by app.
+func GroupSchemesByApp(projectPths []string) ([]App, bool, error) {
+	keys := []appKey{}
+	keyToApp := map[appKey]*App{}
+	projects := map[string]projectXcodeproj.XcodeProj{}
+
+	for _, projectPth := range projectPths {
+		schemePths, err := filepath.Glob(filepath.Join(projectPth, "xcshareddata", "xcschemes", "*.xcscheme"))
+		if err != nil {
+			return nil, false, err
+		}
+		sort.Strings(schemePths)
+
+		for _, schemePth := range schemePths {
+			scheme, err := xcscheme.Open(schemePth)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to parse scheme (%s), error: %s", schemePth, err)
+			}
+
+			entry, ok := scheme.AppBuildActionEntry()
+			if !ok {
+				return nil, false, nil
+			}
+
+			referencedProjectPth, err := entry.BuildableReference.ReferencedContainerAbsPath(filepath.Dir(projectPth))
+			if err != nil {
+				return nil, false, err
+			}
+
+			key := appKey{project: referencedProjectPth, targetID: entry.BuildableReference.BlueprintIdentifier}
+			app, ok := keyToApp[key]
+			if !ok {
+				project, ok := projects[referencedProjectPth]
+				if !ok {
+					if project, err = projectXcodeproj.Open(referencedProjectPth); err != nil {
+						return nil, false, fmt.Errorf("failed to parse project (%s), error: %s", referencedProjectPth, err)
+					}
+					projects[referencedProjectPth] = project
+				}
+
+				app = &App{
+					Name:     entry.BuildableReference.BlueprintName,
+					Project:  filepath.Base(referencedProjectPth),
+					BundleID: targetBundleID(project, key.targetID),
+				}
+				keys = append(keys, key)
+				keyToApp[key] = app
+			}
+			app.Schemes = append(app.Schemes, scheme.Name)
+			app.SchemeProjects = append(app.SchemeProjects, projectPth)
+		}
+	}
+
+	apps := []App{}
+	for _, key := range keys {
+		apps = append(apps, *keyToApp[key])
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+
+	return apps, true, nil
+}
+
+// targetBundleID returns the PRODUCT_BUNDLE_IDENTIFIER build setting of the target's default build configuration,
+// build setting references, like $(PRODUCT_NAME), are not resolved.
+func targetBundleID(project projectXcodeproj.XcodeProj, targetID string) string {
+	target, ok := project.Proj.Target(targetID)
+	if !ok {
+		return ""
+	}
+	configuration := target.BuildConfigurationList.DefaultConfigurationName
+
+	bundleID := ""
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		id, err := buildConfiguration.BuildSettings.String("PRODUCT_BUNDLE_IDENTIFIER")
+		if err != nil {
+			if !serialized.IsKeyNotFoundError(err) {
+				return ""
+			}
+			continue
+		}
+		if buildConfiguration.Name == configuration {
+			return id
+		}
+		if bundleID == "" {
+			bundleID = id
+		}
+	}
+	return bundleID
+}
+
+// HasDistinctApps returns true if the schemes build more than one app and at least one of the apps has multiple schemes,
+// in which case selecting the app first makes the scheme selection clearer.
+func HasDistinctApps(apps []App) bool {
+	if len(apps) < 2 {
+		return false
+	}
+	for _, app := range apps {
+		if len(app.Schemes) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// appOptionValues returns the app option's value of each app: the app name,
+// qualified with the project name if multiple projects have an app with the same name.
+func appOptionValues(apps []App) []string {
+	nameCount := map[string]int{}
+	for _, app := range apps {
+		nameCount[app.Name]++
+	}
+
+	values := []string{}
+	for _, app := range apps {
+		value := app.Name
+		if nameCount[app.Name] > 1 {
+			value = fmt.Sprintf("%s (%s)", app.Name, app.Project)
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// addAppOptions places an app selector between the project path option and the scheme options,
+// returns the scheme option of each scheme.
+func addAppOptions(projectPathOption *models.OptionNode, projectPth string, apps []App) map[schemeKey]*models.OptionNode {
+	appOption := models.NewOption(AppInputTitle, "")
+	projectPathOption.AddOption(projectPth, appOption)
+
+	schemeToOption := map[schemeKey]*models.OptionNode{}
+	for i, value := range appOptionValues(apps) {
+		schemeOption := models.NewOption(SchemeInputTitle, SchemeInputEnvKey)
+		appOption.AddOption(value, schemeOption)
+
+		for j, scheme := range apps[i].Schemes {
+			schemeToOption[schemeKey{project: apps[i].SchemeProjects[j], name: scheme}] = schemeOption
+		}
+	}
+	return schemeToOption
+}
+
+func appsSummary(projectPth string, apps []App) string {
+	descriptions := []string{}
+	for _, app := range apps {
+		description := app.Name
+		if app.BundleID != "" {
+			description += " (" + app.BundleID + ")"
+		}
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", description, strings.Join(app.Schemes, ", ")))
+	}
+	return fmt.Sprintf("%s: %d apps found, schemes grouped by app: %s", projectPth, len(apps), strings.Join(descriptions, "; "))
+}
+
+// schemeOptionsByApp groups the shared schemes by app if the projects contain distinct apps,
+// returns the scheme option of each scheme (nil if the schemes are not grouped), the summary and a warning if the grouping failed.
+func schemeOptionsByApp(projectPathOption *models.OptionNode, containerPth string, projectPths []string) (map[schemeKey]*models.OptionNode, string, string) {
+	apps, ok, err := GroupSchemesByApp(projectPths)
+	if err != nil {
+		return nil, "", fmt.Sprintf("Failed to group the schemes of %s by app, error: %s", containerPth, err)
+	}
+	if !ok || !HasDistinctApps(apps) {
+		return nil, "", ""
+	}
+	return addAppOptions(projectPathOption, containerPth, apps), appsSummary(containerPth, apps), ""
+}
+
+// schemeOptionOf returns the scheme option the given scheme of the given project belongs to, if the schemes are grouped by app,
+// the default scheme option otherwise.
+func schemeOptionOf(defaultSchemeOption *models.OptionNode, schemeToOption map[schemeKey]*models.OptionNode, projectPth, scheme string) *models.OptionNode {
+	if schemeOption, ok := schemeToOption[schemeKey{project: projectPth, name: scheme}]; ok {
+		return schemeOption
+	}
+	return defaultSchemeOption
+}
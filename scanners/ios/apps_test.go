@@ -0,0 +1,330 @@
+package ios
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+// createMultiAppWorkspace creates a workspace with a project containing two apps (Shop and Admin),
+// both having a debug and a release shared scheme, the returned func removes it.
+func createMultiAppWorkspace(t *testing.T) (string, func()) {
+	dir, cleanup := testhelper.TempDir(t, "multi-app")
+
+	testhelper.WriteFile(t, dir, "MultiApp.xcworkspace/contents.xcworkspacedata", testMultiAppWorkspaceContent)
+	testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+	for _, scheme := range []struct {
+		name, targetID, target, configuration string
+	}{
+		{"Shop-Debug", "13C4D5A81F5E4C2B00A1B2C3", "Shop", "Debug"},
+		{"Shop-Release", "13C4D5A81F5E4C2B00A1B2C3", "Shop", "Release"},
+		{"Admin-Debug", "13C4D5B81F5E4C2B00A1B2C3", "Admin", "Debug"},
+		{"Admin-Release", "13C4D5B81F5E4C2B00A1B2C3", "Admin", "Release"},
+	} {
+		testhelper.WriteFile(t, dir, filepath.Join("MultiApp.xcodeproj/xcshareddata/xcschemes", scheme.name+".xcscheme"),
+			testSchemeContent(scheme.targetID, scheme.target, scheme.configuration))
+	}
+
+	return dir, cleanup
+}
+
+func TestGroupSchemesByApp(t *testing.T) {
+	dir, cleanup := createMultiAppWorkspace(t)
+	defer cleanup()
+
+	apps, ok, err := GroupSchemesByApp([]string{filepath.Join(dir, "MultiApp.xcodeproj")})
+	require.NoError(t, err)
+	require.True(t, ok)
+	projectPth := filepath.Join(dir, "MultiApp.xcodeproj")
+	require.Equal(t, []App{
+		{Name: "Admin", Project: "MultiApp.xcodeproj", BundleID: "io.bitrise.admin", Schemes: []string{"Admin-Debug", "Admin-Release"}, SchemeProjects: []string{projectPth, projectPth}},
+		{Name: "Shop", Project: "MultiApp.xcodeproj", BundleID: "io.bitrise.shop", Schemes: []string{"Shop-Debug", "Shop-Release"}, SchemeProjects: []string{projectPth, projectPth}},
+	}, apps)
+	require.True(t, HasDistinctApps(apps))
+
+	t.Log("a single app is not grouped")
+	{
+		require.False(t, HasDistinctApps(apps[:1]))
+	}
+
+	t.Log("apps with a single scheme each are not grouped")
+	{
+		require.False(t, HasDistinctApps([]App{{Name: "A", Schemes: []string{"A"}}, {Name: "B", Schemes: []string{"B"}}}))
+	}
+
+	t.Log("same app names are qualified with the project")
+	{
+		require.Equal(t, []string{"App (A.xcodeproj)", "App (B.xcodeproj)", "Other"}, appOptionValues([]App{
+			{Name: "App", Project: "A.xcodeproj"},
+			{Name: "App", Project: "B.xcodeproj"},
+			{Name: "Other", Project: "B.xcodeproj"},
+		}))
+	}
+}
+
+func TestGenerateOptionsGroupsSchemesByApp(t *testing.T) {
+	dir, cleanup := createMultiAppWorkspace(t)
+	defer cleanup()
+
+	options, _, summary, _, err := generateOptionsIn(t, dir)
+	require.NoError(t, err)
+
+	appOption, ok := options.Child("MultiApp.xcworkspace")
+	require.True(t, ok)
+	require.Equal(t, AppInputTitle, appOption.Title)
+	require.Equal(t, []string{"Admin", "Shop"}, appOption.GetValues())
+
+	schemeOption, ok := appOption.Child("Shop")
+	require.True(t, ok)
+	require.Equal(t, SchemeInputEnvKey, schemeOption.EnvKey)
+	require.Equal(t, []string{"Shop-Debug", "Shop-Release"}, schemeOption.GetValues())
+
+	exportMethodOption, ok := schemeOption.Child("Shop-Release")
+	require.True(t, ok)
+	require.Equal(t, ExportMethodInputEnvKey, exportMethodOption.EnvKey)
+
+	require.Contains(t, summary, "MultiApp.xcworkspace: 2 apps found, schemes grouped by app: "+
+		"Admin (io.bitrise.admin): Admin-Debug, Admin-Release; Shop (io.bitrise.shop): Shop-Debug, Shop-Release")
+
+	configs := 0
+	require.NoError(t, options.Walk(func(opt *models.OptionNode, path []string) error {
+		if opt.IsConfigOption() {
+			configs++
+			require.Equal(t, 4, len(path))
+		}
+		return nil
+	}))
+	require.Equal(t, 4*len(IosExportMethods), configs)
+}
+
+func TestAddAppOptions(t *testing.T) {
+	t.Log("schemes with the same name in different projects")
+	{
+		projectPathOption := models.NewOption(ProjectPathInputTitle, ProjectPathInputEnvKey)
+		schemeToOption := addAppOptions(projectPathOption, "App.xcworkspace", []App{
+			{Name: "Shop", Project: "Shop.xcodeproj", Schemes: []string{"App", "Shop"}, SchemeProjects: []string{"Shop.xcodeproj", "Shop.xcodeproj"}},
+			{Name: "Admin", Project: "Admin.xcodeproj", Schemes: []string{"App", "Admin"}, SchemeProjects: []string{"Admin.xcodeproj", "Admin.xcodeproj"}},
+		})
+
+		appOption, ok := projectPathOption.Child("App.xcworkspace")
+		require.True(t, ok)
+		shopSchemeOption, ok := appOption.Child("Shop")
+		require.True(t, ok)
+		adminSchemeOption, ok := appOption.Child("Admin")
+		require.True(t, ok)
+
+		defaultSchemeOption := models.NewOption(SchemeInputTitle, SchemeInputEnvKey)
+		require.True(t, shopSchemeOption == schemeOptionOf(defaultSchemeOption, schemeToOption, "Shop.xcodeproj", "App"))
+		require.True(t, adminSchemeOption == schemeOptionOf(defaultSchemeOption, schemeToOption, "Admin.xcodeproj", "App"))
+		require.True(t, defaultSchemeOption == schemeOptionOf(defaultSchemeOption, schemeToOption, "Other.xcodeproj", "App"))
+	}
+}
+
+func testSchemeContent(targetID, target, configuration string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "0940"
+   version = "1.3">
+   <BuildAction
+      parallelizeBuildables = "YES"
+      buildImplicitDependencies = "YES">
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "YES"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "` + targetID + `"
+               BuildableName = "` + target + `.app"
+               BlueprintName = "` + target + `"
+               ReferencedContainer = "container:MultiApp.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <ArchiveAction
+      buildConfiguration = "` + configuration + `"
+      revealArchiveInOrganizer = "YES">
+   </ArchiveAction>
+</Scheme>
+`
+}
+
+const testMultiAppWorkspaceContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:MultiApp.xcodeproj">
+   </FileRef>
+</Workspace>
+`
+
+const testMultiAppPbxprojContent = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	classes = {
+	};
+	objectVersion = 50;
+	objects = {
+
+/* Begin PBXFileReference section */
+		13C4D5A91F5E4C2B00A1B2C3 /* Shop.app */ = {isa = PBXFileReference; explicitFileType = wrapper.application; includeInIndex = 0; path = Shop.app; sourceTree = BUILT_PRODUCTS_DIR; };
+		13C4D5B91F5E4C2B00A1B2C3 /* Admin.app */ = {isa = PBXFileReference; explicitFileType = wrapper.application; includeInIndex = 0; path = Admin.app; sourceTree = BUILT_PRODUCTS_DIR; };
+/* End PBXFileReference section */
+
+/* Begin PBXGroup section */
+		13C4D5901F5E4C2B00A1B2C3 = {
+			isa = PBXGroup;
+			children = (
+				13C4D5911F5E4C2B00A1B2C3 /* Products */,
+			);
+			sourceTree = "<group>";
+		};
+		13C4D5911F5E4C2B00A1B2C3 /* Products */ = {
+			isa = PBXGroup;
+			children = (
+				13C4D5A91F5E4C2B00A1B2C3 /* Shop.app */,
+				13C4D5B91F5E4C2B00A1B2C3 /* Admin.app */,
+			);
+			name = Products;
+			sourceTree = "<group>";
+		};
+/* End PBXGroup section */
+
+/* Begin PBXNativeTarget section */
+		13C4D5A81F5E4C2B00A1B2C3 /* Shop */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 13C4D5AA1F5E4C2B00A1B2C3 /* Build configuration list for PBXNativeTarget "Shop" */;
+			buildPhases = (
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = Shop;
+			productName = Shop;
+			productReference = 13C4D5A91F5E4C2B00A1B2C3 /* Shop.app */;
+			productType = "com.apple.product-type.application";
+		};
+		13C4D5B81F5E4C2B00A1B2C3 /* Admin */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 13C4D5BA1F5E4C2B00A1B2C3 /* Build configuration list for PBXNativeTarget "Admin" */;
+			buildPhases = (
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = Admin;
+			productName = Admin;
+			productReference = 13C4D5B91F5E4C2B00A1B2C3 /* Admin.app */;
+			productType = "com.apple.product-type.application";
+		};
+/* End PBXNativeTarget section */
+
+/* Begin PBXProject section */
+		13C4D5921F5E4C2B00A1B2C3 /* Project object */ = {
+			isa = PBXProject;
+			buildConfigurationList = 13C4D5931F5E4C2B00A1B2C3 /* Build configuration list for PBXProject "MultiApp" */;
+			compatibilityVersion = "Xcode 9.3";
+			mainGroup = 13C4D5901F5E4C2B00A1B2C3;
+			productRefGroup = 13C4D5911F5E4C2B00A1B2C3 /* Products */;
+			projectDirPath = "";
+			projectRoot = "";
+			targets = (
+				13C4D5A81F5E4C2B00A1B2C3 /* Shop */,
+				13C4D5B81F5E4C2B00A1B2C3 /* Admin */,
+			);
+		};
+/* End PBXProject section */
+
+/* Begin XCBuildConfiguration section */
+		13C4D5941F5E4C2B00A1B2C3 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 11.0;
+				SDKROOT = iphoneos;
+			};
+			name = Debug;
+		};
+		13C4D5951F5E4C2B00A1B2C3 /* Release */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 11.0;
+				SDKROOT = iphoneos;
+			};
+			name = Release;
+		};
+		13C4D5AB1F5E4C2B00A1B2C3 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.shop.debug;
+				PRODUCT_NAME = "$(TARGET_NAME)";
+			};
+			name = Debug;
+		};
+		13C4D5AC1F5E4C2B00A1B2C3 /* Release */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.shop;
+				PRODUCT_NAME = "$(TARGET_NAME)";
+			};
+			name = Release;
+		};
+		13C4D5BB1F5E4C2B00A1B2C3 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.admin.debug;
+				PRODUCT_NAME = "$(TARGET_NAME)";
+			};
+			name = Debug;
+		};
+		13C4D5BC1F5E4C2B00A1B2C3 /* Release */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.admin;
+				PRODUCT_NAME = "$(TARGET_NAME)";
+			};
+			name = Release;
+		};
+/* End XCBuildConfiguration section */
+
+/* Begin XCConfigurationList section */
+		13C4D5931F5E4C2B00A1B2C3 /* Build configuration list for PBXProject "MultiApp" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				13C4D5941F5E4C2B00A1B2C3 /* Debug */,
+				13C4D5951F5E4C2B00A1B2C3 /* Release */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Release;
+		};
+		13C4D5AA1F5E4C2B00A1B2C3 /* Build configuration list for PBXNativeTarget "Shop" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				13C4D5AB1F5E4C2B00A1B2C3 /* Debug */,
+				13C4D5AC1F5E4C2B00A1B2C3 /* Release */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Release;
+		};
+		13C4D5BA1F5E4C2B00A1B2C3 /* Build configuration list for PBXNativeTarget "Admin" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				13C4D5BB1F5E4C2B00A1B2C3 /* Debug */,
+				13C4D5BC1F5E4C2B00A1B2C3 /* Release */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Release;
+		};
+/* End XCConfigurationList section */
+	};
+	rootObject = 13C4D5921F5E4C2B00A1B2C3 /* Project object */;
+}
+`
@@ -0,0 +1,177 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-tools/xcode-project/serialized"
+	projectXcodeproj "github.com/bitrise-tools/xcode-project/xcodeproj"
+)
+
+const (
+	// ExcludedArchsKey ...
+	ExcludedArchsKey = "EXCLUDED_ARCHS"
+	// ValidArchsKey ...
+	ValidArchsKey = "VALID_ARCHS"
+	// OnlyActiveArchKey ...
+	OnlyActiveArchKey = "ONLY_ACTIVE_ARCH"
+
+	// the simulator SDK conditional of the build settings, like EXCLUDED_ARCHS[sdk=iphonesimulator*]
+	simulatorSDKCondition = "[sdk=iphonesimulator"
+)
+
+const (
+	// XcodebuildTestOptionsInputKey ...
+	XcodebuildTestOptionsInputKey = "xcodebuild_test_options"
+	// SimulatorArchInputEnvKey ...
+	SimulatorArchInputEnvKey = "XCODE_DESTINATION_ARCH"
+	// SimulatorArchInputTitle ...
+	SimulatorArchInputTitle = "Simulator architecture to build the tests for"
+
+	arm64Arch            = "arm64"
+	defaultSimulatorArch = "x86_64"
+)
+
+// ArchSettings are the architecture related build settings of a project, collected from all of its build configurations.
+type ArchSettings struct {
+	// SimulatorExcludedArchs are the architectures excluded from the simulator builds,
+	// by the EXCLUDED_ARCHS and the EXCLUDED_ARCHS[sdk=iphonesimulator*] build settings.
+	SimulatorExcludedArchs []string
+	// ValidArchs are the architectures of the VALID_ARCHS build settings, empty if not set.
+	ValidArchs []string
+	// OnlyActiveArch are the values of the ONLY_ACTIVE_ARCH build settings (YES or NO).
+	OnlyActiveArch []string
+}
+
+// ExcludesArm64OnSimulator returns true if the simulator builds can not target arm64,
+// so the tests can not run natively on Apple Silicon simulators.
+func (settings ArchSettings) ExcludesArm64OnSimulator() bool {
+	if sliceutil.IsStringInSlice(arm64Arch, settings.SimulatorExcludedArchs) {
+		return true
+	}
+	return len(settings.ValidArchs) > 0 && !sliceutil.IsStringInSlice(arm64Arch, settings.ValidArchs)
+}
+
+// ProjectArchSettings returns the architecture related build settings of the project's and its targets' build configurations.
+// Build setting references, like $(inherited), are skipped.
+func ProjectArchSettings(projectPth string) (ArchSettings, error) {
+	settings := ArchSettings{}
+
+	if exist, err := pathutil.IsPathExists(filepath.Join(projectPth, "project.pbxproj")); err != nil {
+		return ArchSettings{}, err
+	} else if !exist {
+		return settings, nil
+	}
+
+	project, err := projectXcodeproj.Open(projectPth)
+	if err != nil {
+		return ArchSettings{}, err
+	}
+
+	buildSettingsList := []serialized.Object{}
+	for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+		buildSettingsList = append(buildSettingsList, buildConfiguration.BuildSettings)
+	}
+	for _, target := range project.Proj.Targets {
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			buildSettingsList = append(buildSettingsList, buildConfiguration.BuildSettings)
+		}
+	}
+
+	for _, buildSettings := range buildSettingsList {
+		for _, key := range buildSettings.Keys() {
+			switch {
+			case key == ExcludedArchsKey || strings.HasPrefix(key, ExcludedArchsKey+simulatorSDKCondition):
+				settings.SimulatorExcludedArchs = appendArchs(settings.SimulatorExcludedArchs, archSettingValues(buildSettings, key)...)
+			case key == ValidArchsKey || strings.HasPrefix(key, ValidArchsKey+simulatorSDKCondition):
+				settings.ValidArchs = appendArchs(settings.ValidArchs, archSettingValues(buildSettings, key)...)
+			case key == OnlyActiveArchKey:
+				settings.OnlyActiveArch = appendArchs(settings.OnlyActiveArch, archSettingValues(buildSettings, key)...)
+			}
+		}
+	}
+
+	sort.Strings(settings.SimulatorExcludedArchs)
+	sort.Strings(settings.ValidArchs)
+	sort.Strings(settings.OnlyActiveArch)
+
+	return settings, nil
+}
+
+// archSettingValues returns the space separated (or listed) values of the build setting.
+func archSettingValues(buildSettings serialized.Object, key string) []string {
+	var values []string
+	if value, err := buildSettings.String(key); err == nil {
+		values = strings.Fields(value)
+	} else if list, err := buildSettings.StringSlice(key); err == nil {
+		values = list
+	}
+
+	archs := []string{}
+	for _, value := range values {
+		value = strings.Trim(value, `"`)
+		if value == "" || strings.HasPrefix(value, "$") {
+			continue
+		}
+		archs = append(archs, value)
+	}
+	return archs
+}
+
+func appendArchs(archs []string, newArchs ...string) []string {
+	for _, arch := range newArchs {
+		if !sliceutil.IsStringInSlice(arch, archs) {
+			archs = append(archs, arch)
+		}
+	}
+	return archs
+}
+
+// inspectSimulatorArchs checks the architecture build settings of the given projects, belonging to the given container.
+// Returns the architecture the simulator builds of the tests need to target explicitly, or an empty string if the
+// simulator builds can target arm64. Excluding arm64 from the simulator builds is reported with a warning.
+func inspectSimulatorArchs(containerPth string, projectPths []string) (string, models.Summary, models.Warnings) {
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+
+	excludesArm64 := false
+	onlyActiveArch := []string{}
+	for _, projectPth := range projectPths {
+		settings, err := ProjectArchSettings(projectPth)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to read the architecture build settings of project (%s), error: %s", projectPth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+
+		if settings.ExcludesArm64OnSimulator() {
+			excludesArm64 = true
+			onlyActiveArch = appendArchs(onlyActiveArch, settings.OnlyActiveArch...)
+		}
+	}
+
+	if !excludesArm64 {
+		return "", summary, warnings
+	}
+
+	log.TPrintf("arm64 is excluded from the simulator builds")
+	summary = append(summary, fmt.Sprintf("%s: simulator architecture: %s", containerPth, defaultSimulatorArch))
+
+	warning := fmt.Sprintf(`%s excludes the %s architecture from the simulator builds (%s or %s), the tests are built for %s and need Rosetta on Apple Silicon stacks.
+This is a common CocoaPods workaround (set in the Podfile's post_install hook), remove it once the dependencies support %s simulators.`,
+		containerPth, arm64Arch, ExcludedArchsKey, ValidArchsKey, defaultSimulatorArch, arm64Arch)
+	if sliceutil.IsStringInSlice("YES", onlyActiveArch) {
+		warning += fmt.Sprintf("\n%s = YES builds only the host's architecture, the simulator builds fail on Apple Silicon without an explicit architecture.", OnlyActiveArchKey)
+	}
+	warnings = append(warnings, warning)
+	log.TWarnf(warning)
+
+	return defaultSimulatorArch, summary, warnings
+}
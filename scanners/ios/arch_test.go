@@ -0,0 +1,125 @@
+package ios
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+// testArchPbxprojContent returns the multi app project, with the given build settings added to the Shop app's debug configuration.
+func testArchPbxprojContent(buildSettings string) string {
+	return strings.Replace(testMultiAppPbxprojContent,
+		"PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.shop.debug;",
+		buildSettings+"\n\t\t\t\tPRODUCT_BUNDLE_IDENTIFIER = io.bitrise.shop.debug;", 1)
+}
+
+func TestProjectArchSettings(t *testing.T) {
+	dir, cleanup := testhelper.TempDir(t, "arch")
+	defer cleanup()
+
+	t.Log("arm64 excluded from the simulator builds")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "Excluded.xcodeproj/project.pbxproj", testArchPbxprojContent(`"EXCLUDED_ARCHS[sdk=iphonesimulator*]" = arm64;
+				ONLY_ACTIVE_ARCH = YES;`))
+
+		settings, err := ProjectArchSettings(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, ArchSettings{SimulatorExcludedArchs: []string{"arm64"}, OnlyActiveArch: []string{"YES"}}, settings)
+		require.True(t, settings.ExcludesArm64OnSimulator())
+	}
+
+	t.Log("valid architectures without arm64")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "Valid.xcodeproj/project.pbxproj", testArchPbxprojContent(`VALID_ARCHS = "armv7 x86_64";`))
+
+		settings, err := ProjectArchSettings(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []string{"armv7", "x86_64"}, settings.ValidArchs)
+		require.True(t, settings.ExcludesArm64OnSimulator())
+	}
+
+	t.Log("valid architectures with arm64, inherited excluded architectures")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "Arm64.xcodeproj/project.pbxproj", testArchPbxprojContent(`EXCLUDED_ARCHS = "$(inherited)";
+				VALID_ARCHS = (
+					arm64,
+					x86_64,
+				);`))
+
+		settings, err := ProjectArchSettings(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, ArchSettings{ValidArchs: []string{"arm64", "x86_64"}}, settings)
+		require.False(t, settings.ExcludesArm64OnSimulator())
+	}
+
+	t.Log("no architecture build settings")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+
+		settings, err := ProjectArchSettings(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, ArchSettings{}, settings)
+		require.False(t, settings.ExcludesArm64OnSimulator())
+
+		simulatorArch, summary, warnings := inspectSimulatorArchs("MultiApp.xcodeproj", []string{projectPth})
+		require.Equal(t, "", simulatorArch)
+		require.Equal(t, models.Summary{}, summary)
+		require.Equal(t, models.Warnings{}, warnings)
+	}
+
+	t.Log("the excluded arm64 is reported with a warning")
+	{
+		simulatorArch, summary, warnings := inspectSimulatorArchs("Excluded.xcodeproj", []string{filepath.Join(dir, "Excluded.xcodeproj")})
+		require.Equal(t, "x86_64", simulatorArch)
+		require.Equal(t, models.Summary{"Excluded.xcodeproj: simulator architecture: x86_64"}, summary)
+		require.Equal(t, 1, len(warnings))
+		require.Contains(t, warnings[0], "Excluded.xcodeproj excludes the arm64 architecture from the simulator builds")
+		require.Contains(t, warnings[0], "ONLY_ACTIVE_ARCH = YES")
+	}
+}
+
+func TestAddConfigOptionSimulatorArch(t *testing.T) {
+	exportMethodOption := models.NewOption(IosExportMethodInputTitle, ExportMethodInputEnvKey)
+	addConfigOption(exportMethodOption, "app-store", models.NewConfigOption("ios-test-simulator-arch-config"), "11.0", "x86_64")
+
+	simulatorArchOption, ok := exportMethodOption.Child("app-store", "11.0")
+	require.True(t, ok)
+	require.Equal(t, SimulatorArchInputEnvKey, simulatorArchOption.EnvKey)
+	require.Equal(t, models.TypeOptionalUserInput, simulatorArchOption.Type)
+	require.Equal(t, []string{"x86_64"}, simulatorArchOption.GetValues())
+
+	configOption, ok := simulatorArchOption.Child("x86_64")
+	require.True(t, ok)
+	require.Equal(t, "ios-test-simulator-arch-config", configOption.Config)
+}
+
+func TestGenerateConfigBuilderSimulatorArch(t *testing.T) {
+	descriptor := NewConfigDescriptor(false, "", true, false)
+	descriptor.HasSimulatorArch = true
+	require.Equal(t, "ios-test-simulator-arch-config", descriptor.ConfigName(XcodeProjectTypeIOS))
+
+	configBuilder := GenerateConfigBuilder(XcodeProjectTypeIOS, descriptor, true)
+
+	config, err := configBuilder.Generate(string(XcodeProjectTypeIOS))
+	require.NoError(t, err)
+
+	found := false
+	for _, stepListItem := range config.Workflows[string(models.TestWorkflowID)].Steps {
+		for stepID, step := range stepListItem {
+			if !strings.HasPrefix(stepID, "xcode-test@") {
+				continue
+			}
+			for _, input := range step.Inputs {
+				if value, ok := input[XcodebuildTestOptionsInputKey]; ok {
+					found = true
+					require.Equal(t, "ARCHS=$XCODE_DESTINATION_ARCH ONLY_ACTIVE_ARCH=NO", value)
+				}
+			}
+		}
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,237 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-tools/xcode-project/serialized"
+	projectXcodeproj "github.com/bitrise-tools/xcode-project/xcodeproj"
+)
+
+const (
+	// IOSDeploymentTargetKey ...
+	IOSDeploymentTargetKey = "IPHONEOS_DEPLOYMENT_TARGET"
+	// MacOSDeploymentTargetKey ...
+	MacOSDeploymentTargetKey = "MACOSX_DEPLOYMENT_TARGET"
+	// TvOSDeploymentTargetKey ...
+	TvOSDeploymentTargetKey = "TVOS_DEPLOYMENT_TARGET"
+)
+
+const (
+	// SimulatorOSVersionInputKey ...
+	SimulatorOSVersionInputKey = "simulator_os_version"
+	// SimulatorOSVersionInputEnvKey ...
+	SimulatorOSVersionInputEnvKey = "BITRISE_SIMULATOR_OS_VERSION"
+	// SimulatorOSVersionInputTitle ...
+	SimulatorOSVersionInputTitle = "Simulator OS version to test against"
+
+	defaultSimulatorOSVersion = "latest"
+)
+
+// deploymentTargetKeys are the inspected deployment target build settings, with the name of their platform.
+var deploymentTargetKeys = []struct {
+	key      string
+	platform string
+}{
+	{IOSDeploymentTargetKey, "iOS"},
+	{MacOSDeploymentTargetKey, "macOS"},
+	{TvOSDeploymentTargetKey, "tvOS"},
+}
+
+var versionRegexp = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+// compareVersions compares the given dot separated numeric versions, the missing components are treated as 0.
+func compareVersions(a, b string) int {
+	aComponents := strings.Split(a, ".")
+	bComponents := strings.Split(b, ".")
+	for i := 0; i < len(aComponents) || i < len(bComponents); i++ {
+		aComponent, bComponent := 0, 0
+		if i < len(aComponents) {
+			aComponent, _ = strconv.Atoi(aComponents[i])
+		}
+		if i < len(bComponents) {
+			bComponent, _ = strconv.Atoi(bComponents[i])
+		}
+		if aComponent != bComponent {
+			if aComponent < bComponent {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// sortVersions sorts the given versions in ascending order.
+func sortVersions(versions []string) {
+	sort.Slice(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) < 0
+	})
+}
+
+// ProjectDeploymentTargets returns the deployment targets declared by the project, by build setting key.
+// A target's build configuration inherits the value of the project's build configuration with the same name,
+// build setting references, like $(RECOMMENDED_IPHONEOS_DEPLOYMENT_TARGET), are skipped.
+func ProjectDeploymentTargets(projectPth string) (map[string][]string, error) {
+	deploymentTargets := map[string][]string{}
+
+	if exist, err := pathutil.IsPathExists(filepath.Join(projectPth, "project.pbxproj")); err != nil {
+		return nil, err
+	} else if !exist {
+		return deploymentTargets, nil
+	}
+
+	project, err := projectXcodeproj.Open(projectPth)
+	if err != nil {
+		return nil, err
+	}
+
+	projectSettings := map[string]serialized.Object{}
+	for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+		projectSettings[buildConfiguration.Name] = buildConfiguration.BuildSettings
+	}
+
+	// the target level settings with the project level settings they inherit
+	type inheritedSettings struct {
+		settings serialized.Object
+		parent   serialized.Object
+	}
+
+	settingsList := []inheritedSettings{}
+	for _, target := range project.Proj.Targets {
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			settingsList = append(settingsList, inheritedSettings{buildConfiguration.BuildSettings, projectSettings[buildConfiguration.Name]})
+		}
+	}
+	if len(project.Proj.Targets) == 0 {
+		for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+			settingsList = append(settingsList, inheritedSettings{settings: buildConfiguration.BuildSettings})
+		}
+	}
+
+	for _, settings := range settingsList {
+		for _, deploymentTarget := range deploymentTargetKeys {
+			value, ok := buildSetting(settings.settings, deploymentTarget.key)
+			if !ok {
+				value, ok = buildSetting(settings.parent, deploymentTarget.key)
+			}
+			if !ok || !versionRegexp.MatchString(value) {
+				continue
+			}
+
+			if !sliceutil.IsStringInSlice(value, deploymentTargets[deploymentTarget.key]) {
+				deploymentTargets[deploymentTarget.key] = append(deploymentTargets[deploymentTarget.key], value)
+			}
+		}
+	}
+
+	for _, versions := range deploymentTargets {
+		sortVersions(versions)
+	}
+
+	return deploymentTargets, nil
+}
+
+// buildSetting returns the string value of the build setting, if it is set.
+func buildSetting(settings serialized.Object, key string) (string, bool) {
+	if settings == nil {
+		return "", false
+	}
+	value, err := settings.String(key)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
+
+// inspectDeploymentTargets collects the deployment targets of the given projects, belonging to the given container,
+// returns the minimum deployment target by build setting key.
+// If the targets declare different deployment targets, the minimum is reported with a warning.
+func inspectDeploymentTargets(containerPth string, projectPths []string) (map[string]string, models.Summary, models.Warnings) {
+	minDeploymentTargets := map[string]string{}
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+
+	versionsByKey := map[string][]string{}
+	for _, projectPth := range projectPths {
+		deploymentTargets, err := ProjectDeploymentTargets(projectPth)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to read the deployment targets of project (%s), error: %s", projectPth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+
+		for key, versions := range deploymentTargets {
+			for _, version := range versions {
+				if !sliceutil.IsStringInSlice(version, versionsByKey[key]) {
+					versionsByKey[key] = append(versionsByKey[key], version)
+				}
+			}
+		}
+	}
+
+	for _, deploymentTarget := range deploymentTargetKeys {
+		versions := versionsByKey[deploymentTarget.key]
+		if len(versions) == 0 {
+			continue
+		}
+		sortVersions(versions)
+
+		minVersion := versions[0]
+		minDeploymentTargets[deploymentTarget.key] = minVersion
+
+		log.TPrintf("%s deployment target: %s", deploymentTarget.platform, minVersion)
+		summary = append(summary, fmt.Sprintf("%s: %s deployment target: %s", containerPth, deploymentTarget.platform, minVersion))
+
+		if len(versions) > 1 {
+			warning := fmt.Sprintf("The targets of %s declare different %s deployment targets (%s), the minimum (%s) is used", containerPth, deploymentTarget.platform, strings.Join(versions, ", "), minVersion)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+		}
+	}
+
+	return minDeploymentTargets, summary, warnings
+}
+
+// simulatorOSVersion returns the simulator OS version offered for the test workflow of an iOS project:
+// the minimum iOS deployment target if detected, latest otherwise.
+// Returns an empty string if no simulator OS version option is needed.
+func simulatorOSVersion(projectType XcodeProjectType, hasTest bool, minDeploymentTargets map[string]string) string {
+	if projectType != XcodeProjectTypeIOS || !hasTest {
+		return ""
+	}
+	if version, ok := minDeploymentTargets[IOSDeploymentTargetKey]; ok {
+		return version
+	}
+	return defaultSimulatorOSVersion
+}
+
+// addConfigOption adds the config option to the export method option, with the given export method value.
+// The optional simulator OS version option, prefilled with the given version, is placed in between if the version is not empty,
+// followed by the optional simulator architecture option, if the simulator architecture is not empty either.
+func addConfigOption(exportMethodOption *models.OptionNode, exportMethod string, configOption *models.OptionNode, simulatorOSVersion, simulatorArch string) {
+	if simulatorOSVersion == "" {
+		exportMethodOption.AddConfig(exportMethod, configOption)
+		return
+	}
+
+	simulatorOSVersionOption := models.NewUserInputOption(SimulatorOSVersionInputTitle, SimulatorOSVersionInputEnvKey, true)
+	exportMethodOption.AddOption(exportMethod, simulatorOSVersionOption)
+	if simulatorArch == "" {
+		simulatorOSVersionOption.AddConfig(simulatorOSVersion, configOption)
+		return
+	}
+
+	simulatorArchOption := models.NewUserInputOption(SimulatorArchInputTitle, SimulatorArchInputEnvKey, true)
+	simulatorOSVersionOption.AddOption(simulatorOSVersion, simulatorArchOption)
+	simulatorArchOption.AddConfig(simulatorArch, configOption)
+}
@@ -0,0 +1,124 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+// testMixedDeploymentTargetPbxprojContent is the multi app project, with the Admin app's release configuration
+// overriding the project level iOS deployment target.
+var testMixedDeploymentTargetPbxprojContent = strings.Replace(testMultiAppPbxprojContent,
+	"PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.admin;",
+	"IPHONEOS_DEPLOYMENT_TARGET = 9.3;\n\t\t\t\tPRODUCT_BUNDLE_IDENTIFIER = io.bitrise.admin;", 1)
+
+func TestCompareVersions(t *testing.T) {
+	require.Equal(t, 0, compareVersions("11.0", "11"))
+	require.Equal(t, -1, compareVersions("9.3", "10.0"))
+	require.Equal(t, 1, compareVersions("10.13", "10.9"))
+
+	versions := []string{"11.0", "9.3", "10.13", "10.9"}
+	sortVersions(versions)
+	require.Equal(t, []string{"9.3", "10.9", "10.13", "11.0"}, versions)
+}
+
+func TestProjectDeploymentTargets(t *testing.T) {
+	tmpDir, cleanup := testhelper.TempDir(t, "deployment-target")
+	defer cleanup()
+
+	t.Log("targets inherit the project level deployment target")
+	{
+		testhelper.WriteFile(t, tmpDir, "Inherited.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+
+		deploymentTargets, err := ProjectDeploymentTargets(filepath.Join(tmpDir, "Inherited.xcodeproj"))
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{IOSDeploymentTargetKey: []string{"11.0"}}, deploymentTargets)
+	}
+
+	t.Log("target level deployment target overrides the project level one")
+	{
+		testhelper.WriteFile(t, tmpDir, "Mixed.xcodeproj/project.pbxproj", testMixedDeploymentTargetPbxprojContent)
+
+		deploymentTargets, err := ProjectDeploymentTargets(filepath.Join(tmpDir, "Mixed.xcodeproj"))
+		require.NoError(t, err)
+		require.Equal(t, map[string][]string{IOSDeploymentTargetKey: []string{"9.3", "11.0"}}, deploymentTargets)
+
+		minDeploymentTargets, summary, warnings := inspectDeploymentTargets("Mixed.xcodeproj", []string{filepath.Join(tmpDir, "Mixed.xcodeproj")})
+		require.Equal(t, map[string]string{IOSDeploymentTargetKey: "9.3"}, minDeploymentTargets)
+		require.Equal(t, models.Summary{"Mixed.xcodeproj: iOS deployment target: 9.3"}, summary)
+		require.Equal(t, models.Warnings{"The targets of Mixed.xcodeproj declare different iOS deployment targets (9.3, 11.0), the minimum (9.3) is used"}, warnings)
+	}
+
+	t.Log("project without pbxproj")
+	{
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "Empty.xcodeproj"), 0700))
+
+		deploymentTargets, err := ProjectDeploymentTargets(filepath.Join(tmpDir, "Empty.xcodeproj"))
+		require.NoError(t, err)
+		require.Equal(t, 0, len(deploymentTargets))
+	}
+}
+
+func TestAddConfigOption(t *testing.T) {
+	t.Log("simulator OS version option is added for iOS test configs")
+	{
+		version := simulatorOSVersion(XcodeProjectTypeIOS, true, map[string]string{IOSDeploymentTargetKey: "9.3"})
+		require.Equal(t, "9.3", version)
+
+		exportMethodOption := models.NewOption(IosExportMethodInputTitle, ExportMethodInputEnvKey)
+		addConfigOption(exportMethodOption, "app-store", models.NewConfigOption("ios-test-config"), version, "")
+
+		simulatorOSVersionOption, ok := exportMethodOption.Child("app-store")
+		require.True(t, ok)
+		require.Equal(t, SimulatorOSVersionInputEnvKey, simulatorOSVersionOption.EnvKey)
+		require.Equal(t, models.TypeOptionalUserInput, simulatorOSVersionOption.Type)
+		require.Equal(t, []string{"9.3"}, simulatorOSVersionOption.GetValues())
+	}
+
+	t.Log("latest simulator OS version, if no deployment target detected")
+	{
+		require.Equal(t, "latest", simulatorOSVersion(XcodeProjectTypeIOS, true, map[string]string{}))
+	}
+
+	t.Log("no simulator OS version option for configs without test or for macOS")
+	{
+		require.Equal(t, "", simulatorOSVersion(XcodeProjectTypeIOS, false, map[string]string{IOSDeploymentTargetKey: "9.3"}))
+		require.Equal(t, "", simulatorOSVersion(XcodeProjectTypeMacOS, true, map[string]string{MacOSDeploymentTargetKey: "10.13"}))
+
+		exportMethodOption := models.NewOption(IosExportMethodInputTitle, ExportMethodInputEnvKey)
+		addConfigOption(exportMethodOption, "app-store", models.NewConfigOption("ios-config"), "", "")
+
+		configOption, ok := exportMethodOption.Child("app-store")
+		require.True(t, ok)
+		require.True(t, configOption.IsConfigOption())
+	}
+}
+
+func TestGenerateConfigBuilderSimulatorOSVersion(t *testing.T) {
+	descriptor := NewConfigDescriptor(false, "", true, false)
+	configBuilder := GenerateConfigBuilder(XcodeProjectTypeIOS, descriptor, true)
+
+	config, err := configBuilder.Generate(string(XcodeProjectTypeIOS))
+	require.NoError(t, err)
+
+	found := false
+	for _, stepListItem := range config.Workflows[string(models.TestWorkflowID)].Steps {
+		for stepID, step := range stepListItem {
+			if !strings.HasPrefix(stepID, "xcode-test@") {
+				continue
+			}
+			for _, input := range step.Inputs {
+				if value, ok := input[SimulatorOSVersionInputKey]; ok {
+					found = true
+					require.Equal(t, "$"+SimulatorOSVersionInputEnvKey, value)
+				}
+			}
+		}
+	}
+	require.True(t, found)
+}
@@ -0,0 +1,68 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	appIconSetExt         = ".appiconset"
+	defaultAppIconSetName = "AppIcon" + appIconSetExt
+)
+
+var iconSearchSkipDirNames = []string{".git", "Pods", "Carthage", "node_modules", "build", "DerivedData"}
+
+// FindAppIcons returns the largest icon of every app icon set (*.appiconset) of the asset catalogs found in searchDir.
+// The icons of the default AppIcon set come first, the icon paths are relative to relDir.
+func FindAppIcons(searchDir, relDir string) (models.Icons, error) {
+	iconSetToImages := map[string][]string{}
+	if err := filepath.Walk(searchDir, func(pth string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if pth != searchDir && (sliceutil.IsStringInSlice(info.Name(), iconSearchSkipDirNames) || filepath.Ext(info.Name()) == ".framework") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		iconSet := filepath.Dir(pth)
+		if filepath.Ext(iconSet) == appIconSetExt && strings.ToLower(filepath.Ext(pth)) == ".png" {
+			iconSetToImages[iconSet] = append(iconSetToImages[iconSet], pth)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	icons := models.Icons{}
+	iconSets := map[string]string{}
+	for iconSet, images := range iconSetToImages {
+		if icon, ok := utility.LargestIcon(images, relDir); ok {
+			icons = append(icons, icon)
+			iconSets[icon.Path] = filepath.Base(iconSet)
+		}
+	}
+
+	sort.Slice(icons, func(i, j int) bool {
+		iDefault := iconSets[icons[i].Path] == defaultAppIconSetName
+		jDefault := iconSets[icons[j].Path] == defaultAppIconSetName
+		if iDefault != jDefault {
+			return iDefault
+		}
+		if icons[i].Width*icons[i].Height != icons[j].Width*icons[j].Height {
+			return icons[i].Width*icons[i].Height > icons[j].Width*icons[j].Height
+		}
+		return icons[i].Path < icons[j].Path
+	})
+
+	return icons, nil
+}
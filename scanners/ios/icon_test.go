@@ -0,0 +1,43 @@
+package ios
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writePNG(t *testing.T, pth string, width, height int) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0777))
+	f, err := os.Create(pth)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))))
+	require.NoError(t, f.Close())
+}
+
+func TestFindAppIcons(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__ios_icon__")
+	require.NoError(t, err)
+
+	assetCatalog := filepath.Join(tmpDir, "App", "Assets.xcassets")
+	writePNG(t, filepath.Join(assetCatalog, "AppIcon.appiconset", "Icon-60@2x.png"), 120, 120)
+	writePNG(t, filepath.Join(assetCatalog, "AppIcon.appiconset", "Icon-1024.png"), 1024, 1024)
+	writePNG(t, filepath.Join(assetCatalog, "AppIcon.appiconset", "Icon-60@3x.png"), 180, 180)
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(assetCatalog, "AppIcon.appiconset", "Contents.json"), "{}"))
+	writePNG(t, filepath.Join(assetCatalog, "AppIcon-Beta.appiconset", "Icon-60@3x.png"), 180, 180)
+	writePNG(t, filepath.Join(assetCatalog, "Logo.imageset", "Logo.png"), 2048, 2048)
+	writePNG(t, filepath.Join(tmpDir, "Pods", "Lib", "Assets.xcassets", "AppIcon.appiconset", "Icon.png"), 1024, 1024)
+
+	icons, err := FindAppIcons(tmpDir, tmpDir)
+	require.NoError(t, err)
+	require.Equal(t, models.Icons{
+		{Path: "App/Assets.xcassets/AppIcon.appiconset/Icon-1024.png", Width: 1024, Height: 1024},
+		{Path: "App/Assets.xcassets/AppIcon-Beta.appiconset/Icon-60@3x.png", Width: 180, Height: 180},
+	}, icons)
+}
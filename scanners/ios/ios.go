@@ -1,6 +1,11 @@
 package ios
 
-import "github.com/bitrise-core/bitrise-init/models"
+import (
+	"fmt"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+)
 
 //------------------
 // ScannerInterface
@@ -10,6 +15,11 @@ import "github.com/bitrise-core/bitrise-init/models"
 type Scanner struct {
 	SearchDir         string
 	ConfigDescriptors []ConfigDescriptor
+	// Offline disables the network dependent detection, like the CocoaPods based Podfile parsing
+	Offline bool
+
+	summary models.Summary
+	icons   models.Icons
 }
 
 // NewScanner ...
@@ -34,6 +44,11 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return detected, nil
 }
 
+// SetOffline ...
+func (scanner *Scanner) SetOffline(offline bool) {
+	scanner.Offline = offline
+}
+
 // ExcludedScannerNames ...
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -41,16 +56,35 @@ func (Scanner) ExcludedScannerNames() []string {
 
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
-	options, configDescriptors, warnings, err := GenerateOptions(XcodeProjectTypeIOS, scanner.SearchDir)
+	options, configDescriptors, summary, warnings, err := GenerateOptions(XcodeProjectTypeIOS, scanner.SearchDir, scanner.Offline)
 	if err != nil {
 		return models.OptionNode{}, warnings, err
 	}
 
 	scanner.ConfigDescriptors = configDescriptors
+	scanner.summary = summary
+
+	icons, err := FindAppIcons(scanner.SearchDir, scanner.SearchDir)
+	if err != nil {
+		warning := fmt.Sprintf("Failed to search for app icons, error: %s", err)
+		log.TWarnf(warning)
+		warnings = append(warnings, warning)
+	}
+	scanner.icons = icons
 
 	return options, warnings, nil
 }
 
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
+// Icons ...
+func (scanner *Scanner) Icons() models.Icons {
+	return scanner.icons
+}
+
 // DefaultOptions ...
 func (Scanner) DefaultOptions() models.OptionNode {
 	return GenerateDefaultOptions(XcodeProjectTypeIOS)
@@ -0,0 +1,231 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-tools/xcode-project/serialized"
+	projectXcodeproj "github.com/bitrise-tools/xcode-project/xcodeproj"
+	"howett.net/plist"
+)
+
+const (
+	// SwiftVersionKey ...
+	SwiftVersionKey = "SWIFT_VERSION"
+	// SwiftObjcBridgingHeaderKey ...
+	SwiftObjcBridgingHeaderKey = "SWIFT_OBJC_BRIDGING_HEADER"
+)
+
+// TargetLanguage ...
+type TargetLanguage string
+
+const (
+	// TargetLanguageUnknown means the target has neither Swift nor Objective-C sources.
+	TargetLanguageUnknown TargetLanguage = ""
+	// TargetLanguageObjectiveC ...
+	TargetLanguageObjectiveC TargetLanguage = "Objective-C"
+	// TargetLanguageSwift ...
+	TargetLanguageSwift TargetLanguage = "Swift"
+	// TargetLanguageMixed means the target has both Swift and Objective-C sources.
+	TargetLanguageMixed TargetLanguage = "mixed Swift and Objective-C"
+)
+
+// TargetLanguageInfo is the source language of a target.
+type TargetLanguageInfo struct {
+	Target   string
+	Language TargetLanguage
+	// BridgingHeader is the value of the SWIFT_OBJC_BRIDGING_HEADER build setting, empty if not set.
+	BridgingHeader string
+}
+
+// classifyTargetLanguage returns the language of a target by the number of its Swift and Objective-C (.m, .mm) sources.
+// The SWIFT_VERSION build setting is only used if the target lists no sources (like the targets using folder references).
+func classifyTargetLanguage(swiftCount, objcCount int, hasSwiftVersion bool) TargetLanguage {
+	switch {
+	case swiftCount > 0 && objcCount > 0:
+		return TargetLanguageMixed
+	case swiftCount > 0:
+		return TargetLanguageSwift
+	case objcCount > 0:
+		return TargetLanguageObjectiveC
+	case hasSwiftVersion:
+		return TargetLanguageSwift
+	}
+	return TargetLanguageUnknown
+}
+
+// ProjectTargetLanguages returns the languages of the project's native targets, by the file extensions of their
+// Sources build phase and the SWIFT_VERSION build setting. The targets with unknown language are not listed.
+func ProjectTargetLanguages(projectPth string) ([]TargetLanguageInfo, error) {
+	pbxprojPth := filepath.Join(projectPth, "project.pbxproj")
+	if exist, err := pathutil.IsPathExists(pbxprojPth); err != nil {
+		return nil, err
+	} else if !exist {
+		return []TargetLanguageInfo{}, nil
+	}
+
+	project, err := projectXcodeproj.Open(projectPth)
+	if err != nil {
+		return nil, err
+	}
+
+	// the build phases are not parsed by the xcodeproj package
+	b, err := fileutil.ReadBytesFromFile(pbxprojPth)
+	if err != nil {
+		return nil, err
+	}
+	var raw serialized.Object
+	if _, err := plist.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+	objects, err := raw.Object("objects")
+	if err != nil {
+		return nil, err
+	}
+
+	projectSettings := map[string]serialized.Object{}
+	for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+		projectSettings[buildConfiguration.Name] = buildConfiguration.BuildSettings
+	}
+
+	languages := []TargetLanguageInfo{}
+	for _, target := range project.Proj.Targets {
+		if target.Type != projectXcodeproj.NativeTargetType {
+			continue
+		}
+
+		swiftCount, objcCount := 0, 0
+		for _, sourcePth := range targetSourcePaths(objects, target.ID) {
+			switch strings.ToLower(filepath.Ext(sourcePth)) {
+			case ".swift":
+				swiftCount++
+			case ".m", ".mm":
+				objcCount++
+			}
+		}
+
+		hasSwiftVersion := false
+		bridgingHeader := ""
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			for _, settings := range []serialized.Object{buildConfiguration.BuildSettings, projectSettings[buildConfiguration.Name]} {
+				if settings == nil {
+					continue
+				}
+				if _, err := settings.String(SwiftVersionKey); err == nil {
+					hasSwiftVersion = true
+				}
+				if value, err := settings.String(SwiftObjcBridgingHeaderKey); err == nil && bridgingHeader == "" {
+					bridgingHeader = value
+				}
+			}
+		}
+
+		language := classifyTargetLanguage(swiftCount, objcCount, hasSwiftVersion)
+		if language == TargetLanguageUnknown {
+			continue
+		}
+
+		info := TargetLanguageInfo{Target: target.Name, Language: language}
+		if language == TargetLanguageMixed {
+			info.BridgingHeader = bridgingHeader
+		}
+		languages = append(languages, info)
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Target < languages[j].Target
+	})
+
+	return languages, nil
+}
+
+// targetSourcePaths returns the paths of the files compiled by the target's Sources build phases.
+func targetSourcePaths(objects serialized.Object, targetID string) []string {
+	pths := []string{}
+
+	target, err := objects.Object(targetID)
+	if err != nil {
+		return pths
+	}
+	buildPhaseIDs, err := target.StringSlice("buildPhases")
+	if err != nil {
+		return pths
+	}
+
+	for _, buildPhaseID := range buildPhaseIDs {
+		buildPhase, err := objects.Object(buildPhaseID)
+		if err != nil {
+			continue
+		}
+		if isa, err := buildPhase.String("isa"); err != nil || isa != "PBXSourcesBuildPhase" {
+			continue
+		}
+
+		buildFileIDs, err := buildPhase.StringSlice("files")
+		if err != nil {
+			continue
+		}
+		for _, buildFileID := range buildFileIDs {
+			buildFile, err := objects.Object(buildFileID)
+			if err != nil {
+				continue
+			}
+			fileRefID, err := buildFile.String("fileRef")
+			if err != nil {
+				continue
+			}
+			fileRef, err := objects.Object(fileRefID)
+			if err != nil {
+				continue
+			}
+			if pth, err := fileRef.String("path"); err == nil {
+				pths = append(pths, pth)
+			}
+		}
+	}
+
+	return pths
+}
+
+// inspectTargetLanguages collects the target languages of the given projects, belonging to the given container.
+// The mixed targets are listed with their bridging header.
+func inspectTargetLanguages(containerPth string, projectPths []string) (models.Summary, models.Warnings) {
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+
+	descriptions := []string{}
+	for _, projectPth := range projectPths {
+		languages, err := ProjectTargetLanguages(projectPth)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to read the target languages of project (%s), error: %s", projectPth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+
+		for _, info := range languages {
+			description := fmt.Sprintf("%s: %s", info.Target, info.Language)
+			if info.Language == TargetLanguageMixed {
+				if info.BridgingHeader != "" {
+					description += fmt.Sprintf(" (bridging header: %s)", info.BridgingHeader)
+				} else {
+					description += " (no bridging header)"
+				}
+			}
+			descriptions = append(descriptions, description)
+		}
+	}
+
+	if len(descriptions) > 0 {
+		log.TPrintf("Target languages: %s", strings.Join(descriptions, ", "))
+		summary = append(summary, fmt.Sprintf("%s: target languages: %s", containerPth, strings.Join(descriptions, ", ")))
+	}
+
+	return summary, warnings
+}
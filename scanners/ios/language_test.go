@@ -0,0 +1,174 @@
+package ios
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+// testLanguagePbxprojContent returns a project with a single App target, compiling the given sources,
+// with the given build settings added to the target's build configuration.
+func testLanguagePbxprojContent(sources []string, buildSettings string) string {
+	fileRefs, buildFiles, buildFileIDs := []string{}, []string{}, []string{}
+	for i, source := range sources {
+		fileRefID := fmt.Sprintf("2D1E4F%02d1F5E4C2B00A1B2C3", i)
+		buildFileID := fmt.Sprintf("2D1E5F%02d1F5E4C2B00A1B2C3", i)
+		fileRefs = append(fileRefs, fmt.Sprintf("\t\t%s = {isa = PBXFileReference; lastKnownFileType = sourcecode; path = %s; sourceTree = \"<group>\"; };", fileRefID, source))
+		buildFiles = append(buildFiles, fmt.Sprintf("\t\t%s = {isa = PBXBuildFile; fileRef = %s; };", buildFileID, fileRefID))
+		buildFileIDs = append(buildFileIDs, "\t\t\t\t"+buildFileID+",")
+	}
+
+	return `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	classes = {
+	};
+	objectVersion = 50;
+	objects = {
+` + strings.Join(fileRefs, "\n") + `
+` + strings.Join(buildFiles, "\n") + `
+		2D1E40001F5E4C2B00A1B2C3 /* App.app */ = {isa = PBXFileReference; explicitFileType = wrapper.application; includeInIndex = 0; path = App.app; sourceTree = BUILT_PRODUCTS_DIR; };
+		2D1E40011F5E4C2B00A1B2C3 /* Sources */ = {
+			isa = PBXSourcesBuildPhase;
+			buildActionMask = 2147483647;
+			files = (
+` + strings.Join(buildFileIDs, "\n") + `
+			);
+			runOnlyForDeploymentPostprocessing = 0;
+		};
+		2D1E40021F5E4C2B00A1B2C3 /* App */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 2D1E40031F5E4C2B00A1B2C3;
+			buildPhases = (
+				2D1E40011F5E4C2B00A1B2C3 /* Sources */,
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = App;
+			productName = App;
+			productReference = 2D1E40001F5E4C2B00A1B2C3 /* App.app */;
+			productType = "com.apple.product-type.application";
+		};
+		2D1E40041F5E4C2B00A1B2C3 /* Project object */ = {
+			isa = PBXProject;
+			buildConfigurationList = 2D1E40051F5E4C2B00A1B2C3;
+			compatibilityVersion = "Xcode 9.3";
+			projectDirPath = "";
+			projectRoot = "";
+			targets = (
+				2D1E40021F5E4C2B00A1B2C3 /* App */,
+			);
+		};
+		2D1E40061F5E4C2B00A1B2C3 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				SDKROOT = iphoneos;
+			};
+			name = Debug;
+		};
+		2D1E40071F5E4C2B00A1B2C3 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				` + buildSettings + `
+				PRODUCT_NAME = "$(TARGET_NAME)";
+			};
+			name = Debug;
+		};
+		2D1E40051F5E4C2B00A1B2C3 = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				2D1E40061F5E4C2B00A1B2C3 /* Debug */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Debug;
+		};
+		2D1E40031F5E4C2B00A1B2C3 = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				2D1E40071F5E4C2B00A1B2C3 /* Debug */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Debug;
+		};
+	};
+	rootObject = 2D1E40041F5E4C2B00A1B2C3 /* Project object */;
+}
+`
+}
+
+func TestClassifyTargetLanguage(t *testing.T) {
+	require.Equal(t, TargetLanguageMixed, classifyTargetLanguage(2, 1, true))
+	require.Equal(t, TargetLanguageSwift, classifyTargetLanguage(2, 0, false))
+	require.Equal(t, TargetLanguageObjectiveC, classifyTargetLanguage(0, 3, false))
+	require.Equal(t, TargetLanguageObjectiveC, classifyTargetLanguage(0, 3, true))
+	require.Equal(t, TargetLanguageSwift, classifyTargetLanguage(0, 0, true))
+	require.Equal(t, TargetLanguageUnknown, classifyTargetLanguage(0, 0, false))
+}
+
+func TestProjectTargetLanguages(t *testing.T) {
+	dir, cleanup := testhelper.TempDir(t, "language")
+	defer cleanup()
+
+	t.Log("pure Objective-C target")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "ObjC.xcodeproj/project.pbxproj", testLanguagePbxprojContent([]string{"AppDelegate.m", "main.m", "Legacy.mm", "AppDelegate.h"}, ""))
+
+		languages, err := ProjectTargetLanguages(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []TargetLanguageInfo{{Target: "App", Language: TargetLanguageObjectiveC}}, languages)
+	}
+
+	t.Log("pure Swift target")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "Swift.xcodeproj/project.pbxproj", testLanguagePbxprojContent([]string{"AppDelegate.swift", "ViewController.swift"}, "SWIFT_VERSION = 5.0;"))
+
+		languages, err := ProjectTargetLanguages(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []TargetLanguageInfo{{Target: "App", Language: TargetLanguageSwift}}, languages)
+
+		summary, warnings := inspectTargetLanguages("Swift.xcodeproj", []string{projectPth})
+		require.Equal(t, models.Summary{"Swift.xcodeproj: target languages: App: Swift"}, summary)
+		require.Equal(t, models.Warnings{}, warnings)
+	}
+
+	t.Log("mixed target with bridging header")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "Mixed.xcodeproj/project.pbxproj", testLanguagePbxprojContent([]string{"AppDelegate.swift", "Legacy.m"}, `SWIFT_OBJC_BRIDGING_HEADER = "App/App-Bridging-Header.h";
+				SWIFT_VERSION = 5.0;`))
+
+		languages, err := ProjectTargetLanguages(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []TargetLanguageInfo{{Target: "App", Language: TargetLanguageMixed, BridgingHeader: "App/App-Bridging-Header.h"}}, languages)
+
+		summary, _ := inspectTargetLanguages("Mixed.xcodeproj", []string{projectPth})
+		require.Equal(t, models.Summary{"Mixed.xcodeproj: target languages: App: mixed Swift and Objective-C (bridging header: App/App-Bridging-Header.h)"}, summary)
+	}
+
+	t.Log("target without listed sources")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "Folders.xcodeproj/project.pbxproj", testLanguagePbxprojContent([]string{}, "SWIFT_VERSION = 5.0;"))
+
+		languages, err := ProjectTargetLanguages(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []TargetLanguageInfo{{Target: "App", Language: TargetLanguageSwift}}, languages)
+	}
+
+	t.Log("no languages")
+	{
+		projectPth := testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+
+		languages, err := ProjectTargetLanguages(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []TargetLanguageInfo{}, languages)
+
+		summary, warnings := inspectTargetLanguages("MultiApp.xcodeproj", []string{projectPth})
+		require.Equal(t, models.Summary{}, summary)
+		require.Equal(t, models.Warnings{}, warnings)
+	}
+}
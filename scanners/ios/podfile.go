@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"encoding/json"
@@ -161,12 +162,58 @@ end
 	return workspacePathOutput.Data, nil
 }
 
+var (
+	podfileProjectDeclarationRegexp   = regexp.MustCompile(`(?m)^(?:project|xcodeproj)\s*\(?\s*['"]([^'"]+)['"]`)
+	podfileWorkspaceDeclarationRegexp = regexp.MustCompile(`(?m)^workspace\s*\(?\s*['"]([^'"]+)['"]`)
+)
+
+// readPodfileDeclaration returns the path declared at the top level of the Podfile by the given regexp,
+// with the given extension appended if missing (as CocoaPods does).
+func readPodfileDeclaration(podfilePth string, declarationRegexp *regexp.Regexp, ext string) (string, error) {
+	content, err := fileutil.ReadStringFromFile(podfilePth)
+	if err != nil {
+		return "", err
+	}
+
+	match := declarationRegexp.FindStringSubmatch(content)
+	if len(match) != 2 {
+		return "", nil
+	}
+
+	pth := match[1]
+	if filepath.Ext(pth) != ext {
+		pth += ext
+	}
+	return pth, nil
+}
+
+func getDeclaredProjectRelativePath(podfilePth, _ string) (string, error) {
+	return readPodfileDeclaration(podfilePth, podfileProjectDeclarationRegexp, ".xcodeproj")
+}
+
+func getDeclaredWorkspaceRelativePath(podfilePth, _ string) (string, error) {
+	return readPodfileDeclaration(podfilePth, podfileWorkspaceDeclarationRegexp, ".xcworkspace")
+}
+
 // GetWorkspaceProjectMap ...
 // If one project exists in the Podfile's directory, workspace name will be the project's name.
 // If more then one project exists in the Podfile's directory, root 'xcodeproj/project' property have to be defined in the Podfile.
 // Root 'xcodeproj/project' property will be mapped to the default cocoapods target (Pods).
 // If workspace property defined in the Podfile, it will override the workspace name.
 func GetWorkspaceProjectMap(podfilePth string, projects []string) (map[string]string, error) {
+	return getWorkspaceProjectMap(podfilePth, projects, getUserDefinedProjectRelavtivePath, getUserDefinedWorkspaceRelativePath)
+}
+
+// GetWorkspaceProjectMapOffline is the network access free variant of GetWorkspaceProjectMap:
+// the Podfile is parsed without CocoaPods, only the top level project (xcodeproj) and workspace declarations are recognized.
+func GetWorkspaceProjectMapOffline(podfilePth string, projects []string) (map[string]string, error) {
+	return getWorkspaceProjectMap(podfilePth, projects, getDeclaredProjectRelativePath, getDeclaredWorkspaceRelativePath)
+}
+
+// podfilePathReader returns a path defined in the Podfile, relative to the Podfile's directory, empty if not defined.
+type podfilePathReader func(podfilePth, cocoapodsVersion string) (string, error)
+
+func getWorkspaceProjectMap(podfilePth string, projects []string, readProjectPath, readWorkspacePath podfilePathReader) (map[string]string, error) {
 	podfileDir := filepath.Dir(podfilePth)
 
 	cocoapodsVersion := ""
@@ -207,7 +254,7 @@ func GetWorkspaceProjectMap(podfilePth string, projects []string) (map[string]st
 	}
 	// ----
 
-	projectRelPth, err := getUserDefinedProjectRelavtivePath(podfilePth, cocoapodsVersion)
+	projectRelPth, err := readProjectPath(podfilePth, cocoapodsVersion)
 	if err != nil {
 		return map[string]string{}, fmt.Errorf("failed to get user defined project path, error: %s", err)
 	}
@@ -234,7 +281,7 @@ func GetWorkspaceProjectMap(podfilePth string, projects []string) (map[string]st
 		return map[string]string{}, fmt.Errorf("project not found at: %s", projectPth)
 	}
 
-	workspaceRelPth, err := getUserDefinedWorkspaceRelativePath(podfilePth, cocoapodsVersion)
+	workspaceRelPth, err := readWorkspacePath(podfilePth, cocoapodsVersion)
 	if err != nil {
 		return map[string]string{}, fmt.Errorf("failed to get user defined workspace path, error: %s", err)
 	}
@@ -223,6 +223,51 @@ pod 'Alamofire', '~> 3.4'
 	}
 }
 
+func TestGetWorkspaceProjectMapOffline(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__utility_test__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	t.Log("1 project in Podfile's dir")
+	{
+		podfilePth := filepath.Join(tmpDir, "one_project", "Podfile")
+		require.NoError(t, os.MkdirAll(filepath.Dir(podfilePth), 0777))
+		require.NoError(t, fileutil.WriteStringToFile(podfilePth, "platform :ios, '9.0'\npod 'Alamofire', '~> 3.4'\n"))
+
+		projectPth := filepath.Join(tmpDir, "one_project", "project.xcodeproj")
+		require.NoError(t, fileutil.WriteStringToFile(projectPth, ""))
+
+		workspaceProjectMap, err := GetWorkspaceProjectMapOffline(podfilePth, []string{projectPth})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{filepath.Join(tmpDir, "one_project", "project.xcworkspace"): projectPth}, workspaceProjectMap)
+	}
+
+	t.Log("project and workspace declared in the Podfile")
+	{
+		podfilePth := filepath.Join(tmpDir, "declared", "Podfile")
+		require.NoError(t, os.MkdirAll(filepath.Dir(podfilePth), 0777))
+		require.NoError(t, fileutil.WriteStringToFile(podfilePth, `platform :ios, '9.0'
+project 'project1'
+workspace "MyWorkspace.xcworkspace"
+
+target 'Other' do
+  project 'project2'
+end
+`))
+
+		project1Pth := filepath.Join(tmpDir, "declared", "project1.xcodeproj")
+		require.NoError(t, fileutil.WriteStringToFile(project1Pth, ""))
+		project2Pth := filepath.Join(tmpDir, "declared", "project2.xcodeproj")
+		require.NoError(t, fileutil.WriteStringToFile(project2Pth, ""))
+
+		workspaceProjectMap, err := GetWorkspaceProjectMapOffline(podfilePth, []string{project1Pth, project2Pth})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{filepath.Join(tmpDir, "declared", "MyWorkspace.xcworkspace"): project1Pth}, workspaceProjectMap)
+	}
+}
+
 func TestGetWorkspaceProjectMap(t *testing.T) {
 	tmpDir, err := pathutil.NormalizedOSTempDirPath("__utility_test__")
 	require.NoError(t, err)
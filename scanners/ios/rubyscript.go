@@ -6,10 +6,11 @@ import (
 
 	"os"
 
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/utility"
 	"github.com/bitrise-io/go-utils/command"
 	"github.com/bitrise-io/go-utils/errorutil"
 	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -31,6 +32,8 @@ func runRubyScriptForOutput(scriptContent, gemfileContent, inDir string, withEnv
 			return "", err
 		}
 
+		utility.ReportNetworkAccess("bundle install")
+
 		cmd := command.New("bundle", "install")
 
 		if inDir != "" {
@@ -0,0 +1,42 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-tools/go-xcode/xcodeproj"
+)
+
+// UserSchemePths returns the user scheme files (xcuserdata/<user>.xcuserdatad/xcschemes/*.xcscheme)
+// of the given projects or workspaces.
+func UserSchemePths(projectOrWorkspacePths ...string) ([]string, error) {
+	pths := []string{}
+	for _, projectOrWorkspacePth := range projectOrWorkspacePths {
+		matches, err := filepath.Glob(filepath.Join(projectOrWorkspacePth, "xcuserdata", "*.xcuserdatad", "xcschemes", "*.xcscheme"))
+		if err != nil {
+			return nil, err
+		}
+		pths = append(pths, matches...)
+	}
+	return pths, nil
+}
+
+// hasNoScheme returns true if there is no scheme to build the project (or workspace) with:
+// it has no shared and no user schemes. Its targets do not count, the build fails with scheme not found without a scheme.
+func hasNoScheme(projectOrWorkspacePths []string, sharedSchemes []xcodeproj.SchemeModel) (bool, error) {
+	if len(sharedSchemes) > 0 {
+		return false, nil
+	}
+
+	userSchemePths, err := UserSchemePths(projectOrWorkspacePths...)
+	if err != nil {
+		return false, err
+	}
+	return len(userSchemePths) == 0, nil
+}
+
+func noSchemeWarning(projectOrWorkspacePth string) string {
+	return fmt.Sprintf(`No scheme found for: %s, it is not offered for building.
+The project has no shared or user schemes.
+Create a scheme in Xcode and <a href="http://devcenter.bitrise.io/ios/frequent-ios-issues/#xcode-scheme-not-found">share it</a>.`, projectOrWorkspacePth)
+}
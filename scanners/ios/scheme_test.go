@@ -0,0 +1,165 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserSchemePths(t *testing.T) {
+	dir, cleanup := testhelper.TempDir(t, "no-scheme")
+	defer cleanup()
+	createNoSchemeProject(t, dir)
+
+	pths, err := UserSchemePths(filepath.Join(dir, "NoScheme.xcodeproj"))
+	require.NoError(t, err)
+	require.Equal(t, []string{}, pths)
+
+	userSchemePth := filepath.Join(testhelper.WriteFile(t, dir, "NoScheme.xcodeproj/xcuserdata/bitrise.xcuserdatad/xcschemes/NoScheme.xcscheme", ""), "NoScheme.xcscheme")
+
+	pths, err = UserSchemePths(filepath.Join(dir, "NoScheme.xcodeproj"))
+	require.NoError(t, err)
+	require.Equal(t, []string{userSchemePth}, pths)
+}
+
+func TestGenerateOptionsSkipsProjectsWithoutScheme(t *testing.T) {
+	t.Log("all projects without scheme")
+	{
+		dir, cleanup := testhelper.TempDir(t, "no-scheme")
+		defer cleanup()
+		createNoSchemeProject(t, dir)
+
+		options, configDescriptors, _, warnings, err := generateOptionsIn(t, dir)
+		require.EqualError(t, err, "No ios project or workspace with a scheme found")
+		require.True(t, options.IsEmpty())
+		require.Equal(t, 0, len(configDescriptors))
+		require.Equal(t, models.Warnings{noSchemeWarning("NoScheme.xcodeproj")}, warnings)
+	}
+
+	t.Log("project with targets, but without scheme")
+	{
+		dir, cleanup := testhelper.TempDir(t, "no-scheme")
+		defer cleanup()
+		testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+
+		_, _, _, warnings, err := generateOptionsIn(t, dir)
+		require.EqualError(t, err, "No ios project or workspace with a scheme found")
+		require.Equal(t, models.Warnings{noSchemeWarning("MultiApp.xcodeproj")}, warnings)
+	}
+
+	t.Log("project with targets and a user scheme")
+	{
+		dir, cleanup := testhelper.TempDir(t, "no-scheme")
+		defer cleanup()
+		testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+		testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/xcuserdata/bitrise.xcuserdatad/xcschemes/Shop.xcscheme", testSchemeContent("13C4D5A81F5E4C2B00A1B2C3", "Shop", "Release"))
+
+		options, _, _, warnings, err := generateOptionsIn(t, dir)
+		require.NoError(t, err)
+		require.Equal(t, []string{"MultiApp.xcodeproj"}, options.GetValues())
+		require.NotContains(t, warnings, noSchemeWarning("MultiApp.xcodeproj"))
+	}
+
+	t.Log("project without scheme next to a workspace with schemes")
+	{
+		dir, cleanup := createMultiAppWorkspace(t)
+		defer cleanup()
+		createNoSchemeProject(t, filepath.Join(dir, "Legacy"))
+
+		options, configDescriptors, _, warnings, err := generateOptionsIn(t, dir)
+		require.NoError(t, err)
+		require.Equal(t, []string{"MultiApp.xcworkspace"}, options.GetValues())
+		require.NotEqual(t, 0, len(configDescriptors))
+		require.Contains(t, warnings, noSchemeWarning("Legacy/NoScheme.xcodeproj"))
+	}
+}
+
+// generateOptionsIn runs GenerateOptions in the given search dir, like the scanner does,
+// the test project fixtures (see testhelper.WriteFile) are scanned by it.
+func generateOptionsIn(t *testing.T, dir string) (models.OptionNode, []ConfigDescriptor, models.Summary, models.Warnings, error) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	return GenerateOptions(XcodeProjectTypeIOS, dir, false)
+}
+
+// createNoSchemeProject creates an iOS project without shared or user schemes and without targets in the given dir.
+func createNoSchemeProject(t *testing.T, dir string) {
+	testhelper.WriteFile(t, dir, "NoScheme.xcodeproj/project.pbxproj", testNoSchemePbxprojContent)
+}
+
+const testNoSchemePbxprojContent = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	classes = {
+	};
+	objectVersion = 50;
+	objects = {
+
+/* Begin PBXGroup section */
+		23C4D5901F5E4C2B00A1B2C3 = {
+			isa = PBXGroup;
+			children = (
+			);
+			sourceTree = "<group>";
+		};
+/* End PBXGroup section */
+
+/* Begin PBXNativeTarget section */
+/* End PBXNativeTarget section */
+
+/* Begin PBXProject section */
+		23C4D5921F5E4C2B00A1B2C3 /* Project object */ = {
+			isa = PBXProject;
+			buildConfigurationList = 23C4D5931F5E4C2B00A1B2C3 /* Build configuration list for PBXProject "NoScheme" */;
+			compatibilityVersion = "Xcode 9.3";
+			mainGroup = 23C4D5901F5E4C2B00A1B2C3;
+			projectDirPath = "";
+			projectRoot = "";
+			targets = (
+			);
+		};
+/* End PBXProject section */
+
+/* Begin XCBuildConfiguration section */
+		23C4D5941F5E4C2B00A1B2C3 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 11.0;
+				SDKROOT = iphoneos;
+			};
+			name = Debug;
+		};
+		23C4D5951F5E4C2B00A1B2C3 /* Release */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 11.0;
+				SDKROOT = iphoneos;
+			};
+			name = Release;
+		};
+/* End XCBuildConfiguration section */
+
+/* Begin XCConfigurationList section */
+		23C4D5931F5E4C2B00A1B2C3 /* Build configuration list for PBXProject "NoScheme" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				23C4D5941F5E4C2B00A1B2C3 /* Debug */,
+				23C4D5951F5E4C2B00A1B2C3 /* Release */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Release;
+		};
+/* End XCConfigurationList section */
+	};
+	rootObject = 23C4D5921F5E4C2B00A1B2C3 /* Project object */;
+}
+`
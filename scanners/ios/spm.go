@@ -0,0 +1,87 @@
+package ios
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-tools/go-xcode/xcodeproj"
+	"github.com/bitrise-tools/xcode-project/xcworkspace"
+)
+
+const (
+	packageManifestFileName = "Package.swift"
+
+	resolvePackagesStepTitle     = "Resolve Swift package dependencies"
+	resolvePackagesScriptContent = `#!/usr/bin/env bash
+set -ex
+
+xcodebuild -resolvePackageDependencies -workspace "$` + ProjectPathInputEnvKey + `" -scheme "$` + SchemeInputEnvKey + `"
+`
+)
+
+// WorkspaceLocalPackages returns the absolute paths of the local Swift packages (directories with a Package.swift manifest)
+// referenced by the workspace.
+func WorkspaceLocalPackages(workspacePth string) ([]string, error) {
+	workspace, err := xcworkspace.Open(workspacePth)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := workspace.FileLocations()
+	if err != nil {
+		return nil, err
+	}
+
+	packages := []string{}
+	for _, location := range locations {
+		exist, err := pathutil.IsPathExists(filepath.Join(location, packageManifestFileName))
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			packages = append(packages, location)
+		}
+	}
+	return packages, nil
+}
+
+// isInDir returns true if the pth is the dir or is inside the dir, both paths are expected to be absolute.
+func isInDir(pth, dir string) bool {
+	return pth == dir || strings.HasPrefix(pth, dir+string(filepath.Separator))
+}
+
+// filterLocalPackageProjects removes the projects placed inside the given local Swift packages,
+// these are not build units on their own, returns the kept and the removed projects.
+func filterLocalPackageProjects(projects []xcodeproj.ProjectModel, packages []string) ([]xcodeproj.ProjectModel, []xcodeproj.ProjectModel, error) {
+	kept := []xcodeproj.ProjectModel{}
+	removed := []xcodeproj.ProjectModel{}
+	for _, project := range projects {
+		absProjectPth, err := pathutil.AbsPath(project.Pth)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		inPackage := false
+		for _, pkg := range packages {
+			if isInDir(absProjectPth, pkg) {
+				inPackage = true
+				break
+			}
+		}
+
+		if inPackage {
+			removed = append(removed, project)
+		} else {
+			kept = append(kept, project)
+		}
+	}
+	return kept, removed, nil
+}
+
+func resolvePackagesStepListItem() bitriseModels.StepListItemModel {
+	return steps.ScriptSteplistItem(resolvePackagesStepTitle, envmanModels.EnvironmentItemModel{"content": resolvePackagesScriptContent})
+}
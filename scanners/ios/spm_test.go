@@ -0,0 +1,84 @@
+package ios
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+// createLocalPackageWorkspace creates a workspace referencing an app project and a local Swift package,
+// the package contains a generated Xcode project as well, the returned func removes it.
+func createLocalPackageWorkspace(t *testing.T) (string, func()) {
+	dir, cleanup := testhelper.TempDir(t, "local-package")
+
+	testhelper.WriteFile(t, dir, "App.xcworkspace/contents.xcworkspacedata", testLocalPackageWorkspaceContent)
+	testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+	testhelper.WriteFile(t, dir, "MultiApp.xcodeproj/xcshareddata/xcschemes/Shop.xcscheme", testSchemeContent("13C4D5A81F5E4C2B00A1B2C3", "Shop", "Release"))
+
+	testhelper.WriteFile(t, dir, "Packages/LocalKit/Package.swift", "// swift-tools-version:5.0\n")
+	testhelper.WriteFile(t, dir, "Packages/LocalKit/LocalKit.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+	testhelper.WriteFile(t, dir, "Packages/LocalKit/LocalKit.xcodeproj/xcshareddata/xcschemes/LocalKit.xcscheme", testSchemeContent("13C4D5A81F5E4C2B00A1B2C3", "Shop", "Release"))
+
+	return dir, cleanup
+}
+
+func TestWorkspaceLocalPackages(t *testing.T) {
+	dir, cleanup := createLocalPackageWorkspace(t)
+	defer cleanup()
+
+	packages, err := WorkspaceLocalPackages(filepath.Join(dir, "App.xcworkspace"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "Packages", "LocalKit")}, packages)
+}
+
+func TestIsInDir(t *testing.T) {
+	require.True(t, isInDir("/repo/Packages/Kit", "/repo/Packages/Kit"))
+	require.True(t, isInDir("/repo/Packages/Kit/Kit.xcodeproj", "/repo/Packages/Kit"))
+	require.False(t, isInDir("/repo/Packages/KitExtras/Kit.xcodeproj", "/repo/Packages/Kit"))
+}
+
+func TestGenerateOptionsSkipsLocalPackageProjects(t *testing.T) {
+	dir, cleanup := createLocalPackageWorkspace(t)
+	defer cleanup()
+
+	options, configDescriptors, summary, _, err := generateOptionsIn(t, dir)
+	require.NoError(t, err)
+
+	// only the app workspace is offered as build unit
+	require.Equal(t, []string{"App.xcworkspace"}, options.GetValues())
+	require.Contains(t, summary, "App.xcworkspace: local Swift packages: Packages/LocalKit")
+
+	for _, descriptor := range configDescriptors {
+		require.True(t, descriptor.HasLocalPackages)
+		require.True(t, strings.HasSuffix(descriptor.ConfigName(XcodeProjectTypeIOS), "-spm-config"))
+
+		configBuilder := GenerateConfigBuilder(XcodeProjectTypeIOS, descriptor, true)
+		config, err := configBuilder.Generate("ios")
+		require.NoError(t, err)
+
+		found := false
+		for _, stepListItem := range config.Workflows["primary"].Steps {
+			for _, step := range stepListItem {
+				if step.Title != nil && *step.Title == resolvePackagesStepTitle {
+					found = true
+				}
+			}
+		}
+		require.True(t, found)
+	}
+}
+
+const testLocalPackageWorkspaceContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:Packages/LocalKit">
+   </FileRef>
+   <FileRef
+      location = "group:MultiApp.xcodeproj">
+   </FileRef>
+</Workspace>
+`
@@ -2,16 +2,18 @@ package ios
 
 import (
 	"fmt"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
 	"path/filepath"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/version"
 	"github.com/bitrise-core/bitrise-init/steps"
 	"github.com/bitrise-core/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-tools/go-xcode/xcodeproj"
 )
@@ -66,6 +68,10 @@ const (
 	CarthageCommandInputKey = "carthage_command"
 )
 
+// XcarchivePath is the archive created by the xcode-archive step, named after the selected scheme.
+// The exported ipa (or app) is deployed from the deploy dir, but the archive itself is not.
+const XcarchivePath = "$BITRISE_XCARCHIVE_PATH"
+
 const cartfileBase = "Cartfile"
 const cartfileResolvedBase = "Cartfile.resolved"
 
@@ -78,6 +84,12 @@ type ConfigDescriptor struct {
 	CarthageCommand      string
 	HasTest              bool
 	MissingSharedSchemes bool
+	HasXcconfig          bool
+	HasLocalPackages     bool
+	// HasSimulatorArch is true if the simulator builds of the tests target an explicit architecture
+	HasSimulatorArch bool
+	// HasVersionBump is true if the app's version source is detected, the build number incrementing is offered
+	HasVersionBump bool
 }
 
 // NewConfigDescriptor ...
@@ -105,6 +117,18 @@ func (descriptor ConfigDescriptor) ConfigName(projectType XcodeProjectType) stri
 	if descriptor.MissingSharedSchemes {
 		qualifiers += "-missing-shared-schemes"
 	}
+	if descriptor.HasXcconfig {
+		qualifiers += "-xcconfig"
+	}
+	if descriptor.HasLocalPackages {
+		qualifiers += "-spm"
+	}
+	if descriptor.HasSimulatorArch {
+		qualifiers += "-simulator-arch"
+	}
+	if descriptor.HasVersionBump {
+		qualifiers += "-version-bump"
+	}
 	return fmt.Sprintf(configNameFormat, string(projectType), qualifiers)
 }
 
@@ -223,29 +247,48 @@ It is <a href="https://github.com/Carthage/Carthage/blob/master/Documentation/Ar
 	return carthageCommand, warning
 }
 
+func noTestSummary(projectPth string, schemes []string) string {
+	return fmt.Sprintf("%s: no test target found for scheme(s): %s, test workflow is not generated", projectPth, strings.Join(schemes, ", "))
+}
+
+// addExportMethodOption adds the export method option to the scheme option, with the given scheme value.
+// The optional development team option is placed in between, if the project is configured by xcconfig files.
+func addExportMethodOption(schemeOption *models.OptionNode, scheme string, exportMethodOption *models.OptionNode, hasXcconfig bool) {
+	if !hasXcconfig {
+		schemeOption.AddOption(scheme, exportMethodOption)
+		return
+	}
+
+	developmentTeamOption := models.NewUserInputOption(DevelopmentTeamInputTitle, DevelopmentTeamInputEnvKey, true)
+	schemeOption.AddOption(scheme, developmentTeamOption)
+	developmentTeamOption.AddOption("_", exportMethodOption)
+}
+
 // GenerateOptions ...
-func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.OptionNode, []ConfigDescriptor, models.Warnings, error) {
+// In offline mode the Podfiles are parsed without CocoaPods, as installing it requires network access.
+func GenerateOptions(projectType XcodeProjectType, searchDir string, isOffline bool) (models.OptionNode, []ConfigDescriptor, models.Summary, models.Warnings, error) {
 	warnings := models.Warnings{}
+	summary := models.Summary{}
 
 	fileList, err := utility.ListPathInDirSortedByComponents(searchDir, true)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
 	}
 
 	// Separate workspaces and standalon projects
 	projectFiles, err := FilterRelevantProjectFiles(fileList, projectType)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
 	}
 
 	workspaceFiles, err := FilterRelevantWorkspaceFiles(fileList, projectType)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
 	}
 
 	standaloneProjects, workspaces, err := CreateStandaloneProjectsAndWorkspaces(projectFiles, workspaceFiles)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
 	}
 
 	exportMethodInputTitle := ""
@@ -263,15 +306,26 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 
 	podfiles, err := FilterRelevantPodfiles(fileList)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
 	}
 
 	log.TPrintf("%d Podfiles detected", len(podfiles))
 
+	if isOffline && len(podfiles) > 0 {
+		warning := "Offline mode: the Podfiles are parsed without CocoaPods, only the top level project and workspace declarations are recognized"
+		warnings = append(warnings, warning)
+		log.TWarnf(warning)
+	}
+
 	for _, podfile := range podfiles {
 		log.TPrintf("- %s", podfile)
 
-		workspaceProjectMap, err := GetWorkspaceProjectMap(podfile, projectFiles)
+		getWorkspaceProjectMap := GetWorkspaceProjectMap
+		if isOffline {
+			getWorkspaceProjectMap = GetWorkspaceProjectMapOffline
+		}
+
+		workspaceProjectMap, err := getWorkspaceProjectMap(podfile, projectFiles)
 		if err != nil {
 			warning := fmt.Sprintf("Failed to determine cocoapods project-workspace mapping, error: %s", err)
 			warnings = append(warnings, warning)
@@ -296,7 +350,7 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 
 	cartfiles, err := FilterRelevantCartFile(fileList)
 	if err != nil {
-		return models.OptionNode{}, []ConfigDescriptor{}, models.Warnings{}, err
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
 	}
 
 	log.TPrintf("%d Cartfiles detected", len(cartfiles))
@@ -304,6 +358,56 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 		log.TPrintf("- %s", file)
 	}
 
+	// xcconfig
+	log.TInfof("Searching for xcconfig files")
+
+	xcconfigFiles, err := FilterRelevantXcconfigFiles(fileList)
+	if err != nil {
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
+	}
+
+	log.TPrintf("%d xcconfig files detected", len(xcconfigFiles))
+
+	// Local Swift packages
+	log.TInfof("Searching for local Swift packages")
+
+	workspaceToLocalPackages := map[string][]string{}
+	localPackages := []string{}
+	for _, workspace := range workspaces {
+		packages, err := WorkspaceLocalPackages(workspace.Pth)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to search for local Swift packages of workspace (%s), error: %s", workspace.Pth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+		if len(packages) == 0 {
+			continue
+		}
+
+		relPackages := []string{}
+		for _, pkg := range packages {
+			log.TPrintf("- %s", pkg)
+			relPackage, err := utility.RelPath(searchDir, pkg)
+			if err != nil {
+				return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
+			}
+			relPackages = append(relPackages, relPackage)
+		}
+		summary = append(summary, fmt.Sprintf("%s: local Swift packages: %s", workspace.Pth, strings.Join(relPackages, ", ")))
+
+		workspaceToLocalPackages[workspace.Pth] = packages
+		localPackages = append(localPackages, packages...)
+	}
+
+	standaloneProjects, packageProjects, err := filterLocalPackageProjects(standaloneProjects, localPackages)
+	if err != nil {
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, err
+	}
+	for _, project := range packageProjects {
+		log.TPrintf("Skipping the project of a local Swift package: %s", project.Pth)
+	}
+
 	// Create config descriptors & options
 	configDescriptors := []ConfigDescriptor{}
 
@@ -311,10 +415,23 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 
 	projectPathOption := models.NewOption(ProjectPathInputTitle, ProjectPathInputEnvKey)
 
+	// the projects and workspaces without any scheme are not offered, a build would fail with scheme not found
+	noSchemeCount := 0
+
 	// Standalon Projects
 	for _, project := range standaloneProjects {
 		log.TInfof("Inspecting standalone project file: %s", project.Pth)
 
+		if noScheme, err := hasNoScheme([]string{project.Pth}, project.SharedSchemes); err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, fmt.Errorf("Failed to search for user schemes, error: %s", err)
+		} else if noScheme {
+			warning := noSchemeWarning(project.Pth)
+			log.TErrorf(warning)
+			warnings = append(warnings, warning)
+			noSchemeCount++
+			continue
+		}
+
 		schemeOption := models.NewOption(SchemeInputTitle, SchemeInputEnvKey)
 		projectPathOption.AddOption(project.Pth, schemeOption)
 
@@ -323,6 +440,32 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 			warnings = append(warnings, warning)
 		}
 
+		hasXcconfig, xcconfigSummary, xcconfigWarnings := inspectXcconfigs([]string{project.Pth}, xcconfigFiles)
+		summary = append(summary, xcconfigSummary...)
+		warnings = append(warnings, xcconfigWarnings...)
+
+		minDeploymentTargets, deploymentTargetSummary, deploymentTargetWarnings := inspectDeploymentTargets(project.Pth, []string{project.Pth})
+		summary = append(summary, deploymentTargetSummary...)
+		warnings = append(warnings, deploymentTargetWarnings...)
+
+		languageSummary, languageWarnings := inspectTargetLanguages(project.Pth, []string{project.Pth})
+		summary = append(summary, languageSummary...)
+		warnings = append(warnings, languageWarnings...)
+
+		hasVersionSource, versionSummary, versionWarnings := inspectVersionSources(project.Pth, []string{project.Pth})
+		summary = append(summary, versionSummary...)
+		warnings = append(warnings, versionWarnings...)
+
+		simulatorArch := ""
+		if projectType == XcodeProjectTypeIOS {
+			arch, archSummary, archWarnings := inspectSimulatorArchs(project.Pth, []string{project.Pth})
+			simulatorArch = arch
+			summary = append(summary, archSummary...)
+			warnings = append(warnings, archWarnings...)
+		}
+
+		schemesWithoutTest := []string{}
+
 		log.TPrintf("%d shared schemes detected", len(project.SharedSchemes))
 
 		if len(project.SharedSchemes) == 0 {
@@ -334,39 +477,83 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 			for _, target := range project.Targets {
 
 				exportMethodOption := models.NewOption(exportMethodInputTitle, ExportMethodInputEnvKey)
-				schemeOption.AddOption(target.Name, exportMethodOption)
+				addExportMethodOption(schemeOption, target.Name, exportMethodOption, hasXcconfig)
+				if !target.HasXCTest {
+					schemesWithoutTest = append(schemesWithoutTest, target.Name)
+				}
 
 				for _, exportMethod := range exportMethods {
 					configDescriptor := NewConfigDescriptor(false, carthageCommand, target.HasXCTest, true)
+					configDescriptor.HasXcconfig = hasXcconfig
+					configDescriptor.HasSimulatorArch = configDescriptor.HasTest && simulatorArch != ""
+					configDescriptor.HasVersionBump = hasVersionSource
 					configDescriptors = append(configDescriptors, configDescriptor)
 
 					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType))
-					exportMethodOption.AddConfig(exportMethod, configOption)
+					addConfigOption(exportMethodOption, exportMethod, configOption, simulatorOSVersion(projectType, configDescriptor.HasTest, minDeploymentTargets), simulatorArch)
 				}
 			}
 		} else {
+			schemeToOption, appSummary, warning := schemeOptionsByApp(projectPathOption, project.Pth, []string{project.Pth})
+			if warning != "" {
+				log.TWarnf(warning)
+				warnings = append(warnings, warning)
+			}
+			if appSummary != "" {
+				summary = append(summary, appSummary)
+			}
+
 			for _, scheme := range project.SharedSchemes {
 				log.TPrintf("- %s", scheme.Name)
 
 				exportMethodOption := models.NewOption(exportMethodInputTitle, ExportMethodInputEnvKey)
-				schemeOption.AddOption(scheme.Name, exportMethodOption)
+				addExportMethodOption(schemeOptionOf(schemeOption, schemeToOption, project.Pth, scheme.Name), scheme.Name, exportMethodOption, hasXcconfig)
+				if !scheme.HasXCTest {
+					schemesWithoutTest = append(schemesWithoutTest, scheme.Name)
+				}
 
 				for _, exportMethod := range exportMethods {
 					configDescriptor := NewConfigDescriptor(false, carthageCommand, scheme.HasXCTest, false)
+					configDescriptor.HasXcconfig = hasXcconfig
+					configDescriptor.HasSimulatorArch = configDescriptor.HasTest && simulatorArch != ""
+					configDescriptor.HasVersionBump = hasVersionSource
 					configDescriptors = append(configDescriptors, configDescriptor)
 
 					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType))
-					exportMethodOption.AddConfig(exportMethod, configOption)
+					addConfigOption(exportMethodOption, exportMethod, configOption, simulatorOSVersion(projectType, configDescriptor.HasTest, minDeploymentTargets), simulatorArch)
 
 				}
 			}
 		}
+
+		if hasVersionSource {
+			version.AddBumpOption(projectPathOption.ChildOptionMap[project.Pth])
+		}
+
+		if len(schemesWithoutTest) > 0 {
+			summary = append(summary, noTestSummary(project.Pth, schemesWithoutTest))
+		}
 	}
 
 	// Workspaces
 	for _, workspace := range workspaces {
 		log.TInfof("Inspecting workspace file: %s", workspace.Pth)
 
+		workspaceProjectPths := []string{}
+		for _, project := range workspace.Projects {
+			workspaceProjectPths = append(workspaceProjectPths, project.Pth)
+		}
+
+		if noScheme, err := hasNoScheme(append([]string{workspace.Pth}, workspaceProjectPths...), workspace.GetSharedSchemes()); err != nil {
+			return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, models.Warnings{}, fmt.Errorf("Failed to search for user schemes, error: %s", err)
+		} else if noScheme {
+			warning := noSchemeWarning(workspace.Pth)
+			log.TErrorf(warning)
+			warnings = append(warnings, warning)
+			noSchemeCount++
+			continue
+		}
+
 		schemeOption := models.NewOption(SchemeInputTitle, SchemeInputEnvKey)
 		projectPathOption.AddOption(workspace.Pth, schemeOption)
 
@@ -375,6 +562,34 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 			warnings = append(warnings, warning)
 		}
 
+		hasXcconfig, xcconfigSummary, xcconfigWarnings := inspectXcconfigs(workspaceProjectPths, xcconfigFiles)
+		summary = append(summary, xcconfigSummary...)
+		warnings = append(warnings, xcconfigWarnings...)
+
+		minDeploymentTargets, deploymentTargetSummary, deploymentTargetWarnings := inspectDeploymentTargets(workspace.Pth, workspaceProjectPths)
+		summary = append(summary, deploymentTargetSummary...)
+		warnings = append(warnings, deploymentTargetWarnings...)
+
+		languageSummary, languageWarnings := inspectTargetLanguages(workspace.Pth, workspaceProjectPths)
+		summary = append(summary, languageSummary...)
+		warnings = append(warnings, languageWarnings...)
+
+		hasVersionSource, versionSummary, versionWarnings := inspectVersionSources(workspace.Pth, workspaceProjectPths)
+		summary = append(summary, versionSummary...)
+		warnings = append(warnings, versionWarnings...)
+
+		simulatorArch := ""
+		if projectType == XcodeProjectTypeIOS {
+			arch, archSummary, archWarnings := inspectSimulatorArchs(workspace.Pth, workspaceProjectPths)
+			simulatorArch = arch
+			summary = append(summary, archSummary...)
+			warnings = append(warnings, archWarnings...)
+		}
+
+		hasLocalPackages := len(workspaceToLocalPackages[workspace.Pth]) > 0
+
+		schemesWithoutTest := []string{}
+
 		sharedSchemes := workspace.GetSharedSchemes()
 		log.TPrintf("%d shared schemes detected", len(sharedSchemes))
 
@@ -388,42 +603,80 @@ func GenerateOptions(projectType XcodeProjectType, searchDir string) (models.Opt
 
 			for _, target := range targets {
 				exportMethodOption := models.NewOption(exportMethodInputTitle, ExportMethodInputEnvKey)
-				schemeOption.AddOption(target.Name, exportMethodOption)
+				addExportMethodOption(schemeOption, target.Name, exportMethodOption, hasXcconfig)
+				if !target.HasXCTest {
+					schemesWithoutTest = append(schemesWithoutTest, target.Name)
+				}
 
 				for _, exportMethod := range exportMethods {
 					configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, target.HasXCTest, true)
+					configDescriptor.HasXcconfig = hasXcconfig
+					configDescriptor.HasSimulatorArch = configDescriptor.HasTest && simulatorArch != ""
+					configDescriptor.HasVersionBump = hasVersionSource
+					configDescriptor.HasLocalPackages = hasLocalPackages
 					configDescriptors = append(configDescriptors, configDescriptor)
 
 					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType))
-					exportMethodOption.AddConfig(exportMethod, configOption)
+					addConfigOption(exportMethodOption, exportMethod, configOption, simulatorOSVersion(projectType, configDescriptor.HasTest, minDeploymentTargets), simulatorArch)
 				}
 			}
 		} else {
-			for _, scheme := range sharedSchemes {
-				log.TPrintf("- %s", scheme.Name)
-
-				exportMethodOption := models.NewOption(exportMethodInputTitle, ExportMethodInputEnvKey)
-				schemeOption.AddOption(scheme.Name, exportMethodOption)
-
-				for _, exportMethod := range exportMethods {
-					configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, scheme.HasXCTest, false)
-					configDescriptors = append(configDescriptors, configDescriptor)
+			schemeToOption, appSummary, warning := schemeOptionsByApp(projectPathOption, workspace.Pth, workspaceProjectPths)
+			if warning != "" {
+				log.TWarnf(warning)
+				warnings = append(warnings, warning)
+			}
+			if appSummary != "" {
+				summary = append(summary, appSummary)
+			}
 
-					configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType))
-					exportMethodOption.AddConfig(exportMethod, configOption)
+			for _, project := range workspace.Projects {
+				for _, scheme := range project.SharedSchemes {
+					log.TPrintf("- %s", scheme.Name)
+
+					exportMethodOption := models.NewOption(exportMethodInputTitle, ExportMethodInputEnvKey)
+					addExportMethodOption(schemeOptionOf(schemeOption, schemeToOption, project.Pth, scheme.Name), scheme.Name, exportMethodOption, hasXcconfig)
+					if !scheme.HasXCTest {
+						schemesWithoutTest = append(schemesWithoutTest, scheme.Name)
+					}
+
+					for _, exportMethod := range exportMethods {
+						configDescriptor := NewConfigDescriptor(workspace.IsPodWorkspace, carthageCommand, scheme.HasXCTest, false)
+						configDescriptor.HasXcconfig = hasXcconfig
+						configDescriptor.HasSimulatorArch = configDescriptor.HasTest && simulatorArch != ""
+						configDescriptor.HasVersionBump = hasVersionSource
+						configDescriptor.HasLocalPackages = hasLocalPackages
+						configDescriptors = append(configDescriptors, configDescriptor)
+
+						configOption := models.NewConfigOption(configDescriptor.ConfigName(projectType))
+						addConfigOption(exportMethodOption, exportMethod, configOption, simulatorOSVersion(projectType, configDescriptor.HasTest, minDeploymentTargets), simulatorArch)
+					}
 				}
 			}
 		}
+
+		if hasVersionSource {
+			version.AddBumpOption(projectPathOption.ChildOptionMap[workspace.Pth])
+		}
+
+		if len(schemesWithoutTest) > 0 {
+			summary = append(summary, noTestSummary(workspace.Pth, schemesWithoutTest))
+		}
 	}
 
 	configDescriptors = RemoveDuplicatedConfigDescriptors(configDescriptors, projectType)
 
+	if noSchemeCount > 0 && noSchemeCount == len(standaloneProjects)+len(workspaces) {
+		log.TErrorf("No %s project or workspace with a scheme found", string(projectType))
+		return models.OptionNode{}, []ConfigDescriptor{}, summary, warnings, fmt.Errorf("No %s project or workspace with a scheme found", string(projectType))
+	}
+
 	if len(configDescriptors) == 0 {
 		log.TErrorf("No valid %s config found", string(projectType))
-		return models.OptionNode{}, []ConfigDescriptor{}, warnings, fmt.Errorf("No valid %s config found", string(projectType))
+		return models.OptionNode{}, []ConfigDescriptor{}, models.Summary{}, warnings, fmt.Errorf("No valid %s config found", string(projectType))
 	}
 
-	return *projectPathOption, configDescriptors, warnings, nil
+	return *projectPathOption, configDescriptors, summary, warnings, nil
 }
 
 // GenerateDefaultOptions ...
@@ -455,7 +708,13 @@ func GenerateDefaultOptions(projectType XcodeProjectType) models.OptionNode {
 }
 
 // GenerateConfigBuilder ...
-func GenerateConfigBuilder(projectType XcodeProjectType, hasPodfile, hasTest, missingSharedSchemes bool, carthageCommand string, isIncludeCache bool) models.ConfigBuilderModel {
+func GenerateConfigBuilder(projectType XcodeProjectType, descriptor ConfigDescriptor, isIncludeCache bool) models.ConfigBuilderModel {
+	hasPodfile := descriptor.HasPodfile
+	hasLocalPackages := descriptor.HasLocalPackages
+	hasTest := descriptor.HasTest
+	missingSharedSchemes := descriptor.MissingSharedSchemes
+	carthageCommand := descriptor.CarthageCommand
+
 	configBuilder := models.NewDefaultConfigBuilder()
 
 	// CI
@@ -478,26 +737,45 @@ func GenerateConfigBuilder(projectType XcodeProjectType, hasPodfile, hasTest, mi
 		))
 	}
 
+	if hasLocalPackages {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, resolvePackagesStepListItem())
+	}
+
 	xcodeStepInputModels := []envmanModels.EnvironmentItemModel{
 		envmanModels.EnvironmentItemModel{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
 		envmanModels.EnvironmentItemModel{SchemeInputKey: "$" + SchemeInputEnvKey},
 	}
+	xcodeTestStepInputModels := append([]envmanModels.EnvironmentItemModel{}, xcodeStepInputModels...)
+	if projectType == XcodeProjectTypeIOS {
+		xcodeTestStepInputModels = append(xcodeTestStepInputModels, envmanModels.EnvironmentItemModel{SimulatorOSVersionInputKey: "$" + SimulatorOSVersionInputEnvKey})
+		if descriptor.HasSimulatorArch {
+			xcodeTestStepInputModels = append(xcodeTestStepInputModels, envmanModels.EnvironmentItemModel{XcodebuildTestOptionsInputKey: fmt.Sprintf("ARCHS=$%s %s=NO", SimulatorArchInputEnvKey, OnlyActiveArchKey)})
+		}
+	}
 	xcodeArchiveStepInputModels := append(xcodeStepInputModels, envmanModels.EnvironmentItemModel{ExportMethodInputKey: "$" + ExportMethodInputEnvKey})
+	if descriptor.HasXcconfig {
+		xcodeArchiveStepInputModels = append(xcodeArchiveStepInputModels, envmanModels.EnvironmentItemModel{DevelopmentTeamInputKey: "$" + DevelopmentTeamInputEnvKey})
+	}
 
 	if hasTest {
 		switch projectType {
 		case XcodeProjectTypeIOS:
-			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeTestStepListItem(xcodeStepInputModels...))
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeTestStepListItem(xcodeTestStepInputModels...))
 		case XcodeProjectTypeMacOS:
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeTestMacStepListItem(xcodeStepInputModels...))
 		}
 	} else {
+		if descriptor.HasVersionBump {
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, version.XcodeStepList(ProjectPathInputEnvKey, SchemeInputEnvKey)...)
+		}
+
 		switch projectType {
 		case XcodeProjectTypeIOS:
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeArchiveStepListItem(xcodeArchiveStepInputModels...))
 		case XcodeProjectTypeMacOS:
 			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XcodeArchiveMacStepListItem(xcodeArchiveStepInputModels...))
 		}
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.ArtifactDeployStepList([]string{XcarchivePath}, nil)...)
 	}
 
 	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(isIncludeCache)...)
@@ -523,21 +801,62 @@ func GenerateConfigBuilder(projectType XcodeProjectType, hasPodfile, hasTest, mi
 			))
 		}
 
+		if hasLocalPackages {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, resolvePackagesStepListItem())
+		}
+
 		switch projectType {
 		case XcodeProjectTypeIOS:
-			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeTestStepListItem(xcodeStepInputModels...))
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeTestStepListItem(xcodeTestStepInputModels...))
 		case XcodeProjectTypeMacOS:
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeTestMacStepListItem(xcodeStepInputModels...))
 		}
 
+		if descriptor.HasVersionBump {
+			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, version.XcodeStepList(ProjectPathInputEnvKey, SchemeInputEnvKey)...)
+		}
+
 		switch projectType {
 		case XcodeProjectTypeIOS:
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveStepListItem(xcodeArchiveStepInputModels...))
 		case XcodeProjectTypeMacOS:
 			configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.XcodeArchiveMacStepListItem(xcodeArchiveStepInputModels...))
 		}
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.ArtifactDeployStepList([]string{XcarchivePath}, nil)...)
 
 		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(isIncludeCache)...)
+
+		// Test
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultPrepareStepList(isIncludeCache)...)
+
+		if missingSharedSchemes {
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.RecreateUserSchemesStepListItem(
+				envmanModels.EnvironmentItemModel{ProjectPathInputKey: "$" + ProjectPathInputEnvKey},
+			))
+		}
+
+		if hasPodfile {
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.CocoapodsInstallStepListItem())
+		}
+
+		if carthageCommand != "" {
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.CarthageStepListItem(
+				envmanModels.EnvironmentItemModel{CarthageCommandInputKey: carthageCommand},
+			))
+		}
+
+		if hasLocalPackages {
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, resolvePackagesStepListItem())
+		}
+
+		switch projectType {
+		case XcodeProjectTypeIOS:
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.XcodeTestStepListItem(xcodeTestStepInputModels...))
+		case XcodeProjectTypeMacOS:
+			configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.XcodeTestMacStepListItem(xcodeStepInputModels...))
+		}
+
+		configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultDeployStepList(isIncludeCache)...)
 	}
 
 	return *configBuilder
@@ -563,7 +882,7 @@ func RemoveDuplicatedConfigDescriptors(configDescriptors []ConfigDescriptor, pro
 func GenerateConfig(projectType XcodeProjectType, configDescriptors []ConfigDescriptor, isIncludeCache bool) (models.BitriseConfigMap, error) {
 	bitriseDataMap := models.BitriseConfigMap{}
 	for _, descriptor := range configDescriptors {
-		configBuilder := GenerateConfigBuilder(projectType, descriptor.HasPodfile, descriptor.HasTest, descriptor.MissingSharedSchemes, descriptor.CarthageCommand, isIncludeCache)
+		configBuilder := GenerateConfigBuilder(projectType, descriptor, isIncludeCache)
 
 		config, err := configBuilder.Generate(string(projectType))
 		if err != nil {
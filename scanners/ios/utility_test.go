@@ -3,6 +3,11 @@ package ios
 import (
 	"testing"
 
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-core/bitrise-init/testhelper"
 	"github.com/stretchr/testify/require"
 )
 
@@ -54,4 +59,61 @@ func TestConfigName(t *testing.T) {
 		descriptor := NewConfigDescriptor(true, "bootstrap", true, true)
 		require.Equal(t, "ios-pod-carthage-test-missing-shared-schemes-config", descriptor.ConfigName(XcodeProjectTypeIOS))
 	}
+
+	{
+		descriptor := NewConfigDescriptor(true, "", false, false)
+		descriptor.HasXcconfig = true
+		require.Equal(t, "ios-pod-xcconfig-config", descriptor.ConfigName(XcodeProjectTypeIOS))
+	}
+}
+
+func TestGenerateConfigBuilderTestWorkflow(t *testing.T) {
+	t.Log("test workflow generated for schemes with tests")
+	{
+		descriptor := NewConfigDescriptor(true, "", true, false)
+		configBuilder := GenerateConfigBuilder(XcodeProjectTypeIOS, descriptor, true)
+
+		config, err := configBuilder.Generate(string(XcodeProjectTypeIOS))
+		require.NoError(t, err)
+		require.Contains(t, config.Workflows, string(models.TestWorkflowID))
+		require.Equal(t, string(models.TestWorkflowID), config.TriggerMap[1].WorkflowID)
+	}
+
+	t.Log("no test workflow for schemes without tests")
+	{
+		descriptor := NewConfigDescriptor(true, "", false, false)
+		configBuilder := GenerateConfigBuilder(XcodeProjectTypeIOS, descriptor, true)
+
+		config, err := configBuilder.Generate(string(XcodeProjectTypeIOS))
+		require.NoError(t, err)
+		require.NotContains(t, config.Workflows, string(models.TestWorkflowID))
+		require.Equal(t, string(models.PrimaryWorkflowID), config.TriggerMap[1].WorkflowID)
+	}
+}
+
+func TestGenerateConfigBuilderArtifactDeploy(t *testing.T) {
+	deployPaths := func(descriptor ConfigDescriptor, workflowID models.WorkflowID) []string {
+		configBuilder := GenerateConfigBuilder(XcodeProjectTypeIOS, descriptor, true)
+		config, err := configBuilder.Generate(string(XcodeProjectTypeIOS))
+		require.NoError(t, err)
+		data, err := yaml.Marshal(config)
+		require.NoError(t, err)
+
+		paths, err := testhelper.StepInputValues(string(data), string(workflowID), steps.DeployToBitriseIoID, steps.DeployToBitriseIoDeployPathInputKey)
+		require.NoError(t, err)
+		return paths
+	}
+
+	t.Log("the archive is deployed by the deploy workflow, if the scheme has tests")
+	{
+		descriptor := NewConfigDescriptor(false, "", true, false)
+		require.Equal(t, []string{"$BITRISE_XCARCHIVE_PATH"}, deployPaths(descriptor, models.DeployWorkflowID))
+		require.Equal(t, []string{}, deployPaths(descriptor, models.PrimaryWorkflowID))
+	}
+
+	t.Log("the archive is deployed by the primary workflow, if the scheme has no tests")
+	{
+		descriptor := NewConfigDescriptor(false, "", false, false)
+		require.Equal(t, []string{"$BITRISE_XCARCHIVE_PATH"}, deployPaths(descriptor, models.PrimaryWorkflowID))
+	}
 }
@@ -0,0 +1,182 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/version"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-tools/xcode-project/serialized"
+	projectXcodeproj "github.com/bitrise-tools/xcode-project/xcodeproj"
+	"howett.net/plist"
+)
+
+const (
+	// MarketingVersionKey ...
+	MarketingVersionKey = "MARKETING_VERSION"
+	// CurrentProjectVersionKey ...
+	CurrentProjectVersionKey = "CURRENT_PROJECT_VERSION"
+
+	infoPlistFileKey            = "INFOPLIST_FILE"
+	bundleShortVersionStringKey = "CFBundleShortVersionString"
+	bundleVersionKey            = "CFBundleVersion"
+)
+
+// versionBuildConfigurationName is the build configuration whose version is reported, if the target has one.
+const versionBuildConfigurationName = "Release"
+
+// isBuildSettingReference returns true if the value refers to a build setting, like $(MARKETING_VERSION) or ${CURRENT_PROJECT_VERSION}.
+func isBuildSettingReference(value string) bool {
+	return strings.Contains(value, "$(") || strings.Contains(value, "${")
+}
+
+// infoPlistVersions returns the version and build number declared by the Info.plist file.
+func infoPlistVersions(infoPlistPth string) (string, string, error) {
+	b, err := fileutil.ReadBytesFromFile(infoPlistPth)
+	if err != nil {
+		return "", "", err
+	}
+
+	var infoPlist map[string]interface{}
+	if _, err := plist.Unmarshal(b, &infoPlist); err != nil {
+		return "", "", err
+	}
+
+	shortVersion, _ := infoPlist[bundleShortVersionStringKey].(string)
+	bundleVersion, _ := infoPlist[bundleVersionKey].(string)
+	return shortVersion, bundleVersion, nil
+}
+
+// ProjectVersionSources returns where the versions of the project's app targets are declared:
+// the Info.plist's CFBundleShortVersionString and CFBundleVersion, or the MARKETING_VERSION and CURRENT_PROJECT_VERSION build settings,
+// if the Info.plist refers to them (or the Info.plist is generated).
+// The app targets whose version is declared outside of the project (like in an xcconfig file) are returned as unresolved.
+func ProjectVersionSources(projectPth string) ([]version.Source, []string, error) {
+	if exist, err := pathutil.IsPathExists(filepath.Join(projectPth, "project.pbxproj")); err != nil {
+		return nil, nil, err
+	} else if !exist {
+		return []version.Source{}, []string{}, nil
+	}
+
+	project, err := projectXcodeproj.Open(projectPth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	projectSettings := map[string]serialized.Object{}
+	for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+		projectSettings[buildConfiguration.Name] = buildConfiguration.BuildSettings
+	}
+
+	sources := []version.Source{}
+	unresolved := []string{}
+	for _, target := range project.Proj.Targets {
+		if target.Type != projectXcodeproj.NativeTargetType || !target.IsAppProduct() {
+			continue
+		}
+
+		buildConfigurations := target.BuildConfigurationList.BuildConfigurations
+		if len(buildConfigurations) == 0 {
+			continue
+		}
+		buildConfiguration := buildConfigurations[0]
+		for _, configuration := range buildConfigurations {
+			if configuration.Name == versionBuildConfigurationName {
+				buildConfiguration = configuration
+				break
+			}
+		}
+
+		setting := func(key string) string {
+			if value, ok := buildSetting(buildConfiguration.BuildSettings, key); ok {
+				return value
+			}
+			value, _ := buildSetting(projectSettings[buildConfiguration.Name], key)
+			return value
+		}
+
+		plistVersion, plistBuildNumber := "", ""
+		if infoPlistFile := setting(infoPlistFileKey); infoPlistFile != "" {
+			infoPlistFile = strings.NewReplacer("$(SRCROOT)/", "", "${SRCROOT}/", "").Replace(infoPlistFile)
+			infoPlistPth := filepath.Join(filepath.Dir(projectPth), infoPlistFile)
+			if exist, err := pathutil.IsPathExists(infoPlistPth); err != nil {
+				return nil, nil, err
+			} else if exist {
+				if plistVersion, plistBuildNumber, err = infoPlistVersions(infoPlistPth); err != nil {
+					return nil, nil, fmt.Errorf("failed to read the versions of %s, error: %s", infoPlistPth, err)
+				}
+			}
+
+			if plistVersion != "" && !isBuildSettingReference(plistVersion) {
+				sources = append(sources, version.Source{
+					App:            target.Name,
+					File:           infoPlistPth,
+					VersionKey:     bundleShortVersionStringKey,
+					Version:        plistVersion,
+					BuildNumberKey: bundleVersionKey,
+					BuildNumber:    plistBuildNumber,
+				})
+				continue
+			}
+		}
+
+		marketingVersion, currentProjectVersion := setting(MarketingVersionKey), setting(CurrentProjectVersionKey)
+		if marketingVersion == "" || isBuildSettingReference(marketingVersion) {
+			unresolved = append(unresolved, target.Name)
+			continue
+		}
+		if isBuildSettingReference(currentProjectVersion) {
+			currentProjectVersion = ""
+		}
+
+		sources = append(sources, version.Source{
+			App:            target.Name,
+			File:           projectPth,
+			VersionKey:     MarketingVersionKey,
+			Version:        marketingVersion,
+			BuildNumberKey: CurrentProjectVersionKey,
+			BuildNumber:    currentProjectVersion,
+		})
+	}
+
+	return sources, unresolved, nil
+}
+
+// inspectVersionSources collects the version sources of the given projects, belonging to the given container,
+// returns if any app's version source is found, so the build number incrementing can be offered.
+func inspectVersionSources(containerPth string, projectPths []string) (bool, models.Summary, models.Warnings) {
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+
+	sources := []version.Source{}
+	for _, projectPth := range projectPths {
+		projectSources, unresolved, err := ProjectVersionSources(projectPth)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to read the version sources of project (%s), error: %s", projectPth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+		sources = append(sources, projectSources...)
+
+		if len(unresolved) > 0 {
+			warning := fmt.Sprintf("The version of %s (%s) is not declared in the project or the Info.plist, it probably comes from an xcconfig or a generated file", projectPth, strings.Join(unresolved, ", "))
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+		}
+	}
+
+	if len(sources) == 0 {
+		return false, summary, warnings
+	}
+
+	versionSummary := version.Summary(containerPth, sources)
+	log.TPrintf(versionSummary)
+	summary = append(summary, versionSummary)
+
+	return true, summary, warnings
+}
@@ -0,0 +1,93 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+// testVersionPbxprojContent is the multi app project, with the Shop app's version declared by build settings
+// and the Admin app's version declared by its Info.plist.
+var testVersionPbxprojContent = strings.NewReplacer(
+	"PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.shop;",
+	"CURRENT_PROJECT_VERSION = 42;\n\t\t\t\tINFOPLIST_FILE = Shop/Info.plist;\n\t\t\t\tMARKETING_VERSION = 1.2.0;\n\t\t\t\tPRODUCT_BUNDLE_IDENTIFIER = io.bitrise.shop;",
+	"PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.admin;",
+	"INFOPLIST_FILE = \"$(SRCROOT)/Admin/Info.plist\";\n\t\t\t\tPRODUCT_BUNDLE_IDENTIFIER = io.bitrise.admin;",
+).Replace(testMultiAppPbxprojContent)
+
+func testVersionInfoPlistContent(shortVersion, bundleVersion string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleShortVersionString</key>
+	<string>` + shortVersion + `</string>
+	<key>CFBundleVersion</key>
+	<string>` + bundleVersion + `</string>
+</dict>
+</plist>
+`
+}
+
+func TestIsBuildSettingReference(t *testing.T) {
+	require.True(t, isBuildSettingReference("$(MARKETING_VERSION)"))
+	require.True(t, isBuildSettingReference("${CURRENT_PROJECT_VERSION}"))
+	require.False(t, isBuildSettingReference("1.2.0"))
+}
+
+func TestProjectVersionSources(t *testing.T) {
+	tmpDir, cleanup := testhelper.TempDir(t, "version")
+	defer cleanup()
+
+	t.Log("versions declared by build settings and the Info.plist")
+	{
+		testhelper.WriteFile(t, tmpDir, "Version.xcodeproj/project.pbxproj", testVersionPbxprojContent)
+		testhelper.WriteFile(t, tmpDir, "Shop/Info.plist", testVersionInfoPlistContent("$(MARKETING_VERSION)", "$(CURRENT_PROJECT_VERSION)"))
+		testhelper.WriteFile(t, tmpDir, "Admin/Info.plist", testVersionInfoPlistContent("2.0", "7"))
+
+		projectPth := filepath.Join(tmpDir, "Version.xcodeproj")
+		sources, unresolved, err := ProjectVersionSources(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, []string{}, unresolved)
+		require.Equal(t, 2, len(sources))
+		require.Equal(t, "Shop: 1.2.0 (42) from MARKETING_VERSION and CURRENT_PROJECT_VERSION of "+projectPth, sources[0].String())
+		require.Equal(t, "Admin: 2.0 (7) from CFBundleShortVersionString and CFBundleVersion of "+filepath.Join(tmpDir, "Admin", "Info.plist"), sources[1].String())
+
+		hasVersionSource, summary, warnings := inspectVersionSources("Version.xcodeproj", []string{projectPth})
+		require.True(t, hasVersionSource)
+		require.Equal(t, 1, len(summary))
+		require.True(t, strings.HasPrefix(summary[0], "Version.xcodeproj: version sources: Shop: 1.2.0 (42)"), summary[0])
+		require.Equal(t, models.Warnings{}, warnings)
+	}
+
+	t.Log("versions declared outside of the project")
+	{
+		testhelper.WriteFile(t, tmpDir, "Unresolved.xcodeproj/project.pbxproj", testMultiAppPbxprojContent)
+
+		projectPth := filepath.Join(tmpDir, "Unresolved.xcodeproj")
+		sources, unresolved, err := ProjectVersionSources(projectPth)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(sources))
+		require.Equal(t, []string{"Shop", "Admin"}, unresolved)
+
+		hasVersionSource, summary, warnings := inspectVersionSources("Unresolved.xcodeproj", []string{projectPth})
+		require.False(t, hasVersionSource)
+		require.Equal(t, models.Summary{}, summary)
+		require.Equal(t, models.Warnings{"The version of " + projectPth + " (Shop, Admin) is not declared in the project or the Info.plist, it probably comes from an xcconfig or a generated file"}, warnings)
+	}
+
+	t.Log("project without pbxproj")
+	{
+		require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "Empty.xcodeproj"), 0700))
+
+		sources, unresolved, err := ProjectVersionSources(filepath.Join(tmpDir, "Empty.xcodeproj"))
+		require.NoError(t, err)
+		require.Equal(t, 0, len(sources))
+		require.Equal(t, 0, len(unresolved))
+	}
+}
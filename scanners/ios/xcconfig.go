@@ -0,0 +1,194 @@
+package ios
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const xcconfigExt = ".xcconfig"
+
+const (
+	// ProductBundleIdentifierKey ...
+	ProductBundleIdentifierKey = "PRODUCT_BUNDLE_IDENTIFIER"
+	// DevelopmentTeamKey ...
+	DevelopmentTeamKey = "DEVELOPMENT_TEAM"
+)
+
+const (
+	// DevelopmentTeamInputKey ...
+	DevelopmentTeamInputKey = "force_team_id"
+	// DevelopmentTeamInputEnvKey ...
+	DevelopmentTeamInputEnvKey = "DEVELOPMENT_TEAM"
+	// DevelopmentTeamInputTitle ...
+	DevelopmentTeamInputTitle = "Development Team (leave empty to use the team set in the project)"
+)
+
+// xcconfigHintKeys are the build settings surfaced in the scanner summary.
+var xcconfigHintKeys = []string{ProductBundleIdentifierKey, DevelopmentTeamKey}
+
+var (
+	xcconfigIncludeRegexp = regexp.MustCompile(`^#include(\?)?\s+"(.+)"`)
+	xcconfigSettingRegexp = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*?)\s*;?$`)
+)
+
+// AllowXcconfigExtFilter ...
+var AllowXcconfigExtFilter = utility.ExtensionFilter(xcconfigExt, true)
+
+// FilterRelevantXcconfigFiles ...
+func FilterRelevantXcconfigFiles(fileList []string) ([]string, error) {
+	return utility.FilterPaths(fileList,
+		AllowXcconfigExtFilter,
+		ForbidGitDirComponentFilter,
+		ForbidPodsDirComponentFilter,
+		ForbidCarthageDirComponentFilter,
+		ForbidFramworkComponentWithExtensionFilter,
+		ForbidCordovaLibDirComponentFilter,
+		ForbidNodeModulesComponentFilter)
+}
+
+// ProjectXcconfigFiles returns the xcconfig files, placed in the project's directory (or in its subdirectories),
+// which are referenced by the project.
+func ProjectXcconfigFiles(projectPth string, xcconfigFiles []string) ([]string, error) {
+	pbxprojPth := filepath.Join(projectPth, "project.pbxproj")
+	if exist, err := pathutil.IsPathExists(pbxprojPth); err != nil {
+		return nil, err
+	} else if !exist {
+		return []string{}, nil
+	}
+
+	projectDir := filepath.Dir(projectPth)
+
+	referenced := []string{}
+	for _, xcconfigFile := range xcconfigFiles {
+		if projectDir != "." && !strings.HasPrefix(xcconfigFile, projectDir+string(filepath.Separator)) {
+			continue
+		}
+
+		contains, err := utility.FileContains(pbxprojPth, filepath.Base(xcconfigFile))
+		if err != nil {
+			return nil, err
+		}
+		if contains {
+			referenced = append(referenced, xcconfigFile)
+		}
+	}
+	return referenced, nil
+}
+
+// ParseXcconfig returns the unconditional build settings defined in the given xcconfig file.
+// The #include-d xcconfig files are resolved recursively, the settings of the including file override the included ones.
+func ParseXcconfig(pth string) (map[string]string, error) {
+	return parseXcconfig(pth, map[string]bool{})
+}
+
+func parseXcconfig(pth string, inProgress map[string]bool) (map[string]string, error) {
+	absPth, err := pathutil.AbsPath(pth)
+	if err != nil {
+		return nil, err
+	}
+	if inProgress[absPth] {
+		return nil, fmt.Errorf("include cycle detected at: %s", pth)
+	}
+	inProgress[absPth] = true
+	defer delete(inProgress, absPth)
+
+	content, err := fileutil.ReadStringFromFile(pth)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := xcconfigIncludeRegexp.FindStringSubmatch(line); len(match) == 3 {
+			isOptional := match[1] == "?"
+			includePth := match[2]
+			if !filepath.IsAbs(includePth) {
+				includePth = filepath.Join(filepath.Dir(pth), includePth)
+			}
+
+			if exist, err := pathutil.IsPathExists(includePth); err != nil {
+				return nil, err
+			} else if !exist {
+				if isOptional {
+					continue
+				}
+				return nil, fmt.Errorf("included xcconfig (%s) does not exist, included by: %s", includePth, pth)
+			}
+
+			includedSettings, err := parseXcconfig(includePth, inProgress)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range includedSettings {
+				settings[key] = value
+			}
+			continue
+		}
+
+		if idx := strings.Index(line, "//"); idx != -1 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		// conditional settings (like KEY[sdk=iphoneos*] = value) are not matched
+		if match := xcconfigSettingRegexp.FindStringSubmatch(line); len(match) == 3 {
+			settings[match[1]] = match[2]
+		}
+	}
+
+	return settings, nil
+}
+
+// inspectXcconfigs collects the xcconfig driven build setting hints of the given projects,
+// returns if any of the projects references an xcconfig file.
+func inspectXcconfigs(projectPths []string, xcconfigFiles []string) (bool, models.Summary, models.Warnings) {
+	hasXcconfig := false
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+
+	for _, projectPth := range projectPths {
+		projectXcconfigFiles, err := ProjectXcconfigFiles(projectPth, xcconfigFiles)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to search for xcconfig files of project (%s), error: %s", projectPth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+		if len(projectXcconfigFiles) == 0 {
+			continue
+		}
+
+		hasXcconfig = true
+
+		sort.Strings(projectXcconfigFiles)
+		for _, xcconfigFile := range projectXcconfigFiles {
+			log.TPrintf("- %s", xcconfigFile)
+
+			settings, err := ParseXcconfig(xcconfigFile)
+			if err != nil {
+				warning := fmt.Sprintf("Failed to parse xcconfig file (%s), error: %s", xcconfigFile, err)
+				warnings = append(warnings, warning)
+				log.TWarnf(warning)
+				continue
+			}
+
+			for _, key := range xcconfigHintKeys {
+				if value, ok := settings[key]; ok && value != "" {
+					summary = append(summary, fmt.Sprintf("%s: %s = %s", xcconfigFile, key, value))
+				}
+			}
+		}
+	}
+
+	return hasXcconfig, summary, warnings
+}
@@ -0,0 +1,97 @@
+package ios
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+const baseXcconfigContent = `// Base settings
+PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.base
+DEVELOPMENT_TEAM = 72SA8V3WYL
+SWIFT_VERSION = 4.0
+`
+
+const appXcconfigContent = `#include "Base.xcconfig"
+#include? "Local.xcconfig"
+
+PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.app // overrides the base bundle id
+CODE_SIGN_IDENTITY[sdk=iphoneos*] = iPhone Developer
+`
+
+const xcconfigPbxprojContent = `// !$*UTF8*$!
+{
+	objects = {
+		13C2FD1A1F34A1D200C2A4C3 /* App.xcconfig */ = {isa = PBXFileReference; lastKnownFileType = text.xcconfig; path = App.xcconfig; sourceTree = "<group>"; };
+	};
+}
+`
+
+func TestParseXcconfig(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__xcconfig__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	basePth := filepath.Join(tmpDir, "Config", "Base.xcconfig")
+	appPth := filepath.Join(tmpDir, "Config", "App.xcconfig")
+	require.NoError(t, os.MkdirAll(filepath.Dir(basePth), 0700))
+	require.NoError(t, fileutil.WriteStringToFile(basePth, baseXcconfigContent))
+	require.NoError(t, fileutil.WriteStringToFile(appPth, appXcconfigContent))
+
+	t.Log("included settings are overridden by the including xcconfig")
+	{
+		settings, err := ParseXcconfig(appPth)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"PRODUCT_BUNDLE_IDENTIFIER": "io.bitrise.app",
+			"DEVELOPMENT_TEAM":          "72SA8V3WYL",
+			"SWIFT_VERSION":             "4.0",
+		}, settings)
+	}
+
+	t.Log("missing required include")
+	{
+		pth := filepath.Join(tmpDir, "Missing.xcconfig")
+		require.NoError(t, fileutil.WriteStringToFile(pth, `#include "NotExists.xcconfig"`))
+
+		_, err := ParseXcconfig(pth)
+		require.Error(t, err)
+	}
+
+	t.Log("include cycle")
+	{
+		pth := filepath.Join(tmpDir, "Cycle.xcconfig")
+		require.NoError(t, fileutil.WriteStringToFile(pth, `#include "Cycle.xcconfig"`))
+
+		_, err := ParseXcconfig(pth)
+		require.Error(t, err)
+	}
+}
+
+func TestProjectXcconfigFiles(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__xcconfig__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	projectPth := filepath.Join(tmpDir, "App", "App.xcodeproj")
+	require.NoError(t, os.MkdirAll(projectPth, 0700))
+	require.NoError(t, fileutil.WriteStringToFile(filepath.Join(projectPth, "project.pbxproj"), xcconfigPbxprojContent))
+
+	xcconfigFiles := []string{
+		filepath.Join(tmpDir, "App", "Config", "App.xcconfig"),
+		filepath.Join(tmpDir, "App", "Config", "Base.xcconfig"),
+		filepath.Join(tmpDir, "Other", "App.xcconfig"),
+	}
+
+	referenced, err := ProjectXcconfigFiles(projectPth, xcconfigFiles)
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(tmpDir, "App", "Config", "App.xcconfig")}, referenced)
+}
@@ -13,6 +13,8 @@ import (
 type Scanner struct {
 	searchDir         string
 	configDescriptors []ios.ConfigDescriptor
+	summary           models.Summary
+	offline           bool
 }
 
 // NewScanner ...
@@ -37,6 +39,11 @@ func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	return detected, nil
 }
 
+// SetOffline ...
+func (scanner *Scanner) SetOffline(offline bool) {
+	scanner.offline = offline
+}
+
 // ExcludedScannerNames ...
 func (Scanner) ExcludedScannerNames() []string {
 	return []string{}
@@ -44,16 +51,22 @@ func (Scanner) ExcludedScannerNames() []string {
 
 // Options ...
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
-	options, configDescriptors, warnings, err := ios.GenerateOptions(ios.XcodeProjectTypeMacOS, scanner.searchDir)
+	options, configDescriptors, summary, warnings, err := ios.GenerateOptions(ios.XcodeProjectTypeMacOS, scanner.searchDir, scanner.offline)
 	if err != nil {
 		return models.OptionNode{}, warnings, err
 	}
 
 	scanner.configDescriptors = configDescriptors
+	scanner.summary = summary
 
 	return options, warnings, nil
 }
 
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
 // DefaultOptions ...
 func (Scanner) DefaultOptions() models.OptionNode {
 	return ios.GenerateDefaultOptions(ios.XcodeProjectTypeMacOS)
@@ -0,0 +1,302 @@
+// Package plugin implements external scanners, executables named bitrise-init-scanner-NAME found on the PATH.
+//
+// Protocol (version 1):
+//
+// The plugin is invoked as:
+//   - bitrise-init-scanner-NAME scan SEARCH_DIR: to scan the project in SEARCH_DIR (the working directory is SEARCH_DIR as well)
+//   - bitrise-init-scanner-NAME defaults: to print the default options and configs of the project type (used by manual-config)
+//
+// The BITRISE_INIT_PLUGIN_PROTOCOL_VERSION env is set to the protocol version.
+// The BITRISE_INIT_OFFLINE env is set to true in offline mode, the plugin must not access the network in this case.
+// The plugin has to exit with 0 and print a single JSON object (Output) to the stdout, the stderr is forwarded to the log.
+//
+//	{
+//	  "detected": true,
+//	  "options": { ... OptionNode ... },
+//	  "configs": { "CONFIG_NAME": "BITRISE_YML_CONTENT" },
+//	  "warnings": [ "..." ]
+//	}
+//
+// If the project type is detected (or the defaults command is invoked), every config leaf of the options
+// has to have a matching, valid bitrise.yml in configs.
+package plugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	"github.com/bitrise-io/go-utils/command"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// ExecutablePrefix ...
+	ExecutablePrefix = "bitrise-init-scanner-"
+	// ProtocolVersion ...
+	ProtocolVersion = "1"
+	// ProtocolVersionEnvKey ...
+	ProtocolVersionEnvKey = "BITRISE_INIT_PLUGIN_PROTOCOL_VERSION"
+	// OfflineEnvKey ...
+	OfflineEnvKey = "BITRISE_INIT_OFFLINE"
+
+	scanCommand     = "scan"
+	defaultsCommand = "defaults"
+)
+
+// Output is the JSON object printed by the plugin.
+type Output struct {
+	Detected bool                    `json:"detected"`
+	Options  *models.OptionNode      `json:"options,omitempty"`
+	Configs  models.BitriseConfigMap `json:"configs,omitempty"`
+	Warnings models.Warnings         `json:"warnings,omitempty"`
+}
+
+// Validate checks if the output is complete: the options are set and every config leaf has a valid config.
+func (output Output) Validate() error {
+	if output.Options == nil || output.Options.IsEmpty() {
+		return fmt.Errorf("no options provided")
+	}
+	if len(output.Configs) == 0 {
+		return fmt.Errorf("no configs provided")
+	}
+
+	configNames := map[string]bool{}
+	if err := output.Options.Walk(func(opt *models.OptionNode, path []string) error {
+		if opt.IsConfigOption() {
+			configNames[opt.Config] = true
+			return nil
+		}
+		if !opt.IsValueOption() {
+			return fmt.Errorf("option (%s) has neither title nor config", strings.Join(path, "/"))
+		}
+		if len(opt.ChildOptionMap) == 0 {
+			return fmt.Errorf("option (%s) has no values", strings.Join(path, "/"))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for configName := range configNames {
+		configStr, ok := output.Configs[configName]
+		if !ok {
+			return fmt.Errorf("config (%s) referenced by the options, but not provided", configName)
+		}
+
+		var config bitriseModels.BitriseDataModel
+		if err := yaml.Unmarshal([]byte(configStr), &config); err != nil {
+			return fmt.Errorf("invalid config (%s), error: %s", configName, err)
+		}
+	}
+
+	return nil
+}
+
+// ParseOutput parses and validates the plugin output.
+// Unknown fields are not allowed, detected output and defaults output (isDefaults) have to be complete.
+func ParseOutput(data []byte, isDefaults bool) (Output, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var output Output
+	if err := decoder.Decode(&output); err != nil {
+		return Output{}, fmt.Errorf("invalid output, error: %s", err)
+	}
+	if decoder.More() {
+		return Output{}, fmt.Errorf("invalid output, multiple JSON values printed")
+	}
+
+	if output.Detected || isDefaults {
+		if err := output.Validate(); err != nil {
+			return Output{}, fmt.Errorf("invalid output, error: %s", err)
+		}
+	}
+
+	return output, nil
+}
+
+//------------------
+// ScannerInterface
+//------------------
+
+// Scanner ...
+type Scanner struct {
+	name           string
+	executablePath string
+	offline        bool
+
+	output Output
+
+	defaultsLoaded bool
+	defaultsOutput Output
+	defaultsErr    error
+}
+
+// NewScanner ...
+func NewScanner(name, executablePath string) *Scanner {
+	return &Scanner{
+		name:           name,
+		executablePath: executablePath,
+	}
+}
+
+// ExecutablePath ...
+func (scanner *Scanner) ExecutablePath() string {
+	return scanner.executablePath
+}
+
+// Name ...
+func (scanner *Scanner) Name() string {
+	return scanner.name
+}
+
+func (scanner *Scanner) run(dir string, args ...string) (Output, error) {
+	envs := []string{ProtocolVersionEnvKey + "=" + ProtocolVersion}
+	if scanner.offline {
+		envs = append(envs, OfflineEnvKey+"=true")
+	}
+
+	var stdout bytes.Buffer
+	cmd := command.New(scanner.executablePath, args...).
+		SetDir(dir).
+		AppendEnvs(envs...).
+		SetStdout(&stdout).
+		SetStderr(os.Stderr)
+
+	log.TPrintf("$ %s", cmd.PrintableCommandArgs())
+	if err := cmd.Run(); err != nil {
+		return Output{}, fmt.Errorf("plugin (%s) failed, error: %s", scanner.executablePath, err)
+	}
+
+	output, err := ParseOutput(stdout.Bytes(), len(args) > 0 && args[0] == defaultsCommand)
+	if err != nil {
+		return Output{}, fmt.Errorf("plugin (%s) printed %s", scanner.executablePath, err)
+	}
+	return output, nil
+}
+
+// SetOffline ...
+func (scanner *Scanner) SetOffline(offline bool) {
+	scanner.offline = offline
+}
+
+// DetectPlatform ...
+func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
+	output, err := scanner.run(searchDir, scanCommand, searchDir)
+	if err != nil {
+		return false, err
+	}
+
+	scanner.output = output
+	return output.Detected, nil
+}
+
+// ExcludedScannerNames ...
+func (scanner *Scanner) ExcludedScannerNames() []string {
+	return []string{}
+}
+
+// Options ...
+func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
+	return *scanner.output.Options, scanner.output.Warnings, nil
+}
+
+// Configs ...
+func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
+	return scanner.output.Configs, nil
+}
+
+// Defaults returns the default options and configs of the plugin, the plugin is invoked only once.
+func (scanner *Scanner) Defaults() (models.OptionNode, models.BitriseConfigMap, error) {
+	if !scanner.defaultsLoaded {
+		scanner.defaultsOutput, scanner.defaultsErr = scanner.run("", defaultsCommand)
+		scanner.defaultsLoaded = true
+	}
+	if scanner.defaultsErr != nil {
+		return models.OptionNode{}, models.BitriseConfigMap{}, scanner.defaultsErr
+	}
+	return *scanner.defaultsOutput.Options, scanner.defaultsOutput.Configs, nil
+}
+
+// DefaultOptions ...
+func (scanner *Scanner) DefaultOptions() models.OptionNode {
+	options, _, err := scanner.Defaults()
+	if err != nil {
+		log.TErrorf("Failed to get default options, error: %s", err)
+		return models.OptionNode{}
+	}
+	return options
+}
+
+// DefaultConfigs ...
+func (scanner *Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
+	_, configs, err := scanner.Defaults()
+	return configs, err
+}
+
+//------------------
+// Discovery
+//------------------
+
+// Discover returns the plugin scanners found in the given PATH list, sorted by name.
+// If multiple executables have the same name, the first one (in PATH order) is used.
+func Discover(pathList string) ([]*Scanner, error) {
+	nameToScanner := map[string]*Scanner{}
+
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+
+		infos, err := ioutil.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to list dir (%s), error: %s", dir, err)
+		}
+
+		for _, info := range infos {
+			name := strings.TrimPrefix(info.Name(), ExecutablePrefix)
+			if name == info.Name() || name == "" {
+				continue
+			}
+
+			pth := filepath.Join(dir, info.Name())
+			if info.Mode()&os.ModeSymlink != 0 {
+				if info, err = os.Stat(pth); err != nil {
+					log.TWarnf("Failed to resolve plugin (%s), error: %s", pth, err)
+					continue
+				}
+			}
+			if !info.Mode().IsRegular() || info.Mode()&0111 == 0 {
+				continue
+			}
+
+			if _, ok := nameToScanner[name]; ok {
+				continue
+			}
+			nameToScanner[name] = NewScanner(name, pth)
+		}
+	}
+
+	names := []string{}
+	for name := range nameToScanner {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	scanners := []*Scanner{}
+	for _, name := range names {
+		scanners = append(scanners, nameToScanner[name])
+	}
+	return scanners, nil
+}
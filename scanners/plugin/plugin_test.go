@@ -0,0 +1,170 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+const validOutput = `{
+	"detected": true,
+	"options": {
+		"title": "Project path",
+		"env_key": "PROJECT_PATH",
+		"value_map": {
+			"app.proj": {
+				"config": "proprietary-config"
+			}
+		}
+	},
+	"configs": {
+		"proprietary-config": "format_version: \"7\"\nworkflows:\n  primary: {}\n"
+	},
+	"warnings": ["no tests found"]
+}`
+
+func TestParseOutput(t *testing.T) {
+	t.Log("valid output")
+	{
+		output, err := ParseOutput([]byte(validOutput), false)
+		require.NoError(t, err)
+		require.True(t, output.Detected)
+		require.Equal(t, "Project path", output.Options.Title)
+		require.Equal(t, 1, len(output.Configs))
+		require.Equal(t, []string{"no tests found"}, []string(output.Warnings))
+	}
+
+	t.Log("not detected output does not need options")
+	{
+		output, err := ParseOutput([]byte(`{"detected": false}`), false)
+		require.NoError(t, err)
+		require.False(t, output.Detected)
+	}
+
+	t.Log("defaults output needs options")
+	{
+		_, err := ParseOutput([]byte(`{"detected": false}`), true)
+		require.Error(t, err)
+	}
+
+	t.Log("invalid outputs")
+	{
+		for _, data := range []string{
+			``,
+			`not json`,
+			`{"detected": true, "unknown": 1}`,
+			`{"detected": true}`,
+			`{"detected": false} {"detected": false}`,
+			// config not provided
+			strings.Replace(validOutput, `"proprietary-config": "format_version`, `"other-config": "format_version`, 1),
+			// invalid config
+			strings.Replace(validOutput, `format_version: \"7\"\nworkflows:\n  primary: {}\n`, `workflows: [`, 1),
+			// value option without values
+			`{"detected": true, "options": {"title": "Project path"}, "configs": {"config": "{}"}}`,
+		} {
+			_, err := ParseOutput([]byte(data), false)
+			require.Error(t, err, data)
+		}
+	}
+}
+
+func writePlugin(t *testing.T, pth, content string, perm os.FileMode) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0777))
+	require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	require.NoError(t, os.Chmod(pth, perm))
+}
+
+func TestDiscover(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__plugin__")
+	require.NoError(t, err)
+
+	firstDir := filepath.Join(tmpDir, "first")
+	secondDir := filepath.Join(tmpDir, "second")
+
+	writePlugin(t, filepath.Join(firstDir, ExecutablePrefix+"b"), "#!/bin/sh\n", 0755)
+	writePlugin(t, filepath.Join(firstDir, ExecutablePrefix+"not-executable"), "#!/bin/sh\n", 0644)
+	writePlugin(t, filepath.Join(firstDir, "bitrise-init"), "#!/bin/sh\n", 0755)
+	writePlugin(t, filepath.Join(secondDir, ExecutablePrefix+"a"), "#!/bin/sh\n", 0755)
+	writePlugin(t, filepath.Join(secondDir, ExecutablePrefix+"b"), "#!/bin/sh\n", 0755)
+	require.NoError(t, os.MkdirAll(filepath.Join(secondDir, ExecutablePrefix+"dir"), 0777))
+
+	pathList := strings.Join([]string{firstDir, filepath.Join(tmpDir, "not-existing"), secondDir}, string(os.PathListSeparator))
+	scanners, err := Discover(pathList)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(scanners))
+
+	require.Equal(t, "a", scanners[0].Name())
+	require.Equal(t, filepath.Join(secondDir, ExecutablePrefix+"a"), scanners[0].ExecutablePath())
+
+	require.Equal(t, "b", scanners[1].Name())
+	require.Equal(t, filepath.Join(firstDir, ExecutablePrefix+"b"), scanners[1].ExecutablePath())
+}
+
+func TestScanner(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__plugin__")
+	require.NoError(t, err)
+
+	pluginPth := filepath.Join(tmpDir, ExecutablePrefix+"proprietary")
+	writePlugin(t, pluginPth, `#!/bin/sh
+if [ "$BITRISE_INIT_PLUGIN_PROTOCOL_VERSION" != "1" ]; then
+	exit 1
+fi
+if [ "$1" = "scan" ] && [ ! -f "$2/app.proj" ]; then
+	echo '{"detected": false}'
+	exit 0
+fi
+cat <<'EOF'
+`+validOutput+`
+EOF
+`, 0755)
+
+	scanner := NewScanner("proprietary", pluginPth)
+
+	t.Log("not detected")
+	{
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.False(t, detected)
+	}
+
+	t.Log("detected")
+	{
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "app.proj"), ""))
+
+		detected, err := scanner.DetectPlatform(tmpDir)
+		require.NoError(t, err)
+		require.True(t, detected)
+
+		options, warnings, err := scanner.Options()
+		require.NoError(t, err)
+		require.Equal(t, "PROJECT_PATH", options.EnvKey)
+		require.Equal(t, 1, len(warnings))
+
+		configs, err := scanner.Configs()
+		require.NoError(t, err)
+		_, ok := configs["proprietary-config"]
+		require.True(t, ok)
+	}
+
+	t.Log("defaults")
+	{
+		configs, err := scanner.DefaultConfigs()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(configs))
+		require.Equal(t, "Project path", scanner.DefaultOptions().Title)
+	}
+
+	t.Log("failing plugin")
+	{
+		failingPth := filepath.Join(tmpDir, ExecutablePrefix+"failing")
+		writePlugin(t, failingPth, "#!/bin/sh\nexit 1\n", 0755)
+
+		_, err := NewScanner("failing", failingPth).DetectPlatform(tmpDir)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,135 @@
+package expo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	easJSONName   = "eas.json"
+	easConfigName = "react-native-expo-eas-config"
+)
+
+const (
+	// EASBuildProfileInputTitle ...
+	EASBuildProfileInputTitle = "EAS build profile"
+	// EASBuildProfileInputEnvKey ...
+	EASBuildProfileInputEnvKey = "EAS_BUILD_PROFILE"
+)
+
+// ExpoTokenEnvKey is the env of the Expo access token, the EAS CLI authenticates by it.
+// The token is not collected as an option, as the option values are written into the bitrise.yml,
+// it has to be added as a secret env.
+const ExpoTokenEnvKey = "EXPO_TOKEN"
+
+const expoTokenWarning = "The EAS build authenticates by an Expo access token: add your token as a secret env with the " + ExpoTokenEnvKey + " key, do not store it in the bitrise.yml"
+
+const easBuildStepTitle = "Build with EAS"
+
+// EASBuildProfiles returns the build profiles declared by the eas.json file (the keys of its build object), in alphabetical order.
+func EASBuildProfiles(easJSONPth string) ([]string, error) {
+	content, err := fileutil.ReadBytesFromFile(easJSONPth)
+	if err != nil {
+		return nil, err
+	}
+
+	var easJSON struct {
+		Build map[string]json.RawMessage `json:"build"`
+	}
+	if err := json.Unmarshal(content, &easJSON); err != nil {
+		return nil, fmt.Errorf("failed to parse %s, error: %s", easJSONPth, err)
+	}
+
+	profiles := []string{}
+	for profile := range easJSON.Build {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return profiles, nil
+}
+
+// easOptions returns the options of an EAS project: the build profile selector.
+func easOptions(profiles []string) models.OptionNode {
+	buildProfileOption := models.NewOption(EASBuildProfileInputTitle, EASBuildProfileInputEnvKey)
+	for _, profile := range profiles {
+		configOption := models.NewConfigOption(easConfigName)
+		buildProfileOption.AddConfig(profile, configOption)
+	}
+	return *buildProfileOption
+}
+
+// easBuildScriptContent returns the script building the app with the EAS CLI in the project's directory,
+// the CLI authenticates by the EXPO_TOKEN secret env, the script fails early if it is not set.
+// The token check runs before enabling the command trace, not to print the token.
+func easBuildScriptContent(relPackageJSONDir string) string {
+	content := `#!/usr/bin/env bash
+set -e
+
+if [ -z "$` + ExpoTokenEnvKey + `" ] ; then
+  echo "` + ExpoTokenEnvKey + ` is not set, add your Expo access token as a secret env"
+  exit 1
+fi
+
+set -x
+
+`
+	if relPackageJSONDir != "" {
+		content += fmt.Sprintf("cd %q\n", relPackageJSONDir)
+	}
+	return content + `npx eas-cli build --profile "$` + EASBuildProfileInputEnvKey + `" --platform all --non-interactive` + "\n"
+}
+
+// easConfigs returns the config of an EAS project: the app is built by the EAS CLI instead of ejecting the native projects.
+// Like the classic Expo config, the build runs in the deploy workflow if the project has tests, in the primary workflow otherwise.
+func easConfigs(relPackageJSONDir string, workdirEnvList []envmanModels.EnvironmentItemModel, hasYarnLockFile, hasTest bool) (models.BitriseConfigMap, error) {
+	installStepListItem := steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...)
+	testStepListItem := steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "test"})...)
+	if hasYarnLockFile {
+		installStepListItem = steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...)
+		testStepListItem = steps.YarnStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "test"})...)
+	}
+	easBuildStepListItem := steps.ScriptSteplistItem(easBuildStepTitle, envmanModels.EnvironmentItemModel{"content": easBuildScriptContent(relPackageJSONDir)})
+
+	configBuilder := models.NewDefaultConfigBuilder()
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, installStepListItem)
+
+	if hasTest {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, testStepListItem)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultPrepareStepList(false)...)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, installStepListItem, easBuildStepListItem)
+		configBuilder.AppendStepListItemsTo(models.DeployWorkflowID, steps.DefaultDeployStepList(false)...)
+	} else {
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, easBuildStepListItem)
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+	}
+
+	bitriseDataModel, err := configBuilder.Generate(Name)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	data, err := yaml.Marshal(bitriseDataModel)
+	if err != nil {
+		return models.BitriseConfigMap{}, err
+	}
+
+	return models.BitriseConfigMap{
+		easConfigName: string(data),
+	}, nil
+}
+
+// easJSONPth returns the path of the eas.json file next to the package.json file.
+func easJSONPth(packageJSONPth string) string {
+	return filepath.Join(filepath.Dir(packageJSONPth), easJSONName)
+}
@@ -0,0 +1,190 @@
+package expo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/scanners/ios"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEASBuildProfiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"eas.json":         testEASJSONContent,
+		"invalid/eas.json": "{",
+		"empty/eas.json":   `{"cli": {"version": ">= 3.0.0"}}`,
+	})
+
+	profiles, err := EASBuildProfiles(filepath.Join(dir, "eas.json"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"development", "preview", "production"}, profiles)
+
+	profiles, err = EASBuildProfiles(filepath.Join(dir, "empty/eas.json"))
+	require.NoError(t, err)
+	require.Equal(t, []string{}, profiles)
+
+	_, err = EASBuildProfiles(filepath.Join(dir, "invalid/eas.json"))
+	require.Error(t, err)
+}
+
+func TestOptions(t *testing.T) {
+	t.Log("EAS project")
+	{
+		dir := writeFiles(t, map[string]string{
+			"package.json": testPackageJSONContent,
+			"app.json":     testAppJSONContent,
+			"eas.json":     testEASJSONContent,
+			"yarn.lock":    "",
+		})
+
+		options, configs, warnings := scan(t, dir)
+		require.Equal(t, models.Warnings{expoTokenWarning}, warnings)
+
+		// the Expo token is a secret, it is not collected as an option
+		configSpec := testhelper.Spec{Config: easConfigName}
+		testhelper.AssertOptionTree(t, options, testhelper.BuildTree(testhelper.Spec{
+			Title:  EASBuildProfileInputTitle,
+			EnvKey: EASBuildProfileInputEnvKey,
+			Values: map[string]testhelper.Spec{
+				"development": configSpec,
+				"preview":     configSpec,
+				"production":  configSpec,
+			},
+		}))
+
+		require.Equal(t, []string{easConfigName}, configNames(configs))
+		config := configs[easConfigName]
+		testhelper.AssertConfigEnvKeys(t, config, EASBuildProfileInputEnvKey, ExpoTokenEnvKey)
+		require.NotContains(t, config, "set -ex")
+		require.Contains(t, config, `npx eas-cli build --profile "$EAS_BUILD_PROFILE" --platform all --non-interactive`)
+		require.Contains(t, config, "yarn@")
+		require.NotContains(t, config, "expo-detach")
+	}
+
+	t.Log("classic Expo project")
+	{
+		dir := writeFiles(t, map[string]string{
+			"package.json": testPackageJSONContent,
+			"app.json":     testAppJSONContent,
+		})
+
+		options, configs, warnings := scan(t, dir)
+		require.Equal(t, 0, len(warnings))
+
+		require.Equal(t, ios.ProjectPathInputEnvKey, options.EnvKey)
+		require.Equal(t, []string{"ios/ExpoSample.xcodeproj"}, options.GetValues())
+
+		require.Equal(t, []string{configName}, configNames(configs))
+		require.Contains(t, configs[configName], "expo-detach")
+		require.NotContains(t, configs[configName], "eas-cli")
+	}
+
+	t.Log("eas.json without build profiles falls back to the classic Expo build")
+	{
+		dir := writeFiles(t, map[string]string{
+			"package.json": testPackageJSONContent,
+			"app.json":     testAppJSONContent,
+			"eas.json":     "{}",
+		})
+
+		options, configs, warnings := scan(t, dir)
+		require.Equal(t, 1, len(warnings))
+		require.True(t, strings.HasPrefix(warnings[0], "No build profile found in"))
+
+		require.Equal(t, ios.ProjectPathInputEnvKey, options.EnvKey)
+		require.Equal(t, []string{configName}, configNames(configs))
+	}
+}
+
+// scan runs the scanner in the given directory, like the CLI does.
+func scan(t *testing.T, dir string) (models.OptionNode, models.BitriseConfigMap, models.Warnings) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	scanner := NewScanner()
+	detected, err := scanner.DetectPlatform(dir)
+	require.NoError(t, err)
+	require.True(t, detected)
+
+	options, warnings, err := scanner.Options()
+	require.NoError(t, err)
+
+	configs, err := scanner.Configs()
+	require.NoError(t, err)
+
+	return options, configs, warnings
+}
+
+func configNames(configs models.BitriseConfigMap) []string {
+	names := []string{}
+	for name := range configs {
+		names = append(names, name)
+	}
+	return names
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__expo__")
+	require.NoError(t, err)
+
+	for pth, content := range files {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	return tmpDir
+}
+
+const testPackageJSONContent = `{
+  "name": "expo-sample",
+  "scripts": {
+    "start": "expo start"
+  },
+  "dependencies": {
+    "expo": "^49.0.0",
+    "react": "18.2.0",
+    "react-native": "0.72.6"
+  }
+}
+`
+
+const testAppJSONContent = `{
+  "name": "ExpoSample",
+  "displayName": "ExpoSample",
+  "expo": {
+    "name": "ExpoSample",
+    "slug": "expo-sample"
+  }
+}
+`
+
+const testEASJSONContent = `{
+  "cli": {
+    "version": ">= 5.0.0"
+  },
+  "build": {
+    "development": {
+      "developmentClient": true,
+      "distribution": "internal"
+    },
+    "preview": {
+      "distribution": "internal"
+    },
+    "production": {}
+  },
+  "submit": {
+    "production": {}
+  }
+}
+`
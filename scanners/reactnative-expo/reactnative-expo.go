@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/scanners/android"
 	"github.com/bitrise-core/bitrise-init/scanners/ios"
@@ -18,7 +19,6 @@ import (
 	"github.com/bitrise-core/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/fileutil"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-tools/xcode-project/serialized"
 	yaml "gopkg.in/yaml.v2"
@@ -95,6 +95,8 @@ type Scanner struct {
 	searchDir      string
 	packageJSONPth string
 	usesExpoKit    bool
+	// easBuildProfiles are the build profiles of the project's eas.json, empty if the project does not use EAS
+	easBuildProfiles []string
 }
 
 // NewScanner ...
@@ -107,6 +109,11 @@ func (Scanner) Name() string {
 	return Name
 }
 
+// WorkflowNamespace ...
+func (Scanner) WorkflowNamespace() string {
+	return "expo-"
+}
+
 // DetectPlatform ...
 func (scanner *Scanner) DetectPlatform(searchDir string) (bool, error) {
 	scanner.searchDir = searchDir
@@ -180,6 +187,27 @@ func appJSONIssue(appJSONPth, reason, explanation string) string {
 func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	warnings := models.Warnings{}
 
+	// the EAS projects are built by the EAS CLI, without ejecting the native projects
+	scanner.easBuildProfiles = nil
+	if exist, err := pathutil.IsPathExists(easJSONPth(scanner.packageJSONPth)); err != nil {
+		return models.OptionNode{}, warnings, err
+	} else if exist {
+		profiles, err := EASBuildProfiles(easJSONPth(scanner.packageJSONPth))
+		if err != nil {
+			log.TWarnf("Failed to read the EAS build profiles, error: %s", err)
+			warnings = append(warnings, fmt.Sprintf("Failed to read the EAS build profiles, the classic Expo build is used, error: %s", err))
+		} else if len(profiles) == 0 {
+			log.TWarnf("No EAS build profile found")
+			warnings = append(warnings, fmt.Sprintf("No build profile found in %s, the classic Expo build is used", easJSONPth(scanner.packageJSONPth)))
+		} else {
+			log.TPrintf("EAS build profiles: %v", profiles)
+			log.TWarnf(expoTokenWarning)
+			warnings = append(warnings, expoTokenWarning)
+			scanner.easBuildProfiles = profiles
+			return easOptions(profiles), warnings, nil
+		}
+	}
+
 	// we need to know if the project uses the Expo Kit,
 	// since its usage differentiates the eject process and the config options
 	usesExpoKit := false
@@ -390,16 +418,12 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	}
 
 	// determine dependency manager step
-	hasYarnLockFile := false
-	if exist, err := pathutil.IsPathExists(filepath.Join(relPackageJSONDir, "yarn.lock")); err != nil {
+	packageManager, err := utility.DetectJSPackageManager(relPackageJSONDir)
+	if err != nil {
 		log.Warnf("Failed to check if yarn.lock file exists in the workdir: %s", err)
-		log.TPrintf("Dependency manager: npm")
-	} else if exist {
-		log.TPrintf("Dependency manager: yarn")
-		hasYarnLockFile = true
-	} else {
-		log.TPrintf("Dependency manager: npm")
 	}
+	log.TPrintf("Dependency manager: %s", packageManager)
+	hasYarnLockFile := packageManager == utility.JSPackageManagerYarn
 
 	// find test script in package.json file
 	b, err := fileutil.ReadBytesFromFile(scanner.packageJSONPth)
@@ -419,6 +443,10 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	}
 	log.TPrintf("test script found in package.json: %v", hasTest)
 
+	if len(scanner.easBuildProfiles) > 0 {
+		return easConfigs(relPackageJSONDir, workdirEnvList, hasYarnLockFile, hasTest)
+	}
+
 	if !hasTest {
 		// if the project has no test script defined,
 		// we can only provide deploy like workflow,
@@ -622,7 +650,7 @@ func (Scanner) DefaultOptions() models.OptionNode {
 		passwordOption := models.NewOption("Expo password", "EXPO_PASSWORD")
 		userNameOption.AddOption("_", passwordOption)
 
-		configOption := models.NewConfigOption("react-native-expo-expo-kit-default-config")
+		configOption := models.NewConfigOption("default-react-native-expo-expo-kit-config")
 		passwordOption.AddConfig("_", configOption)
 	}
 
@@ -653,7 +681,7 @@ func (Scanner) DefaultOptions() models.OptionNode {
 		buildVariantOption := models.NewOption(android.VariantInputTitle, android.VariantInputEnvKey)
 		moduleOption.AddOption("app", buildVariantOption)
 
-		configOption := models.NewConfigOption("react-native-expo-plain-default-config")
+		configOption := models.NewConfigOption("default-react-native-expo-plain-config")
 		buildVariantOption.AddConfig("Release", configOption)
 	}
 
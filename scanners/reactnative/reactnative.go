@@ -7,14 +7,15 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/scanners/android"
+	"github.com/bitrise-core/bitrise-init/scanners/e2e"
 	"github.com/bitrise-core/bitrise-init/scanners/ios"
 	"github.com/bitrise-core/bitrise-init/steps"
 	"github.com/bitrise-core/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
 	"github.com/bitrise-io/go-utils/command"
-	"github.com/bitrise-io/go-utils/log"
 	"github.com/bitrise-io/go-utils/pathutil"
 )
 
@@ -33,6 +34,9 @@ type Scanner struct {
 	androidScanner *android.Scanner
 	hasNPMTest     bool
 	packageJSONPth string
+
+	uiTestFrameworks []e2e.Framework
+	summary          models.Summary
 }
 
 // NewScanner ...
@@ -128,6 +132,21 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 
 	projectDir := filepath.Dir(scanner.packageJSONPth)
 
+	scanner.summary = models.Summary{}
+	scanner.uiTestFrameworks, err = e2e.DetectJSFrameworks(scanner.packageJSONPth)
+	if err != nil {
+		return models.OptionNode{}, warnings, fmt.Errorf("failed to search for UI testing frameworks, error: %s", err)
+	}
+	hasUITest := len(scanner.uiTestFrameworks) > 0
+	if hasUITest {
+		relProjectDir, err := utility.RelPath(scanner.searchDir, projectDir)
+		if err != nil {
+			return models.OptionNode{}, warnings, err
+		}
+		log.TPrintf("UI testing frameworks found: %v", scanner.uiTestFrameworks)
+		scanner.summary = append(scanner.summary, e2e.Summary(relProjectDir, scanner.uiTestFrameworks))
+	}
+
 	// android options
 	var androidOptions *models.OptionNode
 	androidDir := filepath.Join(projectDir, "android")
@@ -198,7 +217,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 						return models.OptionNode{}, warnings, fmt.Errorf("no config for option: %s", child.String())
 					}
 
-					configName := configName(true, false, hasNPMTest)
+					configName := configName(true, false, hasNPMTest, hasUITest)
 					child.Config = configName
 				}
 			}
@@ -220,7 +239,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 					return models.OptionNode{}, warnings, fmt.Errorf("no config for option: %s", child.String())
 				}
 
-				configName := configName(scanner.androidScanner != nil, true, hasNPMTest)
+				configName := configName(scanner.androidScanner != nil, true, hasNPMTest, hasUITest)
 				child.Config = configName
 			}
 		}
@@ -236,9 +255,18 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 
 	}
 
+	if hasUITest {
+		e2e.AddRunUITestsOption(&rootOption)
+	}
+
 	return rootOption, warnings, nil
 }
 
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
 // DefaultOptions ...
 func (Scanner) DefaultOptions() models.OptionNode {
 	androidOptions := (&android.Scanner{ExcludeTest: true}).DefaultOptions()
@@ -277,6 +305,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 
 	if scanner.hasNPMTest {
 		configBuilder := models.NewDefaultConfigBuilder()
+		scanner.appendUITestWorkflow(configBuilder, relPackageJSONDir)
 
 		// ci
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
@@ -340,7 +369,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 					return models.BitriseConfigMap{}, err
 				}
 
-				configName := configName(scanner.androidScanner != nil, true, true)
+				configName := configName(scanner.androidScanner != nil, true, true, len(scanner.uiTestFrameworks) > 0)
 				configMap[configName] = string(data)
 			}
 		} else {
@@ -356,11 +385,12 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 				return models.BitriseConfigMap{}, err
 			}
 
-			configName := configName(scanner.androidScanner != nil, false, true)
+			configName := configName(scanner.androidScanner != nil, false, true, len(scanner.uiTestFrameworks) > 0)
 			configMap[configName] = string(data)
 		}
 	} else {
 		configBuilder := models.NewDefaultConfigBuilder()
+		scanner.appendUITestWorkflow(configBuilder, relPackageJSONDir)
 
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
 		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
@@ -415,7 +445,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 					return models.BitriseConfigMap{}, err
 				}
 
-				configName := configName(scanner.androidScanner != nil, true, false)
+				configName := configName(scanner.androidScanner != nil, true, false, len(scanner.uiTestFrameworks) > 0)
 				configMap[configName] = string(data)
 			}
 		} else {
@@ -431,7 +461,7 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 				return models.BitriseConfigMap{}, err
 			}
 
-			configName := configName(scanner.androidScanner != nil, false, false)
+			configName := configName(scanner.androidScanner != nil, false, false, len(scanner.uiTestFrameworks) > 0)
 			configMap[configName] = string(data)
 		}
 	}
@@ -439,6 +469,23 @@ func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
 	return configMap, nil
 }
 
+// appendUITestWorkflow adds the test workflow, running the UI tests of the detected frameworks, if any.
+func (scanner *Scanner) appendUITestWorkflow(configBuilder *models.ConfigBuilderModel, workdir string) {
+	if len(scanner.uiTestFrameworks) == 0 {
+		return
+	}
+
+	workdirEnvList := []envmanModels.EnvironmentItemModel{}
+	if workdir != "" {
+		workdirEnvList = append(workdirEnvList, envmanModels.EnvironmentItemModel{WorkDirInputKey: workdir})
+	}
+
+	configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultPrepareStepList(false)...)
+	configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.NpmStepListItem(append(workdirEnvList, envmanModels.EnvironmentItemModel{"command": "install"})...))
+	configBuilder.AppendStepListItemsTo(models.TestWorkflowID, e2e.JSStepList(scanner.uiTestFrameworks, workdir, scanner.androidScanner != nil)...)
+	configBuilder.AppendStepListItemsTo(models.TestWorkflowID, steps.DefaultDeployStepList(false)...)
+}
+
 // DefaultConfigs ...
 func (Scanner) DefaultConfigs() (models.BitriseConfigMap, error) {
 	configBuilder := models.NewDefaultConfigBuilder()
@@ -36,7 +36,7 @@ func CollectPackageJSONFiles(searchDir string) ([]string, error) {
 	return relevantPackageFileList, nil
 }
 
-func configName(hasAndroidProject, hasIosProject, hasNPMTest bool) string {
+func configName(hasAndroidProject, hasIosProject, hasNPMTest, hasUITest bool) string {
 	name := "react-native"
 	if hasAndroidProject {
 		name += "-android"
@@ -47,6 +47,9 @@ func configName(hasAndroidProject, hasIosProject, hasNPMTest bool) string {
 	if hasNPMTest {
 		name += "-test"
 	}
+	if hasUITest {
+		name += "-ui-test"
+	}
 	name += "-config"
 	return name
 }
@@ -1,18 +1,24 @@
 package scanners
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/scanners/android"
 	"github.com/bitrise-core/bitrise-init/scanners/cordova"
+	"github.com/bitrise-core/bitrise-init/scanners/electron"
 	"github.com/bitrise-core/bitrise-init/scanners/fastlane"
 	"github.com/bitrise-core/bitrise-init/scanners/flutter"
 	"github.com/bitrise-core/bitrise-init/scanners/ionic"
 	"github.com/bitrise-core/bitrise-init/scanners/ios"
 	"github.com/bitrise-core/bitrise-init/scanners/macos"
+	"github.com/bitrise-core/bitrise-init/scanners/plugin"
 	"github.com/bitrise-core/bitrise-init/scanners/reactnative"
 	expo "github.com/bitrise-core/bitrise-init/scanners/reactnative-expo"
 	"github.com/bitrise-core/bitrise-init/scanners/xamarin"
 	"github.com/bitrise-core/bitrise-init/steps"
+	"github.com/bitrise-io/go-utils/sliceutil"
 	"gopkg.in/yaml.v2"
 )
 
@@ -69,6 +75,53 @@ type AutomationToolScanner interface {
 	SetDetectedProjectTypes(projectTypes []string)
 }
 
+// NetworkAwareScanner can be implemented by a scanner (in addition to ScannerInterface),
+// if its detection may reach the network.
+type NetworkAwareScanner interface {
+	// Called before DetectPlatform(), in offline mode the scanner has to use only the local files and bundled data,
+	// the skipped network dependent detection should be reported as a warning.
+	SetOffline(offline bool)
+}
+
+// SummaryProvider can be implemented by a scanner (in addition to ScannerInterface),
+// to share informational hints about the scanned project.
+type SummaryProvider interface {
+	// Returns:
+	// - the summary collected by the last Options() call
+	Summary() models.Summary
+}
+
+// IconProvider can be implemented by a scanner (in addition to ScannerInterface),
+// to share the detected app icons.
+type IconProvider interface {
+	// Returns:
+	// - the app icons found by the last Options() call, the best candidate first
+	Icons() models.Icons
+}
+
+// WorkflowNamespaceProvider can be implemented by a scanner (in addition to ScannerInterface),
+// to declare the prefix of its workflow IDs, used when the workflows are namespaced.
+type WorkflowNamespaceProvider interface {
+	// Returns:
+	// - the workflow ID prefix, like: ios-
+	WorkflowNamespace() string
+}
+
+// WorkflowNamespace returns the workflow ID prefix of the given scanner:
+// the prefix declared by the scanner (if implements WorkflowNamespaceProvider) or the scanner's name followed by a dash.
+func WorkflowNamespace(scannerName string) string {
+	for _, scanner := range append(append([]ScannerInterface{}, ProjectScanners...), AutomationToolScanners...) {
+		if scanner.Name() != scannerName {
+			continue
+		}
+		if provider, ok := scanner.(WorkflowNamespaceProvider); ok {
+			return provider.WorkflowNamespace()
+		}
+		break
+	}
+	return scannerName + "-"
+}
+
 // ProjectScanners ...
 var ProjectScanners = []ScannerInterface{
 	expo.NewScanner(),
@@ -76,6 +129,7 @@ var ProjectScanners = []ScannerInterface{
 	flutter.NewScanner(),
 	ionic.NewScanner(),
 	cordova.NewScanner(),
+	electron.NewScanner(),
 	ios.NewScanner(),
 	macos.NewScanner(),
 	android.NewScanner(),
@@ -87,6 +141,33 @@ var AutomationToolScanners = []ScannerInterface{
 	fastlane.NewScanner(),
 }
 
+// PluginScanners returns the external scanner plugins found on the PATH.
+// Plugins named after a built-in scanner are not returned, the related errors are returned by the plugin's name.
+func PluginScanners() ([]ScannerInterface, map[string]models.Errors) {
+	pluginToErrors := map[string]models.Errors{}
+
+	plugins, err := plugin.Discover(os.Getenv("PATH"))
+	if err != nil {
+		pluginToErrors["plugins"] = models.Errors{fmt.Sprintf("Failed to search for scanner plugins, error: %s", err)}
+		return []ScannerInterface{}, pluginToErrors
+	}
+
+	reservedNames := []string{CustomProjectType}
+	for _, scanner := range append(ProjectScanners, AutomationToolScanners...) {
+		reservedNames = append(reservedNames, scanner.Name())
+	}
+
+	pluginScanners := []ScannerInterface{}
+	for _, p := range plugins {
+		if sliceutil.IsStringInSlice(p.Name(), reservedNames) {
+			pluginToErrors[p.Name()] = append(pluginToErrors[p.Name()], fmt.Sprintf("Scanner plugin (%s) skipped, the name is reserved by a built-in scanner", p.ExecutablePath()))
+			continue
+		}
+		pluginScanners = append(pluginScanners, p)
+	}
+	return pluginScanners, pluginToErrors
+}
+
 // CustomProjectType ...
 const CustomProjectType = "other"
 
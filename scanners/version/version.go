@@ -0,0 +1,147 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/steps"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/pointers"
+)
+
+const (
+	// BumpInputTitle ...
+	BumpInputTitle = "Increment the build number before building the app?"
+	// BumpInputEnvKey ...
+	BumpInputEnvKey = "VERSION_BUMP"
+	// BumpEnabledValue ...
+	BumpEnabledValue = "yes"
+	// BumpDisabledValue ...
+	BumpDisabledValue = "no"
+)
+
+const bumpStepTitle = "Increment the build number"
+
+// bumpRunIf runs the build number incrementing steps only if the user enabled them.
+var bumpRunIf = fmt.Sprintf(`{{enveq "%s" "%s"}}`, BumpInputEnvKey, BumpEnabledValue)
+
+// Source is where the version and the build number of an app are declared.
+type Source struct {
+	// App is the name of the app, like the target or the module.
+	App string
+	// File is the file declaring the version, like the project, the Info.plist or the build.gradle file.
+	File string
+
+	VersionKey     string
+	Version        string
+	BuildNumberKey string
+	BuildNumber    string
+}
+
+// String describes the source, like: App: 1.2.0 (42) from MARKETING_VERSION and CURRENT_PROJECT_VERSION of App.xcodeproj
+func (source Source) String() string {
+	value := source.Version
+	if source.BuildNumber != "" {
+		value += fmt.Sprintf(" (%s)", source.BuildNumber)
+	}
+
+	keys := source.VersionKey
+	if source.BuildNumberKey != "" && source.BuildNumberKey != source.VersionKey {
+		keys += " and " + source.BuildNumberKey
+	}
+
+	return fmt.Sprintf("%s: %s from %s of %s", source.App, value, keys, source.File)
+}
+
+// Summary returns the summary of the version sources found in the given location,
+// like: App.xcodeproj: version sources: App: 1.2.0 (42) from MARKETING_VERSION and CURRENT_PROJECT_VERSION of App.xcodeproj
+func Summary(location string, sources []Source) string {
+	descriptions := []string{}
+	for _, source := range sources {
+		descriptions = append(descriptions, source.String())
+	}
+
+	label := "version source"
+	if len(sources) > 1 {
+		label += "s"
+	}
+	return fmt.Sprintf("%s: %s: %s", location, label, strings.Join(descriptions, ", "))
+}
+
+// NewBumpOption returns the build number incrementing yes/no selector, both of its values lead to the given config option.
+func NewBumpOption(configOption *models.OptionNode) *models.OptionNode {
+	bumpOption := models.NewOption(BumpInputTitle, BumpInputEnvKey)
+	bumpOption.AddConfig(BumpDisabledValue, configOption)
+	bumpOption.AddConfig(BumpEnabledValue, models.NewConfigOption(configOption.Config))
+	return bumpOption
+}
+
+// AddBumpOption places the build number incrementing selector in front of every config option of the given option tree.
+func AddBumpOption(rootOption *models.OptionNode) {
+	for _, lastChild := range rootOption.LastChilds() {
+		for value, child := range lastChild.ChildOptionMap {
+			if child == nil || !child.IsConfigOption() {
+				continue
+			}
+
+			lastChild.AddOption(value, NewBumpOption(child))
+		}
+	}
+}
+
+// StepList returns the given steps, running only if the build number incrementing is enabled by the user.
+func StepList(stepList ...bitriseModels.StepListItemModel) []bitriseModels.StepListItemModel {
+	for _, stepListItem := range stepList {
+		for id, step := range stepListItem {
+			step.RunIf = pointers.NewStringPtr(bumpRunIf)
+			stepListItem[id] = step
+		}
+	}
+	return stepList
+}
+
+// xcodeBumpScript sets the build number by agvtool, which works on the only project of the current dir:
+// if the project path is a workspace, agvtool runs in the dir of the workspace's project containing the scheme (as a shared or a user scheme).
+const xcodeBumpScript = `#!/usr/bin/env bash
+set -ex
+
+project_path="$%[1]s"
+if [[ "$project_path" == *.xcworkspace ]] ; then
+  workspace_dir="$(dirname "$project_path")"
+  project_path=""
+  while IFS= read -r location ; do
+    if [ -n "$(find "$workspace_dir/$location" -path "*/xcschemes/$%[2]s.xcscheme")" ] ; then
+      project_path="$workspace_dir/$location"
+      break
+    fi
+  done < <(sed -n -E 's/.*location *= *"(group|container):([^"]*\.xcodeproj)".*/\2/p' "$%[1]s/contents.xcworkspacedata")
+
+  if [ -z "$project_path" ] ; then
+    echo "No project of the workspace contains the scheme: $%[2]s"
+    exit 1
+  fi
+fi
+
+cd "$(dirname "$project_path")"
+xcrun agvtool new-version -all "$BITRISE_BUILD_NUMBER"
+`
+
+// XcodeStepList returns the step setting the build number (CURRENT_PROJECT_VERSION and CFBundleVersion) of the Xcode project
+// to the Bitrise build number with agvtool, projectPathEnvKey and schemeEnvKey are the envs of the project (or workspace) path and the scheme.
+func XcodeStepList(projectPathEnvKey, schemeEnvKey string) []bitriseModels.StepListItemModel {
+	content := fmt.Sprintf(xcodeBumpScript, projectPathEnvKey, schemeEnvKey)
+	return StepList(steps.ScriptSteplistItem(bumpStepTitle, envmanModels.EnvironmentItemModel{"content": content}))
+}
+
+// FlutterStepList returns the step setting the build number of the pubspec.yaml version (the part after the +)
+// to the Bitrise build number, projectLocationEnvKey is the env of the Flutter project's directory.
+func FlutterStepList(projectLocationEnvKey string) []bitriseModels.StepListItemModel {
+	content := "#!/usr/bin/env bash\nset -ex\n\n" +
+		`cd "$` + projectLocationEnvKey + `"` + "\n" +
+		`sed -i.bak -E "s/^(version:[[:space:]]*[^+[:space:]]+)(\+[0-9]+)?/\1+$BITRISE_BUILD_NUMBER/" pubspec.yaml` + "\n" +
+		"rm pubspec.yaml.bak\n"
+
+	return StepList(steps.ScriptSteplistItem(bumpStepTitle, envmanModels.EnvironmentItemModel{"content": content}))
+}
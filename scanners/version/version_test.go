@@ -0,0 +1,142 @@
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/testhelper"
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	require.Equal(t, "App.xcodeproj: version source: App: 1.2.0 (42) from MARKETING_VERSION and CURRENT_PROJECT_VERSION of App.xcodeproj", Summary("App.xcodeproj", []Source{
+		{App: "App", File: "App.xcodeproj", VersionKey: "MARKETING_VERSION", Version: "1.2.0", BuildNumberKey: "CURRENT_PROJECT_VERSION", BuildNumber: "42"},
+	}))
+
+	require.Equal(t, ".: version sources: app: 1.2.0 from versionName and versionCode of app/build.gradle, sample: 2.0.0 (3) from version of pubspec.yaml", Summary(".", []Source{
+		{App: "app", File: "app/build.gradle", VersionKey: "versionName", Version: "1.2.0", BuildNumberKey: "versionCode"},
+		{App: "sample", File: "pubspec.yaml", VersionKey: "version", Version: "2.0.0", BuildNumberKey: "version", BuildNumber: "3"},
+	}))
+}
+
+func TestAddBumpOption(t *testing.T) {
+	projectOption := models.NewOption("Project", "PROJECT")
+	schemeOption := models.NewOption("Scheme", "SCHEME")
+	projectOption.AddOption("App.xcodeproj", schemeOption)
+	schemeOption.AddConfig("App", models.NewConfigOption("config-version-bump"))
+	schemeOption.AddConfig("AppClip", models.NewConfigOption("config-version-bump"))
+
+	AddBumpOption(projectOption)
+
+	paths := []string{}
+	require.NoError(t, projectOption.Walk(func(opt *models.OptionNode, path []string) error {
+		if opt.IsConfigOption() {
+			paths = append(paths, opt.String())
+			require.Equal(t, 3, len(path))
+			require.Contains(t, []string{BumpEnabledValue, BumpDisabledValue}, path[2])
+		}
+		return nil
+	}))
+	require.Equal(t, 4, len(paths))
+
+	bumpOption := schemeOption.ChildOptionMap["App"]
+	require.Equal(t, BumpInputEnvKey, bumpOption.EnvKey)
+	require.False(t, bumpOption.IsUserInput())
+	require.Equal(t, []string{"no", "yes"}, bumpOption.GetValues())
+	require.Equal(t, "config-version-bump", bumpOption.ChildOptionMap[BumpEnabledValue].Config)
+	require.Equal(t, "config-version-bump", bumpOption.ChildOptionMap[BumpDisabledValue].Config)
+}
+
+func TestStepList(t *testing.T) {
+	for _, stepListItem := range append(XcodeStepList("BITRISE_PROJECT_PATH", "BITRISE_SCHEME"), FlutterStepList("BITRISE_FLUTTER_PROJECT_LOCATION")...) {
+		for _, step := range stepListItem {
+			require.NotNil(t, step.RunIf)
+			require.Equal(t, `{{enveq "VERSION_BUMP" "yes"}}`, *step.RunIf)
+			require.NotNil(t, step.Title)
+			require.Equal(t, "Increment the build number", *step.Title)
+		}
+	}
+}
+
+const testWorkspaceContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:Framework/Framework.xcodeproj">
+   </FileRef>
+   <FileRef
+      location = "group:App/App.xcodeproj">
+   </FileRef>
+   <FileRef
+      location = "group:Pods/Pods.xcodeproj">
+   </FileRef>
+</Workspace>
+`
+
+// runXcodeBumpScript runs the build number incrementing script with an xcrun stub, returns the dir where agvtool was called.
+func runXcodeBumpScript(t *testing.T, dir, projectPath, scheme string) (string, error) {
+	testhelper.WriteFile(t, dir, "bin/xcrun", "#!/usr/bin/env bash\npwd > \""+filepath.Join(dir, "agvtool_dir")+"\"\n")
+	require.NoError(t, os.Chmod(filepath.Join(dir, "bin", "xcrun"), 0700))
+
+	content := ""
+	for _, step := range XcodeStepList("BITRISE_PROJECT_PATH", "BITRISE_SCHEME")[0] {
+		content = step.Inputs[0]["content"].(string)
+	}
+	cmd := command.New("bash", "-c", content).SetDir(dir).AppendEnvs(
+		"PATH="+filepath.Join(dir, "bin")+":"+os.Getenv("PATH"),
+		"BITRISE_PROJECT_PATH="+projectPath,
+		"BITRISE_SCHEME="+scheme,
+		"BITRISE_BUILD_NUMBER=42",
+	)
+	if out, err := cmd.RunAndReturnTrimmedCombinedOutput(); err != nil {
+		return out, err
+	}
+
+	agvtoolDir, err := fileutil.ReadStringFromFile(filepath.Join(dir, "agvtool_dir"))
+	require.NoError(t, err)
+	return strings.TrimSpace(agvtoolDir), nil
+}
+
+func TestXcodeStepListScript(t *testing.T) {
+	dir, cleanup := testhelper.TempDir(t, "__version_xcode_bump__")
+	defer cleanup()
+
+	testhelper.WriteFile(t, dir, "App.xcworkspace/contents.xcworkspacedata", testWorkspaceContent)
+	testhelper.WriteFile(t, dir, "Framework/Framework.xcodeproj/xcshareddata/xcschemes/Framework.xcscheme", "")
+	testhelper.WriteFile(t, dir, "App/App.xcodeproj/xcshareddata/xcschemes/App.xcscheme", "")
+	testhelper.WriteFile(t, dir, "App/App.xcodeproj/xcuserdata/bitrise.xcuserdatad/xcschemes/AppClip.xcscheme", "")
+	testhelper.WriteFile(t, dir, "Pods/Pods.xcodeproj/project.pbxproj", "")
+
+	t.Log("project")
+	{
+		agvtoolDir, err := runXcodeBumpScript(t, dir, "App/App.xcodeproj", "App")
+		require.NoError(t, err, agvtoolDir)
+		require.Equal(t, filepath.Join(dir, "App"), agvtoolDir)
+	}
+
+	t.Log("workspace: the project containing the shared scheme")
+	{
+		agvtoolDir, err := runXcodeBumpScript(t, dir, "App.xcworkspace", "Framework")
+		require.NoError(t, err, agvtoolDir)
+		require.Equal(t, filepath.Join(dir, "Framework"), agvtoolDir)
+	}
+
+	t.Log("workspace: the project containing the user scheme")
+	{
+		agvtoolDir, err := runXcodeBumpScript(t, dir, "App.xcworkspace", "AppClip")
+		require.NoError(t, err, agvtoolDir)
+		require.Equal(t, filepath.Join(dir, "App"), agvtoolDir)
+	}
+
+	t.Log("workspace: no project contains the scheme")
+	{
+		out, err := runXcodeBumpScript(t, dir, "App.xcworkspace", "Missing")
+		require.Error(t, err)
+		require.Contains(t, out, "No project of the workspace contains the scheme: Missing")
+	}
+}
@@ -0,0 +1,95 @@
+package xamarin
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	simulatorPlatform = "iPhoneSimulator"
+	arm64Arch         = "ARM64"
+)
+
+var (
+	propertyGroupRegexp       = regexp.MustCompile(`(?s)<PropertyGroup\s[^>]*Condition\s*=\s*"([^"]*)"[^>]*>(.*?)</PropertyGroup>`)
+	simulatorConditionRegexp  = regexp.MustCompile(`'\s*([^'|]+)\|` + simulatorPlatform + `\s*'`)
+	mtouchArchRegexp          = regexp.MustCompile(`<MtouchArch>(.*?)</MtouchArch>`)
+	mtouchArchSeparatorRegexp = regexp.MustCompile(`[,;\s]+`)
+)
+
+// simulatorArchs returns the architectures (MtouchArch) of the Xamarin.iOS project's iPhoneSimulator build configurations,
+// by configuration name. The configurations without MtouchArch are not listed.
+func simulatorArchs(projectContent string) map[string][]string {
+	configToArchs := map[string][]string{}
+	for _, group := range propertyGroupRegexp.FindAllStringSubmatch(projectContent, -1) {
+		condition := simulatorConditionRegexp.FindStringSubmatch(group[1])
+		if len(condition) != 2 {
+			continue
+		}
+		arch := mtouchArchRegexp.FindStringSubmatch(group[2])
+		if len(arch) != 2 {
+			continue
+		}
+
+		archs := []string{}
+		for _, a := range mtouchArchSeparatorRegexp.Split(strings.TrimSpace(arch[1]), -1) {
+			if a != "" {
+				archs = append(archs, strings.ToUpper(a))
+			}
+		}
+		configToArchs[strings.TrimSpace(condition[1])] = archs
+	}
+	return configToArchs
+}
+
+// inspectSimulatorArchs checks the simulator architectures of the solution's Xamarin.iOS projects.
+// The simulator builds without ARM64 need explicit architecture handling (Rosetta) on Apple Silicon stacks,
+// they are reported with a warning.
+func inspectSimulatorArchs(projects []SolutionProject) (models.Summary, models.Warnings) {
+	summary := models.Summary{}
+	warnings := models.Warnings{}
+
+	for _, project := range projects {
+		if !sliceutil.IsStringInSlice(iosProjectType, project.ProjectTypes) {
+			continue
+		}
+
+		content, err := fileutil.ReadStringFromFile(project.Pth)
+		if err != nil {
+			warning := fmt.Sprintf("Failed to read project (%s), error: %s", project.Pth, err)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+			continue
+		}
+
+		configToArchs := simulatorArchs(content)
+		configs := []string{}
+		for config := range configToArchs {
+			configs = append(configs, config)
+		}
+		sort.Strings(configs)
+
+		for _, config := range configs {
+			archs := configToArchs[config]
+			summary = append(summary, fmt.Sprintf("%s: %s|%s architectures: %s", project.Pth, config, simulatorPlatform, strings.Join(archs, ", ")))
+
+			if sliceutil.IsStringInSlice(arm64Arch, archs) {
+				continue
+			}
+
+			warning := fmt.Sprintf("The %s|%s build of %s targets only %s, the simulator build needs Rosetta on Apple Silicon stacks, add %s to its MtouchArch to build natively.",
+				config, simulatorPlatform, project.Pth, strings.Join(archs, ", "), arm64Arch)
+			warnings = append(warnings, warning)
+			log.TWarnf(warning)
+		}
+	}
+
+	return summary, warnings
+}
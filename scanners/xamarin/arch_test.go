@@ -0,0 +1,53 @@
+package xamarin
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+const testSimulatorArchProjectContent = `<Project>
+  <PropertyGroup>
+    <ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+  </PropertyGroup>
+  <PropertyGroup Condition=" '$(Configuration)|$(Platform)' == 'Debug|iPhoneSimulator' ">
+    <MtouchArch>i386, x86_64</MtouchArch>
+  </PropertyGroup>
+  <PropertyGroup Condition=" '$(Configuration)|$(Platform)' == 'Release|iPhoneSimulator' ">
+    <MtouchArch>x86_64;arm64</MtouchArch>
+  </PropertyGroup>
+  <PropertyGroup Condition=" '$(Configuration)|$(Platform)' == 'Release|iPhone' ">
+    <MtouchArch>ARM64</MtouchArch>
+  </PropertyGroup>
+</Project>`
+
+func TestSimulatorArchs(t *testing.T) {
+	require.Equal(t, map[string][]string{
+		"Debug":   {"I386", "X86_64"},
+		"Release": {"X86_64", "ARM64"},
+	}, simulatorArchs(testSimulatorArchProjectContent))
+
+	require.Equal(t, map[string][]string{}, simulatorArchs(`<ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842}</ProjectTypeGuids>`))
+}
+
+func TestInspectSimulatorArchs(t *testing.T) {
+	tmpDir := writeFiles(t, map[string]string{
+		"App.iOS/App.iOS.csproj":     testSimulatorArchProjectContent,
+		"App.Droid/App.Droid.csproj": `<Project />`,
+	})
+	iosProjectPth := filepath.Join(tmpDir, "App.iOS/App.iOS.csproj")
+
+	summary, warnings := inspectSimulatorArchs([]SolutionProject{
+		{Name: "App.iOS", Pth: iosProjectPth, ProjectTypes: []string{iosProjectType}},
+		{Name: "App.Droid", Pth: filepath.Join(tmpDir, "App.Droid/App.Droid.csproj"), ProjectTypes: []string{androidProjectType}},
+	})
+	require.Equal(t, models.Summary{
+		iosProjectPth + ": Debug|iPhoneSimulator architectures: I386, X86_64",
+		iosProjectPth + ": Release|iPhoneSimulator architectures: X86_64, ARM64",
+	}, summary)
+	require.Equal(t, models.Warnings{
+		"The Debug|iPhoneSimulator build of " + iosProjectPth + " targets only I386, X86_64, the simulator build needs Rosetta on Apple Silicon stacks, add ARM64 to its MtouchArch to build natively.",
+	}, warnings)
+}
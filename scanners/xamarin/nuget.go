@@ -0,0 +1,134 @@
+package xamarin
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-core/bitrise-init/utility"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+const (
+	packagesConfigFileName = "packages.config"
+	packagesLockFileName   = "packages.lock.json"
+	// compared case insensitively, NuGet accepts NuGet.config, nuget.config and NuGet.Config too
+	nugetConfigFileName = "nuget.config"
+
+	// packages.config restore downloads the packages next to the solution
+	legacyPackagesDirName = "packages"
+	// PackageReference restore uses the global packages folder
+	globalPackagesPth = "$HOME/.nuget/packages"
+)
+
+const cachePathsInputKey = "cache_paths"
+
+var packageReferenceRegexp = regexp.MustCompile(`<PackageReference\s`)
+
+// NuGetUsage describes how the projects of the scanned directory depend on NuGet packages.
+type NuGetUsage struct {
+	// PackagesConfigPths are the packages.config files of the legacy restore, the packages are restored by the nuget-restore step.
+	PackagesConfigPths []string
+	// PackageReferenceProjectPths are the project files declaring PackageReference items, the packages are restored by the build.
+	PackageReferenceProjectPths []string
+	LockFilePths                []string
+	ConfigPths                  []string
+}
+
+// DetectNuGetUsage inspects the given files for the NuGet package restore styles and configuration files.
+func DetectNuGetUsage(fileList []string) (NuGetUsage, error) {
+	usage := NuGetUsage{}
+
+	files, err := utility.FilterPaths(fileList, forbidComponentsSolutionFilter, forbidNodeModulesDirComponentFilter)
+	if err != nil {
+		return NuGetUsage{}, err
+	}
+
+	for _, file := range files {
+		baseName := filepath.Base(file)
+		switch {
+		case baseName == packagesConfigFileName:
+			usage.PackagesConfigPths = append(usage.PackagesConfigPths, file)
+		case baseName == packagesLockFileName:
+			usage.LockFilePths = append(usage.LockFilePths, file)
+		case strings.ToLower(baseName) == nugetConfigFileName:
+			usage.ConfigPths = append(usage.ConfigPths, file)
+		case sliceutil.IsStringInSlice(filepath.Ext(file), projectFileExtensions):
+			content, err := fileutil.ReadStringFromFile(file)
+			if err != nil {
+				return NuGetUsage{}, fmt.Errorf("failed to read project file (%s), error: %s", file, err)
+			}
+			if packageReferenceRegexp.MatchString(content) {
+				usage.PackageReferenceProjectPths = append(usage.PackageReferenceProjectPths, file)
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// HasPackagesConfig ...
+func (usage NuGetUsage) HasPackagesConfig() bool {
+	return len(usage.PackagesConfigPths) > 0
+}
+
+// HasPackageReferences ...
+func (usage NuGetUsage) HasPackageReferences() bool {
+	return len(usage.PackageReferenceProjectPths) > 0
+}
+
+// Summary describes the detected restore styles.
+func (usage NuGetUsage) Summary() models.Summary {
+	summary := models.Summary{}
+	if usage.HasPackagesConfig() {
+		summary = append(summary, fmt.Sprintf("NuGet: packages.config restore (%s), the packages are restored by the nuget-restore step", strings.Join(usage.PackagesConfigPths, ", ")))
+	}
+	if usage.HasPackageReferences() {
+		summary = append(summary, fmt.Sprintf("NuGet: PackageReference restore (%s), the packages are restored by the build", strings.Join(usage.PackageReferenceProjectPths, ", ")))
+	}
+	if len(usage.ConfigPths) > 0 {
+		summary = append(summary, fmt.Sprintf("NuGet: configuration files: %s", strings.Join(usage.ConfigPths, ", ")))
+	}
+	return summary
+}
+
+// CachePaths returns the cache-push step's cache_paths of the restored packages, in the: path -> indicator format.
+// The legacy packages folder of each solution with packages.config files is keyed on the first packages.config of the solution,
+// the global packages folder on the lock file (if any).
+func (usage NuGetUsage) CachePaths(solutionFiles []string) []string {
+	cachePaths := []string{}
+	for _, solutionFile := range solutionFiles {
+		solutionDir := filepath.Dir(solutionFile)
+		for _, packagesConfigPth := range usage.PackagesConfigPths {
+			if !isInDir(packagesConfigPth, solutionDir) {
+				continue
+			}
+
+			cachePath := filepath.Join(solutionDir, legacyPackagesDirName) + " -> " + packagesConfigPth
+			if !sliceutil.IsStringInSlice(cachePath, cachePaths) {
+				cachePaths = append(cachePaths, cachePath)
+			}
+			break
+		}
+	}
+	if usage.HasPackageReferences() {
+		cachePath := globalPackagesPth
+		if len(usage.LockFilePths) > 0 {
+			cachePath += " -> " + usage.LockFilePths[0]
+		}
+		cachePaths = append(cachePaths, cachePath)
+	}
+	return cachePaths
+}
+
+// isInDir returns true if the path is in the dir or in any of its subdirs.
+func isInDir(pth, dir string) bool {
+	rel, err := filepath.Rel(dir, pth)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, "../")
+}
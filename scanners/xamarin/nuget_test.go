@@ -0,0 +1,194 @@
+package xamarin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectNuGetUsage(t *testing.T) {
+	t.Log("packages.config restore")
+	{
+		dir := writeFiles(t, map[string]string{
+			"App.sln":                     testIosSolutionContent,
+			"App.iOS/App.iOS.csproj":      testPackagesConfigProjectContent,
+			"App.iOS/packages.config":     testPackagesConfigContent,
+			"NuGet.Config":                testNuGetConfigContent,
+			"Components/Lib/Lib.csproj":   testPackageReferenceProjectContent,
+			"node_modules/x/x.csproj":     testPackageReferenceProjectContent,
+			"node_modules/x/NuGet.config": testNuGetConfigContent,
+		})
+		fileList := listFiles(t, dir)
+
+		usage, err := DetectNuGetUsage(fileList)
+		require.NoError(t, err)
+		require.Equal(t, NuGetUsage{
+			PackagesConfigPths: []string{filepath.Join(dir, "App.iOS/packages.config")},
+			ConfigPths:         []string{filepath.Join(dir, "NuGet.Config")},
+		}, usage)
+
+		require.Equal(t, models.Summary{
+			"NuGet: packages.config restore (" + filepath.Join(dir, "App.iOS/packages.config") + "), the packages are restored by the nuget-restore step",
+			"NuGet: configuration files: " + filepath.Join(dir, "NuGet.Config"),
+		}, usage.Summary())
+		require.Equal(t, []string{filepath.Join(dir, "packages") + " -> " + filepath.Join(dir, "App.iOS/packages.config")}, usage.CachePaths([]string{filepath.Join(dir, "App.sln")}))
+	}
+
+	t.Log("PackageReference restore")
+	{
+		dir := writeFiles(t, map[string]string{
+			"App.sln":                    testIosSolutionContent,
+			"App.iOS/App.iOS.csproj":     testPackageReferenceProjectContent,
+			"App.iOS/packages.lock.json": "{}",
+		})
+		fileList := listFiles(t, dir)
+
+		usage, err := DetectNuGetUsage(fileList)
+		require.NoError(t, err)
+		require.Equal(t, NuGetUsage{
+			PackageReferenceProjectPths: []string{filepath.Join(dir, "App.iOS/App.iOS.csproj")},
+			LockFilePths:                []string{filepath.Join(dir, "App.iOS/packages.lock.json")},
+		}, usage)
+
+		require.Equal(t, models.Summary{
+			"NuGet: PackageReference restore (" + filepath.Join(dir, "App.iOS/App.iOS.csproj") + "), the packages are restored by the build",
+		}, usage.Summary())
+		require.Equal(t, []string{"$HOME/.nuget/packages -> " + filepath.Join(dir, "App.iOS/packages.lock.json")}, usage.CachePaths([]string{filepath.Join(dir, "App.sln")}))
+	}
+}
+
+func TestCachePaths(t *testing.T) {
+	t.Log("every solution with packages.config files")
+	{
+		usage := NuGetUsage{PackagesConfigPths: []string{"App/App.iOS/packages.config", "App/App.Droid/packages.config", "Lib/Lib/packages.config"}}
+		require.Equal(t, []string{
+			"App/packages -> App/App.iOS/packages.config",
+			"Lib/packages -> Lib/Lib/packages.config",
+		}, usage.CachePaths([]string{"App/App.sln", "Lib/Lib.sln", "Other/Other.sln"}))
+	}
+
+	t.Log("solutions in the same dir")
+	{
+		usage := NuGetUsage{PackagesConfigPths: []string{"App.iOS/packages.config"}}
+		require.Equal(t, []string{"packages -> App.iOS/packages.config"}, usage.CachePaths([]string{"App.sln", "App.Mac.sln"}))
+	}
+}
+
+func TestConfigsNuGetRestore(t *testing.T) {
+	t.Log("packages.config restore")
+	{
+		configs := scanConfigs(t, map[string]string{
+			"App.sln":                 testIosSolutionContent,
+			"App.iOS/App.iOS.csproj":  testPackagesConfigProjectContent,
+			"App.iOS/packages.config": testPackagesConfigContent,
+		})
+
+		config, ok := configs[configName(iosProjectType, true, false, false)]
+		require.True(t, ok)
+		require.Contains(t, config, "nuget-restore@")
+		require.Contains(t, config, "cache-pull@")
+		require.Contains(t, config, "cache_paths: packages -> App.iOS/packages.config")
+	}
+
+	t.Log("PackageReference restore")
+	{
+		configs := scanConfigs(t, map[string]string{
+			"App.sln":                    testIosSolutionContent,
+			"App.iOS/App.iOS.csproj":     testPackageReferenceProjectContent,
+			"App.iOS/packages.lock.json": "{}",
+		})
+
+		config, ok := configs[configName(iosProjectType, false, true, false)]
+		require.True(t, ok)
+		require.NotContains(t, config, "nuget-restore@")
+		require.Contains(t, config, "cache-pull@")
+		require.Contains(t, config, "cache_paths: $HOME/.nuget/packages -> App.iOS/packages.lock.json")
+	}
+}
+
+// scanConfigs runs the scanner on the given project files (in the project's directory, like the scanner runs) and returns the generated configs.
+func scanConfigs(t *testing.T, files map[string]string) models.BitriseConfigMap {
+	dir := writeFiles(t, files)
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	scanner := NewScanner()
+	detected, err := scanner.DetectPlatform(dir)
+	require.NoError(t, err)
+	require.True(t, detected)
+
+	_, _, err = scanner.Options()
+	require.NoError(t, err)
+
+	configs, err := scanner.Configs()
+	require.NoError(t, err)
+	return configs
+}
+
+func listFiles(t *testing.T, dir string) []string {
+	fileList := []string{}
+	require.NoError(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			fileList = append(fileList, path)
+		}
+		return err
+	}))
+	return fileList
+}
+
+const testIosSolutionContent = `
+Microsoft Visual Studio Solution File, Format Version 12.00
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "App.iOS", "App.iOS\App.iOS.csproj", "{6D0D1C0E-8B4B-4E3D-9B6C-1B9A9B1B0C01}"
+EndProject
+Global
+	GlobalSection(SolutionConfigurationPlatforms) = preSolution
+		Release|iPhone = Release|iPhone
+	EndGlobalSection
+EndGlobal
+`
+
+const testPackagesConfigProjectContent = `<?xml version="1.0" encoding="utf-8"?>
+<Project DefaultTargets="Build" ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+  </PropertyGroup>
+  <ItemGroup>
+    <Reference Include="Newtonsoft.Json">
+      <HintPath>..\packages\Newtonsoft.Json.12.0.1\lib\netstandard2.0\Newtonsoft.Json.dll</HintPath>
+    </Reference>
+  </ItemGroup>
+</Project>
+`
+
+const testPackageReferenceProjectContent = `<?xml version="1.0" encoding="utf-8"?>
+<Project DefaultTargets="Build" ToolsVersion="4.0" xmlns="http://schemas.microsoft.com/developer/msbuild/2003">
+  <PropertyGroup>
+    <ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="12.0.1" />
+  </ItemGroup>
+</Project>
+`
+
+const testPackagesConfigContent = `<?xml version="1.0" encoding="utf-8"?>
+<packages>
+  <package id="Newtonsoft.Json" version="12.0.1" targetFramework="xamarinios10" />
+</packages>
+`
+
+const testNuGetConfigContent = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <packageSources>
+    <add key="nuget.org" value="https://api.nuget.org/v3/index.json" />
+  </packageSources>
+</configuration>
+`
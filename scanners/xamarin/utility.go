@@ -2,10 +2,14 @@ package xamarin
 
 import (
 	"fmt"
+	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/bitrise-core/bitrise-init/utility"
 	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
 )
 
 const (
@@ -16,6 +20,36 @@ const (
 
 	solutionConfigurationStart = "GlobalSection(SolutionConfigurationPlatforms) = preSolution"
 	solutionConfigurationEnd   = "EndGlobalSection"
+
+	projectConfigurationStart = "GlobalSection(ProjectConfigurationPlatforms) = postSolution"
+)
+
+const (
+	iosProjectType     = "ios"
+	androidProjectType = "android"
+	macosProjectType   = "macos"
+)
+
+// projectTypes are the supported project types, in the order of the generated options and configs.
+var projectTypes = []string{iosProjectType, androidProjectType, macosProjectType}
+
+// projectTypeGUIDs maps the Xamarin project flavor GUIDs (ProjectTypeGuids of the project file) to the project types.
+var projectTypeGUIDs = map[string]string{
+	"FEACFBD2-3405-455C-9665-78FE426C6842": iosProjectType,     // Xamarin.iOS
+	"6BC8ED88-2882-458C-8E55-DFD12B67127B": iosProjectType,     // MonoTouch
+	"EFBA0AD7-5A72-4C68-AF49-83D382785DCF": androidProjectType, // Xamarin.Android
+	"A3F8F2AB-B479-4A4A-A458-A89E7DC349F1": macosProjectType,   // Xamarin.Mac
+	"42C0BBD9-55CE-4FC1-8D90-A7348ABAFB23": macosProjectType,   // Xamarin.Mac Classic
+}
+
+var projectFileExtensions = []string{".csproj", ".fsproj", ".vbproj"}
+
+var (
+	solutionProjectRegexp      = regexp.MustCompile(`^Project\("\{[^}]+\}"\)\s*=\s*"([^"]*)"\s*,\s*"([^"]*)"\s*,\s*"\{([^}]+)\}"`)
+	projectConfigurationRegexp = regexp.MustCompile(`^\{([^}]+)\}\.(.+)\.Build\.0\s*=`)
+
+	projectTypeGUIDsRegexp = regexp.MustCompile(`(?s)<ProjectTypeGuids>(.*?)</ProjectTypeGuids>`)
+	targetFrameworksRegexp = regexp.MustCompile(`(?s)<TargetFrameworks?>(.*?)</TargetFrameworks?>`)
 )
 
 var allowSolutionExtensionFilter = utility.ExtensionFilter(solutionExtension, true)
@@ -80,3 +114,142 @@ func GetSolutionConfigs(solutionFile string) (map[string][]string, error) {
 
 	return configMap, nil
 }
+
+// SolutionProject is a project referenced by a solution file.
+type SolutionProject struct {
+	Name string
+	Pth  string
+	ID   string
+	// ProjectTypes are the detected Xamarin project types (ios, android, macos), empty for the shared and test projects.
+	ProjectTypes []string
+	// BuildConfigurations are the solution configurations (like Release|iPhone) building the project.
+	BuildConfigurations []string
+}
+
+// GetSolutionProjects returns the projects of the solution, classified by their project files.
+func GetSolutionProjects(solutionFile string) ([]SolutionProject, error) {
+	content, err := fileutil.ReadStringFromFile(solutionFile)
+	if err != nil {
+		return []SolutionProject{}, err
+	}
+
+	projects := []SolutionProject{}
+	idToBuildConfigurations := map[string][]string{}
+	isProjectConfigurationLine := false
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if match := solutionProjectRegexp.FindStringSubmatch(line); len(match) == 4 {
+			pth := strings.Replace(match[2], "\\", "/", -1)
+			if !sliceutil.IsStringInSlice(filepath.Ext(pth), projectFileExtensions) {
+				// solution folder
+				continue
+			}
+
+			projects = append(projects, SolutionProject{
+				Name: match[1],
+				Pth:  filepath.Join(filepath.Dir(solutionFile), pth),
+				ID:   strings.ToUpper(match[3]),
+			})
+			continue
+		}
+
+		if strings.Contains(line, projectConfigurationStart) {
+			isProjectConfigurationLine = true
+			continue
+		}
+
+		if strings.Contains(line, solutionConfigurationEnd) {
+			isProjectConfigurationLine = false
+			continue
+		}
+
+		if isProjectConfigurationLine {
+			if match := projectConfigurationRegexp.FindStringSubmatch(line); len(match) == 3 {
+				id := strings.ToUpper(match[1])
+				idToBuildConfigurations[id] = append(idToBuildConfigurations[id], match[2])
+			}
+		}
+	}
+
+	for i, project := range projects {
+		projects[i].BuildConfigurations = idToBuildConfigurations[project.ID]
+
+		if exist, err := pathutil.IsPathExists(project.Pth); err != nil {
+			return []SolutionProject{}, err
+		} else if !exist {
+			continue
+		}
+
+		projectContent, err := fileutil.ReadStringFromFile(project.Pth)
+		if err != nil {
+			return []SolutionProject{}, err
+		}
+		projects[i].ProjectTypes = getProjectTypes(projectContent)
+	}
+
+	return projects, nil
+}
+
+// getProjectTypes returns the Xamarin project types of the project file,
+// based on the ProjectTypeGuids of the classic, or the TargetFramework(s) of the SDK-style project files.
+func getProjectTypes(projectContent string) []string {
+	types := []string{}
+	addType := func(projectType string) {
+		if !sliceutil.IsStringInSlice(projectType, types) {
+			types = append(types, projectType)
+		}
+	}
+
+	if match := projectTypeGUIDsRegexp.FindStringSubmatch(projectContent); len(match) == 2 {
+		for _, guid := range strings.Split(match[1], ";") {
+			guid = strings.ToUpper(strings.Trim(strings.TrimSpace(guid), "{}"))
+			if projectType, ok := projectTypeGUIDs[guid]; ok {
+				addType(projectType)
+			}
+		}
+	}
+
+	for _, match := range targetFrameworksRegexp.FindAllStringSubmatch(projectContent, -1) {
+		for _, framework := range strings.Split(match[1], ";") {
+			framework = strings.ToLower(strings.TrimSpace(framework))
+			switch {
+			case strings.HasPrefix(framework, "xamarin.ios") || strings.HasPrefix(framework, "xamarinios") || strings.HasSuffix(framework, "-ios"):
+				addType(iosProjectType)
+			case strings.HasPrefix(framework, "monoandroid") || strings.HasSuffix(framework, "-android"):
+				addType(androidProjectType)
+			case strings.HasPrefix(framework, "xamarin.mac") || strings.HasPrefix(framework, "xamarinmac") || strings.HasSuffix(framework, "-macos"):
+				addType(macosProjectType)
+			}
+		}
+	}
+
+	return types
+}
+
+// projectTypeConfigs returns the solution configurations (configuration -> platforms) by project type,
+// a configuration is listed for a project type, if it builds a project of the given type.
+func projectTypeConfigs(configs map[string][]string, projects []SolutionProject) map[string]map[string][]string {
+	typeToConfigs := map[string]map[string][]string{}
+	for _, project := range projects {
+		for _, projectType := range project.ProjectTypes {
+			if _, ok := typeToConfigs[projectType]; !ok {
+				typeToConfigs[projectType] = map[string][]string{}
+			}
+
+			for config, platforms := range configs {
+				for _, platform := range platforms {
+					configuration := config + "|" + platform
+					if len(project.BuildConfigurations) > 0 && !sliceutil.IsStringInSlice(configuration, project.BuildConfigurations) {
+						continue
+					}
+					if !sliceutil.IsStringInSlice(platform, typeToConfigs[projectType][config]) {
+						typeToConfigs[projectType][config] = append(typeToConfigs[projectType][config], platform)
+					}
+				}
+			}
+		}
+	}
+	return typeToConfigs
+}
@@ -3,16 +3,17 @@ package xamarin
 import (
 	"errors"
 	"fmt"
-	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/bitrise-core/bitrise-init/log"
 	"github.com/bitrise-core/bitrise-init/models"
 	"github.com/bitrise-core/bitrise-init/steps"
 	"github.com/bitrise-core/bitrise-init/utility"
 	envmanModels "github.com/bitrise-io/envman/models"
-	"github.com/bitrise-io/go-utils/log"
 )
 
 const scannerName = "xamarin"
@@ -27,6 +28,12 @@ const (
 	xamarinSolutionInputTitle  = "Path to the Xamarin Solution file"
 )
 
+const (
+	xamarinProjectTypeInputKey    = "project_type_whitelist"
+	xamarinProjectTypeInputEnvKey = "BITRISE_XAMARIN_PROJECT_TYPE"
+	xamarinProjectTypeInputTitle  = "Xamarin project type"
+)
+
 const (
 	xamarinConfigurationInputKey    = "xamarin_configuration"
 	xamarinConfigurationInputEnvKey = "BITRISE_XAMARIN_CONFIGURATION"
@@ -45,11 +52,14 @@ const (
 	xamarinMacLicenseInputKey     = "xamarin_mac_license"
 )
 
-func configName(hasNugetPackages, hasXamarinComponents bool) string {
-	name := "xamarin-"
+func configName(projectType string, hasNugetPackages, hasPackageReferences, hasXamarinComponents bool) string {
+	name := "xamarin-" + projectType + "-"
 	if hasNugetPackages {
 		name = name + "nuget-"
 	}
+	if hasPackageReferences {
+		name = name + "packagereference-"
+	}
 	if hasXamarinComponents {
 		name = name + "components-"
 	}
@@ -66,11 +76,15 @@ type Scanner struct {
 	SolutionFiles []string
 
 	HasNugetPackages     bool
+	HasPackageReferences bool
 	HasXamarinComponents bool
 
 	HasIosProject     bool
 	HasAndroidProject bool
 	HasMacProject     bool
+
+	cachePaths []string
+	summary    models.Summary
 }
 
 // NewScanner ...
@@ -126,16 +140,17 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	log.TInfof("Searching for NuGet packages & Xamarin Components")
 
 	warnings := models.Warnings{}
+	scanner.summary = models.Summary{}
 
-	for _, file := range scanner.FileList {
-		// Search for nuget packages
-		if !scanner.HasNugetPackages {
-			baseName := filepath.Base(file)
-			if baseName == "packages.config" {
-				scanner.HasNugetPackages = true
-			}
-		}
+	nugetUsage, err := DetectNuGetUsage(scanner.FileList)
+	if err != nil {
+		return models.OptionNode{}, warnings, fmt.Errorf("failed to search for NuGet packages, error: %s", err)
+	}
+	scanner.HasNugetPackages = nugetUsage.HasPackagesConfig()
+	scanner.HasPackageReferences = nugetUsage.HasPackageReferences()
+	scanner.summary = append(scanner.summary, nugetUsage.Summary()...)
 
+	for _, file := range scanner.FileList {
 		// If adding a component:
 		// /Components/[COMPONENT_NAME]/ dir added
 		// ItemGroup/XamarinComponentReference added to the project
@@ -147,14 +162,18 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 			}
 		}
 
-		if scanner.HasNugetPackages && scanner.HasXamarinComponents {
+		if scanner.HasXamarinComponents {
 			break
 		}
 	}
 
 	if scanner.HasNugetPackages {
-		log.TPrintf("Nuget packages found")
-	} else {
+		log.TPrintf("Nuget packages found (packages.config)")
+	}
+	if scanner.HasPackageReferences {
+		log.TPrintf("Nuget packages found (PackageReference)")
+	}
+	if !scanner.HasNugetPackages && !scanner.HasPackageReferences {
 		log.TPrintf("NO Nuget packages found")
 	}
 
@@ -165,7 +184,7 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	}
 
 	// Check for solution configs
-	validSolutionMap := map[string]map[string][]string{}
+	validSolutionMap := map[string]map[string]map[string][]string{}
 	for _, solutionFile := range scanner.SolutionFiles {
 		log.TInfof("Inspecting solution file: %s", solutionFile)
 
@@ -176,17 +195,53 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 			continue
 		}
 
-		if len(configs) > 0 {
-			log.TPrintf("%d configurations found", len(configs))
-			for config, platforms := range configs {
-				log.TPrintf("- %s with platforms: %v", config, platforms)
-			}
-
-			validSolutionMap[solutionFile] = configs
-		} else {
+		if len(configs) == 0 {
 			log.TWarnf("No config found for %s", solutionFile)
 			warnings = append(warnings, fmt.Sprintf("No configs found for solution: %s", solutionFile))
+			continue
 		}
+
+		log.TPrintf("%d configurations found", len(configs))
+		for config, platforms := range configs {
+			log.TPrintf("- %s with platforms: %v", config, platforms)
+		}
+
+		projects, err := GetSolutionProjects(solutionFile)
+		if err != nil {
+			log.TWarnf("Failed to get solution projects, error: %s", err)
+			warnings = append(warnings, fmt.Sprintf("Failed to get solution (%s) projects, error: %s", solutionFile, err))
+			continue
+		}
+
+		archSummary, archWarnings := inspectSimulatorArchs(projects)
+		scanner.summary = append(scanner.summary, archSummary...)
+		warnings = append(warnings, archWarnings...)
+
+		typeToConfigs := projectTypeConfigs(configs, projects)
+		for _, projectType := range projectTypes {
+			if len(typeToConfigs[projectType]) == 0 {
+				delete(typeToConfigs, projectType)
+				continue
+			}
+
+			log.TPrintf("%s project found", projectType)
+			switch projectType {
+			case iosProjectType:
+				scanner.HasIosProject = true
+			case androidProjectType:
+				scanner.HasAndroidProject = true
+			case macosProjectType:
+				scanner.HasMacProject = true
+			}
+		}
+
+		if len(typeToConfigs) == 0 {
+			log.TWarnf("No Xamarin iOS, Android or Mac project found in %s", solutionFile)
+			warnings = append(warnings, fmt.Sprintf("No Xamarin iOS, Android or Mac project found in solution: %s", solutionFile))
+			continue
+		}
+
+		validSolutionMap[solutionFile] = typeToConfigs
 	}
 
 	if len(validSolutionMap) == 0 {
@@ -194,20 +249,32 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 		return models.OptionNode{}, warnings, errors.New("No valid solution file found")
 	}
 
+	solutionFiles := []string{}
+	for solutionFile := range validSolutionMap {
+		solutionFiles = append(solutionFiles, solutionFile)
+	}
+	sort.Strings(solutionFiles)
+	scanner.cachePaths = nugetUsage.CachePaths(solutionFiles)
+
 	// Check for solution projects
 	xamarinSolutionOption := models.NewOption(xamarinSolutionInputTitle, xamarinSolutionInputEnvKey)
 
-	for solutionFile, configMap := range validSolutionMap {
-		xamarinConfigurationOption := models.NewOption(xamarinConfigurationInputTitle, xamarinConfigurationInputEnvKey)
-		xamarinSolutionOption.AddOption(solutionFile, xamarinConfigurationOption)
+	for solutionFile, typeToConfigs := range validSolutionMap {
+		xamarinProjectTypeOption := models.NewOption(xamarinProjectTypeInputTitle, xamarinProjectTypeInputEnvKey)
+		xamarinSolutionOption.AddOption(solutionFile, xamarinProjectTypeOption)
 
-		for config, platforms := range configMap {
-			xamarinPlatformOption := models.NewOption(xamarinPlatformInputTitle, xamarinPlatformInputEnvKey)
-			xamarinConfigurationOption.AddOption(config, xamarinPlatformOption)
+		for projectType, configMap := range typeToConfigs {
+			xamarinConfigurationOption := models.NewOption(xamarinConfigurationInputTitle, xamarinConfigurationInputEnvKey)
+			xamarinProjectTypeOption.AddOption(projectType, xamarinConfigurationOption)
 
-			for _, platform := range platforms {
-				configOption := models.NewConfigOption(configName(scanner.HasNugetPackages, scanner.HasXamarinComponents))
-				xamarinPlatformOption.AddConfig(platform, configOption)
+			for config, platforms := range configMap {
+				xamarinPlatformOption := models.NewOption(xamarinPlatformInputTitle, xamarinPlatformInputEnvKey)
+				xamarinConfigurationOption.AddOption(config, xamarinPlatformOption)
+
+				for _, platform := range platforms {
+					configOption := models.NewConfigOption(configName(projectType, scanner.HasNugetPackages, scanner.HasPackageReferences, scanner.HasXamarinComponents))
+					xamarinPlatformOption.AddConfig(platform, configOption)
+				}
 			}
 		}
 	}
@@ -215,6 +282,23 @@ func (scanner *Scanner) Options() (models.OptionNode, models.Warnings, error) {
 	return *xamarinSolutionOption, warnings, nil
 }
 
+func (scanner *Scanner) hasProject(projectType string) bool {
+	switch projectType {
+	case iosProjectType:
+		return scanner.HasIosProject
+	case androidProjectType:
+		return scanner.HasAndroidProject
+	case macosProjectType:
+		return scanner.HasMacProject
+	}
+	return false
+}
+
+// Summary ...
+func (scanner *Scanner) Summary() models.Summary {
+	return scanner.summary
+}
+
 // DefaultOptions ...
 func (Scanner) DefaultOptions() models.OptionNode {
 	xamarinSolutionOption := models.NewOption(xamarinSolutionInputTitle, xamarinSolutionInputEnvKey)
@@ -233,59 +317,69 @@ func (Scanner) DefaultOptions() models.OptionNode {
 
 // Configs ...
 func (scanner *Scanner) Configs() (models.BitriseConfigMap, error) {
-	configBuilder := models.NewDefaultConfigBuilder()
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(false)...)
+	bitriseDataMap := models.BitriseConfigMap{}
+	for _, projectType := range projectTypes {
+		if !scanner.hasProject(projectType) {
+			continue
+		}
 
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
+		configBuilder := models.NewDefaultConfigBuilder()
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultPrepareStepList(len(scanner.cachePaths) > 0)...)
 
-	// XamarinUserManagement
-	if scanner.HasXamarinComponents {
-		inputs := []envmanModels.EnvironmentItemModel{}
-		if scanner.HasIosProject {
-			inputs = append(inputs, envmanModels.EnvironmentItemModel{xamarinIosLicenceInputKey: "yes"})
-		}
-		if scanner.HasAndroidProject {
-			inputs = append(inputs, envmanModels.EnvironmentItemModel{xamarinAndroidLicenceInputKey: "yes"})
-		}
-		if scanner.HasMacProject {
-			inputs = append(inputs, envmanModels.EnvironmentItemModel{xamarinMacLicenseInputKey: "yes"})
+		if projectType != androidProjectType {
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CertificateAndProfileInstallerStepListItem())
 		}
 
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinUserManagementStepListItem(inputs...))
-	}
+		// XamarinUserManagement
+		if scanner.HasXamarinComponents {
+			licenseInputKey := map[string]string{
+				iosProjectType:     xamarinIosLicenceInputKey,
+				androidProjectType: xamarinAndroidLicenceInputKey,
+				macosProjectType:   xamarinMacLicenseInputKey,
+			}[projectType]
 
-	// NugetRestore
-	if scanner.HasNugetPackages {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NugetRestoreStepListItem())
-	}
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinUserManagementStepListItem(envmanModels.EnvironmentItemModel{licenseInputKey: "yes"}))
+		}
 
-	// XamarinComponentsRestore
-	if scanner.HasXamarinComponents {
-		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinComponentsRestoreStepListItem())
-	}
+		// NugetRestore, the PackageReference packages are restored by the build
+		if scanner.HasNugetPackages {
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.NugetRestoreStepListItem())
+		}
 
-	// XamarinArchive
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinArchiveStepListItem(
-		envmanModels.EnvironmentItemModel{xamarinSolutionInputKey: "$" + xamarinSolutionInputEnvKey},
-		envmanModels.EnvironmentItemModel{xamarinConfigurationInputKey: "$" + xamarinConfigurationInputEnvKey},
-		envmanModels.EnvironmentItemModel{xamarinPlatformInputKey: "$" + xamarinPlatformInputEnvKey},
-	))
+		// XamarinComponentsRestore
+		if scanner.HasXamarinComponents {
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinComponentsRestoreStepListItem())
+		}
 
-	configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+		// XamarinArchive
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.XamarinArchiveStepListItem(
+			envmanModels.EnvironmentItemModel{xamarinSolutionInputKey: "$" + xamarinSolutionInputEnvKey},
+			envmanModels.EnvironmentItemModel{xamarinConfigurationInputKey: "$" + xamarinConfigurationInputEnvKey},
+			envmanModels.EnvironmentItemModel{xamarinPlatformInputKey: "$" + xamarinPlatformInputEnvKey},
+			envmanModels.EnvironmentItemModel{xamarinProjectTypeInputKey: "$" + xamarinProjectTypeInputEnvKey},
+		))
+
+		configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.DefaultDeployStepList(false)...)
+		if len(scanner.cachePaths) > 0 {
+			configBuilder.AppendStepListItemsTo(models.PrimaryWorkflowID, steps.CachePushStepListItem(
+				envmanModels.EnvironmentItemModel{cachePathsInputKey: strings.Join(scanner.cachePaths, "\n")},
+			))
+		}
 
-	config, err := configBuilder.Generate(scannerName)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
-	}
+		config, err := configBuilder.Generate(scannerName)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		return models.BitriseConfigMap{}, err
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return models.BitriseConfigMap{}, err
+		}
+
+		bitriseDataMap[configName(projectType, scanner.HasNugetPackages, scanner.HasPackageReferences, scanner.HasXamarinComponents)] = string(data)
 	}
 
-	return models.BitriseConfigMap{
-		configName(scanner.HasNugetPackages, scanner.HasXamarinComponents): string(data),
-	}, nil
+	return bitriseDataMap, nil
 }
 
 // DefaultConfigs ...
@@ -1,8 +1,12 @@
 package xamarin
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -36,3 +40,149 @@ func TestFilterSolutionFiles(t *testing.T) {
 		require.Equal(t, 0, len(files))
 	}
 }
+
+func TestGetSolutionProjects(t *testing.T) {
+	solutionFile := writeMixedSolution(t)
+
+	projects, err := GetSolutionProjects(solutionFile)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(projects))
+
+	require.Equal(t, "XamarinSample.iOS", projects[0].Name)
+	require.Equal(t, filepath.Join(filepath.Dir(solutionFile), "XamarinSample.iOS/XamarinSample.iOS.csproj"), projects[0].Pth)
+	require.Equal(t, []string{iosProjectType}, projects[0].ProjectTypes)
+	require.Equal(t, []string{"Debug|iPhoneSimulator", "Release|iPhone"}, projects[0].BuildConfigurations)
+
+	require.Equal(t, []string{androidProjectType}, projects[1].ProjectTypes)
+	require.Equal(t, []string{macosProjectType}, projects[2].ProjectTypes)
+	require.Equal(t, []string{}, projects[3].ProjectTypes)
+}
+
+func TestGetProjectTypes(t *testing.T) {
+	require.Equal(t, []string{iosProjectType}, getProjectTypes(`<ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>`))
+	require.Equal(t, []string{androidProjectType}, getProjectTypes(`<TargetFramework>monoandroid90</TargetFramework>`))
+	require.Equal(t, []string{androidProjectType, iosProjectType, macosProjectType}, getProjectTypes(`<TargetFrameworks>net6.0-android;net6.0-ios;net6.0-macos</TargetFrameworks>`))
+	require.Equal(t, []string{}, getProjectTypes(`<TargetFramework>netstandard2.0</TargetFramework>`))
+}
+
+func TestOptions(t *testing.T) {
+	dir := filepath.Dir(writeMixedSolution(t))
+
+	// the scanner runs in the search dir
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	defer func() {
+		require.NoError(t, os.Chdir(wd))
+	}()
+
+	scanner := NewScanner()
+	detected, err := scanner.DetectPlatform(dir)
+	require.NoError(t, err)
+	require.True(t, detected)
+
+	options, warnings, err := scanner.Options()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(warnings))
+	require.True(t, scanner.HasIosProject)
+	require.True(t, scanner.HasAndroidProject)
+	require.True(t, scanner.HasMacProject)
+
+	projectTypeOption := options.ChildOptionMap["XamarinSample.sln"]
+	require.NotNil(t, projectTypeOption)
+	require.Equal(t, xamarinProjectTypeInputEnvKey, projectTypeOption.EnvKey)
+	require.Equal(t, 3, len(projectTypeOption.ChildOptionMap))
+
+	requirePlatforms := func(projectType, config string, platforms ...string) {
+		configurationOption := projectTypeOption.ChildOptionMap[projectType]
+		require.NotNil(t, configurationOption, projectType)
+		require.Equal(t, xamarinConfigurationInputEnvKey, configurationOption.EnvKey)
+
+		platformOption := configurationOption.ChildOptionMap[config]
+		require.NotNil(t, platformOption, projectType+" "+config)
+		require.Equal(t, xamarinPlatformInputEnvKey, platformOption.EnvKey)
+		require.Equal(t, len(platforms), len(platformOption.ChildOptionMap), projectType+" "+config)
+		for _, platform := range platforms {
+			require.Equal(t, configName(projectType, false, false, false), platformOption.ChildOptionMap[platform].Config)
+		}
+	}
+
+	requirePlatforms(iosProjectType, "Debug", "iPhoneSimulator")
+	requirePlatforms(iosProjectType, "Release", "iPhone")
+	requirePlatforms(androidProjectType, "Debug", "Any CPU")
+	requirePlatforms(androidProjectType, "Release", "Any CPU")
+	require.Equal(t, 1, len(projectTypeOption.ChildOptionMap[macosProjectType].ChildOptionMap))
+	requirePlatforms(macosProjectType, "Release", "Any CPU")
+
+	configs, err := scanner.Configs()
+	require.NoError(t, err)
+	require.Equal(t, 3, len(configs))
+	require.Contains(t, configs[configName(iosProjectType, false, false, false)], "certificate-and-profile-installer")
+	require.NotContains(t, configs[configName(androidProjectType, false, false, false)], "certificate-and-profile-installer")
+	require.Contains(t, configs[configName(macosProjectType, false, false, false)], "project_type_whitelist: $BITRISE_XAMARIN_PROJECT_TYPE")
+}
+
+// writeMixedSolution writes a solution with an iOS, an Android, a Mac and a shared project and returns the solution's path.
+func writeMixedSolution(t *testing.T) string {
+	tmpDir := writeFiles(t, map[string]string{
+		"XamarinSample.sln":                              testMixedSolutionContent,
+		"XamarinSample.iOS/XamarinSample.iOS.csproj":     `<ProjectTypeGuids>{FEACFBD2-3405-455C-9665-78FE426C6842};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>`,
+		"XamarinSample.Droid/XamarinSample.Droid.csproj": `<ProjectTypeGuids>{EFBA0AD7-5A72-4C68-AF49-83D382785DCF};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>`,
+		"XamarinSample.Mac/XamarinSample.Mac.csproj":     `<ProjectTypeGuids>{A3F8F2AB-B479-4A4A-A458-A89E7DC349F1};{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}</ProjectTypeGuids>`,
+		"XamarinSample.Core/XamarinSample.Core.csproj":   `<TargetFramework>netstandard2.0</TargetFramework>`,
+	})
+
+	return filepath.Join(tmpDir, "XamarinSample.sln")
+}
+
+// writeFiles writes the given files (by relative path) into a new temporary directory and returns the directory.
+func writeFiles(t *testing.T, files map[string]string) string {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__xamarin__")
+	require.NoError(t, err)
+
+	for pth, content := range files {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	return tmpDir
+}
+
+const testMixedSolutionContent = `
+Microsoft Visual Studio Solution File, Format Version 12.00
+# Visual Studio 15
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "XamarinSample.iOS", "XamarinSample.iOS\XamarinSample.iOS.csproj", "{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A01}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "XamarinSample.Droid", "XamarinSample.Droid\XamarinSample.Droid.csproj", "{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A02}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "XamarinSample.Mac", "XamarinSample.Mac\XamarinSample.Mac.csproj", "{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A03}"
+EndProject
+Project("{2150E333-8FDC-42A3-9474-1A3956D46DE8}") = "Shared", "Shared", "{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A04}"
+EndProject
+Project("{FAE04EC0-301F-11D3-BF4B-00C04F79EFBC}") = "XamarinSample.Core", "XamarinSample.Core\XamarinSample.Core.csproj", "{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A05}"
+EndProject
+Global
+	GlobalSection(SolutionConfigurationPlatforms) = preSolution
+		Debug|Any CPU = Debug|Any CPU
+		Release|Any CPU = Release|Any CPU
+		Debug|iPhoneSimulator = Debug|iPhoneSimulator
+		Release|iPhone = Release|iPhone
+	EndGlobalSection
+	GlobalSection(ProjectConfigurationPlatforms) = postSolution
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A01}.Debug|Any CPU.ActiveCfg = Debug|iPhoneSimulator
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A01}.Debug|iPhoneSimulator.ActiveCfg = Debug|iPhoneSimulator
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A01}.Debug|iPhoneSimulator.Build.0 = Debug|iPhoneSimulator
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A01}.Release|iPhone.ActiveCfg = Release|iPhone
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A01}.Release|iPhone.Build.0 = Release|iPhone
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A02}.Debug|Any CPU.ActiveCfg = Debug|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A02}.Debug|Any CPU.Build.0 = Debug|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A02}.Release|Any CPU.ActiveCfg = Release|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A02}.Release|Any CPU.Build.0 = Release|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A03}.Release|Any CPU.ActiveCfg = Release|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A03}.Release|Any CPU.Build.0 = Release|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A05}.Debug|Any CPU.ActiveCfg = Debug|Any CPU
+		{0B0B5A3E-1C52-4C1D-8C39-7C4E4D1E0A05}.Debug|Any CPU.Build.0 = Debug|Any CPU
+	EndGlobalSection
+EndGlobal
+`
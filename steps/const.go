@@ -68,6 +68,14 @@ const (
 	DeployToBitriseIoID = "deploy-to-bitrise-io"
 	// DeployToBitriseIoVersion ...
 	DeployToBitriseIoVersion = "1.3.19"
+	// DeployToBitriseIoDeployPathInputKey ...
+	DeployToBitriseIoDeployPathInputKey = "deploy_path"
+	// DeployToBitriseIoIsCompressInputKey ...
+	DeployToBitriseIoIsCompressInputKey = "is_compress"
+	// DeployArtifactsTitle ...
+	DeployArtifactsTitle = "Deploy build artifacts"
+	// DeployTestReportsTitle ...
+	DeployTestReportsTitle = "Deploy test reports"
 )
 
 const (
@@ -93,6 +101,13 @@ const (
 	InstallMissingAndroidToolsVersion = "2.3.5"
 )
 
+const (
+	// GradleRunnerID ...
+	GradleRunnerID = "gradle-runner"
+	// GradleRunnerVersion ...
+	GradleRunnerVersion = "1.8.3"
+)
+
 const (
 	// FastlaneID ...
 	FastlaneID = "fastlane"
@@ -260,3 +275,31 @@ const (
 	// FlutterBuildVersion ...
 	FlutterBuildVersion = "0.9.2"
 )
+
+const (
+	// AVDManagerID ...
+	AVDManagerID = "avd-manager"
+	// AVDManagerVersion ...
+	AVDManagerVersion = "1.0.1"
+)
+
+const (
+	// WaitForAndroidEmulatorID ...
+	WaitForAndroidEmulatorID = "wait-for-android-emulator"
+	// WaitForAndroidEmulatorVersion ...
+	WaitForAndroidEmulatorVersion = "1.0.4"
+)
+
+const (
+	// AndroidBuildForUITestingID ...
+	AndroidBuildForUITestingID = "android-build-for-ui-testing"
+	// AndroidBuildForUITestingVersion ...
+	AndroidBuildForUITestingVersion = "0.1.5"
+)
+
+const (
+	// VirtualDeviceTestingForAndroidID ...
+	VirtualDeviceTestingForAndroidID = "virtual-device-testing-for-android"
+	// VirtualDeviceTestingForAndroidVersion ...
+	VirtualDeviceTestingForAndroidVersion = "1.0.6"
+)
@@ -96,9 +96,9 @@ func CachePullStepListItem() bitriseModels.StepListItemModel {
 }
 
 // CachePushStepListItem ...
-func CachePushStepListItem() bitriseModels.StepListItemModel {
+func CachePushStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(CachePushID, CachePushVersion)
-	return stepListItem(stepIDComposite, "", "")
+	return stepListItem(stepIDComposite, "", "", inputs...)
 }
 
 // CertificateAndProfileInstallerStepListItem ...
@@ -119,6 +119,29 @@ func DeployToBitriseIoStepListItem() bitriseModels.StepListItemModel {
 	return stepListItem(stepIDComposite, "", "")
 }
 
+// DeployPathStepListItem returns the deploy-to-bitrise-io step deploying the given file or directory,
+// directories are deployed as a single compressed file.
+func DeployPathStepListItem(title, deployPath string) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(DeployToBitriseIoID, DeployToBitriseIoVersion)
+	return stepListItem(stepIDComposite, title, "",
+		envmanModels.EnvironmentItemModel{DeployToBitriseIoDeployPathInputKey: deployPath},
+		envmanModels.EnvironmentItemModel{DeployToBitriseIoIsCompressInputKey: "true"},
+	)
+}
+
+// ArtifactDeployStepList returns the steps deploying the build artifacts and the test reports,
+// which are not exported to the deploy dir by the build and test steps.
+func ArtifactDeployStepList(artifactPaths, testReportPaths []string) []bitriseModels.StepListItemModel {
+	stepList := []bitriseModels.StepListItemModel{}
+	for _, pth := range artifactPaths {
+		stepList = append(stepList, DeployPathStepListItem(DeployArtifactsTitle, pth))
+	}
+	for _, pth := range testReportPaths {
+		stepList = append(stepList, DeployPathStepListItem(DeployTestReportsTitle, pth))
+	}
+	return stepList
+}
+
 // ScriptSteplistItem ...
 func ScriptSteplistItem(title string, inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(ScriptID, ScriptVersion)
@@ -137,6 +160,12 @@ func InstallMissingAndroidToolsStepListItem(inputs ...envmanModels.EnvironmentIt
 	return stepListItem(stepIDComposite, "", "", inputs...)
 }
 
+// GradleRunnerStepListItem ...
+func GradleRunnerStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(GradleRunnerID, GradleRunnerVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
 // FastlaneStepListItem ...
 func FastlaneStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
 	stepIDComposite := stepIDComposite(FastlaneID, FastlaneVersion)
@@ -281,3 +310,27 @@ func FlutterBuildStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitri
 	stepIDComposite := stepIDComposite(FlutterBuildID, FlutterBuildVersion)
 	return stepListItem(stepIDComposite, "", "", inputs...)
 }
+
+// AVDManagerStepListItem ...
+func AVDManagerStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(AVDManagerID, AVDManagerVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// WaitForAndroidEmulatorStepListItem ...
+func WaitForAndroidEmulatorStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(WaitForAndroidEmulatorID, WaitForAndroidEmulatorVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// AndroidBuildForUITestingStepListItem ...
+func AndroidBuildForUITestingStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(AndroidBuildForUITestingID, AndroidBuildForUITestingVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
+
+// VirtualDeviceTestingForAndroidStepListItem ...
+func VirtualDeviceTestingForAndroidStepListItem(inputs ...envmanModels.EnvironmentItemModel) bitriseModels.StepListItemModel {
+	stepIDComposite := stepIDComposite(VirtualDeviceTestingForAndroidID, VirtualDeviceTestingForAndroidVersion)
+	return stepListItem(stepIDComposite, "", "", inputs...)
+}
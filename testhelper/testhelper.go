@@ -0,0 +1,181 @@
+// Package testhelper contains helpers for the scanner tests, to build and compare option trees, to inspect the generated configs
+// and to create the project files the scanners run on.
+package testhelper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	bitriseModels "github.com/bitrise-io/bitrise/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Spec describes an option tree for BuildTree: an option (Title, EnvKey, Type and the options or configs of its Values),
+// or a config (Config), the leaf of the tree.
+type Spec struct {
+	Title  string
+	EnvKey string
+	// Type is the option's type, selector if empty.
+	Type   models.OptionType
+	Values map[string]Spec
+
+	Config string
+}
+
+// BuildTree builds the option tree described by the spec, the Components and Head of the options are set like
+// the scanners' AddOption and AddConfig calls set them.
+func BuildTree(spec Spec) models.OptionNode {
+	return *buildOption(spec)
+}
+
+func buildOption(spec Spec) *models.OptionNode {
+	if spec.Config != "" {
+		return models.NewConfigOption(spec.Config)
+	}
+
+	var option *models.OptionNode
+	switch spec.Type {
+	case models.TypeUserInput:
+		option = models.NewUserInputOption(spec.Title, spec.EnvKey, false)
+	case models.TypeOptionalUserInput:
+		option = models.NewUserInputOption(spec.Title, spec.EnvKey, true)
+	case models.TypeInfo:
+		option = models.NewInfoOption(spec.Title, spec.EnvKey)
+	default:
+		option = models.NewOption(spec.Title, spec.EnvKey)
+	}
+
+	// the children are built first, AddOptions sets the Components and Head of their descendants too
+	children := map[string]*models.OptionNode{}
+	for value, childSpec := range spec.Values {
+		children[value] = buildOption(childSpec)
+	}
+	option.AddOptions(children)
+	return option
+}
+
+// AssertOptionTree fails the test if the option trees differ.
+// The trees are compared by their Prettify outline, so a failure shows a readable diff of the trees.
+func AssertOptionTree(t *testing.T, got, want models.OptionNode) {
+	t.Helper()
+	require.Equal(t, want.Prettify(), got.Prettify(), "option trees differ")
+}
+
+// envReferenceRegexp matches the $KEY and ${KEY} env references.
+var envReferenceRegexp = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)\}?`)
+
+// ConfigEnvKeys returns the env keys referenced by the step inputs of the config (bitrise.yml content), in alphabetical order.
+// The references of the script contents are included too, like $HOME.
+func ConfigEnvKeys(config string) ([]string, error) {
+	var bitriseData bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal([]byte(config), &bitriseData); err != nil {
+		return nil, fmt.Errorf("failed to parse config, error: %s", err)
+	}
+
+	keys := []string{}
+	for _, workflow := range bitriseData.Workflows {
+		for _, stepListItem := range workflow.Steps {
+			for _, step := range stepListItem {
+				for _, input := range step.Inputs {
+					_, value, err := input.GetKeyValuePair()
+					if err != nil {
+						return nil, fmt.Errorf("invalid step input, error: %s", err)
+					}
+
+					for _, match := range envReferenceRegexp.FindAllStringSubmatch(value, -1) {
+						if !sliceutil.IsStringInSlice(match[1], keys) {
+							keys = append(keys, match[1])
+						}
+					}
+				}
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// AssertConfigEnvKeys fails the test if the config (bitrise.yml content) does not reference every given env key in its step inputs,
+// like the env keys of the options leading to the config.
+func AssertConfigEnvKeys(t *testing.T, config string, envKeys ...string) {
+	t.Helper()
+
+	referenced, err := ConfigEnvKeys(config)
+	require.NoError(t, err)
+
+	missing := []string{}
+	for _, key := range envKeys {
+		if !sliceutil.IsStringInSlice(key, referenced) {
+			missing = append(missing, key)
+		}
+	}
+	require.Empty(t, missing, "env keys not referenced by the config, referenced env keys: %v", referenced)
+}
+
+// StepInputValues returns the values of the given input of the workflow's steps with the given ID (like deploy-to-bitrise-io),
+// in the order of the steps. The steps without the input are skipped.
+func StepInputValues(config, workflowID, stepID, inputKey string) ([]string, error) {
+	var bitriseData bitriseModels.BitriseDataModel
+	if err := yaml.Unmarshal([]byte(config), &bitriseData); err != nil {
+		return nil, fmt.Errorf("failed to parse config, error: %s", err)
+	}
+
+	workflow, ok := bitriseData.Workflows[workflowID]
+	if !ok {
+		return nil, fmt.Errorf("workflow (%s) not found", workflowID)
+	}
+
+	values := []string{}
+	for _, stepListItem := range workflow.Steps {
+		for compositeID, step := range stepListItem {
+			if strings.Split(compositeID, "@")[0] != stepID {
+				continue
+			}
+
+			for _, input := range step.Inputs {
+				key, value, err := input.GetKeyValuePair()
+				if err != nil {
+					return nil, fmt.Errorf("invalid step input, error: %s", err)
+				}
+				if key == inputKey {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+	return values, nil
+}
+
+// TempDir creates a temporary dir for the project files of a test, the returned func removes it:
+//
+//	dir, cleanup := testhelper.TempDir(t, "multi-app")
+//	defer cleanup()
+func TempDir(t *testing.T, name string) (string, func()) {
+	t.Helper()
+	dir, err := pathutil.NormalizedOSTempDirPath(name)
+	require.NoError(t, err)
+	return dir, func() {
+		require.NoError(t, os.RemoveAll(dir))
+	}
+}
+
+// WriteFile writes the content to the file at pth, relative to dir, creating the missing parent dirs.
+// It returns the dir of the written file, like the project path of a project.pbxproj.
+func WriteFile(t *testing.T, dir, pth, content string) string {
+	t.Helper()
+	pth = filepath.Join(dir, pth)
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0700))
+	require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	return filepath.Dir(pth)
+}
@@ -0,0 +1,116 @@
+package testhelper
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildTree(t *testing.T) {
+	tree := BuildTree(Spec{
+		Title:  "Project path",
+		EnvKey: "PROJECT_PATH",
+		Values: map[string]Spec{
+			"App.xcodeproj": {
+				Title:  "Scheme",
+				EnvKey: "SCHEME",
+				Values: map[string]Spec{
+					"App": {
+						Title:  "Team",
+						EnvKey: "TEAM",
+						Type:   models.TypeOptionalUserInput,
+						Values: map[string]Spec{"_": {Config: "ios-config"}},
+					},
+				},
+			},
+		},
+	})
+
+	want := models.NewOption("Project path", "PROJECT_PATH")
+	schemeOption := models.NewOption("Scheme", "SCHEME")
+	want.AddOption("App.xcodeproj", schemeOption)
+	teamOption := models.NewUserInputOption("Team", "TEAM", true)
+	schemeOption.AddOption("App", teamOption)
+	configOption := models.NewConfigOption("ios-config")
+	teamOption.AddConfig("_", configOption)
+
+	AssertOptionTree(t, tree, *want)
+
+	// the Components and Head are set, like by AddOption
+	builtConfigOption, ok := tree.Child("App.xcodeproj", "App", "_")
+	require.True(t, ok)
+	require.Equal(t, configOption.Components, builtConfigOption.Components)
+	require.Equal(t, "Project path", builtConfigOption.Head.Title)
+
+	parent, underKey, ok := builtConfigOption.Parent()
+	require.True(t, ok)
+	require.Equal(t, "Team", parent.Title)
+	require.Equal(t, "_", underKey)
+}
+
+func TestConfigEnvKeys(t *testing.T) {
+	config := `format_version: "5"
+workflows:
+  primary:
+    steps:
+    - xcode-archive@2:
+        inputs:
+        - project_path: $BITRISE_PROJECT_PATH
+        - scheme: ${BITRISE_SCHEME}
+    - script@1:
+        inputs:
+        - content: echo "$BITRISE_SCHEME" > $HOME/scheme
+`
+	keys, err := ConfigEnvKeys(config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"BITRISE_PROJECT_PATH", "BITRISE_SCHEME", "HOME"}, keys)
+
+	AssertConfigEnvKeys(t, config, "BITRISE_PROJECT_PATH", "BITRISE_SCHEME")
+
+	_, err = ConfigEnvKeys("workflows: [")
+	require.Error(t, err)
+}
+
+func TestStepInputValues(t *testing.T) {
+	config := `format_version: "5"
+workflows:
+  deploy:
+    steps:
+    - xcode-archive@2:
+        inputs:
+        - project_path: $BITRISE_PROJECT_PATH
+    - deploy-to-bitrise-io@1:
+        inputs:
+        - deploy_path: $BITRISE_XCARCHIVE_PATH
+    - deploy-to-bitrise-io: {}
+    - deploy-to-bitrise-io:
+        inputs:
+        - deploy_path: build/test-results
+`
+	values, err := StepInputValues(config, "deploy", "deploy-to-bitrise-io", "deploy_path")
+	require.NoError(t, err)
+	require.Equal(t, []string{"$BITRISE_XCARCHIVE_PATH", "build/test-results"}, values)
+
+	_, err = StepInputValues(config, "primary", "deploy-to-bitrise-io", "deploy_path")
+	require.Error(t, err)
+}
+
+func TestTempDirAndWriteFile(t *testing.T) {
+	dir, cleanup := TempDir(t, "testhelper")
+
+	projectPth := WriteFile(t, dir, "App.xcodeproj/project.pbxproj", "// !$*UTF8*$!")
+	require.Equal(t, filepath.Join(dir, "App.xcodeproj"), projectPth)
+
+	content, err := fileutil.ReadStringFromFile(filepath.Join(projectPth, "project.pbxproj"))
+	require.NoError(t, err)
+	require.Equal(t, "// !$*UTF8*$!", content)
+
+	cleanup()
+	exist, err := pathutil.IsDirExists(dir)
+	require.NoError(t, err)
+	require.False(t, exist)
+}
@@ -0,0 +1,180 @@
+package utility
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/log"
+)
+
+// IsSupportedArchive returns true if the file is a zip (.zip) or gzipped tar (.tar.gz, .tgz) archive, by its extension.
+func IsSupportedArchive(pth string) bool {
+	lower := strings.ToLower(pth)
+	return strings.HasSuffix(lower, ".zip") || strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")
+}
+
+// ExtractArchive extracts the zip or gzipped tar archive into the destination dir.
+// The entries pointing outside of the destination dir (like ../../etc/passwd) fail the extraction,
+// the symbolic links and other special entries are skipped.
+func ExtractArchive(archivePth, destDir string) error {
+	if !IsSupportedArchive(archivePth) {
+		return fmt.Errorf("unsupported archive (%s), supported extensions: .zip, .tar.gz, .tgz", archivePth)
+	}
+
+	destDir, err := filepath.Abs(destDir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(destDir, 0700); err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(strings.ToLower(archivePth), ".zip") {
+		return extractZip(archivePth, destDir)
+	}
+	return extractTarGz(archivePth, destDir)
+}
+
+// archiveEntryPath returns the destination path of the archive entry, or an error if it points outside of the destination dir.
+func archiveEntryPath(destDir, name string) (string, error) {
+	pth := filepath.Join(destDir, filepath.FromSlash(name))
+	if pth != destDir && !strings.HasPrefix(pth, destDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("archive entry (%s) points outside of the destination dir", name)
+	}
+	return pth, nil
+}
+
+func writeArchiveFile(pth string, mode os.FileMode, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(pth), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(pth, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			log.TWarnf("Failed to close file (%s), error: %s", pth, closeErr)
+		}
+		return err
+	}
+	return f.Close()
+}
+
+func extractZip(archivePth, destDir string) error {
+	reader, err := zip.OpenReader(archivePth)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.TWarnf("Failed to close archive (%s), error: %s", archivePth, err)
+		}
+	}()
+
+	for _, file := range reader.File {
+		pth, err := archiveEntryPath(destDir, file.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := file.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(pth, 0700); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			r, err := file.Open()
+			if err != nil {
+				return err
+			}
+			err = writeArchiveFile(pth, mode, r)
+			if closeErr := r.Close(); closeErr != nil {
+				log.TWarnf("Failed to close archive entry (%s), error: %s", file.Name, closeErr)
+			}
+			if err != nil {
+				return err
+			}
+		default:
+			log.TWarnf("Skipping archive entry (%s), not a regular file or directory", file.Name)
+		}
+	}
+	return nil
+}
+
+func extractTarGz(archivePth, destDir string) error {
+	f, err := os.Open(archivePth)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.TWarnf("Failed to close archive (%s), error: %s", archivePth, err)
+		}
+	}()
+
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+
+	reader := tar.NewReader(gzipReader)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		pth, err := archiveEntryPath(destDir, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(pth, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeArchiveFile(pth, os.FileMode(header.Mode), reader); err != nil {
+				return err
+			}
+		default:
+			log.TWarnf("Skipping archive entry (%s), not a regular file or directory", header.Name)
+		}
+	}
+}
+
+// ArchiveRoot returns the project root of the extracted archive: the single top-level directory wrapping
+// every entry (like project-main/ of a GitHub source archive), or the extraction dir itself.
+// The macOS metadata (__MACOSX, .DS_Store) is not taken into account.
+func ArchiveRoot(extractDir string) (string, error) {
+	infos, err := ioutil.ReadDir(extractDir)
+	if err != nil {
+		return "", err
+	}
+
+	entries := []os.FileInfo{}
+	for _, info := range infos {
+		if info.Name() == "__MACOSX" || info.Name() == ".DS_Store" {
+			continue
+		}
+		entries = append(entries, info)
+	}
+
+	if len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(extractDir, entries[0].Name()), nil
+	}
+	return extractDir, nil
+}
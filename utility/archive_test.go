@@ -0,0 +1,131 @@
+package utility
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+// testArchiveAndroidProject is a minimal android project, by file path.
+var testArchiveAndroidProject = map[string]string{
+	"build.gradle":     "buildscript {}",
+	"settings.gradle":  "include ':app'",
+	"gradlew":          "#!/usr/bin/env sh",
+	"app/build.gradle": "apply plugin: 'com.android.application'",
+}
+
+func writeTestZip(t *testing.T, pth string, files map[string]string) {
+	f, err := os.Create(pth)
+	require.NoError(t, err)
+	writer := zip.NewWriter(f)
+	for name, content := range files {
+		w, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, f.Close())
+}
+
+func writeTestTarGz(t *testing.T, pth string, files map[string]string) {
+	f, err := os.Create(pth)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(f)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, f.Close())
+}
+
+func requireTestAndroidProject(t *testing.T, rootDir string) {
+	for name, expected := range testArchiveAndroidProject {
+		content, err := fileutil.ReadStringFromFile(filepath.Join(rootDir, name))
+		require.NoError(t, err)
+		require.Equal(t, expected, content)
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("archive")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	t.Log("zip with a wrapping directory")
+	{
+		files := map[string]string{"__MACOSX/._build.gradle": ""}
+		for name, content := range testArchiveAndroidProject {
+			files["MyApp-main/"+name] = content
+		}
+		archivePth := filepath.Join(tmpDir, "project.zip")
+		writeTestZip(t, archivePth, files)
+
+		extractDir := filepath.Join(tmpDir, "zip")
+		require.NoError(t, ExtractArchive(archivePth, extractDir))
+
+		rootDir, err := ArchiveRoot(extractDir)
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(extractDir, "MyApp-main"), rootDir)
+		requireTestAndroidProject(t, rootDir)
+	}
+
+	t.Log("tar.gz without a wrapping directory")
+	{
+		archivePth := filepath.Join(tmpDir, "project.tar.gz")
+		writeTestTarGz(t, archivePth, testArchiveAndroidProject)
+
+		extractDir := filepath.Join(tmpDir, "targz")
+		require.NoError(t, ExtractArchive(archivePth, extractDir))
+
+		rootDir, err := ArchiveRoot(extractDir)
+		require.NoError(t, err)
+		require.Equal(t, extractDir, rootDir)
+		requireTestAndroidProject(t, rootDir)
+
+		info, err := os.Stat(filepath.Join(rootDir, "gradlew"))
+		require.NoError(t, err)
+		require.NotEqual(t, os.FileMode(0), info.Mode()&0100)
+	}
+
+	t.Log("zip slip")
+	{
+		archivePth := filepath.Join(tmpDir, "slip.zip")
+		writeTestZip(t, archivePth, map[string]string{"../../evil.sh": "rm -rf /"})
+
+		extractDir := filepath.Join(tmpDir, "slip")
+		err := ExtractArchive(archivePth, extractDir)
+		require.EqualError(t, err, "archive entry (../../evil.sh) points outside of the destination dir")
+
+		exist, err := pathutil.IsPathExists(filepath.Join(tmpDir, "..", "evil.sh"))
+		require.NoError(t, err)
+		require.False(t, exist)
+	}
+
+	t.Log("tar.gz slip")
+	{
+		archivePth := filepath.Join(tmpDir, "slip.tgz")
+		writeTestTarGz(t, archivePth, map[string]string{"app/../../evil.sh": "rm -rf /"})
+
+		err := ExtractArchive(archivePth, filepath.Join(tmpDir, "tgzslip"))
+		require.EqualError(t, err, "archive entry (app/../../evil.sh) points outside of the destination dir")
+	}
+
+	t.Log("unsupported archive")
+	{
+		require.Error(t, ExtractArchive(filepath.Join(tmpDir, "project.rar"), filepath.Join(tmpDir, "rar")))
+	}
+}
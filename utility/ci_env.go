@@ -0,0 +1,261 @@
+package utility
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/go-utils/sliceutil"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// The config files of the CI providers, relative to the repository root.
+const (
+	TravisConfigPth   = ".travis.yml"
+	CircleCIConfigPth = ".circleci/config.yml"
+)
+
+// sensitiveEnvKeyParts mark the env vars which should be stored as secrets.
+var sensitiveEnvKeyParts = []string{"TOKEN", "KEY", "SECRET", "PASSWORD"}
+
+// CIEnv is an environment variable declared in a CI provider's config.
+type CIEnv struct {
+	Key   string
+	Value string
+	// IsSecret is true if the key looks sensitive (contains TOKEN, KEY, SECRET or PASSWORD).
+	IsSecret bool
+}
+
+// CIEnvDeclarations are the environment variables declared in a CI provider's config.
+type CIEnvDeclarations struct {
+	// ConfigPth is the CI config's path, relative to the search dir.
+	ConfigPth string
+	Envs      []CIEnv
+	// MatrixEnvs are the env vars of the Travis build matrix, their values differ by job, so these are not app env vars.
+	MatrixEnvs []CIEnv
+	// EncryptedCount is the number of the encrypted env vars (Travis secure entries), their keys are unknown.
+	EncryptedCount int
+}
+
+// IsSensitiveEnvKey returns true if the env key looks like a secret's key.
+func IsSensitiveEnvKey(key string) bool {
+	key = strings.ToUpper(key)
+	for _, part := range sensitiveEnvKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectCIEnvs returns the environment variables declared in the Travis CI (.travis.yml) and CircleCI (.circleci/config.yml)
+// configs of the search dir, the CI configs without env vars are not listed.
+func DetectCIEnvs(searchDir string) ([]CIEnvDeclarations, error) {
+	parsers := []struct {
+		pth   string
+		parse func(content string) (CIEnvDeclarations, error)
+	}{
+		{TravisConfigPth, parseTravisEnvs},
+		{CircleCIConfigPth, parseCircleCIEnvs},
+	}
+
+	declarationsList := []CIEnvDeclarations{}
+	for _, parser := range parsers {
+		pth := filepath.Join(searchDir, parser.pth)
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return nil, err
+		} else if !exist {
+			continue
+		}
+
+		content, err := fileutil.ReadStringFromFile(pth)
+		if err != nil {
+			return nil, err
+		}
+
+		declarations, err := parser.parse(content)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse %s, error: %s", parser.pth, err)
+		}
+		if len(declarations.Envs) == 0 && len(declarations.MatrixEnvs) == 0 && declarations.EncryptedCount == 0 {
+			continue
+		}
+
+		declarations.ConfigPth = parser.pth
+		declarationsList = append(declarationsList, declarations)
+	}
+	return declarationsList, nil
+}
+
+// parseTravisEnvs collects the env vars of the env section of a Travis CI config: the global ones (env.global, or a single
+// env string) and the ones of the build matrix (env.matrix, env.jobs, or an env list).
+// The env vars are declared as KEY=value strings, multiple ones separated by spaces, or as secure (encrypted) entries.
+func parseTravisEnvs(content string) (CIEnvDeclarations, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return CIEnvDeclarations{}, err
+	}
+
+	declarations := CIEnvDeclarations{}
+	var collect func(node interface{}, add func(key, value string))
+	collect = func(node interface{}, add func(key, value string)) {
+		switch node := node.(type) {
+		case string:
+			for _, assignment := range strings.Fields(node) {
+				if split := strings.SplitN(assignment, "=", 2); len(split) == 2 && split[0] != "" {
+					add(split[0], strings.Trim(split[1], `"'`))
+				}
+			}
+		case []interface{}:
+			for _, item := range node {
+				collect(item, add)
+			}
+		case map[interface{}]interface{}:
+			if _, ok := node["secure"]; ok {
+				declarations.EncryptedCount++
+			}
+		}
+	}
+
+	switch env := config["env"].(type) {
+	case string:
+		collect(env, declarations.add)
+	case []interface{}:
+		collect(env, declarations.addMatrix)
+	case map[interface{}]interface{}:
+		collect(env["global"], declarations.add)
+		collect(env["matrix"], declarations.addMatrix)
+		collect(env["jobs"], declarations.addMatrix)
+	}
+
+	declarations.sort()
+	return declarations, nil
+}
+
+// parseCircleCIEnvs collects the env vars of the environment maps (or lists) of a CircleCI config's
+// jobs, executors and their docker images.
+func parseCircleCIEnvs(content string) (CIEnvDeclarations, error) {
+	var config map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &config); err != nil {
+		return CIEnvDeclarations{}, err
+	}
+
+	declarations := CIEnvDeclarations{}
+	collectEnvironment := func(environment interface{}) {
+		switch environment := environment.(type) {
+		case map[interface{}]interface{}:
+			for key, value := range environment {
+				declarations.add(fmt.Sprint(key), fmt.Sprint(value))
+			}
+		case []interface{}:
+			for _, item := range environment {
+				if item, ok := item.(map[interface{}]interface{}); ok {
+					for key, value := range item {
+						declarations.add(fmt.Sprint(key), fmt.Sprint(value))
+					}
+				}
+			}
+		}
+	}
+
+	for _, section := range []string{"jobs", "executors"} {
+		definitions, ok := config[section].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		for _, definition := range definitions {
+			definition, ok := definition.(map[interface{}]interface{})
+			if !ok {
+				continue
+			}
+
+			collectEnvironment(definition["environment"])
+			if images, ok := definition["docker"].([]interface{}); ok {
+				for _, image := range images {
+					if image, ok := image.(map[interface{}]interface{}); ok {
+						collectEnvironment(image["environment"])
+					}
+				}
+			}
+		}
+	}
+
+	declarations.sort()
+	return declarations, nil
+}
+
+// add adds the env var, the first declaration of a key is kept.
+func (declarations *CIEnvDeclarations) add(key, value string) {
+	for _, env := range declarations.Envs {
+		if env.Key == key {
+			return
+		}
+	}
+	declarations.Envs = append(declarations.Envs, CIEnv{Key: key, Value: value, IsSecret: IsSensitiveEnvKey(key)})
+}
+
+// addMatrix adds the env var of a build matrix job, the same key may be declared with a different value per job.
+func (declarations *CIEnvDeclarations) addMatrix(key, value string) {
+	for _, env := range declarations.MatrixEnvs {
+		if env.Key == key && env.Value == value {
+			return
+		}
+	}
+	declarations.MatrixEnvs = append(declarations.MatrixEnvs, CIEnv{Key: key, Value: value, IsSecret: IsSensitiveEnvKey(key)})
+}
+
+func (declarations *CIEnvDeclarations) sort() {
+	for _, envs := range [][]CIEnv{declarations.Envs, declarations.MatrixEnvs} {
+		sort.SliceStable(envs, func(i, j int) bool {
+			return envs[i].Key < envs[j].Key
+		})
+	}
+}
+
+// AppEnvs returns the env vars which are not secrets, these are added to the app env vars of the generated configs.
+func (declarations CIEnvDeclarations) AppEnvs() []envmanModels.EnvironmentItemModel {
+	appEnvs := []envmanModels.EnvironmentItemModel{}
+	for _, env := range declarations.Envs {
+		if !env.IsSecret {
+			appEnvs = append(appEnvs, envmanModels.EnvironmentItemModel{env.Key: env.Value})
+		}
+	}
+	return appEnvs
+}
+
+// Warning returns the warning listing the env vars of the CI config which are not carried over to the generated configs:
+// the secrets, to add to the app's secrets (.bitrise.secrets.yml), and the build matrix env vars, whose values differ by job.
+// The secrets' values are not listed. An empty string is returned if every env var is carried over.
+func (declarations CIEnvDeclarations) Warning() string {
+	secretKeys := []string{}
+	for _, env := range declarations.Envs {
+		if env.IsSecret {
+			secretKeys = append(secretKeys, env.Key)
+		}
+	}
+	matrixKeys := []string{}
+	for _, env := range declarations.MatrixEnvs {
+		if !sliceutil.IsStringInSlice(env.Key, matrixKeys) {
+			matrixKeys = append(matrixKeys, env.Key)
+		}
+	}
+
+	parts := []string{}
+	if len(secretKeys) > 0 {
+		parts = append(parts, fmt.Sprintf("secrets: %s", strings.Join(secretKeys, ", ")))
+	}
+	if declarations.EncryptedCount > 0 {
+		parts = append(parts, fmt.Sprintf("%d encrypted env vars", declarations.EncryptedCount))
+	}
+	if len(parts) > 0 {
+		parts = []string{fmt.Sprintf("%s declares %s, add them to the app's secrets (.bitrise.secrets.yml).", declarations.ConfigPth, strings.Join(parts, "; "))}
+	}
+	if len(matrixKeys) > 0 {
+		parts = append(parts, fmt.Sprintf("%s declares build matrix env vars: %s, their values differ by job: add them to the workflows' env vars.", declarations.ConfigPth, strings.Join(matrixKeys, ", ")))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,137 @@
+package utility
+
+import (
+	"testing"
+
+	envmanModels "github.com/bitrise-io/envman/models"
+	"github.com/stretchr/testify/require"
+)
+
+const testTravisConfigContent = `language: android
+env:
+  global:
+    - ANDROID_API=29 EMULATOR=true
+    - "SONAR_TOKEN=abc"
+    - secure: "c2VjcmV0"
+  matrix:
+    - FLAVOR=staging
+    - FLAVOR=production
+script: ./gradlew build
+`
+
+const testCircleCIConfigContent = `version: 2.1
+executors:
+  node:
+    docker:
+      - image: circleci/node:12
+        environment:
+          NODE_ENV: test
+jobs:
+  build:
+    executor: node
+    environment:
+      FASTLANE_PASSWORD: hunter2
+      LANG: en_US.UTF-8
+  deploy:
+    docker:
+      - image: circleci/ruby:2.6
+    environment:
+      - MATCH_TYPE: appstore
+`
+
+func TestIsSensitiveEnvKey(t *testing.T) {
+	require.True(t, IsSensitiveEnvKey("SONAR_TOKEN"))
+	require.True(t, IsSensitiveEnvKey("api_key"))
+	require.True(t, IsSensitiveEnvKey("FASTLANE_PASSWORD"))
+	require.True(t, IsSensitiveEnvKey("AWS_SECRET_ACCESS_KEY"))
+	require.False(t, IsSensitiveEnvKey("FLAVOR"))
+}
+
+func TestDetectCIEnvs(t *testing.T) {
+	t.Log("Travis CI and CircleCI configs")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".travis.yml":          testTravisConfigContent,
+			".circleci/config.yml": testCircleCIConfigContent,
+		})
+
+		declarationsList, err := DetectCIEnvs(dir)
+		require.NoError(t, err)
+		require.Equal(t, []CIEnvDeclarations{
+			{
+				ConfigPth: ".travis.yml",
+				Envs: []CIEnv{
+					{Key: "ANDROID_API", Value: "29"},
+					{Key: "EMULATOR", Value: "true"},
+					{Key: "SONAR_TOKEN", Value: "abc", IsSecret: true},
+				},
+				MatrixEnvs: []CIEnv{
+					{Key: "FLAVOR", Value: "staging"},
+					{Key: "FLAVOR", Value: "production"},
+				},
+				EncryptedCount: 1,
+			},
+			{
+				ConfigPth: ".circleci/config.yml",
+				Envs: []CIEnv{
+					{Key: "FASTLANE_PASSWORD", Value: "hunter2", IsSecret: true},
+					{Key: "LANG", Value: "en_US.UTF-8"},
+					{Key: "MATCH_TYPE", Value: "appstore"},
+					{Key: "NODE_ENV", Value: "test"},
+				},
+			},
+		}, declarationsList)
+
+		require.Equal(t, []envmanModels.EnvironmentItemModel{
+			{"ANDROID_API": "29"},
+			{"EMULATOR": "true"},
+		}, declarationsList[0].AppEnvs())
+
+		require.Equal(t, ".travis.yml declares secrets: SONAR_TOKEN; 1 encrypted env vars, add them to the app's secrets (.bitrise.secrets.yml). "+
+			".travis.yml declares build matrix env vars: FLAVOR, their values differ by job: add them to the workflows' env vars.", declarationsList[0].Warning())
+		require.Equal(t, ".circleci/config.yml declares secrets: FASTLANE_PASSWORD, add them to the app's secrets (.bitrise.secrets.yml).", declarationsList[1].Warning())
+	}
+
+	t.Log("Travis CI env list is the build matrix, a single env string is global")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".travis.yml": "language: node_js\nenv:\n  - NODE_ENV=test\n  - NODE_ENV=production\n",
+		})
+
+		declarationsList, err := DetectCIEnvs(dir)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(declarationsList))
+		require.Equal(t, 0, len(declarationsList[0].Envs))
+		require.Equal(t, []CIEnv{{Key: "NODE_ENV", Value: "test"}, {Key: "NODE_ENV", Value: "production"}}, declarationsList[0].MatrixEnvs)
+
+		dir = writeGitFixture(t, map[string]string{
+			".travis.yml": "language: node_js\nenv: NODE_ENV=test CI_BUILD=true\n",
+		})
+
+		declarationsList, err = DetectCIEnvs(dir)
+		require.NoError(t, err)
+		require.Equal(t, []CIEnv{{Key: "CI_BUILD", Value: "true"}, {Key: "NODE_ENV", Value: "test"}}, declarationsList[0].Envs)
+		require.Equal(t, "", declarationsList[0].Warning())
+	}
+
+	t.Log("CI config without env vars")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".travis.yml": "language: objective-c\nscript: xcodebuild\n",
+		})
+
+		declarationsList, err := DetectCIEnvs(dir)
+		require.NoError(t, err)
+		require.Equal(t, []CIEnvDeclarations{}, declarationsList)
+	}
+
+	t.Log("invalid CI config")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".circleci/config.yml": "jobs: [",
+		})
+
+		_, err := DetectCIEnvs(dir)
+		require.Error(t, err)
+	}
+}
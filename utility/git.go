@@ -0,0 +1,136 @@
+package utility
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/command"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+const (
+	headRefPrefix   = "ref: "
+	branchRefPrefix = "refs/heads/"
+	gitDirPrefix    = "gitdir: "
+
+	originHeadRef = "refs/remotes/origin/HEAD"
+)
+
+// DefaultGitBranch returns the default branch of the git repository in the given directory:
+// the branch the HEAD points at, if the HEAD is detached the branch of the origin's HEAD,
+// otherwise main (if the repository has a main branch) or master.
+// The detached HEAD, bare and missing repository cases are reported as warnings.
+func DefaultGitBranch(repoDir string) (string, models.Warnings) {
+	warnings := models.Warnings{}
+
+	gitDir, isBare, err := gitDirectory(repoDir)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to find the git repository in %s, error: %s", repoDir, err))
+		return "master", warnings
+	}
+	if gitDir == "" {
+		warnings = append(warnings, fmt.Sprintf("No git repository found in %s, master is used as the default branch", repoDir))
+		return "master", warnings
+	}
+	if isBare {
+		warnings = append(warnings, fmt.Sprintf("%s is a bare git repository, the default branch is read from its HEAD", repoDir))
+	}
+
+	head, err := fileutil.ReadStringFromFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("Failed to read the git HEAD, error: %s", err))
+	} else if branch := branchOfRef(head); branch != "" {
+		return branch, warnings
+	} else {
+		warnings = append(warnings, fmt.Sprintf("The git HEAD is detached (%s), the default branch is read from the origin", strings.TrimSpace(head)))
+	}
+
+	cmd := command.New("git", "symbolic-ref", originHeadRef).SetDir(repoDir)
+	if out, err := cmd.RunAndReturnTrimmedCombinedOutput(); err == nil {
+		if branch := strings.TrimPrefix(out, "refs/remotes/origin/"); branch != out && branch != "" {
+			return branch, warnings
+		}
+	}
+
+	if hasBranch(gitDir, "main") {
+		return "main", warnings
+	}
+	return "master", warnings
+}
+
+// gitDirectory returns the git directory of the repository: the .git directory, the directory referenced by the .git file
+// (worktrees and submodules), or the repository itself, if it is a bare repository. Empty if the directory is not a repository.
+func gitDirectory(repoDir string) (string, bool, error) {
+	dotGitPth := filepath.Join(repoDir, ".git")
+	if exist, err := pathutil.IsDirExists(dotGitPth); err != nil {
+		return "", false, err
+	} else if exist {
+		return dotGitPth, false, nil
+	}
+
+	if exist, err := pathutil.IsPathExists(dotGitPth); err != nil {
+		return "", false, err
+	} else if exist {
+		content, err := fileutil.ReadStringFromFile(dotGitPth)
+		if err != nil {
+			return "", false, err
+		}
+		if !strings.HasPrefix(content, gitDirPrefix) {
+			return "", false, fmt.Errorf("invalid .git file: %s", content)
+		}
+
+		gitDir := strings.TrimSpace(strings.TrimPrefix(content, gitDirPrefix))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(repoDir, gitDir)
+		}
+		return gitDir, false, nil
+	}
+
+	headExist, err := pathutil.IsPathExists(filepath.Join(repoDir, "HEAD"))
+	if err != nil {
+		return "", false, err
+	}
+	refsExist, err := pathutil.IsDirExists(filepath.Join(repoDir, "refs"))
+	if err != nil {
+		return "", false, err
+	}
+	if headExist && refsExist {
+		return repoDir, true, nil
+	}
+	return "", false, nil
+}
+
+// branchOfRef returns the branch of a symbolic ref (like: ref: refs/heads/main), empty if the ref is not a branch.
+func branchOfRef(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if !strings.HasPrefix(ref, headRefPrefix) {
+		return ""
+	}
+	ref = strings.TrimSpace(strings.TrimPrefix(ref, headRefPrefix))
+	if !strings.HasPrefix(ref, branchRefPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(ref, branchRefPrefix)
+}
+
+// hasBranch returns true if the repository has the given local branch, as a loose or as a packed ref.
+func hasBranch(gitDir, branch string) bool {
+	if exist, err := pathutil.IsPathExists(filepath.Join(gitDir, branchRefPrefix, branch)); err == nil && exist {
+		return true
+	}
+
+	packedRefs, err := fileutil.ReadStringFromFile(filepath.Join(gitDir, "packed-refs"))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(packedRefs, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == branchRefPrefix+branch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package utility
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writeGitFixture(t *testing.T, files map[string]string) string {
+	dir, err := pathutil.NormalizedOSTempDirPath("__git__")
+	require.NoError(t, err)
+
+	for pth, content := range files {
+		pth = filepath.Join(dir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+	return dir
+}
+
+func TestDefaultGitBranch(t *testing.T) {
+	t.Log("HEAD points at a branch")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".git/HEAD": "ref: refs/heads/develop\n",
+		})
+
+		branch, warnings := DefaultGitBranch(dir)
+		require.Equal(t, "develop", branch)
+		require.Equal(t, models.Warnings{}, warnings)
+	}
+
+	t.Log("worktree: the .git file references the git dir")
+	{
+		root := writeGitFixture(t, map[string]string{
+			"feature/.git":                    "gitdir: ../repo.git/worktrees/feature\n",
+			"repo.git/worktrees/feature/HEAD": "ref: refs/heads/feature/login\n",
+		})
+
+		branch, warnings := DefaultGitBranch(filepath.Join(root, "feature"))
+		require.Equal(t, "feature/login", branch)
+		require.Equal(t, models.Warnings{}, warnings)
+	}
+
+	t.Log("detached HEAD, main branch")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".git/HEAD":            "2c5a3f1f4b8f0a0e3f6e0d1c9b8a7f6e5d4c3b2a\n",
+			".git/refs/heads/main": "2c5a3f1f4b8f0a0e3f6e0d1c9b8a7f6e5d4c3b2a\n",
+		})
+
+		branch, warnings := DefaultGitBranch(dir)
+		require.Equal(t, "main", branch)
+		require.Equal(t, models.Warnings{"The git HEAD is detached (2c5a3f1f4b8f0a0e3f6e0d1c9b8a7f6e5d4c3b2a), the default branch is read from the origin"}, warnings)
+	}
+
+	t.Log("detached HEAD, packed master branch")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			".git/HEAD":        "2c5a3f1f4b8f0a0e3f6e0d1c9b8a7f6e5d4c3b2a\n",
+			".git/packed-refs": "# pack-refs with: peeled fully-peeled sorted\n2c5a3f1f4b8f0a0e3f6e0d1c9b8a7f6e5d4c3b2a refs/heads/master\n",
+		})
+
+		branch, warnings := DefaultGitBranch(dir)
+		require.Equal(t, "master", branch)
+		require.Equal(t, 1, len(warnings))
+	}
+
+	t.Log("bare repository")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			"HEAD":             "ref: refs/heads/trunk\n",
+			"refs/heads/trunk": "2c5a3f1f4b8f0a0e3f6e0d1c9b8a7f6e5d4c3b2a\n",
+		})
+
+		branch, warnings := DefaultGitBranch(dir)
+		require.Equal(t, "trunk", branch)
+		require.Equal(t, models.Warnings{dir + " is a bare git repository, the default branch is read from its HEAD"}, warnings)
+	}
+
+	t.Log("not a git repository")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			"README.md": "",
+		})
+
+		branch, warnings := DefaultGitBranch(dir)
+		require.Equal(t, "master", branch)
+		require.Equal(t, models.Warnings{"No git repository found in " + dir + ", master is used as the default branch"}, warnings)
+	}
+}
@@ -0,0 +1,61 @@
+package utility
+
+import (
+	"image"
+	// registers the png decoder for image.DecodeConfig
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-core/bitrise-init/log"
+	"github.com/bitrise-core/bitrise-init/models"
+)
+
+// ImageSize returns the dimensions of the given png image, without decoding the whole image.
+func ImageSize(pth string) (int, int, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.TWarnf("Failed to close file (%s), error: %s", pth, err)
+		}
+	}()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
+
+// LargestIcon returns the highest resolution image of the given candidates, the icon path is relative to relDir.
+// Candidates which are not valid png images are skipped, returns false if no valid candidate found.
+func LargestIcon(candidates []string, relDir string) (models.Icon, bool) {
+	var largest models.Icon
+	found := false
+
+	for _, candidate := range candidates {
+		width, height, err := ImageSize(candidate)
+		if err != nil {
+			log.TWarnf("Failed to read icon (%s), error: %s", candidate, err)
+			continue
+		}
+		if found && width*height <= largest.Width*largest.Height {
+			continue
+		}
+
+		pth := candidate
+		if relDir != "" {
+			if relPth, err := filepath.Rel(relDir, candidate); err == nil {
+				pth = relPth
+			}
+		}
+
+		largest = models.Icon{Path: pth, Width: width, Height: height}
+		found = true
+	}
+
+	return largest, found
+}
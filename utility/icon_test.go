@@ -0,0 +1,58 @@
+package utility
+
+import (
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-core/bitrise-init/models"
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func writePNG(t *testing.T, pth string, width, height int) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0777))
+	f, err := os.Create(pth)
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, width, height))))
+	require.NoError(t, f.Close())
+}
+
+func TestLargestIcon(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__icon__")
+	require.NoError(t, err)
+
+	small := filepath.Join(tmpDir, "small.png")
+	large := filepath.Join(tmpDir, "res", "large.png")
+	invalid := filepath.Join(tmpDir, "invalid.png")
+	writePNG(t, small, 48, 48)
+	writePNG(t, large, 192, 192)
+	require.NoError(t, fileutil.WriteStringToFile(invalid, "not a png"))
+
+	t.Log("image size")
+	{
+		width, height, err := ImageSize(large)
+		require.NoError(t, err)
+		require.Equal(t, 192, width)
+		require.Equal(t, 192, height)
+
+		_, _, err = ImageSize(invalid)
+		require.Error(t, err)
+	}
+
+	t.Log("largest icon, relative to the given dir")
+	{
+		icon, found := LargestIcon([]string{small, invalid, large}, tmpDir)
+		require.True(t, found)
+		require.Equal(t, models.Icon{Path: filepath.Join("res", "large.png"), Width: 192, Height: 192}, icon)
+	}
+
+	t.Log("no valid candidate")
+	{
+		_, found := LargestIcon([]string{invalid}, tmpDir)
+		require.False(t, found)
+	}
+}
@@ -0,0 +1,85 @@
+package utility
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/sliceutil"
+)
+
+// Languages counted by LanguageCensus.
+const (
+	LanguageJava       = "Java"
+	LanguageKotlin     = "Kotlin"
+	LanguageSwift      = "Swift"
+	LanguageObjectiveC = "Objective-C"
+	LanguageDart       = "Dart"
+	LanguageCSharp     = "C#"
+	LanguageJavaScript = "JavaScript"
+	LanguageTypeScript = "TypeScript"
+)
+
+var extensionToLanguage = map[string]string{
+	".java":  LanguageJava,
+	".kt":    LanguageKotlin,
+	".swift": LanguageSwift,
+	".m":     LanguageObjectiveC,
+	".mm":    LanguageObjectiveC,
+	".dart":  LanguageDart,
+	".cs":    LanguageCSharp,
+	".js":    LanguageJavaScript,
+	".jsx":   LanguageJavaScript,
+	".ts":    LanguageTypeScript,
+	".tsx":   LanguageTypeScript,
+}
+
+// the dependency, build output and tooling dirs, their sources are not the project's own
+var languageCensusSkipDirNames = []string{
+	".git", "node_modules", "Pods", "Carthage", "build", ".gradle", ".dart_tool", ".pub-cache", "bin", "obj", "platforms",
+}
+
+// LanguageCount is the number of source files of a language.
+type LanguageCount struct {
+	Language string
+	Files    int
+}
+
+// LanguageCensus counts the source files of the search dir by language, based on the file extensions.
+// The dependency and build output dirs (like node_modules, Pods and build) are skipped.
+// The languages are ordered by their file count (the primary language first), then by name.
+func LanguageCensus(searchDir string) ([]LanguageCount, error) {
+	languageToFiles := map[string]int{}
+	if err := filepath.Walk(searchDir, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if info.IsDir() {
+			if path != searchDir && sliceutil.IsStringInSlice(info.Name(), languageCensusSkipDirNames) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if language, ok := extensionToLanguage[strings.ToLower(filepath.Ext(info.Name()))]; ok {
+			languageToFiles[language]++
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	counts := []LanguageCount{}
+	for language, files := range languageToFiles {
+		counts = append(counts, LanguageCount{Language: language, Files: files})
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Files != counts[j].Files {
+			return counts[i].Files > counts[j].Files
+		}
+		return counts[i].Language < counts[j].Language
+	})
+	return counts, nil
+}
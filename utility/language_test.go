@@ -0,0 +1,63 @@
+package utility
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageCensus(t *testing.T) {
+	t.Log("Android project, mostly Kotlin")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			"app/src/main/java/io/bitrise/MainActivity.kt":   "",
+			"app/src/main/java/io/bitrise/Model.kt":          "",
+			"app/src/main/java/io/bitrise/LegacyHelper.java": "",
+			"app/build.gradle.kts":                           "",
+			"app/build/generated/source/BuildConfig.java":    "",
+			".gradle/cache/Cached.java":                      "",
+		})
+
+		counts, err := LanguageCensus(dir)
+		require.NoError(t, err)
+		require.Equal(t, []LanguageCount{
+			{Language: LanguageKotlin, Files: 2},
+			{Language: LanguageJava, Files: 1},
+		}, counts)
+	}
+
+	t.Log("React Native project, the dependencies and the native projects")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			"App.tsx":                           "",
+			"src/screens/Home.tsx":              "",
+			"src/api.ts":                        "",
+			"index.js":                          "",
+			"node_modules/react/index.js":       "",
+			"node_modules/react/cjs/react.js":   "",
+			"ios/App/AppDelegate.m":             "",
+			"ios/Pods/Folly/Folly.mm":           "",
+			"android/app/src/MainActivity.java": "",
+		})
+
+		counts, err := LanguageCensus(dir)
+		require.NoError(t, err)
+		require.Equal(t, []LanguageCount{
+			{Language: LanguageTypeScript, Files: 3},
+			{Language: LanguageJava, Files: 1},
+			{Language: LanguageJavaScript, Files: 1},
+			{Language: LanguageObjectiveC, Files: 1},
+		}, counts)
+	}
+
+	t.Log("no source files")
+	{
+		dir := writeGitFixture(t, map[string]string{
+			"README.md": "",
+		})
+
+		counts, err := LanguageCensus(dir)
+		require.NoError(t, err)
+		require.Equal(t, []LanguageCount{}, counts)
+	}
+}
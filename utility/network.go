@@ -0,0 +1,13 @@
+package utility
+
+// NetworkAccessHook is called with the description of each network access of the scanners, before the access happens.
+// It is meant for tests, to detect network access (for example in offline mode).
+var NetworkAccessHook func(description string)
+
+// ReportNetworkAccess has to be called by the scanners before each network dependent operation,
+// like installing gems or fetching version lists.
+func ReportNetworkAccess(description string) {
+	if NetworkAccessHook != nil {
+		NetworkAccessHook(description)
+	}
+}
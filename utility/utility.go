@@ -17,6 +17,10 @@ type PackagesModel struct {
 	Scripts         map[string]string `json:"scripts"`
 	Dependencies    map[string]string `json:"dependencies"`
 	DevDependencies map[string]string `json:"devDependencies"`
+	// Detox is the Detox config, if set in the package.json
+	Detox json.RawMessage `json:"detox,omitempty"`
+	// Build is the electron-builder config, if set in the package.json
+	Build json.RawMessage `json:"build,omitempty"`
 }
 
 func parsePackagesJSONContent(content string) (PackagesModel, error) {
@@ -36,6 +40,27 @@ func ParsePackagesJSON(packagesJSONPth string) (PackagesModel, error) {
 	return parsePackagesJSONContent(content)
 }
 
+// JSPackageManager is the dependency manager of a JavaScript project.
+type JSPackageManager string
+
+// JavaScript package managers detected by DetectJSPackageManager.
+const (
+	JSPackageManagerNpm  JSPackageManager = "npm"
+	JSPackageManagerYarn JSPackageManager = "yarn"
+)
+
+// DetectJSPackageManager returns yarn if the project dir contains a yarn.lock file, npm otherwise.
+func DetectJSPackageManager(projectDir string) (JSPackageManager, error) {
+	exist, err := pathutil.IsPathExists(filepath.Join(projectDir, "yarn.lock"))
+	if err != nil {
+		return JSPackageManagerNpm, err
+	}
+	if exist {
+		return JSPackageManagerYarn, nil
+	}
+	return JSPackageManagerNpm, nil
+}
+
 // RelPath ...
 func RelPath(basePth, pth string) (string, error) {
 	absBasePth, err := pathutil.AbsPath(basePth)
@@ -60,32 +85,29 @@ func RelPath(basePth, pth string) (string, error) {
 }
 
 // ListPathInDirSortedByComponents ...
+// The reads failing with transient filesystem errors (like EAGAIN or a stale NFS file handle) are retried.
 func ListPathInDirSortedByComponents(searchDir string, relPath bool) ([]string, error) {
 	searchDir, err := filepath.Abs(searchDir)
 	if err != nil {
 		return []string{}, err
 	}
 
-	fileList := []string{}
-
-	if err := filepath.Walk(searchDir, func(path string, _ os.FileInfo, walkErr error) error {
-		if walkErr != nil {
-			return walkErr
-		}
+	pths, err := walkPaths(osWalkFS{}, searchDir)
+	if err != nil {
+		return []string{}, err
+	}
 
+	fileList := []string{}
+	for _, pth := range pths {
 		if relPath {
-			rel, err := filepath.Rel(searchDir, path)
+			rel, err := RelPath(searchDir, pth)
 			if err != nil {
-				return err
+				return []string{}, err
 			}
-			path = rel
+			pth = rel
 		}
 
-		fileList = append(fileList, path)
-
-		return nil
-	}); err != nil {
-		return []string{}, err
+		fileList = append(fileList, pth)
 	}
 	return SortPathsByComponents(fileList)
 }
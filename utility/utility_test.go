@@ -304,3 +304,27 @@ func TestInDirectoryFilter(t *testing.T) {
 		require.Equal(t, []string{"/Users/vagrant/test"}, filtered)
 	}
 }
+
+func TestDetectJSPackageManager(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__package_manager_test__")
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, os.RemoveAll(tmpDir))
+	}()
+
+	t.Log("npm")
+	{
+		packageManager, err := DetectJSPackageManager(tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, JSPackageManagerNpm, packageManager)
+	}
+
+	t.Log("yarn")
+	{
+		require.NoError(t, fileutil.WriteStringToFile(filepath.Join(tmpDir, "yarn.lock"), ""))
+
+		packageManager, err := DetectJSPackageManager(tmpDir)
+		require.NoError(t, err)
+		require.Equal(t, JSPackageManagerYarn, packageManager)
+	}
+}
@@ -0,0 +1,114 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/bitrise-core/bitrise-init/log"
+)
+
+const walkMaxAttempts = 3
+
+// walkRetryWaitTime is the wait time before the first retry of a failed read, doubled for each further retry.
+var walkRetryWaitTime = 100 * time.Millisecond
+
+// transientWalkErrors are the errors of the network mounted (NFS, SMB) and CI filesystems, which may pass on retry.
+var transientWalkErrors = []error{syscall.EAGAIN, syscall.EINTR, syscall.EBUSY, syscall.ESTALE, syscall.ETIMEDOUT}
+
+// walkFS is the filesystem read by the directory walks.
+type walkFS interface {
+	Lstat(pth string) (os.FileInfo, error)
+	// ReadDirNames returns the names of the directory's entries, in any order.
+	ReadDirNames(pth string) ([]string, error)
+}
+
+type osWalkFS struct{}
+
+func (osWalkFS) Lstat(pth string) (os.FileInfo, error) {
+	return os.Lstat(pth)
+}
+
+func (osWalkFS) ReadDirNames(pth string) ([]string, error) {
+	f, err := os.Open(pth)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			log.TWarnf("Failed to close directory (%s), error: %s", pth, err)
+		}
+	}()
+	return f.Readdirnames(-1)
+}
+
+// isTransientWalkError returns true if the read may pass on retry.
+// The permanent errors, like permission denied or not found, are not transient.
+func isTransientWalkError(err error) bool {
+	for _, transientErr := range transientWalkErrors {
+		if errors.Is(err, transientErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryWalkRead calls the read of the given path until it passes, fails with a permanent error,
+// or fails walkMaxAttempts times with transient errors.
+func retryWalkRead(pth string, read func() error) error {
+	waitTime := walkRetryWaitTime
+	for attempt := 1; ; attempt++ {
+		err := read()
+		if err == nil || !isTransientWalkError(err) {
+			return err
+		}
+		if attempt == walkMaxAttempts {
+			return fmt.Errorf("Failed to read %s after %d attempts, error: %s", pth, attempt, err)
+		}
+
+		log.TDebugf("Failed to read %s (attempt %d/%d), retrying in %s, error: %s", pth, attempt, walkMaxAttempts, waitTime, err)
+		time.Sleep(waitTime)
+		waitTime *= 2
+	}
+}
+
+// walkPaths returns the path of the root and of every file and directory below it, in lexical order, like filepath.Walk.
+// Symbolic links are not followed. The reads failing with transient errors are retried with backoff.
+func walkPaths(fs walkFS, root string) ([]string, error) {
+	var info os.FileInfo
+	if err := retryWalkRead(root, func() error {
+		var err error
+		info, err = fs.Lstat(root)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	pths := []string{root}
+	if !info.IsDir() {
+		return pths, nil
+	}
+
+	var names []string
+	if err := retryWalkRead(root, func() error {
+		var err error
+		names, err = fs.ReadDirNames(root)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		childPths, err := walkPaths(fs, filepath.Join(root, name))
+		if err != nil {
+			return nil, err
+		}
+		pths = append(pths, childPths...)
+	}
+	return pths, nil
+}
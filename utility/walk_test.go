@@ -0,0 +1,111 @@
+package utility
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testFileInfo is a file or directory of the testWalkFS.
+type testFileInfo struct {
+	os.FileInfo
+	isDir bool
+}
+
+func (info testFileInfo) IsDir() bool {
+	return info.isDir
+}
+
+// testWalkFS is an in-memory filesystem, which fails the reads of the given paths with the given errors, in order.
+type testWalkFS struct {
+	dirs     map[string][]string
+	failures map[string][]error
+	reads    map[string]int
+}
+
+func (fs *testWalkFS) fail(pth string) error {
+	fs.reads[pth]++
+	if len(fs.failures[pth]) == 0 {
+		return nil
+	}
+	err := fs.failures[pth][0]
+	fs.failures[pth] = fs.failures[pth][1:]
+	return err
+}
+
+func (fs *testWalkFS) Lstat(pth string) (os.FileInfo, error) {
+	_, isDir := fs.dirs[pth]
+	return testFileInfo{isDir: isDir}, nil
+}
+
+func (fs *testWalkFS) ReadDirNames(pth string) ([]string, error) {
+	if err := fs.fail(pth); err != nil {
+		return nil, &os.PathError{Op: "readdirent", Path: pth, Err: err}
+	}
+	return fs.dirs[pth], nil
+}
+
+func TestWalkPaths(t *testing.T) {
+	waitTime := walkRetryWaitTime
+	walkRetryWaitTime = time.Millisecond
+	defer func() {
+		walkRetryWaitTime = waitTime
+	}()
+
+	newFS := func(failures map[string][]error) *testWalkFS {
+		return &testWalkFS{
+			dirs: map[string][]string{
+				"/src":     {"b.txt", "app", "a.txt"},
+				"/src/app": {"main.go"},
+			},
+			failures: failures,
+			reads:    map[string]int{},
+		}
+	}
+	expectedPths := []string{"/src", "/src/a.txt", "/src/app", "/src/app/main.go", "/src/b.txt"}
+
+	t.Log("transient errors are retried")
+	{
+		fs := newFS(map[string][]error{"/src/app": {syscall.EAGAIN, syscall.ESTALE}})
+
+		pths, err := walkPaths(fs, "/src")
+		require.NoError(t, err)
+		require.Equal(t, expectedPths, pths)
+		require.Equal(t, 3, fs.reads["/src/app"])
+	}
+
+	t.Log("giving up after the max attempts")
+	{
+		fs := newFS(map[string][]error{"/src/app": {syscall.EAGAIN, syscall.EAGAIN, syscall.EAGAIN}})
+
+		_, err := walkPaths(fs, "/src")
+		require.Error(t, err)
+		require.True(t, strings.HasPrefix(err.Error(), "Failed to read /src/app after 3 attempts"), err.Error())
+		require.Equal(t, walkMaxAttempts, fs.reads["/src/app"])
+	}
+
+	t.Log("permanent errors are not retried")
+	{
+		fs := newFS(map[string][]error{"/src/app": {syscall.EACCES}})
+
+		_, err := walkPaths(fs, "/src")
+		require.Error(t, err)
+		require.True(t, os.IsPermission(err))
+		require.Equal(t, 1, fs.reads["/src/app"])
+	}
+}
+
+func TestIsTransientWalkError(t *testing.T) {
+	require.True(t, isTransientWalkError(&os.PathError{Op: "open", Path: "/src", Err: syscall.ESTALE}))
+	require.True(t, isTransientWalkError(syscall.EAGAIN))
+	require.False(t, isTransientWalkError(&os.PathError{Op: "open", Path: "/src", Err: syscall.ENOENT}))
+	require.False(t, isTransientWalkError(&os.PathError{Op: "open", Path: "/src", Err: syscall.EACCES}))
+
+	_, err := os.Lstat(filepath.Join(os.TempDir(), "__missing_walk_path__"))
+	require.False(t, isTransientWalkError(err))
+}